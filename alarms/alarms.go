@@ -6,6 +6,7 @@ package alarms
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/absmach/supermq/pkg/authn"
@@ -42,6 +43,10 @@ type Alarm struct {
 	AcknowledgedBy string    `json:"acknowledged_by,omitempty"`
 	ResolvedAt     time.Time `json:"resolved_at,omitempty"`
 	ResolvedBy     string    `json:"resolved_by,omitempty"`
+	Suppressed     bool      `json:"suppressed"`
+	Flapping       bool      `json:"flapping"`
+	Occurrences    uint64    `json:"occurrences"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
 	Metadata       Metadata  `json:"metadata,omitempty"`
 }
 
@@ -70,6 +75,8 @@ type PageMetadata struct {
 	AssignedBy     string    `json:"assigned_by"     db:"assigned_by"`
 	AcknowledgedBy string    `json:"acknowledged_by" db:"acknowledged_by"`
 	ResolvedBy     string    `json:"resolved_by"     db:"resolved_by"`
+	Suppressed     *bool     `json:"suppressed"      db:"suppressed"`
+	Flapping       *bool     `json:"flapping"        db:"flapping"`
 }
 
 func (a Alarm) Validate() error {
@@ -108,12 +115,41 @@ type Service interface {
 	ViewAlarm(ctx context.Context, session authn.Session, id string) (Alarm, error)
 	ListAlarms(ctx context.Context, session authn.Session, pm PageMetadata) (AlarmsPage, error)
 	DeleteAlarm(ctx context.Context, session authn.Session, id string) error
+	// StreamAlarms returns the events of alarms matching the filter which are
+	// created or updated by this service instance until the context is done.
+	StreamAlarms(ctx context.Context, session authn.Session, pm PageMetadata) (<-chan Event, error)
+	// ExportAlarms writes all alarms matching the filter to w in the given format.
+	ExportAlarms(ctx context.Context, session authn.Session, pm PageMetadata, format ExportFormat, w io.Writer) error
+	ApplyBulkOperation(ctx context.Context, session authn.Session, op BulkOperation) (BulkResult, error)
+
+	CreateSuppressionWindow(ctx context.Context, session authn.Session, w SuppressionWindow) (SuppressionWindow, error)
+	ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (SuppressionWindow, error)
+	UpdateSuppressionWindow(ctx context.Context, session authn.Session, w SuppressionWindow) (SuppressionWindow, error)
+	ListSuppressionWindows(ctx context.Context, session authn.Session, pm SuppressionPageMetadata) (SuppressionWindowsPage, error)
+	DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error
 }
 
 type Repository interface {
+	// CreateAlarm saves the alarm if it changes the state of the alarm source.
+	// Repeated alarms and alarms raised while the source is flapping are
+	// recorded on the latest saved alarm, which is returned instead.
 	CreateAlarm(ctx context.Context, alarm Alarm) (Alarm, error)
 	UpdateAlarm(ctx context.Context, alarm Alarm) (Alarm, error)
 	ViewAlarm(ctx context.Context, alarmID, domainID string) (Alarm, error)
 	ListAlarms(ctx context.Context, pm PageMetadata) (AlarmsPage, error)
-	DeleteAlarm(ctx context.Context, id string) error
+	// DeleteAlarm deletes the alarm and returns it as it was deleted.
+	DeleteAlarm(ctx context.Context, id string) (Alarm, error)
+	// ApplyBulkOperation applies the operation to all targeted alarms in a
+	// single transaction, reports the outcome for each of them and returns
+	// the alarms it updated or deleted.
+	ApplyBulkOperation(ctx context.Context, op BulkOperation) (BulkResult, []Alarm, error)
+
+	CreateSuppressionWindow(ctx context.Context, w SuppressionWindow) (SuppressionWindow, error)
+	ViewSuppressionWindow(ctx context.Context, id, domainID string) (SuppressionWindow, error)
+	UpdateSuppressionWindow(ctx context.Context, w SuppressionWindow) (SuppressionWindow, error)
+	ListSuppressionWindows(ctx context.Context, pm SuppressionPageMetadata) (SuppressionWindowsPage, error)
+	DeleteSuppressionWindow(ctx context.Context, id, domainID string) error
+	// RetrieveSuppressionWindows returns the windows whose scope matches the
+	// alarm and which may be active at the alarm creation time.
+	RetrieveSuppressionWindows(ctx context.Context, alarm Alarm) ([]SuppressionWindow, error)
 }
@@ -103,3 +103,139 @@ func deleteAlarmEndpoint(svc alarms.Service) endpoint.Endpoint {
 		return alarmRes{deleted: true}, nil
 	}
 }
+
+func bulkAlarmsEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkReq)
+		if err := req.validate(); err != nil {
+			return bulkRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return bulkRes{}, svcerr.ErrAuthorization
+		}
+
+		res, err := svc.ApplyBulkOperation(ctx, session, req.BulkOperation)
+		if err != nil {
+			return bulkRes{}, err
+		}
+
+		return bulkRes{
+			BulkResult: res,
+		}, nil
+	}
+}
+
+func createSuppressionWindowEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveSuppressionWindowReq)
+		if err := req.validate(); err != nil {
+			return suppressionWindowRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return suppressionWindowRes{}, svcerr.ErrAuthorization
+		}
+
+		w, err := svc.CreateSuppressionWindow(ctx, session, req.SuppressionWindow)
+		if err != nil {
+			return suppressionWindowRes{}, err
+		}
+
+		return suppressionWindowRes{
+			SuppressionWindow: w,
+			created:           true,
+		}, nil
+	}
+}
+
+func viewSuppressionWindowEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(suppressionWindowReq)
+		if err := req.validate(); err != nil {
+			return suppressionWindowRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return suppressionWindowRes{}, svcerr.ErrAuthorization
+		}
+
+		w, err := svc.ViewSuppressionWindow(ctx, session, req.ID)
+		if err != nil {
+			return suppressionWindowRes{}, err
+		}
+
+		return suppressionWindowRes{
+			SuppressionWindow: w,
+		}, nil
+	}
+}
+
+func updateSuppressionWindowEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveSuppressionWindowReq)
+		if err := req.validate(); err != nil {
+			return suppressionWindowRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return suppressionWindowRes{}, svcerr.ErrAuthorization
+		}
+
+		w, err := svc.UpdateSuppressionWindow(ctx, session, req.SuppressionWindow)
+		if err != nil {
+			return suppressionWindowRes{}, err
+		}
+
+		return suppressionWindowRes{
+			SuppressionWindow: w,
+		}, nil
+	}
+}
+
+func listSuppressionWindowsEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSuppressionWindowsReq)
+		if err := req.validate(); err != nil {
+			return suppressionWindowsPageRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return suppressionWindowsPageRes{}, svcerr.ErrAuthorization
+		}
+
+		page, err := svc.ListSuppressionWindows(ctx, session, req.SuppressionPageMetadata)
+		if err != nil {
+			return suppressionWindowsPageRes{}, err
+		}
+
+		return suppressionWindowsPageRes{
+			SuppressionWindowsPage: page,
+		}, nil
+	}
+}
+
+func deleteSuppressionWindowEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(suppressionWindowReq)
+		if err := req.validate(); err != nil {
+			return suppressionWindowRes{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			return suppressionWindowRes{}, svcerr.ErrAuthorization
+		}
+
+		if err := svc.DeleteSuppressionWindow(ctx, session, req.ID); err != nil {
+			return suppressionWindowRes{}, err
+		}
+
+		return suppressionWindowRes{deleted: true}, nil
+	}
+}
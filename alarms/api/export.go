@@ -0,0 +1,80 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	api "github.com/absmach/supermq/api/http"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	"github.com/absmach/supermq/pkg/authn"
+	"github.com/absmach/supermq/pkg/errors"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+)
+
+const formatKey = "format"
+
+// exportWriter sends the file headers with the first write, so errors which
+// occur before any alarm is written are still reported as regular responses.
+type exportWriter struct {
+	http.ResponseWriter
+	format  alarms.ExportFormat
+	written bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if !ew.written {
+		ew.written = true
+		name := fmt.Sprintf("alarms_%s.%s", time.Now().UTC().Format("20060102T150405"), ew.format)
+		ew.Header().Set("Content-Type", ew.format.ContentType())
+		ew.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		ew.WriteHeader(http.StatusOK)
+	}
+
+	return ew.ResponseWriter.Write(p)
+}
+
+// exportAlarmsHandler streams the alarms matching the list filters as a file.
+func exportAlarmsHandler(svc alarms.Service, logger *slog.Logger) http.HandlerFunc {
+	encodeError := apiutil.LoggingErrorEncoder(logger, api.EncodeError)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := decodeListAlarmsReq(ctx, r)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+		f, err := apiutil.ReadStringQuery(r, formatKey, alarms.CSVExport.String())
+		if err != nil {
+			encodeError(ctx, errors.Wrap(apiutil.ErrValidation, err), w)
+			return
+		}
+		format, err := alarms.ToExportFormat(f)
+		if err != nil {
+			encodeError(ctx, errors.Wrap(apiutil.ErrValidation, err), w)
+			return
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			encodeError(ctx, svcerr.ErrAuthorization, w)
+			return
+		}
+
+		ew := &exportWriter{ResponseWriter: w, format: format}
+		if err := svc.ExportAlarms(ctx, session, req.(listAlarmsReq).PageMetadata, format, ew); err != nil {
+			if !ew.written {
+				encodeError(ctx, err, w)
+				return
+			}
+			logger.Warn(fmt.Sprintf("failed to complete alarms export: %s", err))
+		}
+	}
+}
@@ -34,3 +34,71 @@ func (req listAlarmsReq) validate() error {
 
 	return nil
 }
+
+type bulkReq struct {
+	alarms.BulkOperation
+}
+
+func (req bulkReq) validate() error {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return alarms.ErrBulkTarget
+	}
+	if len(req.IDs) > alarms.MaxBulkSize {
+		return alarms.ErrBulkSize
+	}
+	for _, id := range req.IDs {
+		if id == "" {
+			return errors.New("missing alarm id")
+		}
+	}
+	if req.Filter != nil && req.Filter.Limit > alarms.MaxBulkSize {
+		return alarms.ErrBulkSize
+	}
+	if req.Action == alarms.AssignAction && req.AssigneeID == "" {
+		return errors.New("missing assignee id")
+	}
+
+	return nil
+}
+
+type suppressionWindowReq struct {
+	alarms.SuppressionWindow `json:",inline"`
+}
+
+func (req suppressionWindowReq) validate() error {
+	if req.ID == "" {
+		return errors.New("missing suppression window id")
+	}
+
+	return nil
+}
+
+type saveSuppressionWindowReq struct {
+	alarms.SuppressionWindow `json:",inline"`
+}
+
+func (req saveSuppressionWindowReq) validate() error {
+	if req.Name == "" {
+		return errors.New("missing suppression window name")
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return errors.New("missing suppression window start or end time")
+	}
+	if !req.StartsAt.Before(req.EndsAt) {
+		return alarms.ErrInvalidWindow
+	}
+
+	return nil
+}
+
+type listSuppressionWindowsReq struct {
+	alarms.SuppressionPageMetadata
+}
+
+func (req listSuppressionWindowsReq) validate() error {
+	if req.Limit > api.MaxLimitSize || req.Limit < 1 {
+		return apiutil.ErrLimitSize
+	}
+
+	return nil
+}
@@ -14,6 +14,9 @@ import (
 var (
 	_ supermq.Response = (*alarmRes)(nil)
 	_ supermq.Response = (*alarmsPageRes)(nil)
+	_ supermq.Response = (*bulkRes)(nil)
+	_ supermq.Response = (*suppressionWindowRes)(nil)
+	_ supermq.Response = (*suppressionWindowsPageRes)(nil)
 )
 
 type alarmRes struct {
@@ -68,3 +71,67 @@ func (res alarmsPageRes) Code() int {
 func (res alarmsPageRes) Empty() bool {
 	return false
 }
+
+type bulkRes struct {
+	alarms.BulkResult `json:",inline"`
+}
+
+func (res bulkRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res bulkRes) Code() int {
+	return http.StatusOK
+}
+
+func (res bulkRes) Empty() bool {
+	return false
+}
+
+type suppressionWindowRes struct {
+	alarms.SuppressionWindow `json:",inline"`
+	created                  bool
+	deleted                  bool
+}
+
+func (res suppressionWindowRes) Headers() map[string]string {
+	switch {
+	case res.created:
+		return map[string]string{
+			"Location": fmt.Sprintf("/%s/alarms/suppressions/%s", res.DomainID, res.ID),
+		}
+	default:
+		return map[string]string{}
+	}
+}
+
+func (res suppressionWindowRes) Code() int {
+	switch {
+	case res.created:
+		return http.StatusCreated
+	case res.deleted:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
+
+func (res suppressionWindowRes) Empty() bool {
+	return res.deleted
+}
+
+type suppressionWindowsPageRes struct {
+	alarms.SuppressionWindowsPage `json:",inline"`
+}
+
+func (res suppressionWindowsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res suppressionWindowsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res suppressionWindowsPageRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,172 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	api "github.com/absmach/supermq/api/http"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	"github.com/absmach/supermq/pkg/authn"
+	"github.com/absmach/supermq/pkg/errors"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/gorilla/websocket"
+)
+
+// heartbeatPeriod is the period of keep-alive messages sent on idle streams.
+const heartbeatPeriod = 30 * time.Second
+
+var errStreamingUnsupported = errors.New("streaming is not supported")
+
+// checkOrigin accepts WebSocket upgrades of clients which are not browsers,
+// of pages served by the same host, and of the given origins.
+func checkOrigin(origins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(origins))
+	for _, o := range origins {
+		allowed[strings.ToLower(strings.TrimSuffix(o, "/"))] = struct{}{}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		_, ok := allowed[strings.ToLower(u.Scheme+"://"+u.Host)]
+
+		return ok
+	}
+}
+
+// streamAlarmsHandler pushes alarm events to the caller using WebSocket when
+// the connection upgrade is requested, and Server-Sent Events otherwise.
+// WebSocket upgrades are accepted from the same host and the given origins.
+func streamAlarmsHandler(svc alarms.Service, logger *slog.Logger, origins []string) http.HandlerFunc {
+	encodeError := apiutil.LoggingErrorEncoder(logger, api.EncodeError)
+	upgrader := websocket.Upgrader{CheckOrigin: checkOrigin(origins)}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		req, err := decodeListAlarmsReq(ctx, r)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+
+		session, ok := ctx.Value(api.SessionKey).(authn.Session)
+		if !ok {
+			encodeError(ctx, svcerr.ErrAuthorization, w)
+			return
+		}
+
+		events, err := svc.StreamAlarms(ctx, session, req.(listAlarmsReq).PageMetadata)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+
+		if websocket.IsWebSocketUpgrade(r) {
+			streamWebSocket(ctx, cancel, upgrader, w, r, events, logger)
+			return
+		}
+		streamSSE(ctx, w, events, encodeError)
+	}
+}
+
+func streamSSE(ctx context.Context, w http.ResponseWriter, events <-chan alarms.Event, encodeError func(context.Context, error, http.ResponseWriter)) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		encodeError(ctx, errStreamingUnsupported, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e.Alarm)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Alarm.ID, e.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func streamWebSocket(ctx context.Context, cancel context.CancelFunc, upgrader websocket.Upgrader, w http.ResponseWriter, r *http.Request, events <-chan alarms.Event, logger *slog.Logger) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to upgrade alarms stream connection: %s", err))
+		return
+	}
+	defer conn.Close()
+
+	// Incoming messages are discarded; reading detects closed connections.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeatPeriod)); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	}
+}
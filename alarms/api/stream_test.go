@@ -0,0 +1,213 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/absmach/magistrala/alarms/api"
+	"github.com/absmach/magistrala/alarms/mocks"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqlog "github.com/absmach/supermq/logger"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	authnmocks "github.com/absmach/supermq/pkg/authn/mocks"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "valid"
+	invalidToken = "invalid"
+	domainID     = "domain-id"
+	allowed      = "https://dashboard.example.com"
+)
+
+var (
+	session = smqauthn.Session{UserID: "user-id", DomainID: domainID, DomainUserID: domainID + "_user-id"}
+	alarm   = alarms.Alarm{
+		ID:          "alarm-id",
+		RuleID:      "rule-id",
+		DomainID:    domainID,
+		ChannelID:   "channel-id",
+		Measurement: "temperature",
+		Status:      alarms.ActiveStatus,
+	}
+)
+
+func newAlarmsServer() (*httptest.Server, *mocks.Service, *authnmocks.Authentication) {
+	svc := new(mocks.Service)
+	authn := new(authnmocks.Authentication)
+
+	mux := api.MakeHandler(svc, smqlog.NewMock(), uuid.NewMock(), "", authn, []string{allowed})
+
+	return httptest.NewServer(mux), svc, authn
+}
+
+// events returns a channel of the events, which is closed once they are read.
+func events(es ...alarms.Event) <-chan alarms.Event {
+	ch := make(chan alarms.Event, len(es))
+	for _, e := range es {
+		ch <- e
+	}
+	close(ch)
+
+	return ch
+}
+
+func TestStreamAlarmsSSE(t *testing.T) {
+	ts, svc, authn := newAlarmsServer()
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		token    string
+		authnErr error
+		svcErr   error
+		status   int
+		body     []string
+	}{
+		{
+			desc:   "stream alarms",
+			token:  validToken,
+			status: http.StatusOK,
+			body: []string{
+				fmt.Sprintf("id: %s\nevent: %s\n", alarm.ID, alarms.AlarmCreated),
+				fmt.Sprintf("id: %s\nevent: %s\n", alarm.ID, alarms.AlarmDeleted),
+			},
+		},
+		{
+			desc:     "stream alarms with invalid token",
+			token:    invalidToken,
+			authnErr: svcerr.ErrAuthentication,
+			status:   http.StatusUnauthorized,
+		},
+		{
+			desc:   "stream alarms with service error",
+			token:  validToken,
+			svcErr: svcerr.ErrAuthorization,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authCall := authn.On("Authenticate", mock.Anything, tc.token).Return(session, tc.authnErr)
+			svcCall := svc.On("StreamAlarms", mock.Anything, mock.Anything, mock.Anything).Return(events(
+				alarms.Event{Type: alarms.AlarmCreated, Alarm: alarm},
+				alarms.Event{Type: alarms.AlarmDeleted, Alarm: alarm},
+			), tc.svcErr)
+			defer func() {
+				authCall.Unset()
+				svcCall.Unset()
+			}()
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s/alarms/stream", ts.URL, domainID), nil)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			req.Header.Set("Authorization", apiutil.BearerPrefix+tc.token)
+			res, err := ts.Client().Do(req)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+			if tc.status != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+			body, err := io.ReadAll(res.Body)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			for _, b := range tc.body {
+				assert.Contains(t, string(body), b, fmt.Sprintf("%s: expected event %q", tc.desc, b))
+			}
+		})
+	}
+}
+
+func TestStreamAlarmsWebSocket(t *testing.T) {
+	ts, svc, authn := newAlarmsServer()
+	defer ts.Close()
+
+	wsURL := fmt.Sprintf("%s/%s/alarms/stream", strings.Replace(ts.URL, "http", "ws", 1), domainID)
+
+	cases := []struct {
+		desc     string
+		token    string
+		origin   string
+		authnErr error
+		status   int
+	}{
+		{
+			desc:   "stream alarms without origin",
+			token:  validToken,
+			status: http.StatusSwitchingProtocols,
+		},
+		{
+			desc:   "stream alarms from the same host",
+			token:  validToken,
+			origin: ts.URL,
+			status: http.StatusSwitchingProtocols,
+		},
+		{
+			desc:   "stream alarms from an allowed origin",
+			token:  validToken,
+			origin: allowed,
+			status: http.StatusSwitchingProtocols,
+		},
+		{
+			desc:   "stream alarms from a disallowed origin",
+			token:  validToken,
+			origin: "https://attacker.example.com",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:     "stream alarms with invalid token",
+			token:    invalidToken,
+			authnErr: svcerr.ErrAuthentication,
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authCall := authn.On("Authenticate", mock.Anything, tc.token).Return(session, tc.authnErr)
+			svcCall := svc.On("StreamAlarms", mock.Anything, mock.Anything, mock.Anything).Return(events(
+				alarms.Event{Type: alarms.AlarmUpdated, Alarm: alarm},
+			), nil)
+			defer func() {
+				authCall.Unset()
+				svcCall.Unset()
+			}()
+
+			header := http.Header{}
+			header.Set("Authorization", apiutil.BearerPrefix+tc.token)
+			if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+			conn, res, err := websocket.DefaultDialer.Dial(wsURL, header)
+			require.NotNil(t, res, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+			if tc.status != http.StatusSwitchingProtocols {
+				assert.NotNil(t, err, fmt.Sprintf("%s: expected handshake error", tc.desc))
+				return
+			}
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			defer conn.Close()
+
+			var e alarms.Event
+			err = conn.ReadJSON(&e)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, alarms.AlarmUpdated, e.Type, fmt.Sprintf("%s: expected event type %s got %s", tc.desc, alarms.AlarmUpdated, e.Type))
+			assert.Equal(t, alarm.ID, e.Alarm.ID, fmt.Sprintf("%s: expected alarm %s got %s", tc.desc, alarm.ID, e.Alarm.ID))
+		})
+	}
+}
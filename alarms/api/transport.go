@@ -24,7 +24,9 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-func MakeHandler(svc alarms.Service, logger *slog.Logger, idp supermq.IDProvider, instanceID string, authn smqauthn.Authentication) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. WebSocket alarm
+// streams are accepted from the same host and the stream origins.
+func MakeHandler(svc alarms.Service, logger *slog.Logger, idp supermq.IDProvider, instanceID string, authn smqauthn.Authentication, streamOrigins []string) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, api.EncodeError)),
 	}
@@ -42,6 +44,48 @@ func MakeHandler(svc alarms.Service, logger *slog.Logger, idp supermq.IDProvider
 				api.EncodeResponse,
 				opts...,
 			), "list_alarms").ServeHTTP)
+			r.Get("/export", otelhttp.NewHandler(exportAlarmsHandler(svc, logger), "export_alarms").ServeHTTP)
+			r.Get("/stream", otelhttp.NewHandler(streamAlarmsHandler(svc, logger, streamOrigins), "stream_alarms").ServeHTTP)
+			r.Post("/bulk", otelhttp.NewHandler(kithttp.NewServer(
+				bulkAlarmsEndpoint(svc),
+				decodeBulkReq,
+				api.EncodeResponse,
+				opts...,
+			), "bulk_alarms").ServeHTTP)
+			r.Route("/suppressions", func(r chi.Router) {
+				r.Post("/", otelhttp.NewHandler(kithttp.NewServer(
+					createSuppressionWindowEndpoint(svc),
+					decodeSaveSuppressionWindowReq,
+					api.EncodeResponse,
+					opts...,
+				), "create_suppression_window").ServeHTTP)
+				r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+					listSuppressionWindowsEndpoint(svc),
+					decodeListSuppressionWindowsReq,
+					api.EncodeResponse,
+					opts...,
+				), "list_suppression_windows").ServeHTTP)
+				r.Route("/{windowID}", func(r chi.Router) {
+					r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+						viewSuppressionWindowEndpoint(svc),
+						decodeSuppressionWindowReq,
+						api.EncodeResponse,
+						opts...,
+					), "view_suppression_window").ServeHTTP)
+					r.Put("/", otelhttp.NewHandler(kithttp.NewServer(
+						updateSuppressionWindowEndpoint(svc),
+						decodeSaveSuppressionWindowReq,
+						api.EncodeResponse,
+						opts...,
+					), "update_suppression_window").ServeHTTP)
+					r.Delete("/", otelhttp.NewHandler(kithttp.NewServer(
+						deleteSuppressionWindowEndpoint(svc),
+						decodeSuppressionWindowReq,
+						api.EncodeResponse,
+						opts...,
+					), "delete_suppression_window").ServeHTTP)
+				})
+			})
 			r.Route("/{alarmID}", func(r chi.Router) {
 				r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
 					viewAlarmEndpoint(svc),
@@ -140,6 +184,22 @@ func decodeListAlarmsReq(_ context.Context, r *http.Request) (interface{}, error
 	if err != nil {
 		return listAlarmsReq{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
+	var suppressed *bool
+	if r.URL.Query().Has("suppressed") {
+		s, err := apiutil.ReadBoolQuery(r, "suppressed", false)
+		if err != nil {
+			return listAlarmsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		suppressed = &s
+	}
+	var flapping *bool
+	if r.URL.Query().Has("flapping") {
+		f, err := apiutil.ReadBoolQuery(r, "flapping", false)
+		if err != nil {
+			return listAlarmsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		flapping = &f
+	}
 
 	var createdFrom, createdTo time.Time
 	if cfrom != "" {
@@ -171,6 +231,8 @@ func decodeListAlarmsReq(_ context.Context, r *http.Request) (interface{}, error
 			AssignedBy:     assignedBy,
 			CreatedFrom:    createdFrom,
 			CreatedTo:      createdTo,
+			Suppressed:     suppressed,
+			Flapping:       flapping,
 		},
 	}, nil
 }
@@ -197,3 +259,107 @@ func decodeUpdateAlarmReq(_ context.Context, r *http.Request) (interface{}, erro
 
 	return req, nil
 }
+
+func decodeBulkReq(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), api.ContentType) {
+		return bulkReq{}, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	var body struct {
+		Action     string          `json:"action"`
+		IDs        []string        `json:"ids"`
+		AssigneeID string          `json:"assignee_id"`
+		Filter     json.RawMessage `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return bulkReq{}, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+	}
+
+	action, err := alarms.ToBulkAction(body.Action)
+	if err != nil {
+		return bulkReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	req := bulkReq{
+		BulkOperation: alarms.BulkOperation{
+			Action:     action,
+			IDs:        body.IDs,
+			AssigneeID: body.AssigneeID,
+		},
+	}
+
+	if len(body.Filter) > 0 && string(body.Filter) != "null" {
+		// Use the same defaults as listing alarms, so omitted filters match everything.
+		filter := alarms.PageMetadata{
+			Status:   alarms.AllStatus,
+			Severity: math.MaxUint8,
+		}
+		if err := json.Unmarshal(body.Filter, &filter); err != nil {
+			return bulkReq{}, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+		}
+		req.Filter = &filter
+	}
+
+	return req, nil
+}
+
+func decodeSuppressionWindowReq(_ context.Context, r *http.Request) (interface{}, error) {
+	return suppressionWindowReq{
+		SuppressionWindow: alarms.SuppressionWindow{
+			ID: chi.URLParam(r, "windowID"),
+		},
+	}, nil
+}
+
+func decodeSaveSuppressionWindowReq(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), api.ContentType) {
+		return saveSuppressionWindowReq{}, errors.Wrap(apiutil.ErrValidation, apiutil.ErrUnsupportedContentType)
+	}
+
+	req := saveSuppressionWindowReq{}
+	if err := json.NewDecoder(r.Body).Decode(&req.SuppressionWindow); err != nil {
+		return saveSuppressionWindowReq{}, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+	}
+
+	req.SuppressionWindow.ID = chi.URLParam(r, "windowID")
+
+	return req, nil
+}
+
+func decodeListSuppressionWindowsReq(_ context.Context, r *http.Request) (interface{}, error) {
+	offset, err := apiutil.ReadNumQuery[uint64](r, api.OffsetKey, api.DefOffset)
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	limit, err := apiutil.ReadNumQuery[uint64](r, api.LimitKey, api.DefLimit)
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	ruleID, err := apiutil.ReadStringQuery(r, "rule_id", "")
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	channelID, err := apiutil.ReadStringQuery(r, "channel_id", "")
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	clientID, err := apiutil.ReadStringQuery(r, "client_id", "")
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	subtopic, err := apiutil.ReadStringQuery(r, "subtopic", "")
+	if err != nil {
+		return listSuppressionWindowsReq{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	return listSuppressionWindowsReq{
+		SuppressionPageMetadata: alarms.SuppressionPageMetadata{
+			Offset:    offset,
+			Limit:     limit,
+			RuleID:    ruleID,
+			ChannelID: channelID,
+			ClientID:  clientID,
+			Subtopic:  subtopic,
+		},
+	}, nil
+}
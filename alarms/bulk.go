@@ -0,0 +1,148 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxBulkSize is the maximum number of alarms a single bulk operation can affect.
+const MaxBulkSize = 1000
+
+var (
+	ErrInvalidBulkAction = errors.New("invalid bulk action. Must be one of acknowledge, assign, resolve or delete")
+	ErrBulkTarget        = errors.New("bulk operation requires either alarm ids or a filter, not both")
+	ErrBulkSize          = errors.New("bulk operation exceeds maximum number of alarms")
+
+	// Per-item bulk operation failures.
+	ErrAlarmNotFound            = errors.New("alarm not found")
+	ErrAlarmAlreadyAcknowledged = errors.New("alarm already acknowledged")
+	ErrAlarmAlreadyResolved     = errors.New("alarm already resolved")
+)
+
+// BulkAction is an action that can be applied to many alarms at once.
+type BulkAction uint8
+
+const (
+	AcknowledgeAction BulkAction = iota
+	AssignAction
+	ResolveAction
+	DeleteAction
+)
+
+const (
+	acknowledge = "acknowledge"
+	assign      = "assign"
+	resolve     = "resolve"
+	remove      = "delete"
+)
+
+// String converts bulk action to string literal.
+func (a BulkAction) String() string {
+	switch a {
+	case AcknowledgeAction:
+		return acknowledge
+	case AssignAction:
+		return assign
+	case ResolveAction:
+		return resolve
+	case DeleteAction:
+		return remove
+	default:
+		return Unknown
+	}
+}
+
+// ToBulkAction converts string value to a valid bulk action.
+func ToBulkAction(action string) (BulkAction, error) {
+	switch strings.ToLower(action) {
+	case acknowledge:
+		return AcknowledgeAction, nil
+	case assign:
+		return AssignAction, nil
+	case resolve:
+		return ResolveAction, nil
+	case remove:
+		return DeleteAction, nil
+	default:
+		return BulkAction(0), ErrInvalidBulkAction
+	}
+}
+
+func (a BulkAction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *BulkAction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	val, err := ToBulkAction(s)
+	*a = val
+
+	return err
+}
+
+// BulkOperation describes an action applied to a set of alarms selected
+// either by their IDs or by a filter.
+type BulkOperation struct {
+	Action     BulkAction    `json:"action"`
+	IDs        []string      `json:"ids,omitempty"`
+	Filter     *PageMetadata `json:"filter,omitempty"`
+	AssigneeID string        `json:"assignee_id,omitempty"`
+	DomainID   string        `json:"-"`
+	UserID     string        `json:"-"`
+	Time       time.Time     `json:"-"`
+}
+
+// BulkItemResult is the outcome of a bulk operation for a single alarm.
+type BulkItemResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+type BulkResult struct {
+	Total     uint64           `json:"total"`
+	Succeeded uint64           `json:"succeeded"`
+	Failed    uint64           `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+func (op BulkOperation) Validate() error {
+	if op.Action > DeleteAction {
+		return ErrInvalidBulkAction
+	}
+	if (len(op.IDs) == 0) == (op.Filter == nil) {
+		return ErrBulkTarget
+	}
+	if len(op.IDs) > MaxBulkSize {
+		return ErrBulkSize
+	}
+	if op.Filter != nil && op.Filter.Limit > MaxBulkSize {
+		return ErrBulkSize
+	}
+	if op.Action == AssignAction && op.AssigneeID == "" {
+		return errors.New("assignee_id is required")
+	}
+
+	return nil
+}
+
+// Add records the outcome for a single alarm.
+func (res *BulkResult) Add(id string, err error) {
+	item := BulkItemResult{ID: id}
+	res.Total++
+	switch err {
+	case nil:
+		res.Succeeded++
+	default:
+		res.Failed++
+		item.Error = err.Error()
+	}
+	res.Results = append(res.Results, item)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBulkOperation(t *testing.T) {
+	ids := make([]string, alarms.MaxBulkSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("alarm-%d", i)
+	}
+
+	cases := []struct {
+		desc string
+		op   alarms.BulkOperation
+		err  error
+	}{
+		{
+			desc: "acknowledge by ids",
+			op:   alarms.BulkOperation{Action: alarms.AcknowledgeAction, IDs: []string{"alarm-id"}},
+			err:  nil,
+		},
+		{
+			desc: "delete by filter",
+			op:   alarms.BulkOperation{Action: alarms.DeleteAction, Filter: &alarms.PageMetadata{Limit: 10}},
+			err:  nil,
+		},
+		{
+			desc: "ids and filter",
+			op:   alarms.BulkOperation{Action: alarms.DeleteAction, IDs: []string{"alarm-id"}, Filter: &alarms.PageMetadata{}},
+			err:  alarms.ErrBulkTarget,
+		},
+		{
+			desc: "neither ids nor filter",
+			op:   alarms.BulkOperation{Action: alarms.ResolveAction},
+			err:  alarms.ErrBulkTarget,
+		},
+		{
+			desc: "too many ids",
+			op:   alarms.BulkOperation{Action: alarms.ResolveAction, IDs: ids},
+			err:  alarms.ErrBulkSize,
+		},
+		{
+			desc: "filter limit too large",
+			op:   alarms.BulkOperation{Action: alarms.ResolveAction, Filter: &alarms.PageMetadata{Limit: alarms.MaxBulkSize + 1}},
+			err:  alarms.ErrBulkSize,
+		},
+		{
+			desc: "invalid action",
+			op:   alarms.BulkOperation{Action: alarms.DeleteAction + 1, IDs: []string{"alarm-id"}},
+			err:  alarms.ErrInvalidBulkAction,
+		},
+		{
+			desc: "assign without assignee",
+			op:   alarms.BulkOperation{Action: alarms.AssignAction, IDs: []string{"alarm-id"}},
+			err:  errors.New("assignee_id is required"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.op.Validate()
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestBulkResultAdd(t *testing.T) {
+	var res alarms.BulkResult
+	res.Add("alarm-1", nil)
+	res.Add("alarm-2", alarms.ErrAlarmNotFound)
+	res.Add("alarm-3", nil)
+
+	assert.Equal(t, alarms.BulkResult{
+		Total:     3,
+		Succeeded: 2,
+		Failed:    1,
+		Results: []alarms.BulkItemResult{
+			{ID: "alarm-1"},
+			{ID: "alarm-2", Error: alarms.ErrAlarmNotFound.Error()},
+			{ID: "alarm-3"},
+		},
+	}, res)
+}
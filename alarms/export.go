@@ -0,0 +1,256 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/reports"
+)
+
+const (
+	// MaxPDFExportSize is the maximum number of alarms exported to a PDF
+	// file. PDF files are rendered in memory, unlike CSV files which are
+	// streamed as the alarms are read.
+	MaxPDFExportSize = 10000
+
+	exportPageSize = 1000
+	exportTitle    = "Alarms Report"
+	timeLayout     = "2006-01-02 15:04:05"
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format. Must be one of csv or pdf")
+	ErrExportSize          = errors.New("too many alarms to export to pdf, narrow the filter or use csv")
+)
+
+// ExportFormat is the file format alarms are exported to.
+type ExportFormat uint8
+
+const (
+	CSVExport ExportFormat = iota
+	PDFExport
+)
+
+const (
+	csvFormat = "csv"
+	pdfFormat = "pdf"
+)
+
+// String converts export format to string literal.
+func (f ExportFormat) String() string {
+	switch f {
+	case CSVExport:
+		return csvFormat
+	case PDFExport:
+		return pdfFormat
+	default:
+		return Unknown
+	}
+}
+
+// ContentType returns the media type of the exported file.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case PDFExport:
+		return "application/pdf"
+	default:
+		return "text/csv"
+	}
+}
+
+// ToExportFormat converts string value to a valid export format.
+func ToExportFormat(format string) (ExportFormat, error) {
+	switch strings.ToLower(format) {
+	case csvFormat:
+		return CSVExport, nil
+	case pdfFormat:
+		return PDFExport, nil
+	default:
+		return ExportFormat(0), ErrInvalidExportFormat
+	}
+}
+
+func (f ExportFormat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
+func (f *ExportFormat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	val, err := ToExportFormat(s)
+	*f = val
+
+	return err
+}
+
+var (
+	csvColumns = []reports.Column{
+		{Name: "Created At"},
+		{Name: "Status"},
+		{Name: "Severity"},
+		{Name: "Rule ID"},
+		{Name: "Channel ID"},
+		{Name: "Client ID"},
+		{Name: "Subtopic"},
+		{Name: "Measurement"},
+		{Name: "Value"},
+		{Name: "Unit"},
+		{Name: "Threshold"},
+		{Name: "Cause"},
+		{Name: "Assignee ID"},
+		{Name: "Acknowledged At"},
+		{Name: "Resolved At"},
+		{Name: "Occurrences"},
+		{Name: "Last Seen At"},
+		{Name: "Suppressed"},
+		{Name: "Flapping"},
+	}
+	pdfColumns = []reports.Column{
+		{Name: "Created At", Width: 2, Style: reports.Plain},
+		{Name: "Status", Width: 1, Style: reports.Accent},
+		{Name: "Severity", Width: 1, Style: reports.Plain},
+		{Name: "Measurement", Width: 2, Style: reports.Plain},
+		{Name: "Value", Width: 1, Style: reports.Plain},
+		{Name: "Threshold", Width: 1, Style: reports.Muted},
+		{Name: "Cause", Width: 3, Style: reports.Plain},
+		{Name: "Count", Width: 1, Style: reports.Muted},
+	}
+)
+
+// export writes the alarms matching the filter to w. The alarms are read in
+// pages, so the filter's offset and limit are ignored.
+func (s *service) export(ctx context.Context, pm PageMetadata, format ExportFormat, w io.Writer) error {
+	details := exportDetails(pm)
+
+	switch format {
+	case CSVExport:
+		cw := reports.NewCSVWriter(w, exportTitle)
+		if err := cw.Begin(reports.Table{Details: details, Columns: csvColumns}); err != nil {
+			return err
+		}
+		err := s.eachAlarm(ctx, pm, func(a Alarm) error {
+			return cw.Write(csvRow(a))
+		})
+		if err != nil {
+			return err
+		}
+
+		return cw.Flush()
+	case PDFExport:
+		var rows [][]string
+		err := s.eachAlarm(ctx, pm, func(a Alarm) error {
+			if len(rows) == MaxPDFExportSize {
+				return ErrExportSize
+			}
+			rows = append(rows, pdfRow(a))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		data, err := reports.PDF(exportTitle, []reports.Table{{
+			Title:   "Alarms",
+			Details: details,
+			Columns: pdfColumns,
+			Rows:    rows,
+		}})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+
+		return err
+	default:
+		return ErrInvalidExportFormat
+	}
+}
+
+func (s *service) eachAlarm(ctx context.Context, pm PageMetadata, fn func(Alarm) error) error {
+	// Bound the export so pages do not shift as new alarms arrive.
+	if pm.CreatedTo.IsZero() {
+		pm.CreatedTo = time.Now()
+	}
+	pm.Limit = exportPageSize
+	for pm.Offset = 0; ; pm.Offset += pm.Limit {
+		page, err := s.repo.ListAlarms(ctx, pm)
+		if err != nil {
+			return err
+		}
+		for _, a := range page.Alarms {
+			if err := fn(a); err != nil {
+				return err
+			}
+		}
+		if uint64(len(page.Alarms)) < pm.Limit {
+			return nil
+		}
+	}
+}
+
+func exportDetails(pm PageMetadata) []reports.Detail {
+	details := []reports.Detail{{Name: "Domain ID", Value: pm.DomainID}}
+	if !pm.CreatedFrom.IsZero() {
+		details = append(details, reports.Detail{Name: "From", Value: pm.CreatedFrom.Format(timeLayout)})
+	}
+	if !pm.CreatedTo.IsZero() {
+		details = append(details, reports.Detail{Name: "To", Value: pm.CreatedTo.Format(timeLayout)})
+	}
+
+	return details
+}
+
+func csvRow(a Alarm) []string {
+	return []string{
+		formatTime(a.CreatedAt),
+		a.Status.String(),
+		strconv.FormatUint(uint64(a.Severity), 10),
+		a.RuleID,
+		a.ChannelID,
+		a.ClientID,
+		a.Subtopic,
+		a.Measurement,
+		a.Value,
+		a.Unit,
+		a.Threshold,
+		a.Cause,
+		a.AssigneeID,
+		formatTime(a.AcknowledgedAt),
+		formatTime(a.ResolvedAt),
+		strconv.FormatUint(a.Occurrences, 10),
+		formatTime(a.LastSeenAt),
+		strconv.FormatBool(a.Suppressed),
+		strconv.FormatBool(a.Flapping),
+	}
+}
+
+func pdfRow(a Alarm) []string {
+	return []string{
+		formatTime(a.CreatedAt),
+		a.Status.String(),
+		strconv.FormatUint(uint64(a.Severity), 10),
+		a.Measurement,
+		a.Value,
+		a.Threshold,
+		a.Cause,
+		strconv.FormatUint(a.Occurrences, 10),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(timeLayout)
+}
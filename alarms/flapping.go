@@ -0,0 +1,22 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import "time"
+
+// FlapConfig configures alarm flap detection.
+//
+// An alarm is flapping when its state changes Threshold times within Window.
+// While flapping, further state changes are folded into the flapping alarm
+// instead of creating new alarms, until the state has not changed for Window.
+// Flap detection is disabled when Threshold is 0.
+type FlapConfig struct {
+	Threshold uint
+	Window    time.Duration
+}
+
+// Enabled reports whether flap detection is enabled.
+func (c FlapConfig) Enabled() bool {
+	return c.Threshold > 0 && c.Window > 0
+}
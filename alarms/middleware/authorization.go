@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"io"
 
 	"github.com/absmach/magistrala/alarms"
 	"github.com/absmach/supermq/auth"
@@ -66,6 +67,22 @@ func (am *authorizationMiddleware) UpdateAlarm(ctx context.Context, session auth
 	return am.svc.UpdateAlarm(ctx, session, alarm)
 }
 
+func (am *authorizationMiddleware) ExportAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) error {
+	if err := am.authorize(ctx, session, policies.MembershipPermission); err != nil {
+		return err
+	}
+
+	return am.svc.ExportAlarms(ctx, session, pm, format, w)
+}
+
+func (am *authorizationMiddleware) StreamAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (<-chan alarms.Event, error) {
+	if err := am.authorize(ctx, session, policies.MembershipPermission); err != nil {
+		return nil, err
+	}
+
+	return am.svc.StreamAlarms(ctx, session, pm)
+}
+
 func (am *authorizationMiddleware) DeleteAlarm(ctx context.Context, session authn.Session, id string) error {
 	req := smqauthz.PolicyReq{
 		SubjectType: policies.UserType,
@@ -83,6 +100,29 @@ func (am *authorizationMiddleware) DeleteAlarm(ctx context.Context, session auth
 	return am.svc.DeleteAlarm(ctx, session, id)
 }
 
+func (am *authorizationMiddleware) ApplyBulkOperation(ctx context.Context, session authn.Session, op alarms.BulkOperation) (alarms.BulkResult, error) {
+	if err := am.authorize(ctx, session, policies.AdminPermission); err != nil {
+		return alarms.BulkResult{}, err
+	}
+
+	if op.Action == alarms.AssignAction && op.AssigneeID != "" {
+		domainUserId := auth.EncodeDomainUserID(session.DomainID, op.AssigneeID)
+		if err := am.authz.Authorize(ctx, smqauthz.PolicyReq{
+			Domain:      session.DomainID,
+			SubjectType: policies.UserType,
+			SubjectKind: policies.UsersKind,
+			Subject:     domainUserId,
+			Permission:  policies.MembershipPermission,
+			ObjectType:  policies.DomainType,
+			Object:      session.DomainID,
+		}); err != nil {
+			return alarms.BulkResult{}, err
+		}
+	}
+
+	return am.svc.ApplyBulkOperation(ctx, session, op)
+}
+
 func (am *authorizationMiddleware) ListAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (alarms.AlarmsPage, error) {
 	if pm.DomainID == "" {
 		pm.DomainID = session.DomainID
@@ -122,3 +162,57 @@ func (am *authorizationMiddleware) ViewAlarm(ctx context.Context, session authn.
 
 	return am.svc.ViewAlarm(ctx, session, id)
 }
+
+func (am *authorizationMiddleware) CreateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	if err := am.authorize(ctx, session, policies.AdminPermission); err != nil {
+		return alarms.SuppressionWindow{}, err
+	}
+
+	return am.svc.CreateSuppressionWindow(ctx, session, w)
+}
+
+func (am *authorizationMiddleware) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (alarms.SuppressionWindow, error) {
+	if err := am.authorize(ctx, session, policies.MembershipPermission); err != nil {
+		return alarms.SuppressionWindow{}, err
+	}
+
+	return am.svc.ViewSuppressionWindow(ctx, session, id)
+}
+
+func (am *authorizationMiddleware) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	if err := am.authorize(ctx, session, policies.AdminPermission); err != nil {
+		return alarms.SuppressionWindow{}, err
+	}
+
+	return am.svc.UpdateSuppressionWindow(ctx, session, w)
+}
+
+func (am *authorizationMiddleware) ListSuppressionWindows(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	if err := am.authorize(ctx, session, policies.MembershipPermission); err != nil {
+		return alarms.SuppressionWindowsPage{}, err
+	}
+
+	return am.svc.ListSuppressionWindows(ctx, session, pm)
+}
+
+func (am *authorizationMiddleware) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error {
+	if err := am.authorize(ctx, session, policies.AdminPermission); err != nil {
+		return err
+	}
+
+	return am.svc.DeleteSuppressionWindow(ctx, session, id)
+}
+
+func (am *authorizationMiddleware) authorize(ctx context.Context, session authn.Session, permission string) error {
+	req := smqauthz.PolicyReq{
+		Domain:      session.DomainID,
+		SubjectType: policies.UserType,
+		SubjectKind: policies.UsersKind,
+		Subject:     session.DomainUserID,
+		Permission:  permission,
+		ObjectType:  policies.DomainType,
+		Object:      session.DomainID,
+	}
+
+	return am.authz.Authorize(ctx, req)
+}
@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"time"
 
@@ -136,6 +137,55 @@ func (lm *loggingMiddleware) ListAlarms(ctx context.Context, session authn.Sessi
 	return lm.service.ListAlarms(ctx, session, pm)
 }
 
+func (lm *loggingMiddleware) ExportAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.String("domain_id", session.DomainID),
+			slog.String("format", format.String()),
+			slog.String("rule_id", pm.RuleID),
+			slog.String("channel_id", pm.ChannelID),
+			slog.String("client_id", pm.ClientID),
+			slog.String("status", pm.Status.String()),
+			slog.Time("created_from", pm.CreatedFrom),
+			slog.Time("created_to", pm.CreatedTo),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Export alarms failed", args...)
+			return
+		}
+		lm.logger.Info("Export alarms completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.ExportAlarms(ctx, session, pm, format, w)
+}
+
+func (lm *loggingMiddleware) StreamAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (events <-chan alarms.Event, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.String("domain_id", session.DomainID),
+			slog.String("rule_id", pm.RuleID),
+			slog.String("channel_id", pm.ChannelID),
+			slog.String("client_id", pm.ClientID),
+			slog.String("subtopic", pm.Subtopic),
+			slog.String("status", pm.Status.String()),
+			slog.Uint64("severity", uint64(pm.Severity)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Stream alarms failed", args...)
+			return
+		}
+		lm.logger.Info("Stream alarms subscribed successfully", args...)
+	}(time.Now())
+
+	return lm.service.StreamAlarms(ctx, session, pm)
+}
+
 func (lm *loggingMiddleware) DeleteAlarm(ctx context.Context, session authn.Session, id string) (err error) {
 	defer func(begin time.Time) {
 		args := []any{
@@ -153,3 +203,137 @@ func (lm *loggingMiddleware) DeleteAlarm(ctx context.Context, session authn.Sess
 
 	return lm.service.DeleteAlarm(ctx, session, id)
 }
+
+func (lm *loggingMiddleware) ApplyBulkOperation(ctx context.Context, session authn.Session, op alarms.BulkOperation) (res alarms.BulkResult, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.String("action", op.Action.String()),
+			slog.Int("ids", len(op.IDs)),
+			slog.Bool("filter", op.Filter != nil),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bulk alarm operation failed", args...)
+			return
+		}
+		args = append(args,
+			slog.Uint64("succeeded", res.Succeeded),
+			slog.Uint64("failed", res.Failed),
+		)
+		lm.logger.Info("Bulk alarm operation completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.ApplyBulkOperation(ctx, session, op)
+}
+
+func (lm *loggingMiddleware) CreateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (sw alarms.SuppressionWindow, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.Group("suppression_window",
+				slog.String("id", sw.ID),
+				slog.String("name", w.Name),
+				slog.String("domain_id", session.DomainID),
+				slog.String("starts_at", w.StartsAt.String()),
+				slog.String("ends_at", w.EndsAt.String()),
+				slog.String("recurring", w.Recurring.String()),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Create suppression window failed", args...)
+			return
+		}
+		lm.logger.Info("Create suppression window completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.CreateSuppressionWindow(ctx, session, w)
+}
+
+func (lm *loggingMiddleware) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (sw alarms.SuppressionWindow, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.String("id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View suppression window failed", args...)
+			return
+		}
+		lm.logger.Info("View suppression window completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.ViewSuppressionWindow(ctx, session, id)
+}
+
+func (lm *loggingMiddleware) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (sw alarms.SuppressionWindow, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.Group("suppression_window",
+				slog.String("id", w.ID),
+				slog.String("name", w.Name),
+				slog.String("domain_id", session.DomainID),
+				slog.String("starts_at", w.StartsAt.String()),
+				slog.String("ends_at", w.EndsAt.String()),
+				slog.String("recurring", w.Recurring.String()),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update suppression window failed", args...)
+			return
+		}
+		lm.logger.Info("Update suppression window completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.UpdateSuppressionWindow(ctx, session, w)
+}
+
+func (lm *loggingMiddleware) ListSuppressionWindows(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (swp alarms.SuppressionWindowsPage, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.Int("offset", int(pm.Offset)),
+			slog.Int("limit", int(pm.Limit)),
+			slog.String("domain_id", session.DomainID),
+			slog.String("rule_id", pm.RuleID),
+			slog.String("channel_id", pm.ChannelID),
+			slog.String("client_id", pm.ClientID),
+			slog.String("subtopic", pm.Subtopic),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("List suppression windows failed", args...)
+			return
+		}
+		lm.logger.Info("List suppression windows completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.ListSuppressionWindows(ctx, session, pm)
+}
+
+func (lm *loggingMiddleware) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+			slog.String("id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Delete suppression window failed", args...)
+			return
+		}
+		lm.logger.Info("Delete suppression window completed successfully", args...)
+	}(time.Now())
+
+	return lm.service.DeleteSuppressionWindow(ctx, session, id)
+}
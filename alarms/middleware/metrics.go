@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/absmach/magistrala/alarms"
@@ -64,6 +65,24 @@ func (mm *metricsMiddleware) ListAlarms(ctx context.Context, session authn.Sessi
 	return mm.service.ListAlarms(ctx, session, pm)
 }
 
+func (mm *metricsMiddleware) ExportAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export_alarms_"+format.String()).Add(1)
+		mm.latency.With("method", "export_alarms_"+format.String()).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.ExportAlarms(ctx, session, pm, format, w)
+}
+
+func (mm *metricsMiddleware) StreamAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (<-chan alarms.Event, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "stream_alarms").Add(1)
+		mm.latency.With("method", "stream_alarms").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.StreamAlarms(ctx, session, pm)
+}
+
 func (mm *metricsMiddleware) DeleteAlarm(ctx context.Context, session authn.Session, id string) error {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "delete_alarm").Add(1)
@@ -72,3 +91,57 @@ func (mm *metricsMiddleware) DeleteAlarm(ctx context.Context, session authn.Sess
 
 	return mm.service.DeleteAlarm(ctx, session, id)
 }
+
+func (mm *metricsMiddleware) ApplyBulkOperation(ctx context.Context, session authn.Session, op alarms.BulkOperation) (alarms.BulkResult, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bulk_"+op.Action.String()+"_alarms").Add(1)
+		mm.latency.With("method", "bulk_"+op.Action.String()+"_alarms").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.ApplyBulkOperation(ctx, session, op)
+}
+
+func (mm *metricsMiddleware) CreateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "create_suppression_window").Add(1)
+		mm.latency.With("method", "create_suppression_window").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.CreateSuppressionWindow(ctx, session, w)
+}
+
+func (mm *metricsMiddleware) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (alarms.SuppressionWindow, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_suppression_window").Add(1)
+		mm.latency.With("method", "view_suppression_window").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.ViewSuppressionWindow(ctx, session, id)
+}
+
+func (mm *metricsMiddleware) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_suppression_window").Add(1)
+		mm.latency.With("method", "update_suppression_window").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.UpdateSuppressionWindow(ctx, session, w)
+}
+
+func (mm *metricsMiddleware) ListSuppressionWindows(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_suppression_windows").Add(1)
+		mm.latency.With("method", "list_suppression_windows").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.ListSuppressionWindows(ctx, session, pm)
+}
+
+func (mm *metricsMiddleware) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "delete_suppression_window").Add(1)
+		mm.latency.With("method", "delete_suppression_window").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.service.DeleteSuppressionWindow(ctx, session, id)
+}
@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"io"
 
 	"github.com/absmach/magistrala/alarms"
 	"github.com/absmach/supermq/pkg/authn"
@@ -74,6 +75,27 @@ func (tm *tracingMiddleware) ListAlarms(ctx context.Context, session authn.Sessi
 	return tm.svc.ListAlarms(ctx, session, pm)
 }
 
+func (tm *tracingMiddleware) ExportAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) error {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "export_alarms", trace.WithAttributes(
+		attribute.String("format", format.String()),
+		attribute.String("channel_id", pm.ChannelID),
+		attribute.String("status", pm.Status.String()),
+	))
+	defer span.End()
+
+	return tm.svc.ExportAlarms(ctx, session, pm, format, w)
+}
+
+func (tm *tracingMiddleware) StreamAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (<-chan alarms.Event, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "stream_alarms", trace.WithAttributes(
+		attribute.String("channel_id", pm.ChannelID),
+		attribute.String("status", pm.Status.String()),
+	))
+	defer span.End()
+
+	return tm.svc.StreamAlarms(ctx, session, pm)
+}
+
 func (tm *tracingMiddleware) DeleteAlarm(ctx context.Context, session authn.Session, id string) error {
 	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "delete_alarm", trace.WithAttributes(
 		attribute.String("id", id),
@@ -82,3 +104,63 @@ func (tm *tracingMiddleware) DeleteAlarm(ctx context.Context, session authn.Sess
 
 	return tm.svc.DeleteAlarm(ctx, session, id)
 }
+
+func (tm *tracingMiddleware) ApplyBulkOperation(ctx context.Context, session authn.Session, op alarms.BulkOperation) (alarms.BulkResult, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "apply_bulk_operation", trace.WithAttributes(
+		attribute.String("action", op.Action.String()),
+		attribute.Int("ids", len(op.IDs)),
+		attribute.Bool("filter", op.Filter != nil),
+	))
+	defer span.End()
+
+	return tm.svc.ApplyBulkOperation(ctx, session, op)
+}
+
+func (tm *tracingMiddleware) CreateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "create_suppression_window", trace.WithAttributes(
+		attribute.String("name", w.Name),
+		attribute.String("recurring", w.Recurring.String()),
+	))
+	defer span.End()
+
+	return tm.svc.CreateSuppressionWindow(ctx, session, w)
+}
+
+func (tm *tracingMiddleware) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (alarms.SuppressionWindow, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "view_suppression_window", trace.WithAttributes(
+		attribute.String("id", id),
+	))
+	defer span.End()
+
+	return tm.svc.ViewSuppressionWindow(ctx, session, id)
+}
+
+func (tm *tracingMiddleware) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "update_suppression_window", trace.WithAttributes(
+		attribute.String("id", w.ID),
+		attribute.String("name", w.Name),
+		attribute.String("recurring", w.Recurring.String()),
+	))
+	defer span.End()
+
+	return tm.svc.UpdateSuppressionWindow(ctx, session, w)
+}
+
+func (tm *tracingMiddleware) ListSuppressionWindows(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "list_suppression_windows", trace.WithAttributes(
+		attribute.Int("offset", int(pm.Offset)),
+		attribute.Int("limit", int(pm.Limit)),
+	))
+	defer span.End()
+
+	return tm.svc.ListSuppressionWindows(ctx, session, pm)
+}
+
+func (tm *tracingMiddleware) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error {
+	ctx, span := smqTracing.StartSpan(ctx, tm.tracer, "delete_suppression_window", trace.WithAttributes(
+		attribute.String("id", id),
+	))
+	defer span.End()
+
+	return tm.svc.DeleteSuppressionWindow(ctx, session, id)
+}
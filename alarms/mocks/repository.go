@@ -41,6 +41,69 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// ApplyBulkOperation provides a mock function for the type Repository
+func (_mock *Repository) ApplyBulkOperation(ctx context.Context, op alarms.BulkOperation) (alarms.BulkResult, []alarms.Alarm, error) {
+	ret := _mock.Called(ctx, op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBulkOperation")
+	}
+
+	var r0 alarms.BulkResult
+	var r1 []alarms.Alarm
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.BulkOperation) (alarms.BulkResult, []alarms.Alarm, error)); ok {
+		return returnFunc(ctx, op)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.BulkOperation) alarms.BulkResult); ok {
+		r0 = returnFunc(ctx, op)
+	} else {
+		r0 = ret.Get(0).(alarms.BulkResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, alarms.BulkOperation) []alarms.Alarm); ok {
+		r1 = returnFunc(ctx, op)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]alarms.Alarm)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, alarms.BulkOperation) error); ok {
+		r2 = returnFunc(ctx, op)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// Repository_ApplyBulkOperation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyBulkOperation'
+type Repository_ApplyBulkOperation_Call struct {
+	*mock.Call
+}
+
+// ApplyBulkOperation is a helper method to define mock.On call
+//   - ctx
+//   - op
+func (_e *Repository_Expecter) ApplyBulkOperation(ctx interface{}, op interface{}) *Repository_ApplyBulkOperation_Call {
+	return &Repository_ApplyBulkOperation_Call{Call: _e.mock.On("ApplyBulkOperation", ctx, op)}
+}
+
+func (_c *Repository_ApplyBulkOperation_Call) Run(run func(ctx context.Context, op alarms.BulkOperation)) *Repository_ApplyBulkOperation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(alarms.BulkOperation))
+	})
+	return _c
+}
+
+func (_c *Repository_ApplyBulkOperation_Call) Return(bulkResult alarms.BulkResult, alarms1 []alarms.Alarm, err error) *Repository_ApplyBulkOperation_Call {
+	_c.Call.Return(bulkResult, alarms1, err)
+	return _c
+}
+
+func (_c *Repository_ApplyBulkOperation_Call) RunAndReturn(run func(ctx context.Context, op alarms.BulkOperation) (alarms.BulkResult, []alarms.Alarm, error)) *Repository_ApplyBulkOperation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAlarm provides a mock function for the type Repository
 func (_mock *Repository) CreateAlarm(ctx context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, alarm)
@@ -96,21 +159,85 @@ func (_c *Repository_CreateAlarm_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// CreateSuppressionWindow provides a mock function for the type Repository
+func (_mock *Repository) CreateSuppressionWindow(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionWindow) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionWindow) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, w)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, alarms.SuppressionWindow) error); ok {
+		r1 = returnFunc(ctx, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Repository_CreateSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSuppressionWindow'
+type Repository_CreateSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// CreateSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - w
+func (_e *Repository_Expecter) CreateSuppressionWindow(ctx interface{}, w interface{}) *Repository_CreateSuppressionWindow_Call {
+	return &Repository_CreateSuppressionWindow_Call{Call: _e.mock.On("CreateSuppressionWindow", ctx, w)}
+}
+
+func (_c *Repository_CreateSuppressionWindow_Call) Run(run func(ctx context.Context, w alarms.SuppressionWindow)) *Repository_CreateSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(alarms.SuppressionWindow))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Repository_CreateSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Repository_CreateSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error)) *Repository_CreateSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteAlarm provides a mock function for the type Repository
-func (_mock *Repository) DeleteAlarm(ctx context.Context, id string) error {
+func (_mock *Repository) DeleteAlarm(ctx context.Context, id string) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteAlarm")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	var r0 alarms.Alarm
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (alarms.Alarm, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) alarms.Alarm); ok {
 		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(alarms.Alarm)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // Repository_DeleteAlarm_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAlarm'
@@ -132,12 +259,59 @@ func (_c *Repository_DeleteAlarm_Call) Run(run func(ctx context.Context, id stri
 	return _c
 }
 
-func (_c *Repository_DeleteAlarm_Call) Return(err error) *Repository_DeleteAlarm_Call {
+func (_c *Repository_DeleteAlarm_Call) Return(alarm alarms.Alarm, err error) *Repository_DeleteAlarm_Call {
+	_c.Call.Return(alarm, err)
+	return _c
+}
+
+func (_c *Repository_DeleteAlarm_Call) RunAndReturn(run func(ctx context.Context, id string) (alarms.Alarm, error)) *Repository_DeleteAlarm_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSuppressionWindow provides a mock function for the type Repository
+func (_mock *Repository) DeleteSuppressionWindow(ctx context.Context, id string, domainID string) error {
+	ret := _mock.Called(ctx, id, domainID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSuppressionWindow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, id, domainID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Repository_DeleteSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSuppressionWindow'
+type Repository_DeleteSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// DeleteSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - domainID
+func (_e *Repository_Expecter) DeleteSuppressionWindow(ctx interface{}, id interface{}, domainID interface{}) *Repository_DeleteSuppressionWindow_Call {
+	return &Repository_DeleteSuppressionWindow_Call{Call: _e.mock.On("DeleteSuppressionWindow", ctx, id, domainID)}
+}
+
+func (_c *Repository_DeleteSuppressionWindow_Call) Run(run func(ctx context.Context, id string, domainID string)) *Repository_DeleteSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_DeleteSuppressionWindow_Call) Return(err error) *Repository_DeleteSuppressionWindow_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Repository_DeleteAlarm_Call) RunAndReturn(run func(ctx context.Context, id string) error) *Repository_DeleteAlarm_Call {
+func (_c *Repository_DeleteSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, id string, domainID string) error) *Repository_DeleteSuppressionWindow_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -197,6 +371,118 @@ func (_c *Repository_ListAlarms_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// ListSuppressionWindows provides a mock function for the type Repository
+func (_mock *Repository) ListSuppressionWindows(ctx context.Context, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	ret := _mock.Called(ctx, pm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSuppressionWindows")
+	}
+
+	var r0 alarms.SuppressionWindowsPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error)); ok {
+		return returnFunc(ctx, pm)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionPageMetadata) alarms.SuppressionWindowsPage); ok {
+		r0 = returnFunc(ctx, pm)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindowsPage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, alarms.SuppressionPageMetadata) error); ok {
+		r1 = returnFunc(ctx, pm)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Repository_ListSuppressionWindows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSuppressionWindows'
+type Repository_ListSuppressionWindows_Call struct {
+	*mock.Call
+}
+
+// ListSuppressionWindows is a helper method to define mock.On call
+//   - ctx
+//   - pm
+func (_e *Repository_Expecter) ListSuppressionWindows(ctx interface{}, pm interface{}) *Repository_ListSuppressionWindows_Call {
+	return &Repository_ListSuppressionWindows_Call{Call: _e.mock.On("ListSuppressionWindows", ctx, pm)}
+}
+
+func (_c *Repository_ListSuppressionWindows_Call) Run(run func(ctx context.Context, pm alarms.SuppressionPageMetadata)) *Repository_ListSuppressionWindows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(alarms.SuppressionPageMetadata))
+	})
+	return _c
+}
+
+func (_c *Repository_ListSuppressionWindows_Call) Return(suppressionWindowsPage alarms.SuppressionWindowsPage, err error) *Repository_ListSuppressionWindows_Call {
+	_c.Call.Return(suppressionWindowsPage, err)
+	return _c
+}
+
+func (_c *Repository_ListSuppressionWindows_Call) RunAndReturn(run func(ctx context.Context, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error)) *Repository_ListSuppressionWindows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetrieveSuppressionWindows provides a mock function for the type Repository
+func (_mock *Repository) RetrieveSuppressionWindows(ctx context.Context, alarm alarms.Alarm) ([]alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, alarm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetrieveSuppressionWindows")
+	}
+
+	var r0 []alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.Alarm) ([]alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, alarm)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.Alarm) []alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, alarm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]alarms.SuppressionWindow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, alarms.Alarm) error); ok {
+		r1 = returnFunc(ctx, alarm)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Repository_RetrieveSuppressionWindows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetrieveSuppressionWindows'
+type Repository_RetrieveSuppressionWindows_Call struct {
+	*mock.Call
+}
+
+// RetrieveSuppressionWindows is a helper method to define mock.On call
+//   - ctx
+//   - alarm
+func (_e *Repository_Expecter) RetrieveSuppressionWindows(ctx interface{}, alarm interface{}) *Repository_RetrieveSuppressionWindows_Call {
+	return &Repository_RetrieveSuppressionWindows_Call{Call: _e.mock.On("RetrieveSuppressionWindows", ctx, alarm)}
+}
+
+func (_c *Repository_RetrieveSuppressionWindows_Call) Run(run func(ctx context.Context, alarm alarms.Alarm)) *Repository_RetrieveSuppressionWindows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(alarms.Alarm))
+	})
+	return _c
+}
+
+func (_c *Repository_RetrieveSuppressionWindows_Call) Return(suppressionWindows []alarms.SuppressionWindow, err error) *Repository_RetrieveSuppressionWindows_Call {
+	_c.Call.Return(suppressionWindows, err)
+	return _c
+}
+
+func (_c *Repository_RetrieveSuppressionWindows_Call) RunAndReturn(run func(ctx context.Context, alarm alarms.Alarm) ([]alarms.SuppressionWindow, error)) *Repository_RetrieveSuppressionWindows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateAlarm provides a mock function for the type Repository
 func (_mock *Repository) UpdateAlarm(ctx context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, alarm)
@@ -252,6 +538,61 @@ func (_c *Repository_UpdateAlarm_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// UpdateSuppressionWindow provides a mock function for the type Repository
+func (_mock *Repository) UpdateSuppressionWindow(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionWindow) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, alarms.SuppressionWindow) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, w)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, alarms.SuppressionWindow) error); ok {
+		r1 = returnFunc(ctx, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Repository_UpdateSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSuppressionWindow'
+type Repository_UpdateSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// UpdateSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - w
+func (_e *Repository_Expecter) UpdateSuppressionWindow(ctx interface{}, w interface{}) *Repository_UpdateSuppressionWindow_Call {
+	return &Repository_UpdateSuppressionWindow_Call{Call: _e.mock.On("UpdateSuppressionWindow", ctx, w)}
+}
+
+func (_c *Repository_UpdateSuppressionWindow_Call) Run(run func(ctx context.Context, w alarms.SuppressionWindow)) *Repository_UpdateSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(alarms.SuppressionWindow))
+	})
+	return _c
+}
+
+func (_c *Repository_UpdateSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Repository_UpdateSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Repository_UpdateSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error)) *Repository_UpdateSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ViewAlarm provides a mock function for the type Repository
 func (_mock *Repository) ViewAlarm(ctx context.Context, alarmID string, domainID string) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, alarmID, domainID)
@@ -307,3 +648,59 @@ func (_c *Repository_ViewAlarm_Call) RunAndReturn(run func(ctx context.Context,
 	_c.Call.Return(run)
 	return _c
 }
+
+// ViewSuppressionWindow provides a mock function for the type Repository
+func (_mock *Repository) ViewSuppressionWindow(ctx context.Context, id string, domainID string) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, id, domainID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ViewSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, id, domainID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, id, domainID)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, id, domainID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Repository_ViewSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ViewSuppressionWindow'
+type Repository_ViewSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// ViewSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - domainID
+func (_e *Repository_Expecter) ViewSuppressionWindow(ctx interface{}, id interface{}, domainID interface{}) *Repository_ViewSuppressionWindow_Call {
+	return &Repository_ViewSuppressionWindow_Call{Call: _e.mock.On("ViewSuppressionWindow", ctx, id, domainID)}
+}
+
+func (_c *Repository_ViewSuppressionWindow_Call) Run(run func(ctx context.Context, id string, domainID string)) *Repository_ViewSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_ViewSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Repository_ViewSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Repository_ViewSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, id string, domainID string) (alarms.SuppressionWindow, error)) *Repository_ViewSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -9,6 +9,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	"github.com/absmach/magistrala/alarms"
 	"github.com/absmach/supermq/pkg/authn"
@@ -42,6 +43,62 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// ApplyBulkOperation provides a mock function for the type Service
+func (_mock *Service) ApplyBulkOperation(ctx context.Context, session authn.Session, op alarms.BulkOperation) (alarms.BulkResult, error) {
+	ret := _mock.Called(ctx, session, op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBulkOperation")
+	}
+
+	var r0 alarms.BulkResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.BulkOperation) (alarms.BulkResult, error)); ok {
+		return returnFunc(ctx, session, op)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.BulkOperation) alarms.BulkResult); ok {
+		r0 = returnFunc(ctx, session, op)
+	} else {
+		r0 = ret.Get(0).(alarms.BulkResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, alarms.BulkOperation) error); ok {
+		r1 = returnFunc(ctx, session, op)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_ApplyBulkOperation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyBulkOperation'
+type Service_ApplyBulkOperation_Call struct {
+	*mock.Call
+}
+
+// ApplyBulkOperation is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - op
+func (_e *Service_Expecter) ApplyBulkOperation(ctx interface{}, session interface{}, op interface{}) *Service_ApplyBulkOperation_Call {
+	return &Service_ApplyBulkOperation_Call{Call: _e.mock.On("ApplyBulkOperation", ctx, session, op)}
+}
+
+func (_c *Service_ApplyBulkOperation_Call) Run(run func(ctx context.Context, session authn.Session, op alarms.BulkOperation)) *Service_ApplyBulkOperation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.BulkOperation))
+	})
+	return _c
+}
+
+func (_c *Service_ApplyBulkOperation_Call) Return(bulkResult alarms.BulkResult, err error) *Service_ApplyBulkOperation_Call {
+	_c.Call.Return(bulkResult, err)
+	return _c
+}
+
+func (_c *Service_ApplyBulkOperation_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, op alarms.BulkOperation) (alarms.BulkResult, error)) *Service_ApplyBulkOperation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAlarm provides a mock function for the type Service
 func (_mock *Service) CreateAlarm(ctx context.Context, alarm alarms.Alarm) error {
 	ret := _mock.Called(ctx, alarm)
@@ -88,6 +145,62 @@ func (_c *Service_CreateAlarm_Call) RunAndReturn(run func(ctx context.Context, a
 	return _c
 }
 
+// CreateSuppressionWindow provides a mock function for the type Service
+func (_mock *Service) CreateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, session, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionWindow) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, session, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionWindow) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, session, w)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, alarms.SuppressionWindow) error); ok {
+		r1 = returnFunc(ctx, session, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_CreateSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSuppressionWindow'
+type Service_CreateSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// CreateSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - w
+func (_e *Service_Expecter) CreateSuppressionWindow(ctx interface{}, session interface{}, w interface{}) *Service_CreateSuppressionWindow_Call {
+	return &Service_CreateSuppressionWindow_Call{Call: _e.mock.On("CreateSuppressionWindow", ctx, session, w)}
+}
+
+func (_c *Service_CreateSuppressionWindow_Call) Run(run func(ctx context.Context, session authn.Session, w alarms.SuppressionWindow)) *Service_CreateSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.SuppressionWindow))
+	})
+	return _c
+}
+
+func (_c *Service_CreateSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Service_CreateSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Service_CreateSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error)) *Service_CreateSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteAlarm provides a mock function for the type Service
 func (_mock *Service) DeleteAlarm(ctx context.Context, session authn.Session, id string) error {
 	ret := _mock.Called(ctx, session, id)
@@ -135,6 +248,102 @@ func (_c *Service_DeleteAlarm_Call) RunAndReturn(run func(ctx context.Context, s
 	return _c
 }
 
+// DeleteSuppressionWindow provides a mock function for the type Service
+func (_mock *Service) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error {
+	ret := _mock.Called(ctx, session, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSuppressionWindow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, string) error); ok {
+		r0 = returnFunc(ctx, session, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Service_DeleteSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSuppressionWindow'
+type Service_DeleteSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// DeleteSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - id
+func (_e *Service_Expecter) DeleteSuppressionWindow(ctx interface{}, session interface{}, id interface{}) *Service_DeleteSuppressionWindow_Call {
+	return &Service_DeleteSuppressionWindow_Call{Call: _e.mock.On("DeleteSuppressionWindow", ctx, session, id)}
+}
+
+func (_c *Service_DeleteSuppressionWindow_Call) Run(run func(ctx context.Context, session authn.Session, id string)) *Service_DeleteSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_DeleteSuppressionWindow_Call) Return(err error) *Service_DeleteSuppressionWindow_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Service_DeleteSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, id string) error) *Service_DeleteSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportAlarms provides a mock function for the type Service
+func (_mock *Service) ExportAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) error {
+	ret := _mock.Called(ctx, session, pm, format, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportAlarms")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.PageMetadata, alarms.ExportFormat, io.Writer) error); ok {
+		r0 = returnFunc(ctx, session, pm, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Service_ExportAlarms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportAlarms'
+type Service_ExportAlarms_Call struct {
+	*mock.Call
+}
+
+// ExportAlarms is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - pm
+//   - format
+//   - w
+func (_e *Service_Expecter) ExportAlarms(ctx interface{}, session interface{}, pm interface{}, format interface{}, w interface{}) *Service_ExportAlarms_Call {
+	return &Service_ExportAlarms_Call{Call: _e.mock.On("ExportAlarms", ctx, session, pm, format, w)}
+}
+
+func (_c *Service_ExportAlarms_Call) Run(run func(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer)) *Service_ExportAlarms_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.PageMetadata), args[3].(alarms.ExportFormat), args[4].(io.Writer))
+	})
+	return _c
+}
+
+func (_c *Service_ExportAlarms_Call) Return(err error) *Service_ExportAlarms_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Service_ExportAlarms_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, pm alarms.PageMetadata, format alarms.ExportFormat, w io.Writer) error) *Service_ExportAlarms_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAlarms provides a mock function for the type Service
 func (_mock *Service) ListAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (alarms.AlarmsPage, error) {
 	ret := _mock.Called(ctx, session, pm)
@@ -191,6 +400,120 @@ func (_c *Service_ListAlarms_Call) RunAndReturn(run func(ctx context.Context, se
 	return _c
 }
 
+// ListSuppressionWindows provides a mock function for the type Service
+func (_mock *Service) ListSuppressionWindows(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	ret := _mock.Called(ctx, session, pm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSuppressionWindows")
+	}
+
+	var r0 alarms.SuppressionWindowsPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error)); ok {
+		return returnFunc(ctx, session, pm)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionPageMetadata) alarms.SuppressionWindowsPage); ok {
+		r0 = returnFunc(ctx, session, pm)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindowsPage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, alarms.SuppressionPageMetadata) error); ok {
+		r1 = returnFunc(ctx, session, pm)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_ListSuppressionWindows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSuppressionWindows'
+type Service_ListSuppressionWindows_Call struct {
+	*mock.Call
+}
+
+// ListSuppressionWindows is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - pm
+func (_e *Service_Expecter) ListSuppressionWindows(ctx interface{}, session interface{}, pm interface{}) *Service_ListSuppressionWindows_Call {
+	return &Service_ListSuppressionWindows_Call{Call: _e.mock.On("ListSuppressionWindows", ctx, session, pm)}
+}
+
+func (_c *Service_ListSuppressionWindows_Call) Run(run func(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata)) *Service_ListSuppressionWindows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.SuppressionPageMetadata))
+	})
+	return _c
+}
+
+func (_c *Service_ListSuppressionWindows_Call) Return(suppressionWindowsPage alarms.SuppressionWindowsPage, err error) *Service_ListSuppressionWindows_Call {
+	_c.Call.Return(suppressionWindowsPage, err)
+	return _c
+}
+
+func (_c *Service_ListSuppressionWindows_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error)) *Service_ListSuppressionWindows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamAlarms provides a mock function for the type Service
+func (_mock *Service) StreamAlarms(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (<-chan alarms.Event, error) {
+	ret := _mock.Called(ctx, session, pm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamAlarms")
+	}
+
+	var r0 <-chan alarms.Event
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.PageMetadata) (<-chan alarms.Event, error)); ok {
+		return returnFunc(ctx, session, pm)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.PageMetadata) <-chan alarms.Event); ok {
+		r0 = returnFunc(ctx, session, pm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan alarms.Event)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, alarms.PageMetadata) error); ok {
+		r1 = returnFunc(ctx, session, pm)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_StreamAlarms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamAlarms'
+type Service_StreamAlarms_Call struct {
+	*mock.Call
+}
+
+// StreamAlarms is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - pm
+func (_e *Service_Expecter) StreamAlarms(ctx interface{}, session interface{}, pm interface{}) *Service_StreamAlarms_Call {
+	return &Service_StreamAlarms_Call{Call: _e.mock.On("StreamAlarms", ctx, session, pm)}
+}
+
+func (_c *Service_StreamAlarms_Call) Run(run func(ctx context.Context, session authn.Session, pm alarms.PageMetadata)) *Service_StreamAlarms_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.PageMetadata))
+	})
+	return _c
+}
+
+func (_c *Service_StreamAlarms_Call) Return(eventCh <-chan alarms.Event, err error) *Service_StreamAlarms_Call {
+	_c.Call.Return(eventCh, err)
+	return _c
+}
+
+func (_c *Service_StreamAlarms_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, pm alarms.PageMetadata) (<-chan alarms.Event, error)) *Service_StreamAlarms_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateAlarm provides a mock function for the type Service
 func (_mock *Service) UpdateAlarm(ctx context.Context, session authn.Session, alarm alarms.Alarm) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, session, alarm)
@@ -247,6 +570,62 @@ func (_c *Service_UpdateAlarm_Call) RunAndReturn(run func(ctx context.Context, s
 	return _c
 }
 
+// UpdateSuppressionWindow provides a mock function for the type Service
+func (_mock *Service) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, session, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionWindow) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, session, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, alarms.SuppressionWindow) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, session, w)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, alarms.SuppressionWindow) error); ok {
+		r1 = returnFunc(ctx, session, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_UpdateSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSuppressionWindow'
+type Service_UpdateSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// UpdateSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - w
+func (_e *Service_Expecter) UpdateSuppressionWindow(ctx interface{}, session interface{}, w interface{}) *Service_UpdateSuppressionWindow_Call {
+	return &Service_UpdateSuppressionWindow_Call{Call: _e.mock.On("UpdateSuppressionWindow", ctx, session, w)}
+}
+
+func (_c *Service_UpdateSuppressionWindow_Call) Run(run func(ctx context.Context, session authn.Session, w alarms.SuppressionWindow)) *Service_UpdateSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(alarms.SuppressionWindow))
+	})
+	return _c
+}
+
+func (_c *Service_UpdateSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Service_UpdateSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Service_UpdateSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error)) *Service_UpdateSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ViewAlarm provides a mock function for the type Service
 func (_mock *Service) ViewAlarm(ctx context.Context, session authn.Session, id string) (alarms.Alarm, error) {
 	ret := _mock.Called(ctx, session, id)
@@ -302,3 +681,59 @@ func (_c *Service_ViewAlarm_Call) RunAndReturn(run func(ctx context.Context, ses
 	_c.Call.Return(run)
 	return _c
 }
+
+// ViewSuppressionWindow provides a mock function for the type Service
+func (_mock *Service) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (alarms.SuppressionWindow, error) {
+	ret := _mock.Called(ctx, session, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ViewSuppressionWindow")
+	}
+
+	var r0 alarms.SuppressionWindow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, string) (alarms.SuppressionWindow, error)); ok {
+		return returnFunc(ctx, session, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authn.Session, string) alarms.SuppressionWindow); ok {
+		r0 = returnFunc(ctx, session, id)
+	} else {
+		r0 = ret.Get(0).(alarms.SuppressionWindow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authn.Session, string) error); ok {
+		r1 = returnFunc(ctx, session, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Service_ViewSuppressionWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ViewSuppressionWindow'
+type Service_ViewSuppressionWindow_Call struct {
+	*mock.Call
+}
+
+// ViewSuppressionWindow is a helper method to define mock.On call
+//   - ctx
+//   - session
+//   - id
+func (_e *Service_Expecter) ViewSuppressionWindow(ctx interface{}, session interface{}, id interface{}) *Service_ViewSuppressionWindow_Call {
+	return &Service_ViewSuppressionWindow_Call{Call: _e.mock.On("ViewSuppressionWindow", ctx, session, id)}
+}
+
+func (_c *Service_ViewSuppressionWindow_Call) Run(run func(ctx context.Context, session authn.Session, id string)) *Service_ViewSuppressionWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(authn.Session), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_ViewSuppressionWindow_Call) Return(suppressionWindow alarms.SuppressionWindow, err error) *Service_ViewSuppressionWindow_Call {
+	_c.Call.Return(suppressionWindow, err)
+	return _c
+}
+
+func (_c *Service_ViewSuppressionWindow_Call) RunAndReturn(run func(ctx context.Context, session authn.Session, id string) (alarms.SuppressionWindow, error)) *Service_ViewSuppressionWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -20,63 +20,179 @@ import (
 )
 
 type repository struct {
-	db *sqlx.DB
+	db   *sqlx.DB
+	flap alarms.FlapConfig
 }
 
 var _ alarms.Repository = (*repository)(nil)
 
-func NewAlarmsRepo(db *sqlx.DB) alarms.Repository {
-	return &repository{db: db}
+func NewAlarmsRepo(db *sqlx.DB, flap alarms.FlapConfig) alarms.Repository {
+	return &repository{db: db, flap: flap}
 }
 
-func (r *repository) CreateAlarm(ctx context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
-	query := `
-	WITH existing AS (
-		SELECT status, severity
-		FROM alarms
-		WHERE domain_id = :domain_id
-			AND rule_id = :rule_id
-			AND channel_id = :channel_id
-			AND client_id = :client_id
-			AND subtopic = :subtopic
-			AND measurement = :measurement
-			AND created_at <= :created_at
-		ORDER BY created_at DESC
-		LIMIT 1
-	)
-	INSERT INTO alarms (
+func (r *repository) CreateAlarm(ctx context.Context, alarm alarms.Alarm) (a alarms.Alarm, err error) {
+	dba, err := toDBAlarm(alarm)
+	if err != nil {
+		return alarms.Alarm{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return alarms.Alarm{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(repoerr.ErrCreateEntity, err)
+		}
+	}()
+
+	latest, err := latestAlarm(ctx, tx, dba)
+	switch {
+	case err == repoerr.ErrNotFound:
+		// There is nothing to clear.
+		if dba.Status != alarms.ActiveStatus {
+			return alarms.Alarm{}, repoerr.ErrNotFound
+		}
+		return insertAlarm(ctx, tx, dba)
+	case err != nil:
+		return alarms.Alarm{}, err
+	}
+
+	if latest.Flapping {
+		if dba.CreatedAt.Sub(latest.LastChangedAt.Time) < r.flap.Window {
+			return updateLatestAlarm(ctx, tx, latest, dba, true)
+		}
+		// The state has settled, so alarms are recorded as usual again.
+		if _, err := tx.ExecContext(ctx, `UPDATE alarms SET flapping = FALSE WHERE id = $1;`, latest.ID); err != nil {
+			return alarms.Alarm{}, postgres.HandleError(repoerr.ErrCreateEntity, err)
+		}
+	}
+
+	if latest.Status == dba.Status && (dba.Status != alarms.ActiveStatus || latest.Severity == dba.Severity) {
+		return updateLatestAlarm(ctx, tx, latest, dba, false)
+	}
+
+	flapping, err := r.flapping(ctx, tx, dba)
+	if err != nil {
+		return alarms.Alarm{}, err
+	}
+	if flapping {
+		return updateLatestAlarm(ctx, tx, latest, dba, true)
+	}
+
+	return insertAlarm(ctx, tx, dba)
+}
+
+// latestAlarm locks and returns the latest alarm raised by the same source.
+func latestAlarm(ctx context.Context, tx *sqlx.Tx, dba dbAlarm) (dbAlarm, error) {
+	q := `SELECT * FROM alarms
+	WHERE domain_id = :domain_id
+		AND rule_id = :rule_id
+		AND channel_id = :channel_id
+		AND client_id = :client_id
+		AND subtopic = :subtopic
+		AND measurement = :measurement
+		AND created_at <= :created_at
+	ORDER BY created_at DESC
+	LIMIT 1
+	FOR UPDATE;`
+
+	rows, err := sqlx.NamedQueryContext(ctx, tx, q, dba)
+	if err != nil {
+		return dbAlarm{}, postgres.HandleError(repoerr.ErrCreateEntity, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return dbAlarm{}, repoerr.ErrNotFound
+	}
+
+	var latest dbAlarm
+	if err := rows.StructScan(&latest); err != nil {
+		return dbAlarm{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	return latest, nil
+}
+
+// flapping reports whether saving the alarm would reach the flap threshold.
+func (r *repository) flapping(ctx context.Context, tx *sqlx.Tx, dba dbAlarm) (bool, error) {
+	if !r.flap.Enabled() {
+		return false, nil
+	}
+
+	q := `SELECT COUNT(*) FROM alarms
+	WHERE domain_id = $1
+		AND rule_id = $2
+		AND channel_id = $3
+		AND client_id = $4
+		AND subtopic = $5
+		AND measurement = $6
+		AND created_at > $7
+		AND created_at <= $8;`
+
+	var changes uint
+	if err := tx.QueryRowxContext(ctx, q, dba.DomainID, dba.RuleID, dba.ChannelID, dba.ClientID, dba.Subtopic,
+		dba.Measurement, dba.CreatedAt.Add(-r.flap.Window), dba.CreatedAt).Scan(&changes); err != nil {
+		return false, postgres.HandleError(repoerr.ErrCreateEntity, err)
+	}
+
+	return changes+1 >= r.flap.Threshold, nil
+}
+
+func insertAlarm(ctx context.Context, tx *sqlx.Tx, dba dbAlarm) (alarms.Alarm, error) {
+	q := `INSERT INTO alarms (
 		id, rule_id, domain_id, channel_id, client_id, subtopic, measurement,
 		value, unit, threshold, cause, status, severity, assignee_id,
 		created_at, updated_at, updated_by, assigned_at, assigned_by,
-		acknowledged_at, acknowledged_by, resolved_at, resolved_by, metadata
+		acknowledged_at, acknowledged_by, resolved_at, resolved_by, suppressed,
+		flapping, occurrences, last_seen_at, last_changed_at, metadata
 	)
-	SELECT
+	VALUES (
 		:id, :rule_id, :domain_id, :channel_id, :client_id, :subtopic, :measurement,
 		:value, :unit, :threshold, :cause, :status, :severity, :assignee_id,
 		:created_at, :updated_at, :updated_by, :assigned_at, :assigned_by,
-		:acknowledged_at, :acknowledged_by, :resolved_at, :resolved_by, :metadata
-	WHERE (
-		EXISTS (
-			SELECT 1 FROM existing
-			WHERE existing.status IS DISTINCT FROM :status
-			OR (:status = 0 AND existing.status = 0 AND existing.severity IS DISTINCT FROM :severity)
-		)
-		OR (
-			NOT EXISTS (SELECT 1 FROM existing) AND :status = 0
-		)
+		:acknowledged_at, :acknowledged_by, :resolved_at, :resolved_by, :suppressed,
+		:flapping, :occurrences, :last_seen_at, :last_changed_at, :metadata
 	)
-	RETURNING
-		id, rule_id, domain_id, channel_id, client_id, subtopic, measurement,
-		value, unit, threshold, cause, status, severity, created_at,
-		assignee_id, updated_at, updated_by, assigned_at, assigned_by,
-		acknowledged_at, acknowledged_by, resolved_at, resolved_by, metadata
-	;
-	`
-	dba, err := toDBAlarm(alarm)
-	if err != nil {
-		return alarms.Alarm{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	RETURNING *;`
+
+	return saveAlarm(ctx, tx, q, dba)
+}
+
+// updateLatestAlarm records the alarm on the latest alarm of the same source.
+// Flapping alarms also take over the state of the recorded alarm.
+func updateLatestAlarm(ctx context.Context, tx *sqlx.Tx, latest, dba dbAlarm, flapping bool) (alarms.Alarm, error) {
+	dba.ID = latest.ID
+	dba.Flapping = flapping
+	dba.LastChangedAt = latest.LastChangedAt
+	if latest.Status != dba.Status || latest.Severity != dba.Severity {
+		dba.LastChangedAt = sql.NullTime{Time: dba.CreatedAt, Valid: true}
+	}
+
+	q := `UPDATE alarms SET occurrences = occurrences + 1, last_seen_at = GREATEST(last_seen_at, :last_seen_at)
+	WHERE id = :id
+	RETURNING *;`
+	if flapping {
+		q = `UPDATE alarms SET status = :status, severity = :severity, value = :value, threshold = :threshold,
+			cause = :cause, flapping = :flapping, last_changed_at = :last_changed_at,
+			occurrences = occurrences + 1, last_seen_at = GREATEST(last_seen_at, :last_seen_at)
+		WHERE id = :id
+		RETURNING *;`
 	}
-	row, err := r.db.NamedQueryContext(ctx, query, dba)
+
+	return saveAlarm(ctx, tx, q, dba)
+}
+
+func saveAlarm(ctx context.Context, tx *sqlx.Tx, q string, dba dbAlarm) (alarms.Alarm, error) {
+	row, err := sqlx.NamedQueryContext(ctx, tx, q, dba)
 	if err != nil {
 		return alarms.Alarm{}, postgres.HandleError(repoerr.ErrCreateEntity, err)
 	}
@@ -129,7 +245,7 @@ func (r *repository) UpdateAlarm(ctx context.Context, alarm alarms.Alarm) (alarm
 	}
 
 	q := fmt.Sprintf(`UPDATE alarms SET %s updated_by = :updated_by, updated_at = :updated_at WHERE id = :id
-		RETURNING id, rule_id, measurement, value, unit, cause, status, domain_id, assignee_id, metadata, created_at, updated_by, updated_at, resolved_by, resolved_at;`, upq)
+		RETURNING *;`, upq)
 
 	dba, err := toDBAlarm(alarm)
 	if err != nil {
@@ -188,7 +304,8 @@ func (r *repository) ListAlarms(ctx context.Context, pm alarms.PageMetadata) (al
 
 	q := fmt.Sprintf(`SELECT id, rule_id, domain_id, channel_id, client_id, subtopic, measurement, value, unit,
 			threshold, cause, status, severity, assignee_id, created_at, updated_at, updated_by, assigned_at,
-			assigned_by, acknowledged_at, acknowledged_by, resolved_at, resolved_by, metadata
+			assigned_by, acknowledged_at, acknowledged_by, resolved_at, resolved_by, suppressed, flapping,
+			occurrences, last_seen_at, metadata
 			FROM alarms %s ORDER BY created_at DESC LIMIT :limit OFFSET :offset;`, query)
 
 	rows, err := r.db.NamedQueryContext(ctx, q, pm)
@@ -226,23 +343,24 @@ func (r *repository) ListAlarms(ctx context.Context, pm alarms.PageMetadata) (al
 	}, nil
 }
 
-func (r *repository) DeleteAlarm(ctx context.Context, id string) error {
-	query := `DELETE FROM alarms WHERE id = :id;`
-	result, err := r.db.NamedExecContext(ctx, query, map[string]interface{}{"id": id})
+func (r *repository) DeleteAlarm(ctx context.Context, id string) (alarms.Alarm, error) {
+	query := `DELETE FROM alarms WHERE id = :id RETURNING *;`
+	row, err := r.db.NamedQueryContext(ctx, query, map[string]interface{}{"id": id})
 	if err != nil {
-		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+		return alarms.Alarm{}, errors.Wrap(repoerr.ErrRemoveEntity, err)
 	}
+	defer row.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	if !row.Next() {
+		return alarms.Alarm{}, repoerr.ErrNotFound
 	}
 
-	if rowsAffected == 0 {
-		return repoerr.ErrNotFound
+	dba := dbAlarm{}
+	if err := row.StructScan(&dba); err != nil {
+		return alarms.Alarm{}, errors.Wrap(repoerr.ErrRemoveEntity, err)
 	}
 
-	return nil
+	return toAlarm(dba)
 }
 
 type dbAlarm struct {
@@ -269,6 +387,11 @@ type dbAlarm struct {
 	AcknowledgedBy *string       `db:"acknowledged_by,omitempty"`
 	ResolvedAt     sql.NullTime  `db:"resolved_at,omitempty"`
 	ResolvedBy     *string       `db:"resolved_by,omitempty"`
+	Suppressed     bool          `db:"suppressed"`
+	Flapping       bool          `db:"flapping"`
+	Occurrences    uint64        `db:"occurrences"`
+	LastSeenAt     sql.NullTime  `db:"last_seen_at,omitempty"`
+	LastChangedAt  sql.NullTime  `db:"last_changed_at,omitempty"`
 	Metadata       []byte        `db:"metadata,omitempty"`
 }
 
@@ -312,6 +435,15 @@ func toDBAlarm(a alarms.Alarm) (dbAlarm, error) {
 		assignedAt = sql.NullTime{Time: a.AssignedAt, Valid: true}
 	}
 
+	occurrences := a.Occurrences
+	if occurrences == 0 {
+		occurrences = 1
+	}
+	lastSeenAt := sql.NullTime{Time: a.CreatedAt, Valid: true}
+	if !a.LastSeenAt.IsZero() {
+		lastSeenAt.Time = a.LastSeenAt
+	}
+
 	metadata := []byte("{}")
 	if len(a.Metadata) > 0 {
 		b, err := json.Marshal(a.Metadata)
@@ -345,6 +477,11 @@ func toDBAlarm(a alarms.Alarm) (dbAlarm, error) {
 		AcknowledgedBy: acknowledgedBy,
 		ResolvedAt:     resolvedAt,
 		ResolvedBy:     resolvedBy,
+		Suppressed:     a.Suppressed,
+		Flapping:       a.Flapping,
+		Occurrences:    occurrences,
+		LastSeenAt:     lastSeenAt,
+		LastChangedAt:  sql.NullTime{Time: a.CreatedAt, Valid: true},
 		Metadata:       metadata,
 	}, nil
 }
@@ -386,6 +523,11 @@ func toAlarm(dbr dbAlarm) (alarms.Alarm, error) {
 		resolvedAt = dbr.ResolvedAt.Time
 	}
 
+	var lastSeenAt time.Time
+	if dbr.LastSeenAt.Valid {
+		lastSeenAt = dbr.LastSeenAt.Time
+	}
+
 	var metadata map[string]interface{}
 	if len(dbr.Metadata) > 0 {
 		err := json.Unmarshal(dbr.Metadata, &metadata)
@@ -418,6 +560,10 @@ func toAlarm(dbr dbAlarm) (alarms.Alarm, error) {
 		AcknowledgedBy: acknowledgedBy,
 		ResolvedAt:     resolvedAt,
 		ResolvedBy:     resolvedBy,
+		Suppressed:     dbr.Suppressed,
+		Flapping:       dbr.Flapping,
+		Occurrences:    dbr.Occurrences,
+		LastSeenAt:     lastSeenAt,
 		Metadata:       metadata,
 	}, nil
 }
@@ -463,6 +609,12 @@ func pageQuery(pm alarms.PageMetadata) (string, error) {
 	if pm.AssignedBy != "" {
 		query = append(query, "assigned_by = :assigned_by")
 	}
+	if pm.Suppressed != nil {
+		query = append(query, "suppressed = :suppressed")
+	}
+	if pm.Flapping != nil {
+		query = append(query, "flapping = :flapping")
+	}
 	if !pm.CreatedFrom.IsZero() {
 		query = append(query, "created_at >= :created_from")
 	}
@@ -31,7 +31,7 @@ func TestCreateAlarm(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
 	})
 
-	repo := postgres.NewAlarmsRepo(db)
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
 
 	alarm := alarms.Alarm{
 		ID:          generateUUID(&testing.T{}),
@@ -66,7 +66,7 @@ func TestCreateAlarm(t *testing.T) {
 		{
 			desc:  "duplicate alarm",
 			alarm: alarm,
-			err:   repoerr.ErrNotFound,
+			err:   nil,
 		},
 		{
 			desc: "missing rule id",
@@ -150,7 +150,7 @@ func TestUpdateAlarm(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
 	})
 
-	repo := postgres.NewAlarmsRepo(db)
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
 
 	alarm := alarms.Alarm{
 		ID:          generateUUID(&testing.T{}),
@@ -249,7 +249,7 @@ func TestViewAlarm(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
 	})
 
-	repo := postgres.NewAlarmsRepo(db)
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
 
 	alarm := alarms.Alarm{
 		ID:          generateUUID(&testing.T{}),
@@ -318,7 +318,7 @@ func TestListAlarms(t *testing.T) {
 		_, err := db.Exec("DELETE FROM alarms")
 		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
 	})
-	repo := postgres.NewAlarmsRepo(db)
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
 	items := make([]alarms.Alarm, 1000)
 	for i := range 1000 {
 		items[i] = alarms.Alarm{
@@ -414,7 +414,7 @@ func TestDeleteAlarm(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
 	})
 
-	repo := postgres.NewAlarmsRepo(db)
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
 
 	alarm := alarms.Alarm{
 		ID:          generateUUID(&testing.T{}),
@@ -456,13 +456,14 @@ func TestDeleteAlarm(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := repo.DeleteAlarm(context.Background(), tc.id)
+			deleted, err := repo.DeleteAlarm(context.Background(), tc.id)
 			if tc.err != nil {
 				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 				return
 			}
 			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.id, deleted.ID, fmt.Sprintf("%s: expected deleted alarm %s got %s\n", tc.desc, tc.id, deleted.ID))
 		})
 	}
 }
@@ -472,3 +473,114 @@ func generateUUID(t *testing.T) string {
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	return ulid
 }
+
+func TestCreateAlarmOccurrences(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarms")
+		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{Threshold: 4, Window: time.Minute})
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	alarm := alarms.Alarm{
+		RuleID:      generateUUID(&testing.T{}),
+		DomainID:    generateUUID(&testing.T{}),
+		ChannelID:   generateUUID(&testing.T{}),
+		ClientID:    generateUUID(&testing.T{}),
+		Subtopic:    namegen.Generate(),
+		Measurement: namegen.Generate(),
+		Value:       namegen.Generate(),
+		Cause:       namegen.Generate(),
+	}
+
+	cases := []struct {
+		desc        string
+		status      alarms.Status
+		after       time.Duration
+		created     bool
+		flapping    bool
+		occurrences uint64
+		err         error
+	}{
+		{
+			desc:   "cleared alarm without active alarm",
+			status: alarms.ClearedStatus,
+			err:    repoerr.ErrNotFound,
+		},
+		{
+			desc:        "active alarm",
+			status:      alarms.ActiveStatus,
+			created:     true,
+			occurrences: 1,
+		},
+		{
+			desc:        "repeated active alarm",
+			status:      alarms.ActiveStatus,
+			after:       time.Second,
+			occurrences: 2,
+		},
+		{
+			desc:        "cleared alarm",
+			status:      alarms.ClearedStatus,
+			after:       2 * time.Second,
+			created:     true,
+			occurrences: 1,
+		},
+		{
+			desc:        "active alarm after clear",
+			status:      alarms.ActiveStatus,
+			after:       3 * time.Second,
+			created:     true,
+			occurrences: 1,
+		},
+		{
+			desc:        "cleared alarm reaching flap threshold",
+			status:      alarms.ClearedStatus,
+			after:       4 * time.Second,
+			flapping:    true,
+			occurrences: 2,
+		},
+		{
+			desc:        "active alarm while flapping",
+			status:      alarms.ActiveStatus,
+			after:       5 * time.Second,
+			flapping:    true,
+			occurrences: 3,
+		},
+		{
+			desc:        "repeated active alarm after flapping settled",
+			status:      alarms.ActiveStatus,
+			after:       5*time.Second + 2*time.Minute,
+			occurrences: 4,
+		},
+	}
+
+	var latestID string
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			a := alarm
+			a.ID = generateUUID(&testing.T{})
+			a.Status = tc.status
+			a.CreatedAt = now.Add(tc.after)
+			saved, err := repo.CreateAlarm(context.Background(), a)
+			if tc.err != nil {
+				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+				return
+			}
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			switch tc.created {
+			case true:
+				assert.Equal(t, a.ID, saved.ID, fmt.Sprintf("%s: expected new alarm to be saved\n", tc.desc))
+				latestID = saved.ID
+			default:
+				assert.Equal(t, latestID, saved.ID, fmt.Sprintf("%s: expected latest alarm to be updated\n", tc.desc))
+			}
+			assert.Equal(t, tc.status, saved.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, saved.Status))
+			assert.Equal(t, tc.flapping, saved.Flapping, fmt.Sprintf("%s: expected flapping %t got %t\n", tc.desc, tc.flapping, saved.Flapping))
+			assert.Equal(t, tc.occurrences, saved.Occurrences, fmt.Sprintf("%s: expected occurrences %d got %d\n", tc.desc, tc.occurrences, saved.Occurrences))
+			assert.True(t, a.CreatedAt.Equal(saved.LastSeenAt), fmt.Sprintf("%s: expected last seen %s got %s\n", tc.desc, a.CreatedAt, saved.LastSeenAt))
+		})
+	}
+}
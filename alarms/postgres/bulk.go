@@ -0,0 +1,180 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/jackc/pgtype"
+	"github.com/jmoiron/sqlx"
+)
+
+var errTransRollback = errors.New("failed to rollback transaction")
+
+type bulkTarget struct {
+	ID             string       `db:"id"`
+	AcknowledgedAt sql.NullTime `db:"acknowledged_at"`
+	ResolvedAt     sql.NullTime `db:"resolved_at"`
+}
+
+func (r *repository) ApplyBulkOperation(ctx context.Context, op alarms.BulkOperation) (res alarms.BulkResult, changed []alarms.Alarm, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return alarms.BulkResult{}, nil, errors.Wrap(repoerr.ErrUpdateEntity, err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(repoerr.ErrUpdateEntity, err)
+		}
+	}()
+
+	targets, err := lockBulkTargets(ctx, tx, op)
+	if err != nil {
+		return alarms.BulkResult{}, nil, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	// Report alarms in the order they were requested.
+	order := op.IDs
+	if op.Filter != nil {
+		order = make([]string, 0, len(targets))
+		for _, t := range targets {
+			order = append(order, t.ID)
+		}
+	}
+	found := make(map[string]bulkTarget, len(targets))
+	for _, t := range targets {
+		found[t.ID] = t
+	}
+
+	outcomes := make(map[string]error, len(order))
+	var ids []string
+	for _, id := range order {
+		t, ok := found[id]
+		switch {
+		case !ok:
+			outcomes[id] = alarms.ErrAlarmNotFound
+		case op.Action == alarms.AcknowledgeAction && t.AcknowledgedAt.Valid:
+			outcomes[id] = alarms.ErrAlarmAlreadyAcknowledged
+		case op.Action == alarms.ResolveAction && t.ResolvedAt.Valid:
+			outcomes[id] = alarms.ErrAlarmAlreadyResolved
+		default:
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > 0 {
+		if changed, err = applyBulkAction(ctx, tx, op, ids); err != nil {
+			return alarms.BulkResult{}, nil, err
+		}
+	}
+
+	res = alarms.BulkResult{Results: []alarms.BulkItemResult{}}
+	for _, id := range order {
+		res.Add(id, outcomes[id])
+	}
+
+	return res, changed, nil
+}
+
+// lockBulkTargets selects and locks the alarms targeted by the operation.
+func lockBulkTargets(ctx context.Context, tx *sqlx.Tx, op alarms.BulkOperation) ([]bulkTarget, error) {
+	var rows *sqlx.Rows
+	var err error
+	switch op.Filter {
+	case nil:
+		var ids pgtype.TextArray
+		if err := ids.Set(op.IDs); err != nil {
+			return nil, err
+		}
+		q := `SELECT id, acknowledged_at, resolved_at FROM alarms WHERE domain_id = $1 AND id = ANY($2) FOR UPDATE;`
+		rows, err = tx.QueryxContext(ctx, q, op.DomainID, ids)
+	default:
+		query, qErr := pageQuery(*op.Filter)
+		if qErr != nil {
+			return nil, qErr
+		}
+		q := fmt.Sprintf(`SELECT id, acknowledged_at, resolved_at FROM alarms %s
+			ORDER BY created_at DESC LIMIT :limit OFFSET :offset FOR UPDATE;`, query)
+		rows, err = tx.NamedQuery(q, *op.Filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []bulkTarget
+	for rows.Next() {
+		var t bulkTarget
+		if err := rows.StructScan(&t); err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, rows.Err()
+}
+
+// applyBulkAction applies the action to the alarms and returns the alarms
+// as they were updated or deleted.
+func applyBulkAction(ctx context.Context, tx *sqlx.Tx, op alarms.BulkOperation, ids []string) ([]alarms.Alarm, error) {
+	var arr pgtype.TextArray
+	if err := arr.Set(ids); err != nil {
+		return nil, errors.Wrap(repoerr.ErrUpdateEntity, err)
+	}
+
+	var q string
+	args := []interface{}{arr, op.UserID, op.Time}
+	repoErr := repoerr.ErrUpdateEntity
+	switch op.Action {
+	case alarms.AcknowledgeAction:
+		q = `UPDATE alarms SET acknowledged_by = $2, acknowledged_at = $3, updated_by = $2, updated_at = $3 WHERE id = ANY($1) RETURNING *;`
+	case alarms.AssignAction:
+		q = `UPDATE alarms SET assignee_id = $4, assigned_by = $2, assigned_at = $3, updated_by = $2, updated_at = $3 WHERE id = ANY($1) RETURNING *;`
+		args = append(args, op.AssigneeID)
+	case alarms.ResolveAction:
+		q = `UPDATE alarms SET resolved_by = $2, resolved_at = $3, updated_by = $2, updated_at = $3 WHERE id = ANY($1) RETURNING *;`
+	case alarms.DeleteAction:
+		q = `DELETE FROM alarms WHERE id = ANY($1) RETURNING *;`
+		args = args[:1]
+		repoErr = repoerr.ErrRemoveEntity
+	default:
+		return nil, alarms.ErrInvalidBulkAction
+	}
+
+	rows, err := tx.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrap(repoErr, err)
+	}
+	defer rows.Close()
+
+	var changed []alarms.Alarm
+	for rows.Next() {
+		dba := dbAlarm{}
+		if err := rows.StructScan(&dba); err != nil {
+			return nil, errors.Wrap(repoErr, err)
+		}
+		a, err := toAlarm(dba)
+		if err != nil {
+			return nil, errors.Wrap(repoErr, err)
+		}
+		changed = append(changed, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(repoErr, err)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/absmach/magistrala/alarms/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBulkOperation(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarms")
+		require.Nil(t, err, fmt.Sprintf("clean alarms unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	domainID := generateUUID(&testing.T{})
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	ids := make([]string, 5)
+	for i := range ids {
+		a, err := repo.CreateAlarm(context.Background(), alarms.Alarm{
+			ID:          generateUUID(&testing.T{}),
+			RuleID:      generateUUID(&testing.T{}),
+			DomainID:    domainID,
+			ChannelID:   generateUUID(&testing.T{}),
+			ClientID:    generateUUID(&testing.T{}),
+			Measurement: namegen.Generate(),
+			Value:       namegen.Generate(),
+			Cause:       namegen.Generate(),
+			Status:      alarms.ActiveStatus,
+			Severity:    uint8(i),
+			CreatedAt:   now.Add(time.Duration(i) * time.Second),
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ids[i] = a.ID
+	}
+	missing := generateUUID(&testing.T{})
+	userID := generateUUID(&testing.T{})
+	assigneeID := generateUUID(&testing.T{})
+
+	cases := []struct {
+		desc     string
+		domainID string
+		op       alarms.BulkOperation
+		res      alarms.BulkResult
+	}{
+		{
+			desc: "acknowledge alarms",
+			op: alarms.BulkOperation{
+				Action: alarms.AcknowledgeAction,
+				IDs:    []string{ids[0], ids[1], missing},
+			},
+			res: alarms.BulkResult{
+				Total:     3,
+				Succeeded: 2,
+				Failed:    1,
+				Results: []alarms.BulkItemResult{
+					{ID: ids[0]},
+					{ID: ids[1]},
+					{ID: missing, Error: alarms.ErrAlarmNotFound.Error()},
+				},
+			},
+		},
+		{
+			desc: "acknowledge already acknowledged alarms",
+			op: alarms.BulkOperation{
+				Action: alarms.AcknowledgeAction,
+				IDs:    []string{ids[1], ids[2]},
+			},
+			res: alarms.BulkResult{
+				Total:     2,
+				Succeeded: 1,
+				Failed:    1,
+				Results: []alarms.BulkItemResult{
+					{ID: ids[1], Error: alarms.ErrAlarmAlreadyAcknowledged.Error()},
+					{ID: ids[2]},
+				},
+			},
+		},
+		{
+			desc: "assign alarms",
+			op: alarms.BulkOperation{
+				Action:     alarms.AssignAction,
+				IDs:        []string{ids[3]},
+				AssigneeID: assigneeID,
+			},
+			res: alarms.BulkResult{
+				Total:     1,
+				Succeeded: 1,
+				Results:   []alarms.BulkItemResult{{ID: ids[3]}},
+			},
+		},
+		{
+			desc: "resolve alarms by filter",
+			op: alarms.BulkOperation{
+				Action: alarms.ResolveAction,
+				Filter: &alarms.PageMetadata{
+					DomainID:   domainID,
+					AssigneeID: assigneeID,
+					Status:     alarms.AllStatus,
+					Severity:   math.MaxUint8,
+					Limit:      alarms.MaxBulkSize,
+				},
+			},
+			res: alarms.BulkResult{
+				Total:     1,
+				Succeeded: 1,
+				Results:   []alarms.BulkItemResult{{ID: ids[3]}},
+			},
+		},
+		{
+			desc: "resolve already resolved alarms",
+			op: alarms.BulkOperation{
+				Action: alarms.ResolveAction,
+				IDs:    []string{ids[3]},
+			},
+			res: alarms.BulkResult{
+				Total:   1,
+				Failed:  1,
+				Results: []alarms.BulkItemResult{{ID: ids[3], Error: alarms.ErrAlarmAlreadyResolved.Error()}},
+			},
+		},
+		{
+			desc: "delete alarms",
+			op: alarms.BulkOperation{
+				Action: alarms.DeleteAction,
+				IDs:    []string{ids[4]},
+			},
+			res: alarms.BulkResult{
+				Total:     1,
+				Succeeded: 1,
+				Results:   []alarms.BulkItemResult{{ID: ids[4]}},
+			},
+		},
+		{
+			desc:     "alarms from another domain",
+			domainID: generateUUID(&testing.T{}),
+			op: alarms.BulkOperation{
+				Action: alarms.DeleteAction,
+				IDs:    []string{ids[0]},
+			},
+			res: alarms.BulkResult{
+				Total:   1,
+				Failed:  1,
+				Results: []alarms.BulkItemResult{{ID: ids[0], Error: alarms.ErrAlarmNotFound.Error()}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.op.DomainID = domainID
+			if tc.domainID != "" {
+				tc.op.DomainID = tc.domainID
+			}
+			tc.op.UserID = userID
+			tc.op.Time = now
+			res, changed, err := repo.ApplyBulkOperation(context.Background(), tc.op)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+
+			var succeeded, changedIDs []string
+			for _, r := range res.Results {
+				if r.Error == "" {
+					succeeded = append(succeeded, r.ID)
+				}
+			}
+			for _, a := range changed {
+				changedIDs = append(changedIDs, a.ID)
+			}
+			assert.ElementsMatch(t, succeeded, changedIDs, fmt.Sprintf("%s: expected changed alarms %v got %v\n", tc.desc, succeeded, changedIDs))
+		})
+	}
+
+	alarm, err := repo.ViewAlarm(context.Background(), ids[3], domainID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, assigneeID, alarm.AssigneeID)
+	assert.Equal(t, userID, alarm.ResolvedBy)
+
+	_, err = repo.ViewAlarm(context.Background(), ids[4], domainID)
+	assert.NotNil(t, err, "expected deleted alarm to be missing")
+}
@@ -48,6 +48,51 @@ func Migration() *migrate.MemoryMigrationSource {
 					`DROP TABLE IF EXISTS alarms`,
 				},
 			},
+			{
+				Id: "alarms_02",
+				Up: []string{
+					`ALTER TABLE alarms ADD COLUMN IF NOT EXISTS suppressed BOOLEAN NOT NULL DEFAULT FALSE;`,
+					`CREATE TABLE IF NOT EXISTS alarm_suppressions (
+						id         	     VARCHAR(36) PRIMARY KEY,
+						name		     VARCHAR(1024) NOT NULL,
+						description	     TEXT,
+						domain_id	     VARCHAR(36) NOT NULL,
+						rule_id		     VARCHAR(36) NOT NULL DEFAULT '',
+						channel_id	     VARCHAR(36) NOT NULL DEFAULT '',
+						client_id	     VARCHAR(36) NOT NULL DEFAULT '',
+						subtopic         TEXT NOT NULL DEFAULT '',
+						starts_at	     TIMESTAMPTZ NOT NULL,
+						ends_at	         TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+						recurring        SMALLINT NOT NULL DEFAULT 0 CHECK (recurring >= 0),
+						recurring_period SMALLINT NOT NULL DEFAULT 0 CHECK (recurring_period >= 0),
+						created_at	     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						created_by	     VARCHAR(36),
+						updated_at	     TIMESTAMPTZ NULL,
+						updated_by	     VARCHAR(36) NULL
+					);`,
+					"CREATE INDEX IF NOT EXISTS idx_alarm_suppressions_domain ON alarm_suppressions (domain_id, starts_at);",
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS alarm_suppressions`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS suppressed`,
+				},
+			},
+			{
+				Id: "alarms_03",
+				Up: []string{
+					`ALTER TABLE alarms ADD COLUMN IF NOT EXISTS flapping BOOLEAN NOT NULL DEFAULT FALSE;`,
+					`ALTER TABLE alarms ADD COLUMN IF NOT EXISTS occurrences BIGINT NOT NULL DEFAULT 1 CHECK (occurrences > 0);`,
+					`ALTER TABLE alarms ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;`,
+					`ALTER TABLE alarms ADD COLUMN IF NOT EXISTS last_changed_at TIMESTAMPTZ;`,
+					`UPDATE alarms SET last_seen_at = created_at, last_changed_at = created_at WHERE last_seen_at IS NULL;`,
+				},
+				Down: []string{
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS flapping`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS occurrences`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS last_seen_at`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS last_changed_at`,
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,295 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/absmach/supermq/pkg/postgres"
+)
+
+const suppressionColumns = `id, name, description, domain_id, rule_id, channel_id, client_id, subtopic, starts_at, ends_at,
+	recurring, recurring_period, created_at, created_by, updated_at, updated_by`
+
+func (r *repository) CreateSuppressionWindow(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	q := fmt.Sprintf(`INSERT INTO alarm_suppressions (%[1]s)
+	VALUES (:id, :name, :description, :domain_id, :rule_id, :channel_id, :client_id, :subtopic, :starts_at, :ends_at,
+		:recurring, :recurring_period, :created_at, :created_by, :updated_at, :updated_by)
+	RETURNING %[1]s;`, suppressionColumns)
+
+	row, err := r.db.NamedQueryContext(ctx, q, toDBSuppression(w))
+	if err != nil {
+		return alarms.SuppressionWindow{}, postgres.HandleError(repoerr.ErrCreateEntity, err)
+	}
+	defer row.Close()
+
+	if !row.Next() {
+		return alarms.SuppressionWindow{}, repoerr.ErrCreateEntity
+	}
+
+	var dbs dbSuppression
+	if err := row.StructScan(&dbs); err != nil {
+		return alarms.SuppressionWindow{}, errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	return toSuppression(dbs), nil
+}
+
+func (r *repository) ViewSuppressionWindow(ctx context.Context, id, domainID string) (alarms.SuppressionWindow, error) {
+	q := fmt.Sprintf(`SELECT %s FROM alarm_suppressions WHERE id = :id AND domain_id = :domain_id;`, suppressionColumns)
+
+	row, err := r.db.NamedQueryContext(ctx, q, map[string]interface{}{
+		"id": id, "domain_id": domainID,
+	})
+	if err != nil {
+		return alarms.SuppressionWindow{}, postgres.HandleError(repoerr.ErrViewEntity, err)
+	}
+	defer row.Close()
+
+	if !row.Next() {
+		return alarms.SuppressionWindow{}, repoerr.ErrNotFound
+	}
+
+	var dbs dbSuppression
+	if err := row.StructScan(&dbs); err != nil {
+		return alarms.SuppressionWindow{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return toSuppression(dbs), nil
+}
+
+func (r *repository) UpdateSuppressionWindow(ctx context.Context, w alarms.SuppressionWindow) (alarms.SuppressionWindow, error) {
+	q := fmt.Sprintf(`UPDATE alarm_suppressions SET name = :name, description = :description, rule_id = :rule_id,
+		channel_id = :channel_id, client_id = :client_id, subtopic = :subtopic, starts_at = :starts_at, ends_at = :ends_at,
+		recurring = :recurring, recurring_period = :recurring_period, updated_at = :updated_at, updated_by = :updated_by
+	WHERE id = :id AND domain_id = :domain_id
+	RETURNING %s;`, suppressionColumns)
+
+	row, err := r.db.NamedQueryContext(ctx, q, toDBSuppression(w))
+	if err != nil {
+		return alarms.SuppressionWindow{}, postgres.HandleError(repoerr.ErrUpdateEntity, err)
+	}
+	defer row.Close()
+
+	if !row.Next() {
+		return alarms.SuppressionWindow{}, repoerr.ErrNotFound
+	}
+
+	var dbs dbSuppression
+	if err := row.StructScan(&dbs); err != nil {
+		return alarms.SuppressionWindow{}, errors.Wrap(repoerr.ErrUpdateEntity, err)
+	}
+
+	return toSuppression(dbs), nil
+}
+
+func (r *repository) ListSuppressionWindows(ctx context.Context, pm alarms.SuppressionPageMetadata) (alarms.SuppressionWindowsPage, error) {
+	query := suppressionPageQuery(pm)
+
+	q := fmt.Sprintf(`SELECT %s FROM alarm_suppressions %s ORDER BY starts_at DESC LIMIT :limit OFFSET :offset;`, suppressionColumns, query)
+
+	rows, err := r.db.NamedQueryContext(ctx, q, pm)
+	if err != nil {
+		return alarms.SuppressionWindowsPage{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []alarms.SuppressionWindow{}
+	for rows.Next() {
+		var dbs dbSuppression
+		if err := rows.StructScan(&dbs); err != nil {
+			return alarms.SuppressionWindowsPage{}, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		items = append(items, toSuppression(dbs))
+	}
+
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM alarm_suppressions %s;`, query)
+	total, err := postgres.Total(ctx, r.db, q, pm)
+	if err != nil {
+		return alarms.SuppressionWindowsPage{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return alarms.SuppressionWindowsPage{
+		Total:   total,
+		Offset:  pm.Offset,
+		Limit:   pm.Limit,
+		Windows: items,
+	}, nil
+}
+
+func (r *repository) DeleteSuppressionWindow(ctx context.Context, id, domainID string) error {
+	q := `DELETE FROM alarm_suppressions WHERE id = :id AND domain_id = :domain_id;`
+	result, err := r.db.NamedExecContext(ctx, q, map[string]interface{}{"id": id, "domain_id": domainID})
+	if err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+
+	if rowsAffected == 0 {
+		return repoerr.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) RetrieveSuppressionWindows(ctx context.Context, alarm alarms.Alarm) ([]alarms.SuppressionWindow, error) {
+	// Recurring windows keep their first occurrence in starts_at and ends_at,
+	// so only one-off windows can be ruled out by their end time here.
+	q := fmt.Sprintf(`SELECT %s FROM alarm_suppressions
+	WHERE domain_id = :domain_id
+		AND (rule_id = '' OR rule_id = :rule_id)
+		AND (channel_id = '' OR channel_id = :channel_id)
+		AND (client_id = '' OR client_id = :client_id)
+		AND (subtopic = '' OR subtopic = :subtopic)
+		AND starts_at <= :created_at
+		AND (recurring <> 0 OR ends_at > :created_at);`, suppressionColumns)
+
+	rows, err := r.db.NamedQueryContext(ctx, q, map[string]interface{}{
+		"domain_id":  alarm.DomainID,
+		"rule_id":    alarm.RuleID,
+		"channel_id": alarm.ChannelID,
+		"client_id":  alarm.ClientID,
+		"subtopic":   alarm.Subtopic,
+		"created_at": alarm.CreatedAt,
+	})
+	if err != nil {
+		return nil, postgres.HandleError(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var windows []alarms.SuppressionWindow
+	for rows.Next() {
+		var dbs dbSuppression
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		windows = append(windows, toSuppression(dbs))
+	}
+
+	return windows, nil
+}
+
+type dbSuppression struct {
+	ID              string           `db:"id"`
+	Name            string           `db:"name"`
+	Description     sql.NullString   `db:"description"`
+	DomainID        string           `db:"domain_id"`
+	RuleID          string           `db:"rule_id"`
+	ChannelID       string           `db:"channel_id"`
+	ClientID        string           `db:"client_id"`
+	Subtopic        string           `db:"subtopic"`
+	StartsAt        time.Time        `db:"starts_at"`
+	EndsAt          time.Time        `db:"ends_at"`
+	Recurring       alarms.Recurring `db:"recurring"`
+	RecurringPeriod uint             `db:"recurring_period"`
+	CreatedAt       time.Time        `db:"created_at"`
+	CreatedBy       *string          `db:"created_by,omitempty"`
+	UpdatedAt       sql.NullTime     `db:"updated_at,omitempty"`
+	UpdatedBy       *string          `db:"updated_by,omitempty"`
+}
+
+func toDBSuppression(w alarms.SuppressionWindow) dbSuppression {
+	var createdBy *string
+	if w.CreatedBy != "" {
+		createdBy = &w.CreatedBy
+	}
+	var updatedBy *string
+	if w.UpdatedBy != "" {
+		updatedBy = &w.UpdatedBy
+	}
+	var updatedAt sql.NullTime
+	if !w.UpdatedAt.IsZero() {
+		updatedAt = sql.NullTime{Time: w.UpdatedAt, Valid: true}
+	}
+
+	return dbSuppression{
+		ID:              w.ID,
+		Name:            w.Name,
+		Description:     sql.NullString{String: w.Description, Valid: w.Description != ""},
+		DomainID:        w.DomainID,
+		RuleID:          w.RuleID,
+		ChannelID:       w.ChannelID,
+		ClientID:        w.ClientID,
+		Subtopic:        w.Subtopic,
+		StartsAt:        w.StartsAt,
+		EndsAt:          w.EndsAt,
+		Recurring:       w.Recurring,
+		RecurringPeriod: w.RecurringPeriod,
+		CreatedAt:       w.CreatedAt,
+		CreatedBy:       createdBy,
+		UpdatedAt:       updatedAt,
+		UpdatedBy:       updatedBy,
+	}
+}
+
+func toSuppression(dbs dbSuppression) alarms.SuppressionWindow {
+	var createdBy string
+	if dbs.CreatedBy != nil {
+		createdBy = *dbs.CreatedBy
+	}
+	var updatedBy string
+	if dbs.UpdatedBy != nil {
+		updatedBy = *dbs.UpdatedBy
+	}
+	var updatedAt time.Time
+	if dbs.UpdatedAt.Valid {
+		updatedAt = dbs.UpdatedAt.Time
+	}
+
+	return alarms.SuppressionWindow{
+		ID:              dbs.ID,
+		Name:            dbs.Name,
+		Description:     dbs.Description.String,
+		DomainID:        dbs.DomainID,
+		RuleID:          dbs.RuleID,
+		ChannelID:       dbs.ChannelID,
+		ClientID:        dbs.ClientID,
+		Subtopic:        dbs.Subtopic,
+		StartsAt:        dbs.StartsAt,
+		EndsAt:          dbs.EndsAt,
+		Recurring:       dbs.Recurring,
+		RecurringPeriod: dbs.RecurringPeriod,
+		CreatedAt:       dbs.CreatedAt,
+		CreatedBy:       createdBy,
+		UpdatedAt:       updatedAt,
+		UpdatedBy:       updatedBy,
+	}
+}
+
+func suppressionPageQuery(pm alarms.SuppressionPageMetadata) string {
+	var query []string
+	if pm.DomainID != "" {
+		query = append(query, "domain_id = :domain_id")
+	}
+	if pm.RuleID != "" {
+		query = append(query, "rule_id = :rule_id")
+	}
+	if pm.ChannelID != "" {
+		query = append(query, "channel_id = :channel_id")
+	}
+	if pm.ClientID != "" {
+		query = append(query, "client_id = :client_id")
+	}
+	if pm.Subtopic != "" {
+		query = append(query, "subtopic = :subtopic")
+	}
+
+	var emq string
+	if len(query) > 0 {
+		emq = fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+	}
+
+	return emq
+}
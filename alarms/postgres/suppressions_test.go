@@ -0,0 +1,315 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/absmach/magistrala/alarms/postgres"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSuppressionWindow(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarm_suppressions")
+		require.Nil(t, err, fmt.Sprintf("clean suppressions unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	window := alarms.SuppressionWindow{
+		ID:        generateUUID(&testing.T{}),
+		Name:      namegen.Generate(),
+		DomainID:  generateUUID(&testing.T{}),
+		ChannelID: generateUUID(&testing.T{}),
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		Recurring: alarms.Weekly,
+		CreatedAt: now,
+		CreatedBy: generateUUID(&testing.T{}),
+	}
+
+	cases := []struct {
+		desc   string
+		window alarms.SuppressionWindow
+		err    error
+	}{
+		{
+			desc:   "valid window",
+			window: window,
+			err:    nil,
+		},
+		{
+			desc:   "duplicate window",
+			window: window,
+			err:    repoerr.ErrConflict,
+		},
+		{
+			desc: "window ending before it starts",
+			window: alarms.SuppressionWindow{
+				ID:        generateUUID(&testing.T{}),
+				Name:      namegen.Generate(),
+				DomainID:  generateUUID(&testing.T{}),
+				StartsAt:  now,
+				EndsAt:    now.Add(-time.Hour),
+				CreatedAt: now,
+			},
+			err: repoerr.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			w, err := repo.CreateSuppressionWindow(context.Background(), tc.window)
+			if tc.err != nil {
+				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+				return
+			}
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.window.ID, w.ID)
+			assert.Equal(t, tc.window.ChannelID, w.ChannelID)
+			assert.Equal(t, tc.window.Recurring, w.Recurring)
+			assert.True(t, tc.window.StartsAt.Equal(w.StartsAt))
+		})
+	}
+}
+
+func TestUpdateSuppressionWindow(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarm_suppressions")
+		require.Nil(t, err, fmt.Sprintf("clean suppressions unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	now := time.Now().UTC()
+
+	window, err := repo.CreateSuppressionWindow(context.Background(), alarms.SuppressionWindow{
+		ID:        generateUUID(&testing.T{}),
+		Name:      namegen.Generate(),
+		DomainID:  generateUUID(&testing.T{}),
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedAt: now,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		window alarms.SuppressionWindow
+		err    error
+	}{
+		{
+			desc: "valid window",
+			window: alarms.SuppressionWindow{
+				ID:        window.ID,
+				DomainID:  window.DomainID,
+				Name:      namegen.Generate(),
+				ClientID:  generateUUID(&testing.T{}),
+				StartsAt:  now,
+				EndsAt:    now.Add(2 * time.Hour),
+				Recurring: alarms.Daily,
+				UpdatedAt: now,
+				UpdatedBy: generateUUID(&testing.T{}),
+			},
+			err: nil,
+		},
+		{
+			desc: "window from another domain",
+			window: alarms.SuppressionWindow{
+				ID:        window.ID,
+				DomainID:  generateUUID(&testing.T{}),
+				Name:      namegen.Generate(),
+				StartsAt:  now,
+				EndsAt:    now.Add(time.Hour),
+				UpdatedAt: now,
+			},
+			err: repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			w, err := repo.UpdateSuppressionWindow(context.Background(), tc.window)
+			if tc.err != nil {
+				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+				return
+			}
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.window.Name, w.Name)
+			assert.Equal(t, tc.window.ClientID, w.ClientID)
+			assert.Equal(t, tc.window.Recurring, w.Recurring)
+			assert.Equal(t, tc.window.UpdatedBy, w.UpdatedBy)
+		})
+	}
+}
+
+func TestListSuppressionWindows(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarm_suppressions")
+		require.Nil(t, err, fmt.Sprintf("clean suppressions unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	now := time.Now().UTC()
+	domainID := generateUUID(&testing.T{})
+	channelID := generateUUID(&testing.T{})
+
+	for i := range 20 {
+		w := alarms.SuppressionWindow{
+			ID:        generateUUID(&testing.T{}),
+			Name:      namegen.Generate(),
+			DomainID:  domainID,
+			StartsAt:  now.Add(time.Duration(i) * time.Hour),
+			EndsAt:    now.Add(time.Duration(i+1) * time.Hour),
+			CreatedAt: now,
+		}
+		if i%2 == 0 {
+			w.ChannelID = channelID
+		}
+		_, err := repo.CreateSuppressionWindow(context.Background(), w)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		pm    alarms.SuppressionPageMetadata
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "domain windows",
+			pm:    alarms.SuppressionPageMetadata{Limit: 10, DomainID: domainID},
+			size:  10,
+			total: 20,
+		},
+		{
+			desc:  "channel windows",
+			pm:    alarms.SuppressionPageMetadata{Limit: 100, DomainID: domainID, ChannelID: channelID},
+			size:  10,
+			total: 10,
+		},
+		{
+			desc:  "windows from another domain",
+			pm:    alarms.SuppressionPageMetadata{Limit: 10, DomainID: generateUUID(&testing.T{})},
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := repo.ListSuppressionWindows(context.Background(), tc.pm)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.size, len(page.Windows))
+			assert.Equal(t, tc.total, page.Total)
+		})
+	}
+}
+
+func TestRetrieveSuppressionWindows(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarm_suppressions")
+		require.Nil(t, err, fmt.Sprintf("clean suppressions unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	now := time.Now().UTC()
+	domainID := generateUUID(&testing.T{})
+	channelID := generateUUID(&testing.T{})
+
+	windows := []alarms.SuppressionWindow{
+		{Name: "domain", DomainID: domainID, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Name: "channel", DomainID: domainID, ChannelID: channelID, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Name: "other channel", DomainID: domainID, ChannelID: generateUUID(&testing.T{}), StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Name: "expired", DomainID: domainID, StartsAt: now.Add(-3 * time.Hour), EndsAt: now.Add(-2 * time.Hour)},
+		{Name: "recurring", DomainID: domainID, StartsAt: now.Add(-72 * time.Hour), EndsAt: now.Add(-71 * time.Hour), Recurring: alarms.Daily},
+		{Name: "future", DomainID: domainID, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+	}
+	for _, w := range windows {
+		w.ID = generateUUID(&testing.T{})
+		w.CreatedAt = now
+		_, err := repo.CreateSuppressionWindow(context.Background(), w)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	alarm := alarms.Alarm{
+		DomainID:  domainID,
+		RuleID:    generateUUID(&testing.T{}),
+		ChannelID: channelID,
+		ClientID:  generateUUID(&testing.T{}),
+		CreatedAt: now,
+	}
+
+	got, err := repo.RetrieveSuppressionWindows(context.Background(), alarm)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var names []string
+	for _, w := range got {
+		names = append(names, w.Name)
+	}
+	assert.ElementsMatch(t, []string{"domain", "channel", "recurring"}, names)
+}
+
+func TestDeleteSuppressionWindow(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM alarm_suppressions")
+		require.Nil(t, err, fmt.Sprintf("clean suppressions unexpected error: %s", err))
+	})
+
+	repo := postgres.NewAlarmsRepo(db, alarms.FlapConfig{})
+	now := time.Now().UTC()
+
+	window, err := repo.CreateSuppressionWindow(context.Background(), alarms.SuppressionWindow{
+		ID:        generateUUID(&testing.T{}),
+		Name:      namegen.Generate(),
+		DomainID:  generateUUID(&testing.T{}),
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedAt: now,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		domainID string
+		err      error
+	}{
+		{
+			desc:     "window from another domain",
+			id:       window.ID,
+			domainID: generateUUID(&testing.T{}),
+			err:      repoerr.ErrNotFound,
+		},
+		{
+			desc:     "valid window",
+			id:       window.ID,
+			domainID: window.DomainID,
+			err:      nil,
+		},
+		{
+			desc:     "non existing window",
+			id:       window.ID,
+			domainID: window.DomainID,
+			err:      repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.DeleteSuppressionWindow(context.Background(), tc.id, tc.domainID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		})
+	}
+}
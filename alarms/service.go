@@ -5,24 +5,29 @@ package alarms
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/absmach/supermq"
 	"github.com/absmach/supermq/pkg/authn"
+	"github.com/absmach/supermq/pkg/errors"
 	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
 )
 
 type service struct {
-	idp  supermq.IDProvider
-	repo Repository
+	idp    supermq.IDProvider
+	repo   Repository
+	stream *stream
 }
 
 var _ Service = (*service)(nil)
 
 func NewService(idp supermq.IDProvider, repo Repository) Service {
 	return &service{
-		idp:  idp,
-		repo: repo,
+		idp:    idp,
+		repo:   repo,
+		stream: newStream(),
 	}
 }
 
@@ -40,9 +45,30 @@ func (s *service) CreateAlarm(ctx context.Context, alarm Alarm) error {
 		return err
 	}
 
-	if _, err = s.repo.CreateAlarm(ctx, alarm); err != nil && err != repoerr.ErrNotFound {
+	suppressed, err := s.suppressed(ctx, alarm)
+	if err != nil {
 		return err
 	}
+	alarm.Suppressed = suppressed
+
+	saved, err := s.repo.CreateAlarm(ctx, alarm)
+	switch {
+	case err == repoerr.ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	// Alarms which were not saved are recorded on the latest alarm of their source.
+	e := Event{Type: AlarmUpdated, Alarm: saved}
+	if saved.ID == alarm.ID {
+		e.Type = AlarmCreated
+		if saved.Status == ClearedStatus {
+			e.Type = AlarmCleared
+		}
+	}
+	s.stream.publish(e)
+
 	return nil
 }
 
@@ -55,12 +81,151 @@ func (s *service) ListAlarms(ctx context.Context, session authn.Session, pm Page
 }
 
 func (s *service) DeleteAlarm(ctx context.Context, session authn.Session, alarmID string) error {
-	return s.repo.DeleteAlarm(ctx, alarmID)
+	alarm, err := s.repo.DeleteAlarm(ctx, alarmID)
+	if err != nil {
+		return err
+	}
+	s.stream.publish(Event{Type: AlarmDeleted, Alarm: alarm})
+
+	return nil
 }
 
 func (s *service) UpdateAlarm(ctx context.Context, session authn.Session, alarm Alarm) (Alarm, error) {
 	alarm.UpdatedAt = time.Now()
 	alarm.UpdatedBy = session.UserID
 
-	return s.repo.UpdateAlarm(ctx, alarm)
+	alarm, err := s.repo.UpdateAlarm(ctx, alarm)
+	if err != nil {
+		return Alarm{}, err
+	}
+	s.stream.publish(Event{Type: AlarmUpdated, Alarm: alarm})
+
+	return alarm, nil
+}
+
+func (s *service) ExportAlarms(ctx context.Context, session authn.Session, pm PageMetadata, format ExportFormat, w io.Writer) error {
+	pm.DomainID = session.DomainID
+
+	switch err := s.export(ctx, pm, format, w); err {
+	case ErrExportSize, ErrInvalidExportFormat:
+		return errors.Wrap(svcerr.ErrMalformedEntity, err)
+	default:
+		return err
+	}
+}
+
+func (s *service) StreamAlarms(ctx context.Context, session authn.Session, pm PageMetadata) (<-chan Event, error) {
+	pm.DomainID = session.DomainID
+
+	return s.stream.subscribe(ctx, pm), nil
+}
+
+func (s *service) ApplyBulkOperation(ctx context.Context, session authn.Session, op BulkOperation) (BulkResult, error) {
+	op.DomainID = session.DomainID
+	op.UserID = session.UserID
+	op.Time = time.Now()
+	op.IDs = unique(op.IDs)
+	if op.Filter != nil {
+		filter := *op.Filter
+		filter.DomainID = session.DomainID
+		if filter.Limit == 0 {
+			filter.Limit = MaxBulkSize
+		}
+		op.Filter = &filter
+	}
+
+	if err := op.Validate(); err != nil {
+		return BulkResult{}, errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+
+	res, changed, err := s.repo.ApplyBulkOperation(ctx, op)
+	if err != nil {
+		return BulkResult{}, err
+	}
+
+	e := Event{Type: AlarmUpdated}
+	if op.Action == DeleteAction {
+		e.Type = AlarmDeleted
+	}
+	for _, a := range changed {
+		e.Alarm = a
+		s.stream.publish(e)
+	}
+
+	return res, nil
+}
+
+func (s *service) CreateSuppressionWindow(ctx context.Context, session authn.Session, w SuppressionWindow) (SuppressionWindow, error) {
+	id, err := s.idp.ID()
+	if err != nil {
+		return SuppressionWindow{}, err
+	}
+	w.ID = id
+	w.DomainID = session.DomainID
+	w.CreatedAt = time.Now()
+	w.CreatedBy = session.UserID
+
+	if err := w.Validate(); err != nil {
+		return SuppressionWindow{}, errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+
+	return s.repo.CreateSuppressionWindow(ctx, w)
+}
+
+func (s *service) ViewSuppressionWindow(ctx context.Context, session authn.Session, id string) (SuppressionWindow, error) {
+	return s.repo.ViewSuppressionWindow(ctx, id, session.DomainID)
+}
+
+func (s *service) UpdateSuppressionWindow(ctx context.Context, session authn.Session, w SuppressionWindow) (SuppressionWindow, error) {
+	w.DomainID = session.DomainID
+	w.UpdatedAt = time.Now()
+	w.UpdatedBy = session.UserID
+
+	if err := w.Validate(); err != nil {
+		return SuppressionWindow{}, errors.Wrap(svcerr.ErrMalformedEntity, err)
+	}
+
+	return s.repo.UpdateSuppressionWindow(ctx, w)
+}
+
+func (s *service) ListSuppressionWindows(ctx context.Context, session authn.Session, pm SuppressionPageMetadata) (SuppressionWindowsPage, error) {
+	pm.DomainID = session.DomainID
+
+	return s.repo.ListSuppressionWindows(ctx, pm)
+}
+
+func (s *service) DeleteSuppressionWindow(ctx context.Context, session authn.Session, id string) error {
+	return s.repo.DeleteSuppressionWindow(ctx, id, session.DomainID)
+}
+
+// suppressed reports whether any suppression window covers the alarm.
+func (s *service) suppressed(ctx context.Context, alarm Alarm) (bool, error) {
+	windows, err := s.repo.RetrieveSuppressionWindows(ctx, alarm)
+	if err != nil {
+		return false, err
+	}
+	for _, w := range windows {
+		if w.Matches(alarm) && w.Active(alarm.CreatedAt) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func unique(ids []string) []string {
+	if len(ids) == 0 {
+		return ids
+	}
+	seen := make(map[string]struct{}, len(ids))
+	ret := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ret = append(ret, id)
+	}
+
+	return ret
 }
@@ -4,20 +4,24 @@
 package alarms_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/absmach/magistrala/alarms"
 	"github.com/absmach/magistrala/alarms/mocks"
-	"github.com/absmach/magistrala/pkg/errors"
 	"github.com/absmach/supermq/pkg/authn"
+	"github.com/absmach/supermq/pkg/errors"
 	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
 	"github.com/absmach/supermq/pkg/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var idp = uuid.New()
@@ -26,27 +30,30 @@ func TestCreateAlarm(t *testing.T) {
 	repo := new(mocks.Repository)
 	svc := alarms.NewService(idp, repo)
 	ts := time.Now()
+	alarm := alarms.Alarm{
+		RuleID:      "rule-id",
+		DomainID:    "domain-id",
+		ChannelID:   "channel-id",
+		ClientID:    "client-id",
+		Subtopic:    "subtopic",
+		Measurement: "measurement",
+		Value:       "value",
+		Unit:        "unit",
+		Cause:       "cause",
+		Severity:    100,
+		CreatedAt:   ts,
+	}
 	cases := []struct {
-		desc  string
-		alarm alarms.Alarm
-		err   error
+		desc       string
+		alarm      alarms.Alarm
+		windows    []alarms.SuppressionWindow
+		suppressed bool
+		err        error
 	}{
 		{
-			desc: "valid alarm",
-			alarm: alarms.Alarm{
-				RuleID:      "rule-id",
-				DomainID:    "domain-id",
-				ChannelID:   "channel-id",
-				ClientID:    "client-id",
-				Subtopic:    "subtopic",
-				Measurement: "measurement",
-				Value:       "value",
-				Unit:        "unit",
-				Cause:       "cause",
-				Severity:    100,
-				CreatedAt:   ts,
-			},
-			err: nil,
+			desc:  "valid alarm",
+			alarm: alarm,
+			err:   nil,
 		},
 		{
 			desc: "missing rule_id",
@@ -64,31 +71,72 @@ func TestCreateAlarm(t *testing.T) {
 			},
 			err: errors.New("rule_id is required"),
 		},
+		{
+			desc:  "alarm during active suppression window",
+			alarm: alarm,
+			windows: []alarms.SuppressionWindow{
+				{
+					DomainID:  "domain-id",
+					ChannelID: "channel-id",
+					StartsAt:  ts.Add(-time.Hour),
+					EndsAt:    ts.Add(time.Hour),
+				},
+			},
+			suppressed: true,
+			err:        nil,
+		},
+		{
+			desc:  "alarm outside of recurring suppression window",
+			alarm: alarm,
+			windows: []alarms.SuppressionWindow{
+				{
+					DomainID:  "domain-id",
+					StartsAt:  ts.Add(-36 * time.Hour),
+					EndsAt:    ts.Add(-35 * time.Hour),
+					Recurring: alarms.Daily,
+				},
+			},
+			suppressed: false,
+			err:        nil,
+		},
+		{
+			desc:  "alarm during suppression window for another client",
+			alarm: alarm,
+			windows: []alarms.SuppressionWindow{
+				{
+					DomainID: "domain-id",
+					ClientID: "other-client-id",
+					StartsAt: ts.Add(-time.Hour),
+					EndsAt:   ts.Add(time.Hour),
+				},
+			},
+			suppressed: false,
+			err:        nil,
+		},
+		{
+			desc:  "failed to retrieve suppression windows",
+			alarm: alarm,
+			err:   repoerr.ErrViewEntity,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			repoCall := repo.On("CreateAlarm", context.Background(), mock.Anything).Return(tc.alarm, tc.err)
-			repoCall1 := repo.On("ListAlarms", context.Background(), alarms.PageMetadata{
-				Offset: 0, Limit: 1,
-				DomainID:    tc.alarm.DomainID,
-				ChannelID:   tc.alarm.ChannelID,
-				ClientID:    tc.alarm.ClientID,
-				Subtopic:    tc.alarm.Subtopic,
-				Measurement: tc.alarm.Measurement,
-				RuleID:      tc.alarm.RuleID,
-				Status:      alarms.AllStatus,
-				Severity:    math.MaxUint8,
-				CreatedTo:   tc.alarm.CreatedAt,
-			}).Return(alarms.AlarmsPage{}, tc.err)
+			var saved alarms.Alarm
+			repoCall := repo.On("RetrieveSuppressionWindows", context.Background(), mock.Anything).Return(tc.windows, tc.err)
+			repoCall1 := repo.On("CreateAlarm", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(alarms.Alarm)
+			}).Return(tc.alarm, tc.err)
 			err := svc.CreateAlarm(context.Background(), tc.alarm)
+			repoCall.Unset()
+			repoCall1.Unset()
 			if tc.err != nil {
 				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 				return
 			}
-			repoCall.Unset()
-			repoCall1.Unset()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+			assert.Equal(t, tc.suppressed, saved.Suppressed, fmt.Sprintf("%s: expected suppressed %t got %t\n", tc.desc, tc.suppressed, saved.Suppressed))
 		})
 	}
 }
@@ -255,7 +303,7 @@ func TestDeleteAlarm(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
 			s := authn.Session{DomainID: tc.id}
-			repoCall := repo.On("DeleteAlarm", context.Background(), tc.id).Return(tc.err)
+			repoCall := repo.On("DeleteAlarm", context.Background(), tc.id).Return(alarms.Alarm{ID: tc.id}, tc.err)
 			err := svc.DeleteAlarm(context.Background(), s, tc.id)
 			if tc.err != nil {
 				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
@@ -266,3 +314,638 @@ func TestDeleteAlarm(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateSuppressionWindow(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+	ts := time.Now()
+
+	cases := []struct {
+		desc    string
+		session authn.Session
+		window  alarms.SuppressionWindow
+		repoErr error
+		err     error
+	}{
+		{
+			desc:    "valid window",
+			session: authn.Session{DomainID: "domain-id", UserID: "user-id"},
+			window: alarms.SuppressionWindow{
+				Name:      "maintenance",
+				ChannelID: "channel-id",
+				StartsAt:  ts,
+				EndsAt:    ts.Add(time.Hour),
+				Recurring: alarms.Weekly,
+			},
+			err: nil,
+		},
+		{
+			desc:    "window ending before it starts",
+			session: authn.Session{DomainID: "domain-id", UserID: "user-id"},
+			window: alarms.SuppressionWindow{
+				Name:     "maintenance",
+				StartsAt: ts,
+				EndsAt:   ts.Add(-time.Hour),
+			},
+			err: svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:    "window longer than its recurring interval",
+			session: authn.Session{DomainID: "domain-id", UserID: "user-id"},
+			window: alarms.SuppressionWindow{
+				Name:      "maintenance",
+				StartsAt:  ts,
+				EndsAt:    ts.Add(25 * time.Hour),
+				Recurring: alarms.Daily,
+			},
+			err: svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:    "failed to save window",
+			session: authn.Session{DomainID: "domain-id", UserID: "user-id"},
+			window: alarms.SuppressionWindow{
+				Name:     "maintenance",
+				StartsAt: ts,
+				EndsAt:   ts.Add(time.Hour),
+			},
+			repoErr: repoerr.ErrCreateEntity,
+			err:     repoerr.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var saved alarms.SuppressionWindow
+			repoCall := repo.On("CreateSuppressionWindow", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(alarms.SuppressionWindow)
+			}).Return(tc.window, tc.repoErr)
+			_, err := svc.CreateSuppressionWindow(context.Background(), tc.session, tc.window)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			if tc.err == nil {
+				assert.NotEmpty(t, saved.ID, fmt.Sprintf("%s: expected window ID to be generated\n", tc.desc))
+				assert.Equal(t, tc.session.DomainID, saved.DomainID, fmt.Sprintf("%s: expected domain %s got %s\n", tc.desc, tc.session.DomainID, saved.DomainID))
+				assert.Equal(t, tc.session.UserID, saved.CreatedBy, fmt.Sprintf("%s: expected creator %s got %s\n", tc.desc, tc.session.UserID, saved.CreatedBy))
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestUpdateSuppressionWindow(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+	ts := time.Now()
+
+	cases := []struct {
+		desc    string
+		window  alarms.SuppressionWindow
+		repoErr error
+		err     error
+	}{
+		{
+			desc: "valid window",
+			window: alarms.SuppressionWindow{
+				ID:       "window-id",
+				Name:     "maintenance",
+				StartsAt: ts,
+				EndsAt:   ts.Add(time.Hour),
+			},
+			err: nil,
+		},
+		{
+			desc: "invalid window",
+			window: alarms.SuppressionWindow{
+				ID:       "window-id",
+				StartsAt: ts,
+				EndsAt:   ts.Add(time.Hour),
+			},
+			err: svcerr.ErrMalformedEntity,
+		},
+		{
+			desc: "non existing window",
+			window: alarms.SuppressionWindow{
+				ID:       "window-id",
+				Name:     "maintenance",
+				StartsAt: ts,
+				EndsAt:   ts.Add(time.Hour),
+			},
+			repoErr: repoerr.ErrNotFound,
+			err:     repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := authn.Session{DomainID: "domain-id", UserID: "user-id"}
+			repoCall := repo.On("UpdateSuppressionWindow", context.Background(), mock.Anything).Return(tc.window, tc.repoErr)
+			_, err := svc.UpdateSuppressionWindow(context.Background(), s, tc.window)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestListSuppressionWindows(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	cases := []struct {
+		desc string
+		pm   alarms.SuppressionPageMetadata
+		page alarms.SuppressionWindowsPage
+		err  error
+	}{
+		{
+			desc: "valid page",
+			pm: alarms.SuppressionPageMetadata{
+				Offset: 0,
+				Limit:  10,
+			},
+			page: alarms.SuppressionWindowsPage{
+				Offset:  0,
+				Limit:   10,
+				Total:   1,
+				Windows: []alarms.SuppressionWindow{{ID: "window-id"}},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := authn.Session{DomainID: "domain-id"}
+			pm := tc.pm
+			pm.DomainID = s.DomainID
+			repoCall := repo.On("ListSuppressionWindows", context.Background(), pm).Return(tc.page, tc.err)
+			page, err := svc.ListSuppressionWindows(context.Background(), s, tc.pm)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			assert.Equal(t, tc.page, page, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.page, page))
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestDeleteSuppressionWindow(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "valid window",
+			id:   "window-id",
+			err:  nil,
+		},
+		{
+			desc: "non existing window",
+			id:   "window-id",
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := authn.Session{DomainID: "domain-id"}
+			repoCall := repo.On("DeleteSuppressionWindow", context.Background(), tc.id, s.DomainID).Return(tc.err)
+			err := svc.DeleteSuppressionWindow(context.Background(), s, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestApplyBulkOperation(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	cases := []struct {
+		desc    string
+		op      alarms.BulkOperation
+		ids     []string
+		limit   uint64
+		res     alarms.BulkResult
+		repoErr error
+		err     error
+	}{
+		{
+			desc: "acknowledge alarms by ids",
+			op: alarms.BulkOperation{
+				Action: alarms.AcknowledgeAction,
+				IDs:    []string{"alarm-1", "alarm-2", "alarm-1"},
+			},
+			ids: []string{"alarm-1", "alarm-2"},
+			res: alarms.BulkResult{
+				Total:     2,
+				Succeeded: 2,
+				Results:   []alarms.BulkItemResult{{ID: "alarm-1"}, {ID: "alarm-2"}},
+			},
+			err: nil,
+		},
+		{
+			desc: "resolve alarms by filter",
+			op: alarms.BulkOperation{
+				Action: alarms.ResolveAction,
+				Filter: &alarms.PageMetadata{Status: alarms.ActiveStatus, Severity: math.MaxUint8},
+			},
+			limit: alarms.MaxBulkSize,
+			res: alarms.BulkResult{
+				Total:   1,
+				Failed:  1,
+				Results: []alarms.BulkItemResult{{ID: "alarm-1", Error: alarms.ErrAlarmAlreadyResolved.Error()}},
+			},
+			err: nil,
+		},
+		{
+			desc: "bulk operation without target",
+			op: alarms.BulkOperation{
+				Action: alarms.DeleteAction,
+			},
+			err: svcerr.ErrMalformedEntity,
+		},
+		{
+			desc: "assign alarms without assignee",
+			op: alarms.BulkOperation{
+				Action: alarms.AssignAction,
+				IDs:    []string{"alarm-1"},
+			},
+			err: svcerr.ErrMalformedEntity,
+		},
+		{
+			desc: "failed to apply bulk operation",
+			op: alarms.BulkOperation{
+				Action: alarms.DeleteAction,
+				IDs:    []string{"alarm-1"},
+			},
+			ids:     []string{"alarm-1"},
+			repoErr: repoerr.ErrRemoveEntity,
+			err:     repoerr.ErrRemoveEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := authn.Session{DomainID: "domain-id", UserID: "user-id"}
+			var saved alarms.BulkOperation
+			repoCall := repo.On("ApplyBulkOperation", context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(alarms.BulkOperation)
+			}).Return(tc.res, nil, tc.repoErr)
+			res, err := svc.ApplyBulkOperation(context.Background(), s, tc.op)
+			repoCall.Unset()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+			assert.Equal(t, s.DomainID, saved.DomainID)
+			assert.Equal(t, s.UserID, saved.UserID)
+			assert.Equal(t, tc.ids, saved.IDs)
+			if tc.op.Filter != nil {
+				assert.Equal(t, s.DomainID, saved.Filter.DomainID)
+				assert.Equal(t, tc.limit, saved.Filter.Limit)
+			}
+		})
+	}
+}
+
+func TestStreamAlarms(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	alarm := alarms.Alarm{
+		RuleID:      "rule-id",
+		DomainID:    "domain-id",
+		ChannelID:   "channel-id",
+		ClientID:    "client-id",
+		Measurement: "temperature",
+		Value:       "100",
+		Cause:       "high temperature",
+		Status:      alarms.ActiveStatus,
+	}
+	cleared := alarm
+	cleared.Status = alarms.ClearedStatus
+
+	cases := []struct {
+		desc      string
+		session   authn.Session
+		pm        alarms.PageMetadata
+		alarm     alarms.Alarm
+		saved     func(a alarms.Alarm) alarms.Alarm
+		repoErr   error
+		eventType alarms.EventType
+		received  bool
+	}{
+		{
+			desc:      "created alarm",
+			session:   authn.Session{DomainID: "domain-id"},
+			pm:        alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:     alarm,
+			saved:     func(a alarms.Alarm) alarms.Alarm { return a },
+			eventType: alarms.AlarmCreated,
+			received:  true,
+		},
+		{
+			desc:      "cleared alarm",
+			session:   authn.Session{DomainID: "domain-id"},
+			pm:        alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:     cleared,
+			saved:     func(a alarms.Alarm) alarms.Alarm { return a },
+			eventType: alarms.AlarmCleared,
+			received:  true,
+		},
+		{
+			desc:    "repeated alarm",
+			session: authn.Session{DomainID: "domain-id"},
+			pm:      alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:   alarm,
+			saved: func(a alarms.Alarm) alarms.Alarm {
+				a.ID = "latest-alarm-id"
+				a.Occurrences = 2
+				return a
+			},
+			eventType: alarms.AlarmUpdated,
+			received:  true,
+		},
+		{
+			desc:     "alarm from another domain",
+			session:  authn.Session{DomainID: "other-domain-id"},
+			pm:       alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:    alarm,
+			saved:    func(a alarms.Alarm) alarms.Alarm { return a },
+			received: false,
+		},
+		{
+			desc:     "alarm not matching the filter",
+			session:  authn.Session{DomainID: "domain-id"},
+			pm:       alarms.PageMetadata{Status: alarms.ClearedStatus, Severity: math.MaxUint8},
+			alarm:    alarm,
+			saved:    func(a alarms.Alarm) alarms.Alarm { return a },
+			received: false,
+		},
+		{
+			desc:    "suppressed alarm",
+			session: authn.Session{DomainID: "domain-id"},
+			pm:      alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:   alarm,
+			saved: func(a alarms.Alarm) alarms.Alarm {
+				a.Suppressed = true
+				return a
+			},
+			eventType: alarms.AlarmCreated,
+			received:  true,
+		},
+		{
+			desc:    "suppressed alarm not matching the filter",
+			session: authn.Session{DomainID: "domain-id"},
+			pm:      alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8, Suppressed: func() *bool { b := false; return &b }()},
+			alarm:   alarm,
+			saved: func(a alarms.Alarm) alarms.Alarm {
+				a.Suppressed = true
+				return a
+			},
+			received: false,
+		},
+		{
+			desc:     "alarm which was not saved",
+			session:  authn.Session{DomainID: "domain-id"},
+			pm:       alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8},
+			alarm:    cleared,
+			saved:    func(a alarms.Alarm) alarms.Alarm { return alarms.Alarm{} },
+			repoErr:  repoerr.ErrNotFound,
+			received: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			events, err := svc.StreamAlarms(ctx, tc.session, tc.pm)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+			repoCall := repo.On("RetrieveSuppressionWindows", context.Background(), mock.Anything).Return(nil, nil)
+			repoCall1 := repo.On("CreateAlarm", context.Background(), mock.Anything).Return(func(_ context.Context, a alarms.Alarm) (alarms.Alarm, error) {
+				return tc.saved(a), tc.repoErr
+			})
+			err = svc.CreateAlarm(context.Background(), tc.alarm)
+			repoCall.Unset()
+			repoCall1.Unset()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+			select {
+			case e := <-events:
+				assert.True(t, tc.received, fmt.Sprintf("%s: unexpected event %v\n", tc.desc, e))
+				assert.Equal(t, tc.eventType, e.Type, fmt.Sprintf("%s: expected event type %s got %s\n", tc.desc, tc.eventType, e.Type))
+			default:
+				assert.False(t, tc.received, fmt.Sprintf("%s: expected event\n", tc.desc))
+			}
+
+			cancel()
+			for range events {
+			}
+		})
+	}
+}
+
+func TestStreamAlarmChanges(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	session := authn.Session{DomainID: "domain-id", UserID: "user-id"}
+	pm := alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8}
+	alarm := alarms.Alarm{
+		ID:          "alarm-id",
+		RuleID:      "rule-id",
+		DomainID:    "domain-id",
+		ChannelID:   "channel-id",
+		Measurement: "temperature",
+		Status:      alarms.ActiveStatus,
+	}
+	other := alarm
+	other.ID = "other-alarm-id"
+
+	cases := []struct {
+		desc   string
+		change func() error
+		events []alarms.Event
+	}{
+		{
+			desc: "deleted alarm",
+			change: func() error {
+				repoCall := repo.On("DeleteAlarm", context.Background(), alarm.ID).Return(alarm, nil)
+				defer repoCall.Unset()
+				return svc.DeleteAlarm(context.Background(), session, alarm.ID)
+			},
+			events: []alarms.Event{{Type: alarms.AlarmDeleted, Alarm: alarm}},
+		},
+		{
+			desc: "failed to delete alarm",
+			change: func() error {
+				repoCall := repo.On("DeleteAlarm", context.Background(), alarm.ID).Return(alarms.Alarm{}, repoerr.ErrNotFound)
+				defer repoCall.Unset()
+				if err := svc.DeleteAlarm(context.Background(), session, alarm.ID); !errors.Contains(err, repoerr.ErrNotFound) {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			desc: "bulk acknowledged alarms",
+			change: func() error {
+				repoCall := repo.On("ApplyBulkOperation", context.Background(), mock.Anything).Return(alarms.BulkResult{}, []alarms.Alarm{alarm, other}, nil)
+				defer repoCall.Unset()
+				_, err := svc.ApplyBulkOperation(context.Background(), session, alarms.BulkOperation{Action: alarms.AcknowledgeAction, IDs: []string{alarm.ID, other.ID}})
+				return err
+			},
+			events: []alarms.Event{{Type: alarms.AlarmUpdated, Alarm: alarm}, {Type: alarms.AlarmUpdated, Alarm: other}},
+		},
+		{
+			desc: "bulk deleted alarms",
+			change: func() error {
+				repoCall := repo.On("ApplyBulkOperation", context.Background(), mock.Anything).Return(alarms.BulkResult{}, []alarms.Alarm{alarm}, nil)
+				defer repoCall.Unset()
+				_, err := svc.ApplyBulkOperation(context.Background(), session, alarms.BulkOperation{Action: alarms.DeleteAction, IDs: []string{alarm.ID}})
+				return err
+			},
+			events: []alarms.Event{{Type: alarms.AlarmDeleted, Alarm: alarm}},
+		},
+		{
+			desc: "failed bulk operation",
+			change: func() error {
+				repoCall := repo.On("ApplyBulkOperation", context.Background(), mock.Anything).Return(alarms.BulkResult{}, []alarms.Alarm{alarm}, repoerr.ErrUpdateEntity)
+				defer repoCall.Unset()
+				if _, err := svc.ApplyBulkOperation(context.Background(), session, alarms.BulkOperation{Action: alarms.ResolveAction, IDs: []string{alarm.ID}}); !errors.Contains(err, repoerr.ErrUpdateEntity) {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			events, err := svc.StreamAlarms(ctx, session, pm)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+			err = tc.change()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+			var received []alarms.Event
+			for done := false; !done; {
+				select {
+				case e := <-events:
+					received = append(received, e)
+				default:
+					done = true
+				}
+			}
+			assert.Equal(t, tc.events, received, fmt.Sprintf("%s: expected events %v got %v\n", tc.desc, tc.events, received))
+
+			cancel()
+			for range events {
+			}
+		})
+	}
+}
+
+func TestExportAlarms(t *testing.T) {
+	repo := new(mocks.Repository)
+	svc := alarms.NewService(idp, repo)
+
+	alarm := alarms.Alarm{
+		ID:          "alarm-id",
+		RuleID:      "rule-id",
+		DomainID:    "domain-id",
+		ChannelID:   "channel-id",
+		ClientID:    "client-id",
+		Measurement: "temperature",
+		Value:       "100",
+		Threshold:   "80",
+		Cause:       "high temperature",
+		Occurrences: 1,
+		CreatedAt:   time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}
+	page := func(n int) alarms.AlarmsPage {
+		p := alarms.AlarmsPage{Alarms: make([]alarms.Alarm, n)}
+		for i := range p.Alarms {
+			p.Alarms[i] = alarm
+		}
+		return p
+	}
+
+	cases := []struct {
+		desc    string
+		format  alarms.ExportFormat
+		pages   []alarms.AlarmsPage
+		rows    int
+		prefix  string
+		repoErr error
+		err     error
+	}{
+		{
+			desc:   "export single page to csv",
+			format: alarms.CSVExport,
+			pages:  []alarms.AlarmsPage{page(2)},
+			rows:   2,
+			prefix: "Alarms Report\n",
+		},
+		{
+			desc:   "export multiple pages to csv",
+			format: alarms.CSVExport,
+			pages:  []alarms.AlarmsPage{page(1000), page(3)},
+			rows:   1003,
+			prefix: "Alarms Report\n",
+		},
+		{
+			desc:   "export to pdf",
+			format: alarms.PDFExport,
+			pages:  []alarms.AlarmsPage{page(2)},
+			prefix: "%PDF",
+		},
+		{
+			desc:   "export too many alarms to pdf",
+			format: alarms.PDFExport,
+			pages:  []alarms.AlarmsPage{page(1000), page(1000), page(1000), page(1000), page(1000), page(1000), page(1000), page(1000), page(1000), page(1000), page(1)},
+			err:    svcerr.ErrMalformedEntity,
+		},
+		{
+			desc:    "export with failed list",
+			format:  alarms.CSVExport,
+			pages:   []alarms.AlarmsPage{{}},
+			repoErr: repoerr.ErrViewEntity,
+			err:     repoerr.ErrViewEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := authn.Session{DomainID: "domain-id"}
+			var offsets []uint64
+			calls := 0
+			repoCall := repo.On("ListAlarms", context.Background(), mock.Anything).Return(func(_ context.Context, pm alarms.PageMetadata) (alarms.AlarmsPage, error) {
+				offsets = append(offsets, pm.Offset)
+				assert.Equal(t, s.DomainID, pm.DomainID)
+				assert.False(t, pm.CreatedTo.IsZero(), "expected export to be bounded in time")
+				p := tc.pages[min(calls, len(tc.pages)-1)]
+				calls++
+				return p, tc.repoErr
+			})
+			var buf bytes.Buffer
+			err := svc.ExportAlarms(context.Background(), s, alarms.PageMetadata{Status: alarms.AllStatus, Severity: math.MaxUint8}, tc.format, &buf)
+			repoCall.Unset()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+			assert.True(t, strings.HasPrefix(buf.String(), tc.prefix), fmt.Sprintf("%s: expected prefix %q\n", tc.desc, tc.prefix))
+			if tc.format == alarms.CSVExport {
+				assert.Equal(t, tc.rows, strings.Count(buf.String(), "high temperature"), fmt.Sprintf("%s: expected %d rows\n", tc.desc, tc.rows))
+			}
+			for i, offset := range offsets {
+				assert.Equal(t, uint64(i*1000), offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// streamBuffer is the number of events buffered for each subscriber.
+// Events are dropped for subscribers which do not keep up.
+const streamBuffer = 64
+
+// EventType is the type of alarm event pushed to stream subscribers.
+type EventType string
+
+const (
+	AlarmCreated EventType = "create"
+	AlarmUpdated EventType = "update"
+	AlarmCleared EventType = "clear"
+	AlarmDeleted EventType = "delete"
+)
+
+// Event is a change of an alarm pushed to stream subscribers.
+type Event struct {
+	Type  EventType `json:"type"`
+	Alarm Alarm     `json:"alarm"`
+}
+
+type subscription struct {
+	pm     PageMetadata
+	events chan Event
+}
+
+// stream fans out alarm events to the subscribers of this service instance.
+type stream struct {
+	mu   sync.RWMutex
+	subs map[*subscription]struct{}
+}
+
+func newStream() *stream {
+	return &stream{subs: make(map[*subscription]struct{})}
+}
+
+// subscribe returns the channel of events matching the filter. The channel is
+// closed once the context is done.
+func (s *stream) subscribe(ctx context.Context, pm PageMetadata) <-chan Event {
+	sub := &subscription{pm: pm, events: make(chan Event, streamBuffer)}
+
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.subs, sub)
+		close(sub.events)
+		s.mu.Unlock()
+	}()
+
+	return sub.events
+}
+
+func (s *stream) publish(e Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subs {
+		if !matches(e.Alarm, sub.pm) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+		}
+	}
+}
+
+// matches reports whether the alarm passes the list filter. Pagination and
+// creation time bounds do not apply to streamed alarms.
+func matches(a Alarm, pm PageMetadata) bool {
+	switch {
+	case a.DomainID != pm.DomainID,
+		pm.RuleID != "" && a.RuleID != pm.RuleID,
+		pm.ChannelID != "" && a.ChannelID != pm.ChannelID,
+		pm.ClientID != "" && a.ClientID != pm.ClientID,
+		pm.Subtopic != "" && a.Subtopic != pm.Subtopic,
+		pm.Measurement != "" && a.Measurement != pm.Measurement,
+		pm.Status != AllStatus && a.Status != pm.Status,
+		pm.Severity != math.MaxUint8 && a.Severity != pm.Severity,
+		pm.AssigneeID != "" && a.AssigneeID != pm.AssigneeID,
+		pm.UpdatedBy != "" && a.UpdatedBy != pm.UpdatedBy,
+		pm.AssignedBy != "" && a.AssignedBy != pm.AssignedBy,
+		pm.AcknowledgedBy != "" && a.AcknowledgedBy != pm.AcknowledgedBy,
+		pm.ResolvedBy != "" && a.ResolvedBy != pm.ResolvedBy,
+		pm.Flapping != nil && a.Flapping != *pm.Flapping,
+		pm.Suppressed != nil && a.Suppressed != *pm.Suppressed:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRecurring = errors.New("invalid recurring type. Must be one of none, daily, weekly or monthly")
+	ErrInvalidWindow    = errors.New("invalid suppression window. Start must be before end")
+)
+
+// Recurring determines how often a suppression window repeats.
+type Recurring uint8
+
+const (
+	None Recurring = iota
+	Daily
+	Weekly
+	Monthly
+)
+
+const (
+	none    = "none"
+	daily   = "daily"
+	weekly  = "weekly"
+	monthly = "monthly"
+)
+
+// String converts recurring type to string literal.
+func (r Recurring) String() string {
+	switch r {
+	case Daily:
+		return daily
+	case Weekly:
+		return weekly
+	case Monthly:
+		return monthly
+	default:
+		return none
+	}
+}
+
+// ToRecurring converts string value to a valid Recurring type.
+func ToRecurring(r string) (Recurring, error) {
+	switch strings.ToLower(r) {
+	case none, "":
+		return None, nil
+	case daily:
+		return Daily, nil
+	case weekly:
+		return Weekly, nil
+	case monthly:
+		return Monthly, nil
+	default:
+		return None, ErrInvalidRecurring
+	}
+}
+
+func (r Recurring) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Recurring) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	val, err := ToRecurring(s)
+	*r = val
+
+	return err
+}
+
+// SuppressionWindow represents a maintenance window during which alarms
+// matching its scope are still recorded, but flagged as suppressed so that
+// no notifications are sent for them. Empty scope fields match any value.
+type SuppressionWindow struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description,omitempty"`
+	DomainID        string    `json:"domain_id"`
+	RuleID          string    `json:"rule_id,omitempty"`
+	ChannelID       string    `json:"channel_id,omitempty"`
+	ClientID        string    `json:"client_id,omitempty"`
+	Subtopic        string    `json:"subtopic,omitempty"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	Recurring       Recurring `json:"recurring"`
+	RecurringPeriod uint      `json:"recurring_period,omitempty"` // Controls how many intervals to skip between occurrences: 1 = every interval, 2 = every second interval, etc.
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedBy       string    `json:"created_by"`
+	UpdatedAt       time.Time `json:"updated_at,omitempty"`
+	UpdatedBy       string    `json:"updated_by,omitempty"`
+}
+
+type SuppressionWindowsPage struct {
+	Offset  uint64              `json:"offset"`
+	Limit   uint64              `json:"limit"`
+	Total   uint64              `json:"total"`
+	Windows []SuppressionWindow `json:"windows"`
+}
+
+type SuppressionPageMetadata struct {
+	Offset    uint64 `json:"offset"     db:"offset"`
+	Limit     uint64 `json:"limit"      db:"limit"`
+	DomainID  string `json:"domain_id"  db:"domain_id"`
+	RuleID    string `json:"rule_id"    db:"rule_id"`
+	ChannelID string `json:"channel_id" db:"channel_id"`
+	ClientID  string `json:"client_id"  db:"client_id"`
+	Subtopic  string `json:"subtopic"   db:"subtopic"`
+}
+
+func (w SuppressionWindow) Validate() error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	if w.DomainID == "" {
+		return errors.New("domain_id is required")
+	}
+	if w.StartsAt.IsZero() || w.EndsAt.IsZero() || !w.StartsAt.Before(w.EndsAt) {
+		return ErrInvalidWindow
+	}
+	if w.Recurring > Monthly {
+		return ErrInvalidRecurring
+	}
+	if w.Recurring != None && w.EndsAt.Sub(w.StartsAt) > w.interval() {
+		return errors.New("suppression window must be shorter than its recurring interval")
+	}
+
+	return nil
+}
+
+// Matches reports whether the alarm falls into the window scope. It does
+// not take the window schedule into account.
+func (w SuppressionWindow) Matches(a Alarm) bool {
+	return w.DomainID == a.DomainID &&
+		(w.RuleID == "" || w.RuleID == a.RuleID) &&
+		(w.ChannelID == "" || w.ChannelID == a.ChannelID) &&
+		(w.ClientID == "" || w.ClientID == a.ClientID) &&
+		(w.Subtopic == "" || w.Subtopic == a.Subtopic)
+}
+
+// Active reports whether the window, or one of its recurrences, covers t.
+func (w SuppressionWindow) Active(t time.Time) bool {
+	if t.Before(w.StartsAt) {
+		return false
+	}
+	if w.Recurring == None {
+		return t.Before(w.EndsAt)
+	}
+	start := w.occurrence(t)
+
+	return t.Before(start.Add(w.EndsAt.Sub(w.StartsAt)))
+}
+
+// occurrence returns the start of the latest recurrence not after t.
+func (w SuppressionWindow) occurrence(t time.Time) time.Time {
+	period := int(w.RecurringPeriod)
+	if period == 0 {
+		period = 1
+	}
+	var n int
+	switch w.Recurring {
+	case Daily:
+		n = int(t.Sub(w.StartsAt) / (24 * time.Hour))
+	case Weekly:
+		n = int(t.Sub(w.StartsAt) / (7 * 24 * time.Hour))
+	case Monthly:
+		n = (t.Year()-w.StartsAt.Year())*12 + int(t.Month()-w.StartsAt.Month())
+	}
+	n -= n % period
+	// Calendar arithmetic may be off by one interval across DST changes or month ends.
+	for n > 0 && w.next(n).After(t) {
+		n -= period
+	}
+	for !w.next(n + period).After(t) {
+		n += period
+	}
+
+	return w.next(n)
+}
+
+func (w SuppressionWindow) next(n int) time.Time {
+	switch w.Recurring {
+	case Daily:
+		return w.StartsAt.AddDate(0, 0, n)
+	case Weekly:
+		return w.StartsAt.AddDate(0, 0, 7*n)
+	case Monthly:
+		return w.StartsAt.AddDate(0, n, 0)
+	default:
+		return w.StartsAt
+	}
+}
+
+func (w SuppressionWindow) interval() time.Duration {
+	period := time.Duration(w.RecurringPeriod)
+	if period == 0 {
+		period = 1
+	}
+	switch w.Recurring {
+	case Daily:
+		return period * 24 * time.Hour
+	case Weekly:
+		return period * 7 * 24 * time.Hour
+	case Monthly:
+		// Shortest possible month.
+		return period * 28 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
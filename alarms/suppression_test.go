@@ -0,0 +1,196 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/alarms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressionWindowActive(t *testing.T) {
+	start := time.Date(2025, time.January, 31, 22, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	cases := []struct {
+		desc   string
+		window alarms.SuppressionWindow
+		time   time.Time
+		active bool
+	}{
+		{
+			desc:   "one-off window before start",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end},
+			time:   start.Add(-time.Minute),
+			active: false,
+		},
+		{
+			desc:   "one-off window at start",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end},
+			time:   start,
+			active: true,
+		},
+		{
+			desc:   "one-off window at end",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end},
+			time:   end,
+			active: false,
+		},
+		{
+			desc:   "one-off window a day later",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end},
+			time:   start.AddDate(0, 0, 1),
+			active: false,
+		},
+		{
+			desc:   "daily window during later occurrence",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Daily},
+			time:   start.AddDate(0, 0, 10).Add(time.Hour),
+			active: true,
+		},
+		{
+			desc:   "daily window between occurrences",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Daily},
+			time:   start.AddDate(0, 0, 10).Add(3 * time.Hour),
+			active: false,
+		},
+		{
+			desc:   "every second day window on skipped day",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Daily, RecurringPeriod: 2},
+			time:   start.AddDate(0, 0, 3).Add(time.Hour),
+			active: false,
+		},
+		{
+			desc:   "every second day window on matching day",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Daily, RecurringPeriod: 2},
+			time:   start.AddDate(0, 0, 4).Add(time.Hour),
+			active: true,
+		},
+		{
+			desc:   "weekly window during occurrence",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Weekly},
+			time:   start.AddDate(0, 0, 21).Add(90 * time.Minute),
+			active: true,
+		},
+		{
+			desc:   "weekly window on another weekday",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Weekly},
+			time:   start.AddDate(0, 0, 22).Add(time.Hour),
+			active: false,
+		},
+		{
+			desc:   "monthly window past month end",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Monthly},
+			time:   start.AddDate(0, 1, 0).Add(time.Hour),
+			active: true,
+		},
+		{
+			desc:   "monthly window between occurrences",
+			window: alarms.SuppressionWindow{StartsAt: start, EndsAt: end, Recurring: alarms.Monthly},
+			time:   start.AddDate(0, 1, 5),
+			active: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			active := tc.window.Active(tc.time)
+			assert.Equal(t, tc.active, active, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.active, active))
+		})
+	}
+}
+
+func TestSuppressionWindowMatches(t *testing.T) {
+	alarm := alarms.Alarm{
+		DomainID:  "domain-id",
+		RuleID:    "rule-id",
+		ChannelID: "channel-id",
+		ClientID:  "client-id",
+		Subtopic:  "subtopic",
+	}
+
+	cases := []struct {
+		desc    string
+		window  alarms.SuppressionWindow
+		matches bool
+	}{
+		{
+			desc:    "domain wide window",
+			window:  alarms.SuppressionWindow{DomainID: "domain-id"},
+			matches: true,
+		},
+		{
+			desc:    "window for other domain",
+			window:  alarms.SuppressionWindow{DomainID: "other-domain-id"},
+			matches: false,
+		},
+		{
+			desc:    "window for rule and subtopic",
+			window:  alarms.SuppressionWindow{DomainID: "domain-id", RuleID: "rule-id", Subtopic: "subtopic"},
+			matches: true,
+		},
+		{
+			desc:    "window for other channel",
+			window:  alarms.SuppressionWindow{DomainID: "domain-id", ChannelID: "other-channel-id"},
+			matches: false,
+		},
+		{
+			desc:    "window for other client",
+			window:  alarms.SuppressionWindow{DomainID: "domain-id", ClientID: "other-client-id"},
+			matches: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			matches := tc.window.Matches(alarm)
+			assert.Equal(t, tc.matches, matches, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.matches, matches))
+		})
+	}
+}
+
+func TestRecurringJSON(t *testing.T) {
+	cases := []struct {
+		desc      string
+		data      string
+		recurring alarms.Recurring
+		err       error
+	}{
+		{
+			desc:      "daily",
+			data:      `"daily"`,
+			recurring: alarms.Daily,
+		},
+		{
+			desc:      "monthly in upper case",
+			data:      `"MONTHLY"`,
+			recurring: alarms.Monthly,
+		},
+		{
+			desc: "invalid value",
+			data: `"yearly"`,
+			err:  alarms.ErrInvalidRecurring,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var r alarms.Recurring
+			err := json.Unmarshal([]byte(tc.data), &r)
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %v got %v\n", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+			assert.Equal(t, tc.recurring, r)
+			data, err := json.Marshal(r)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+			assert.Equal(t, fmt.Sprintf("%q", tc.recurring.String()), string(data))
+		})
+	}
+}
@@ -34,17 +34,31 @@ const (
 	Aggregation_SUM                     Aggregation = 3
 	Aggregation_COUNT                   Aggregation = 4
 	Aggregation_AVG                     Aggregation = 5
+	Aggregation_P95                     Aggregation = 6
+	Aggregation_P99                     Aggregation = 7
+	Aggregation_STDDEV                  Aggregation = 8
+	Aggregation_FIRST                   Aggregation = 9
+	Aggregation_LAST                    Aggregation = 10
+	Aggregation_RATE                    Aggregation = 11
+	Aggregation_DELTA                   Aggregation = 12
 )
 
 // Enum value maps for Aggregation.
 var (
 	Aggregation_name = map[int32]string{
-		0: "AGGREGATION_UNSPECIFIED",
-		1: "MAX",
-		2: "MIN",
-		3: "SUM",
-		4: "COUNT",
-		5: "AVG",
+		0:  "AGGREGATION_UNSPECIFIED",
+		1:  "MAX",
+		2:  "MIN",
+		3:  "SUM",
+		4:  "COUNT",
+		5:  "AVG",
+		6:  "P95",
+		7:  "P99",
+		8:  "STDDEV",
+		9:  "FIRST",
+		10: "LAST",
+		11: "RATE",
+		12: "DELTA",
 	}
 	Aggregation_value = map[string]int32{
 		"AGGREGATION_UNSPECIFIED": 0,
@@ -53,6 +67,13 @@ var (
 		"SUM":                     3,
 		"COUNT":                   4,
 		"AVG":                     5,
+		"P95":                     6,
+		"P99":                     7,
+		"STDDEV":                  8,
+		"FIRST":                   9,
+		"LAST":                    10,
+		"RATE":                    11,
+		"DELTA":                   12,
 	}
 )
 
@@ -102,6 +123,10 @@ type PageMetadata struct {
 	Aggregation   Aggregation            `protobuf:"varint,15,opt,name=aggregation,proto3,enum=readers.v1.Aggregation" json:"aggregation,omitempty"`
 	Comparator    string                 `protobuf:"bytes,16,opt,name=comparator,proto3" json:"comparator,omitempty"`
 	Format        string                 `protobuf:"bytes,17,opt,name=format,proto3" json:"format,omitempty"`
+	Fill          string                 `protobuf:"bytes,18,opt,name=fill,proto3" json:"fill,omitempty"`
+	FillValue     float64                `protobuf:"fixed64,19,opt,name=fill_value,json=fillValue,proto3" json:"fill_value,omitempty"`
+	Cursor        string                 `protobuf:"bytes,20,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	SkipTotal     bool                   `protobuf:"varint,21,opt,name=skip_total,json=skipTotal,proto3" json:"skip_total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -255,11 +280,40 @@ func (x *PageMetadata) GetFormat() string {
 	return ""
 }
 
+func (x *PageMetadata) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *PageMetadata) GetFillValue() float64 {
+	if x != nil {
+		return x.FillValue
+	}
+	return 0
+}
+
+func (x *PageMetadata) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *PageMetadata) GetSkipTotal() bool {
+	if x != nil {
+		return x.SkipTotal
+	}
+	return false
+}
+
 type ReadMessagesRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Total         uint64                 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
 	PageMetadata  *PageMetadata          `protobuf:"bytes,2,opt,name=page_metadata,json=pageMetadata,proto3" json:"page_metadata,omitempty"`
 	Messages      []*Message             `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -315,6 +369,13 @@ func (x *ReadMessagesRes) GetMessages() []*Message {
 	return nil
 }
 
+func (x *ReadMessagesRes) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type Message struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Payload:
@@ -646,6 +707,7 @@ type ReadMessagesReq struct {
 	ChannelId     string                 `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 	DomainId      string                 `protobuf:"bytes,2,opt,name=domain_id,json=domainId,proto3" json:"domain_id,omitempty"`
 	PageMetadata  *PageMetadata          `protobuf:"bytes,3,opt,name=page_metadata,json=pageMetadata,proto3" json:"page_metadata,omitempty"`
+	ChannelIds    []string               `protobuf:"bytes,4,rep,name=channel_ids,json=channelIds,proto3" json:"channel_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -701,12 +763,139 @@ func (x *ReadMessagesReq) GetPageMetadata() *PageMetadata {
 	return nil
 }
 
+func (x *ReadMessagesReq) GetChannelIds() []string {
+	if x != nil {
+		return x.ChannelIds
+	}
+	return nil
+}
+
+type MessagesGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Publisher     string                 `protobuf:"bytes,2,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Total         uint64                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Messages      []*Message             `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagesGroup) Reset() {
+	*x = MessagesGroup{}
+	mi := &file_readers_v1_readers_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagesGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagesGroup) ProtoMessage() {}
+
+func (x *MessagesGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_readers_v1_readers_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagesGroup.ProtoReflect.Descriptor instead.
+func (*MessagesGroup) Descriptor() ([]byte, []int) {
+	return file_readers_v1_readers_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MessagesGroup) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *MessagesGroup) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *MessagesGroup) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *MessagesGroup) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ReadGroupsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageMetadata  *PageMetadata          `protobuf:"bytes,1,opt,name=page_metadata,json=pageMetadata,proto3" json:"page_metadata,omitempty"`
+	Groups        []*MessagesGroup       `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadGroupsRes) Reset() {
+	*x = ReadGroupsRes{}
+	mi := &file_readers_v1_readers_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadGroupsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadGroupsRes) ProtoMessage() {}
+
+func (x *ReadGroupsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_readers_v1_readers_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadGroupsRes.ProtoReflect.Descriptor instead.
+func (*ReadGroupsRes) Descriptor() ([]byte, []int) {
+	return file_readers_v1_readers_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReadGroupsRes) GetPageMetadata() *PageMetadata {
+	if x != nil {
+		return x.PageMetadata
+	}
+	return nil
+}
+
+func (x *ReadGroupsRes) GetGroups() []*MessagesGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
 var File_readers_v1_readers_proto protoreflect.FileDescriptor
 
 const file_readers_v1_readers_proto_rawDesc = "" +
 	"\n" +
 	"\x18readers/v1/readers.proto\x12\n" +
-	"readers.v1\"\xe4\x03\n" +
+	"readers.v1\"\xce\x04\n" +
 	"\fPageMetadata\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x04R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x1a\n" +
@@ -729,11 +918,19 @@ const file_readers_v1_readers_proto_rawDesc = "" +
 	"\n" +
 	"comparator\x18\x10 \x01(\tR\n" +
 	"comparator\x12\x16\n" +
-	"\x06format\x18\x11 \x01(\tR\x06format\"\x97\x01\n" +
+	"\x06format\x18\x11 \x01(\tR\x06format\x12\x12\n" +
+	"\x04fill\x18\x12 \x01(\tR\x04fill\x12\x1d\n" +
+	"\n" +
+	"fill_value\x18\x13 \x01(\x01R\tfillValue\x12\x16\n" +
+	"\x06cursor\x18\x14 \x01(\tR\x06cursor\x12\x1d\n" +
+	"\n" +
+	"skip_total\x18\x15 \x01(\bR\tskipTotal\"\xb8\x01\n" +
 	"\x0fReadMessagesRes\x12\x14\n" +
 	"\x05total\x18\x01 \x01(\x04R\x05total\x12=\n" +
 	"\rpage_metadata\x18\x02 \x01(\v2\x18.readers.v1.PageMetadataR\fpageMetadata\x12/\n" +
-	"\bmessages\x18\x03 \x03(\v2\x13.readers.v1.MessageR\bmessages\"u\n" +
+	"\bmessages\x18\x03 \x03(\v2\x13.readers.v1.MessageR\bmessages\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursor\"u\n" +
 	"\aMessage\x120\n" +
 	"\x05senml\x18\x01 \x01(\v2\x18.readers.v1.SenMLMessageH\x00R\x05senml\x12-\n" +
 	"\x04json\x18\x02 \x01(\v2\x17.readers.v1.JsonMessageH\x00R\x04jsonB\t\n" +
@@ -766,21 +963,45 @@ const file_readers_v1_readers_proto_rawDesc = "" +
 	"\vJsonMessage\x12+\n" +
 	"\x04base\x18\x01 \x01(\v2\x17.readers.v1.BaseMessageR\x04base\x12\x18\n" +
 	"\acreated\x18\x02 \x01(\x03R\acreated\x12\x18\n" +
-	"\apayload\x18\x03 \x01(\fR\apayload\"\x8c\x01\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\"\xad\x01\n" +
 	"\x0fReadMessagesReq\x12\x1d\n" +
 	"\n" +
 	"channel_id\x18\x01 \x01(\tR\tchannelId\x12\x1b\n" +
 	"\tdomain_id\x18\x02 \x01(\tR\bdomainId\x12=\n" +
-	"\rpage_metadata\x18\x03 \x01(\v2\x18.readers.v1.PageMetadataR\fpageMetadata*Y\n" +
+	"\rpage_metadata\x18\x03 \x01(\v2\x18.readers.v1.PageMetadataR\fpageMetadata\x12\x1f\n" +
+	"\vchannel_ids\x18\x04 \x03(\tR\n" +
+	"channelIds\"\x8e\x01\n" +
+	"\rMessagesGroup\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x1c\n" +
+	"\tpublisher\x18\x02 \x01(\tR\tpublisher\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x04R\x05total\x12/\n" +
+	"\bmessages\x18\x04 \x03(\v2\x13.readers.v1.MessageR\bmessages\"\x81\x01\n" +
+	"\rReadGroupsRes\x12=\n" +
+	"\rpage_metadata\x18\x01 \x01(\v2\x18.readers.v1.PageMetadataR\fpageMetadata\x121\n" +
+	"\x06groups\x18\x02 \x03(\v2\x19.readers.v1.MessagesGroupR\x06groups*\xa1\x01\n" +
 	"\vAggregation\x12\x1b\n" +
 	"\x17AGGREGATION_UNSPECIFIED\x10\x00\x12\a\n" +
 	"\x03MAX\x10\x01\x12\a\n" +
 	"\x03MIN\x10\x02\x12\a\n" +
 	"\x03SUM\x10\x03\x12\t\n" +
 	"\x05COUNT\x10\x04\x12\a\n" +
-	"\x03AVG\x10\x052\\\n" +
+	"\x03AVG\x10\x05\x12\a\n" +
+	"\x03P95\x10\x06\x12\a\n" +
+	"\x03P99\x10\a\x12\n" +
+	"\n" +
+	"\x06STDDEV\x10\b\x12\t\n" +
+	"\x05FIRST\x10\t\x12\b\n" +
+	"\x04LAST\x10\n" +
+	"\x12\b\n" +
+	"\x04RATE\x10\v\x12\t\n" +
+	"\x05DELTA\x10\f2\xbe\x02\n" +
 	"\x0eReadersService\x12J\n" +
-	"\fReadMessages\x12\x1b.readers.v1.ReadMessagesReq\x1a\x1b.readers.v1.ReadMessagesRes\"\x00B3Z1github.com/absmach/magistrala/api/grpc/readers/v1b\x06proto3"
+	"\fReadMessages\x12\x1b.readers.v1.ReadMessagesReq\x1a\x1b.readers.v1.ReadMessagesRes\"\x00\x12H\n" +
+	"\n" +
+	"ReadLatest\x12\x1b.readers.v1.ReadMessagesReq\x1a\x1b.readers.v1.ReadMessagesRes\"\x00\x12N\n" +
+	"\x0eStreamMessages\x12\x1b.readers.v1.ReadMessagesReq\x1a\x1b.readers.v1.ReadMessagesRes\"\x000\x01\x12F\n" +
+	"\n" +
+	"ReadGroups\x12\x1b.readers.v1.ReadMessagesReq\x1a\x19.readers.v1.ReadGroupsRes\"\x00B3Z1github.com/absmach/magistrala/api/grpc/readers/v1b\x06proto3"
 
 var (
 	file_readers_v1_readers_proto_rawDescOnce sync.Once
@@ -795,7 +1016,7 @@ func file_readers_v1_readers_proto_rawDescGZIP() []byte {
 }
 
 var file_readers_v1_readers_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_readers_v1_readers_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_readers_v1_readers_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_readers_v1_readers_proto_goTypes = []any{
 	(Aggregation)(0),        // 0: readers.v1.Aggregation
 	(*PageMetadata)(nil),    // 1: readers.v1.PageMetadata
@@ -805,23 +1026,34 @@ var file_readers_v1_readers_proto_goTypes = []any{
 	(*SenMLMessage)(nil),    // 5: readers.v1.SenMLMessage
 	(*JsonMessage)(nil),     // 6: readers.v1.JsonMessage
 	(*ReadMessagesReq)(nil), // 7: readers.v1.ReadMessagesReq
+	(*MessagesGroup)(nil),   // 8: readers.v1.MessagesGroup
+	(*ReadGroupsRes)(nil),   // 9: readers.v1.ReadGroupsRes
 }
 var file_readers_v1_readers_proto_depIdxs = []int32{
-	0, // 0: readers.v1.PageMetadata.aggregation:type_name -> readers.v1.Aggregation
-	1, // 1: readers.v1.ReadMessagesRes.page_metadata:type_name -> readers.v1.PageMetadata
-	3, // 2: readers.v1.ReadMessagesRes.messages:type_name -> readers.v1.Message
-	5, // 3: readers.v1.Message.senml:type_name -> readers.v1.SenMLMessage
-	6, // 4: readers.v1.Message.json:type_name -> readers.v1.JsonMessage
-	4, // 5: readers.v1.SenMLMessage.base:type_name -> readers.v1.BaseMessage
-	4, // 6: readers.v1.JsonMessage.base:type_name -> readers.v1.BaseMessage
-	1, // 7: readers.v1.ReadMessagesReq.page_metadata:type_name -> readers.v1.PageMetadata
-	7, // 8: readers.v1.ReadersService.ReadMessages:input_type -> readers.v1.ReadMessagesReq
-	2, // 9: readers.v1.ReadersService.ReadMessages:output_type -> readers.v1.ReadMessagesRes
-	9, // [9:10] is the sub-list for method output_type
-	8, // [8:9] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	0,  // 0: readers.v1.PageMetadata.aggregation:type_name -> readers.v1.Aggregation
+	1,  // 1: readers.v1.ReadMessagesRes.page_metadata:type_name -> readers.v1.PageMetadata
+	3,  // 2: readers.v1.ReadMessagesRes.messages:type_name -> readers.v1.Message
+	5,  // 3: readers.v1.Message.senml:type_name -> readers.v1.SenMLMessage
+	6,  // 4: readers.v1.Message.json:type_name -> readers.v1.JsonMessage
+	4,  // 5: readers.v1.SenMLMessage.base:type_name -> readers.v1.BaseMessage
+	4,  // 6: readers.v1.JsonMessage.base:type_name -> readers.v1.BaseMessage
+	1,  // 7: readers.v1.ReadMessagesReq.page_metadata:type_name -> readers.v1.PageMetadata
+	3,  // 8: readers.v1.MessagesGroup.messages:type_name -> readers.v1.Message
+	1,  // 9: readers.v1.ReadGroupsRes.page_metadata:type_name -> readers.v1.PageMetadata
+	8,  // 10: readers.v1.ReadGroupsRes.groups:type_name -> readers.v1.MessagesGroup
+	7,  // 11: readers.v1.ReadersService.ReadMessages:input_type -> readers.v1.ReadMessagesReq
+	7,  // 12: readers.v1.ReadersService.ReadLatest:input_type -> readers.v1.ReadMessagesReq
+	7,  // 13: readers.v1.ReadersService.StreamMessages:input_type -> readers.v1.ReadMessagesReq
+	7,  // 14: readers.v1.ReadersService.ReadGroups:input_type -> readers.v1.ReadMessagesReq
+	2,  // 15: readers.v1.ReadersService.ReadMessages:output_type -> readers.v1.ReadMessagesRes
+	2,  // 16: readers.v1.ReadersService.ReadLatest:output_type -> readers.v1.ReadMessagesRes
+	2,  // 17: readers.v1.ReadersService.StreamMessages:output_type -> readers.v1.ReadMessagesRes
+	9,  // 18: readers.v1.ReadersService.ReadGroups:output_type -> readers.v1.ReadGroupsRes
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_readers_v1_readers_proto_init() }
@@ -840,7 +1072,7 @@ func file_readers_v1_readers_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_readers_v1_readers_proto_rawDesc), len(file_readers_v1_readers_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,7 +22,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ReadersService_ReadMessages_FullMethodName = "/readers.v1.ReadersService/ReadMessages"
+	ReadersService_ReadMessages_FullMethodName   = "/readers.v1.ReadersService/ReadMessages"
+	ReadersService_ReadLatest_FullMethodName     = "/readers.v1.ReadersService/ReadLatest"
+	ReadersService_StreamMessages_FullMethodName = "/readers.v1.ReadersService/StreamMessages"
+	ReadersService_ReadGroups_FullMethodName     = "/readers.v1.ReadersService/ReadGroups"
 )
 
 // ReadersServiceClient is the client API for ReadersService service.
@@ -33,6 +36,9 @@ const (
 // readers functionalities for Magistrala services.
 type ReadersServiceClient interface {
 	ReadMessages(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (*ReadMessagesRes, error)
+	ReadLatest(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (*ReadMessagesRes, error)
+	StreamMessages(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadMessagesRes], error)
+	ReadGroups(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (*ReadGroupsRes, error)
 }
 
 type readersServiceClient struct {
@@ -53,6 +59,45 @@ func (c *readersServiceClient) ReadMessages(ctx context.Context, in *ReadMessage
 	return out, nil
 }
 
+func (c *readersServiceClient) ReadLatest(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (*ReadMessagesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadMessagesRes)
+	err := c.cc.Invoke(ctx, ReadersService_ReadLatest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readersServiceClient) StreamMessages(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadMessagesRes], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReadersService_ServiceDesc.Streams[0], ReadersService_StreamMessages_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadMessagesReq, ReadMessagesRes]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadersService_StreamMessagesClient = grpc.ServerStreamingClient[ReadMessagesRes]
+
+func (c *readersServiceClient) ReadGroups(ctx context.Context, in *ReadMessagesReq, opts ...grpc.CallOption) (*ReadGroupsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadGroupsRes)
+	err := c.cc.Invoke(ctx, ReadersService_ReadGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReadersServiceServer is the server API for ReadersService service.
 // All implementations must embed UnimplementedReadersServiceServer
 // for forward compatibility.
@@ -61,6 +106,9 @@ func (c *readersServiceClient) ReadMessages(ctx context.Context, in *ReadMessage
 // readers functionalities for Magistrala services.
 type ReadersServiceServer interface {
 	ReadMessages(context.Context, *ReadMessagesReq) (*ReadMessagesRes, error)
+	ReadLatest(context.Context, *ReadMessagesReq) (*ReadMessagesRes, error)
+	StreamMessages(*ReadMessagesReq, grpc.ServerStreamingServer[ReadMessagesRes]) error
+	ReadGroups(context.Context, *ReadMessagesReq) (*ReadGroupsRes, error)
 	mustEmbedUnimplementedReadersServiceServer()
 }
 
@@ -74,6 +122,15 @@ type UnimplementedReadersServiceServer struct{}
 func (UnimplementedReadersServiceServer) ReadMessages(context.Context, *ReadMessagesReq) (*ReadMessagesRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadMessages not implemented")
 }
+func (UnimplementedReadersServiceServer) ReadLatest(context.Context, *ReadMessagesReq) (*ReadMessagesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadLatest not implemented")
+}
+func (UnimplementedReadersServiceServer) StreamMessages(*ReadMessagesReq, grpc.ServerStreamingServer[ReadMessagesRes]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMessages not implemented")
+}
+func (UnimplementedReadersServiceServer) ReadGroups(context.Context, *ReadMessagesReq) (*ReadGroupsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadGroups not implemented")
+}
 func (UnimplementedReadersServiceServer) mustEmbedUnimplementedReadersServiceServer() {}
 func (UnimplementedReadersServiceServer) testEmbeddedByValue()                        {}
 
@@ -113,6 +170,53 @@ func _ReadersService_ReadMessages_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ReadersService_ReadLatest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMessagesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadersServiceServer).ReadLatest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadersService_ReadLatest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadersServiceServer).ReadLatest(ctx, req.(*ReadMessagesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadersService_StreamMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadMessagesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReadersServiceServer).StreamMessages(m, &grpc.GenericServerStream[ReadMessagesReq, ReadMessagesRes]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadersService_StreamMessagesServer = grpc.ServerStreamingServer[ReadMessagesRes]
+
+func _ReadersService_ReadGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMessagesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadersServiceServer).ReadGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadersService_ReadGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadersServiceServer).ReadGroups(ctx, req.(*ReadMessagesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ReadersService_ServiceDesc is the grpc.ServiceDesc for ReadersService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -124,7 +228,21 @@ var ReadersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReadMessages",
 			Handler:    _ReadersService_ReadMessages_Handler,
 		},
+		{
+			MethodName: "ReadLatest",
+			Handler:    _ReadersService_ReadLatest_Handler,
+		},
+		{
+			MethodName: "ReadGroups",
+			Handler:    _ReadersService_ReadGroups_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMessages",
+			Handler:       _ReadersService_StreamMessages_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "readers/v1/readers.proto",
 }
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/absmach/magistrala/alarms"
 	httpAPI "github.com/absmach/magistrala/alarms/api"
@@ -44,11 +45,14 @@ const (
 )
 
 type config struct {
-	LogLevel   string  `env:"MG_ALARMS_LOG_LEVEL"    envDefault:"info"`
-	BrokerURL  string  `env:"SMQ_MESSAGE_BROKER_URL" envDefault:"nats://localhost:4222"`
-	InstanceID string  `env:"MG_ALARMS_INSTANCE_ID"  envDefault:""`
-	JaegerURL  url.URL `env:"SMQ_JAEGER_URL"         envDefault:"http://localhost:4318/v1/traces"`
-	TraceRatio float64 `env:"SMQ_JAEGER_TRACE_RATIO" envDefault:"1.0"`
+	LogLevel      string        `env:"MG_ALARMS_LOG_LEVEL"      envDefault:"info"`
+	BrokerURL     string        `env:"SMQ_MESSAGE_BROKER_URL"   envDefault:"nats://localhost:4222"`
+	InstanceID    string        `env:"MG_ALARMS_INSTANCE_ID"    envDefault:""`
+	JaegerURL     url.URL       `env:"SMQ_JAEGER_URL"           envDefault:"http://localhost:4318/v1/traces"`
+	TraceRatio    float64       `env:"SMQ_JAEGER_TRACE_RATIO"   envDefault:"1.0"`
+	FlapThreshold uint          `env:"MG_ALARMS_FLAP_THRESHOLD" envDefault:"5"`
+	FlapWindow    time.Duration `env:"MG_ALARMS_FLAP_WINDOW"    envDefault:"1m"`
+	StreamOrigins []string      `env:"MG_ALARMS_STREAM_ORIGINS" envSeparator:","`
 }
 
 func main() {
@@ -94,7 +98,7 @@ func main() {
 	}
 	defer db.Close()
 
-	repo := alarmsRepo.NewAlarmsRepo(db)
+	repo := alarmsRepo.NewAlarmsRepo(db, alarms.FlapConfig{Threshold: cfg.FlapThreshold, Window: cfg.FlapWindow})
 
 	authConfig := grpcclient.Config{}
 	if err := env.ParseWithOptions(&authConfig, env.Options{Prefix: envPrefixAuth}); err != nil {
@@ -152,7 +156,7 @@ func main() {
 		exitCode = 1
 		return
 	}
-	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpAPI.MakeHandler(svc, logger, idp, cfg.InstanceID, authn), logger)
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpAPI.MakeHandler(svc, logger, idp, cfg.InstanceID, authn, cfg.StreamOrigins), logger)
 
 	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, logger)
 	if err != nil {
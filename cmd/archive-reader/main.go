@@ -0,0 +1,231 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main contains archive-reader main function to start the archive-reader service.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	chclient "github.com/absmach/callhome/pkg/client"
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/magistrala/readers"
+	readersgrpcapi "github.com/absmach/magistrala/readers/api/grpc"
+	httpapi "github.com/absmach/magistrala/readers/api/http"
+	"github.com/absmach/magistrala/readers/archive"
+	middleware "github.com/absmach/magistrala/readers/middleware"
+	"github.com/absmach/supermq"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
+	"github.com/absmach/supermq/pkg/grpcclient"
+	"github.com/absmach/supermq/pkg/prometheus"
+	"github.com/absmach/supermq/pkg/server"
+	grpcserver "github.com/absmach/supermq/pkg/server/grpc"
+	httpserver "github.com/absmach/supermq/pkg/server/http"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/caarlos0/env/v11"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	svcName           = "archive-reader"
+	envPrefixBucket   = "MG_ARCHIVE_"
+	envPrefixHTTP     = "MG_ARCHIVE_READER_HTTP_"
+	envPrefixAuth     = "SMQ_AUTH_GRPC_"
+	envPrefixClients  = "SMQ_CLIENTS_GRPC_"
+	envPrefixChannels = "SMQ_CHANNELS_GRPC_"
+	envPrefixDomains  = "SMQ_DOMAINS_GRPC_"
+	defSvcHTTPPort    = "9018"
+	defSvcGRPCPort    = "7018"
+	envPrefixGrpc     = "MG_ARCHIVE_READER_GRPC_"
+)
+
+type config struct {
+	LogLevel      string `env:"MG_ARCHIVE_READER_LOG_LEVEL"   envDefault:"info"`
+	SendTelemetry bool   `env:"SMQ_SEND_TELEMETRY"            envDefault:"true"`
+	InstanceID    string `env:"MG_ARCHIVE_READER_INSTANCE_ID" envDefault:""`
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	g, ctx := errgroup.WithContext(ctx)
+
+	cfg := config{}
+	if err := env.Parse(&cfg); err != nil {
+		log.Fatalf("failed to load %s configuration : %s", svcName, err)
+	}
+
+	logger, err := smqlog.New(os.Stdout, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("failed to init logger: %s", err.Error())
+	}
+
+	var exitCode int
+	defer smqlog.ExitWithError(&exitCode)
+
+	if cfg.InstanceID == "" {
+		if cfg.InstanceID, err = uuid.New().ID(); err != nil {
+			logger.Error(fmt.Sprintf("failed to generate instanceID: %s", err))
+			exitCode = 1
+			return
+		}
+	}
+
+	bucketConfig := archiveclient.Config{}
+	if err := env.ParseWithOptions(&bucketConfig, env.Options{Prefix: envPrefixBucket}); err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	bucket, err := archiveclient.Connect(bucketConfig)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+
+	repo := newService(bucket, logger)
+
+	grpcServerConfig := server.Config{
+		Port: defSvcGRPCPort,
+	}
+	if err := env.ParseWithOptions(&grpcServerConfig, env.Options{Prefix: envPrefixGrpc}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s gRPC server configuration : %s", svcName, err.Error()))
+		exitCode = 1
+		return
+	}
+
+	clientsClientCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&clientsClientCfg, env.Options{Prefix: envPrefixClients}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	clientsClient, clientsHandler, err := grpcclient.SetupClientsClient(ctx, clientsClientCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer clientsHandler.Close()
+
+	logger.Info("Clients service gRPC client successfully connected to clients gRPC server " + clientsHandler.Secure())
+
+	channelsClientCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&channelsClientCfg, env.Options{Prefix: envPrefixChannels}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load channels gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	channelsClient, channelsHandler, err := grpcclient.SetupChannelsClient(ctx, channelsClientCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer channelsHandler.Close()
+	logger.Info("Channels service gRPC client successfully connected to channels gRPC server " + channelsHandler.Secure())
+
+	authnCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&authnCfg, env.Options{Prefix: envPrefixAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load auth gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	authn, authnHandler, err := authsvc.NewAuthentication(ctx, authnCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer authnHandler.Close()
+	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
+
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
+	httpServerConfig := server.Config{Port: defSvcHTTPPort}
+	if err := env.ParseWithOptions(&httpServerConfig, env.Options{Prefix: envPrefixHTTP}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s HTTP server configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(repo, authn, authz, clientsClient, channelsClient, svcName, cfg.InstanceID), logger)
+
+	if cfg.SendTelemetry {
+		chc := chclient.New(svcName, supermq.Version, logger, cancel)
+		go chc.CallHome(ctx)
+	}
+
+	grpcAuthConfig := readersgrpcapi.AuthConfig{}
+	if err := env.ParseWithOptions(&grpcAuthConfig, env.Options{Prefix: envPrefixGrpc}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s gRPC auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	grpcAuthz := readersgrpcapi.NewAuthorizer(grpcAuthConfig, authn, authz, clientsClient, channelsClient)
+	registerReadersServiceServer := func(srv *grpc.Server) {
+		reflection.Register(srv)
+		desc := readersgrpcapi.ServiceDesc(grpcAuthz.UnaryServerInterceptor(), grpcAuthz.StreamServerInterceptor())
+		srv.RegisterService(desc, readersgrpcapi.NewReadersServer(repo))
+	}
+
+	gs := grpcserver.NewServer(ctx, cancel, svcName, grpcServerConfig, registerReadersServiceServer, logger)
+
+	g.Go(func() error {
+		return gs.Start()
+	})
+
+	g.Go(func() error {
+		return hs.Start()
+	})
+
+	g.Go(func() error {
+		return server.StopSignalHandler(ctx, cancel, logger, svcName, hs)
+	})
+
+	if err := g.Wait(); err != nil {
+		logger.Error(fmt.Sprintf("Archive reader service terminated: %s", err))
+	}
+}
+
+func newService(bucket *archiveclient.Bucket, logger *slog.Logger) readers.MessageRepository {
+	svc := archive.New(bucket)
+	svc = middleware.LoggingMiddleware(svc, logger)
+	counter, latency := prometheus.MakeMetrics("archive", "message_reader")
+	svc = middleware.MetricsMiddleware(svc, counter, latency)
+
+	return svc
+}
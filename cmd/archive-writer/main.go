@@ -0,0 +1,242 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main contains archive-writer main function to start the archive-writer service.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/url"
+	"os"
+
+	chclient "github.com/absmach/callhome/pkg/client"
+	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
+	httpapi "github.com/absmach/magistrala/consumers/writers/api"
+	"github.com/absmach/magistrala/consumers/writers/archive"
+	"github.com/absmach/magistrala/consumers/writers/brokers"
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	quotaapi "github.com/absmach/magistrala/consumers/writers/quota/api"
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/magistrala/pkg/prometheus"
+	"github.com/absmach/supermq"
+	"github.com/absmach/supermq/consumers"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
+	"github.com/absmach/supermq/pkg/grpcclient"
+	jaegerclient "github.com/absmach/supermq/pkg/jaeger"
+	brokerstracing "github.com/absmach/supermq/pkg/messaging/brokers/tracing"
+	"github.com/absmach/supermq/pkg/server"
+	httpserver "github.com/absmach/supermq/pkg/server/http"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/caarlos0/env/v11"
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	svcName          = "archive-writer"
+	envPrefixBucket  = "MG_ARCHIVE_"
+	envPrefixHTTP    = "MG_ARCHIVE_WRITER_HTTP_"
+	envPrefixWriter  = "MG_ARCHIVE_WRITER_"
+	envPrefixQuota   = "MG_ARCHIVE_WRITER_QUOTA_"
+	envPrefixAuth    = "SMQ_AUTH_GRPC_"
+	envPrefixDomains = "SMQ_DOMAINS_GRPC_"
+	defSvcHTTPPort   = "9017"
+)
+
+type config struct {
+	LogLevel      string  `env:"MG_ARCHIVE_WRITER_LOG_LEVEL"   envDefault:"info"`
+	ConfigPath    string  `env:"MG_ARCHIVE_WRITER_CONFIG_PATH" envDefault:"/config.toml"`
+	BrokerURL     string  `env:"SMQ_MESSAGE_BROKER_URL"        envDefault:"nats://localhost:4222"`
+	JaegerURL     url.URL `env:"SMQ_JAEGER_URL"                envDefault:"http://localhost:4318/v1/traces"`
+	SendTelemetry bool    `env:"SMQ_SEND_TELEMETRY"            envDefault:"true"`
+	InstanceID    string  `env:"MG_ARCHIVE_WRITER_INSTANCE_ID" envDefault:""`
+	TraceRatio    float64 `env:"SMQ_JAEGER_TRACE_RATIO"        envDefault:"1.0"`
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	g, ctx := errgroup.WithContext(ctx)
+
+	cfg := config{}
+	if err := env.Parse(&cfg); err != nil {
+		log.Fatalf("failed to load %s service configuration : %s", svcName, err)
+	}
+
+	logger, err := smqlog.New(os.Stdout, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("failed to init logger: %s", err.Error())
+	}
+
+	var exitCode int
+	defer smqlog.ExitWithError(&exitCode)
+
+	if cfg.InstanceID == "" {
+		if cfg.InstanceID, err = uuid.New().ID(); err != nil {
+			logger.Error(fmt.Sprintf("failed to generate instanceID: %s", err))
+			exitCode = 1
+			return
+		}
+	}
+
+	httpServerConfig := server.Config{Port: defSvcHTTPPort}
+	if err := env.ParseWithOptions(&httpServerConfig, env.Options{Prefix: envPrefixHTTP}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s HTTP server configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	bucketConfig := archiveclient.Config{}
+	if err := env.ParseWithOptions(&bucketConfig, env.Options{Prefix: envPrefixBucket}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s object storage configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	bucket, err := archiveclient.Setup(ctx, bucketConfig)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+
+	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger: %s", err))
+		exitCode = 1
+		return
+	}
+	defer func() {
+		if err := tp.Shutdown(ctx); err != nil {
+			logger.Error(fmt.Sprintf("Error shutting down tracer provider: %v", err))
+		}
+	}()
+	tracer := tp.Tracer(svcName)
+
+	writerConfig := archive.Config{}
+	if err := env.ParseWithOptions(&writerConfig, env.Options{Prefix: envPrefixWriter}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := writerConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	quotaConfig := quota.Config{}
+	if err := env.ParseWithOptions(&quotaConfig, env.Options{Prefix: envPrefixQuota}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := quotaConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	// Dead letters are stored in Postgres, so they aren't available here.
+	if quotaConfig.Action == quota.ActionDeadLetter {
+		logger.Error(fmt.Sprintf("invalid %s quota configuration : %s", svcName, quota.ErrInvalidConfig))
+		exitCode = 1
+		return
+	}
+
+	repo := newService(bucket, writerConfig, logger)
+	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
+
+	quotaSvc := quota.New(quotaConfig)
+	messages, bytes, limited := prometheus.MakeUsageMetrics("archive", "message_writer")
+	consumer := quota.NewConsumer(repo, quotaSvc, quotaConfig, nil, messages, bytes, limited)
+
+	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, writerConfig.Batch.MaxPending(), logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to connect to message broker: %s", err))
+		exitCode = 1
+		return
+	}
+	defer pubSub.Close()
+	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
+
+	authnCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&authnCfg, env.Options{Prefix: envPrefixAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load auth gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	authn, authnHandler, err := authsvc.NewAuthentication(ctx, authnCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer authnHandler.Close()
+	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
+
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
+	if err = writers.Start(ctx, svcName, pubSub, consumer, cfg.ConfigPath, logger); err != nil {
+		logger.Error(fmt.Sprintf("failed to create archive writer: %s", err))
+		exitCode = 1
+		return
+	}
+
+	mux := chi.NewRouter()
+	mux.Mount("/usage", quotaapi.MakeHandler(quotaSvc, authn, authz, logger))
+	mux.Mount("/", httpapi.MakeHandler(svcName, cfg.InstanceID))
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, mux, logger)
+
+	if cfg.SendTelemetry {
+		chc := chclient.New(svcName, supermq.Version, logger, cancel)
+		go chc.CallHome(ctx)
+	}
+
+	g.Go(func() error {
+		return hs.Start()
+	})
+
+	g.Go(func() error {
+		return server.StopSignalHandler(ctx, cancel, logger, svcName, hs)
+	})
+
+	if err := g.Wait(); err != nil {
+		logger.Error(fmt.Sprintf("Archive writer service terminated: %s", err))
+	}
+}
+
+func newService(bucket *archiveclient.Bucket, writerConfig archive.Config, logger *slog.Logger) consumers.BlockingConsumer {
+	svc := archive.New(bucket, writerConfig, uuid.New())
+	svc = httpapi.LoggingMiddleware(svc, logger)
+	counter, latency := prometheus.MakeMetrics("archive", "message_writer")
+	svc = httpapi.MetricsMiddleware(svc, counter, latency)
+	return svc
+}
@@ -0,0 +1,229 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main contains clickhouse-reader main function to start the clickhouse-reader service.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	chclient "github.com/absmach/callhome/pkg/client"
+	clickhouseclient "github.com/absmach/magistrala/pkg/clickhouse"
+	"github.com/absmach/magistrala/readers"
+	readersgrpcapi "github.com/absmach/magistrala/readers/api/grpc"
+	httpapi "github.com/absmach/magistrala/readers/api/http"
+	"github.com/absmach/magistrala/readers/clickhouse"
+	middleware "github.com/absmach/magistrala/readers/middleware"
+	"github.com/absmach/supermq"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
+	"github.com/absmach/supermq/pkg/grpcclient"
+	"github.com/absmach/supermq/pkg/prometheus"
+	"github.com/absmach/supermq/pkg/server"
+	grpcserver "github.com/absmach/supermq/pkg/server/grpc"
+	httpserver "github.com/absmach/supermq/pkg/server/http"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/caarlos0/env/v11"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	svcName           = "clickhouse-reader"
+	envPrefixDB       = "MG_CLICKHOUSE_"
+	envPrefixHTTP     = "MG_CLICKHOUSE_READER_HTTP_"
+	envPrefixAuth     = "SMQ_AUTH_GRPC_"
+	envPrefixClients  = "SMQ_CLIENTS_GRPC_"
+	envPrefixChannels = "SMQ_CHANNELS_GRPC_"
+	envPrefixDomains  = "SMQ_DOMAINS_GRPC_"
+	defDB             = "messages"
+	defSvcHTTPPort    = "9015"
+	defSvcGRPCPort    = "7015"
+	envPrefixGrpc     = "MG_CLICKHOUSE_READER_GRPC_"
+)
+
+type config struct {
+	LogLevel      string `env:"MG_CLICKHOUSE_READER_LOG_LEVEL"    envDefault:"info"`
+	SendTelemetry bool   `env:"SMQ_SEND_TELEMETRY"               envDefault:"true"`
+	InstanceID    string `env:"MG_CLICKHOUSE_READER_INSTANCE_ID"  envDefault:""`
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	g, ctx := errgroup.WithContext(ctx)
+
+	cfg := config{}
+	if err := env.Parse(&cfg); err != nil {
+		log.Fatalf("failed to load %s configuration : %s", svcName, err)
+	}
+
+	logger, err := smqlog.New(os.Stdout, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("failed to init logger: %s", err.Error())
+	}
+
+	var exitCode int
+	defer smqlog.ExitWithError(&exitCode)
+
+	if cfg.InstanceID == "" {
+		if cfg.InstanceID, err = uuid.New().ID(); err != nil {
+			logger.Error(fmt.Sprintf("failed to generate instanceID: %s", err))
+			exitCode = 1
+			return
+		}
+	}
+
+	dbConfig := clickhouseclient.Config{Name: defDB}
+	if err := env.ParseWithOptions(&dbConfig, env.Options{Prefix: envPrefixDB}); err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	db := clickhouseclient.Connect(dbConfig)
+	defer db.Close()
+
+	repo := newService(db, logger)
+
+	grpcServerConfig := server.Config{
+		Port: defSvcGRPCPort,
+	}
+	if err := env.ParseWithOptions(&grpcServerConfig, env.Options{Prefix: envPrefixGrpc}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s gRPC server configuration : %s", svcName, err.Error()))
+		exitCode = 1
+		return
+	}
+
+	clientsClientCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&clientsClientCfg, env.Options{Prefix: envPrefixClients}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	clientsClient, clientsHandler, err := grpcclient.SetupClientsClient(ctx, clientsClientCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer clientsHandler.Close()
+
+	logger.Info("Clients service gRPC client successfully connected to clients gRPC server " + clientsHandler.Secure())
+
+	channelsClientCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&channelsClientCfg, env.Options{Prefix: envPrefixChannels}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load channels gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	channelsClient, channelsHandler, err := grpcclient.SetupChannelsClient(ctx, channelsClientCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer channelsHandler.Close()
+	logger.Info("Channels service gRPC client successfully connected to channels gRPC server " + channelsHandler.Secure())
+
+	authnCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&authnCfg, env.Options{Prefix: envPrefixAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load auth gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	authn, authnHandler, err := authsvc.NewAuthentication(ctx, authnCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer authnHandler.Close()
+	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
+
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
+	httpServerConfig := server.Config{Port: defSvcHTTPPort}
+	if err := env.ParseWithOptions(&httpServerConfig, env.Options{Prefix: envPrefixHTTP}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s HTTP server configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(repo, authn, authz, clientsClient, channelsClient, svcName, cfg.InstanceID), logger)
+
+	if cfg.SendTelemetry {
+		chc := chclient.New(svcName, supermq.Version, logger, cancel)
+		go chc.CallHome(ctx)
+	}
+
+	grpcAuthConfig := readersgrpcapi.AuthConfig{}
+	if err := env.ParseWithOptions(&grpcAuthConfig, env.Options{Prefix: envPrefixGrpc}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s gRPC auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	grpcAuthz := readersgrpcapi.NewAuthorizer(grpcAuthConfig, authn, authz, clientsClient, channelsClient)
+	registerReadersServiceServer := func(srv *grpc.Server) {
+		reflection.Register(srv)
+		desc := readersgrpcapi.ServiceDesc(grpcAuthz.UnaryServerInterceptor(), grpcAuthz.StreamServerInterceptor())
+		srv.RegisterService(desc, readersgrpcapi.NewReadersServer(repo))
+	}
+
+	gs := grpcserver.NewServer(ctx, cancel, svcName, grpcServerConfig, registerReadersServiceServer, logger)
+
+	g.Go(func() error {
+		return gs.Start()
+	})
+
+	g.Go(func() error {
+		return hs.Start()
+	})
+
+	g.Go(func() error {
+		return server.StopSignalHandler(ctx, cancel, logger, svcName, hs)
+	})
+
+	if err := g.Wait(); err != nil {
+		logger.Error(fmt.Sprintf("ClickHouse reader service terminated: %s", err))
+	}
+}
+
+func newService(db *sqlx.DB, logger *slog.Logger) readers.MessageRepository {
+	svc := clickhouse.New(db)
+	svc = middleware.LoggingMiddleware(svc, logger)
+	counter, latency := prometheus.MakeMetrics("clickhouse", "message_reader")
+	svc = middleware.MetricsMiddleware(svc, counter, latency)
+
+	return svc
+}
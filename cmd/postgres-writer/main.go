@@ -14,40 +14,61 @@ import (
 
 	chclient "github.com/absmach/callhome/pkg/client"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	httpapi "github.com/absmach/magistrala/consumers/writers/api"
 	"github.com/absmach/magistrala/consumers/writers/brokers"
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	dlapi "github.com/absmach/magistrala/consumers/writers/deadletter/api"
+	dlpostgres "github.com/absmach/magistrala/consumers/writers/deadletter/postgres"
 	writerpg "github.com/absmach/magistrala/consumers/writers/postgres"
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	quotaapi "github.com/absmach/magistrala/consumers/writers/quota/api"
+	"github.com/absmach/magistrala/consumers/writers/retention"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	schemasapi "github.com/absmach/magistrala/consumers/writers/schemas/api"
+	schemaspg "github.com/absmach/magistrala/consumers/writers/schemas/postgres"
+	"github.com/absmach/magistrala/pkg/prometheus"
 	"github.com/absmach/supermq"
 	"github.com/absmach/supermq/consumers"
 	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
+	"github.com/absmach/supermq/pkg/grpcclient"
 	jaegerclient "github.com/absmach/supermq/pkg/jaeger"
 	brokerstracing "github.com/absmach/supermq/pkg/messaging/brokers/tracing"
 	pgclient "github.com/absmach/supermq/pkg/postgres"
-	"github.com/absmach/supermq/pkg/prometheus"
 	"github.com/absmach/supermq/pkg/server"
 	httpserver "github.com/absmach/supermq/pkg/server/http"
 	"github.com/absmach/supermq/pkg/uuid"
 	"github.com/caarlos0/env/v11"
+	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
-	svcName        = "postgres-writer"
-	envPrefixDB    = "MG_POSTGRES_"
-	envPrefixHTTP  = "MG_POSTGRES_WRITER_HTTP_"
-	defDB          = "messages"
-	defSvcHTTPPort = "9010"
+	svcName            = "postgres-writer"
+	envPrefixDB        = "MG_POSTGRES_"
+	envPrefixHTTP      = "MG_POSTGRES_WRITER_HTTP_"
+	envPrefixWriter    = "MG_POSTGRES_WRITER_"
+	envPrefixDL        = "MG_POSTGRES_WRITER_DEADLETTER_"
+	envPrefixRetention = "MG_POSTGRES_WRITER_RETENTION_"
+	envPrefixQuota     = "MG_POSTGRES_WRITER_QUOTA_"
+	envPrefixAuth      = "SMQ_AUTH_GRPC_"
+	envPrefixDomains   = "SMQ_DOMAINS_GRPC_"
+	defDB              = "messages"
+	defSvcHTTPPort     = "9010"
 )
 
 type config struct {
-	LogLevel      string  `env:"MG_POSTGRES_WRITER_LOG_LEVEL"     envDefault:"info"`
-	ConfigPath    string  `env:"MG_POSTGRES_WRITER_CONFIG_PATH"   envDefault:"/config.toml"`
-	BrokerURL     string  `env:"SMQ_MESSAGE_BROKER_URL"            envDefault:"nats://localhost:4222"`
-	JaegerURL     url.URL `env:"SMQ_JAEGER_URL"                    envDefault:"http://localhost:4318/v1/traces"`
-	SendTelemetry bool    `env:"SMQ_SEND_TELEMETRY"                envDefault:"true"`
-	InstanceID    string  `env:"MG_POSTGRES_WRITER_INSTANCE_ID"   envDefault:""`
-	TraceRatio    float64 `env:"SMQ_JAEGER_TRACE_RATIO"            envDefault:"1.0"`
+	LogLevel      string  `env:"MG_POSTGRES_WRITER_LOG_LEVEL"   envDefault:"info"`
+	ConfigPath    string  `env:"MG_POSTGRES_WRITER_CONFIG_PATH" envDefault:"/config.toml"`
+	BrokerURL     string  `env:"SMQ_MESSAGE_BROKER_URL"         envDefault:"nats://localhost:4222"`
+	JaegerURL     url.URL `env:"SMQ_JAEGER_URL"                 envDefault:"http://localhost:4318/v1/traces"`
+	SendTelemetry bool    `env:"SMQ_SEND_TELEMETRY"             envDefault:"true"`
+	InstanceID    string  `env:"MG_POSTGRES_WRITER_INSTANCE_ID" envDefault:""`
+	TraceRatio    float64 `env:"SMQ_JAEGER_TRACE_RATIO"         envDefault:"1.0"`
 }
 
 func main() {
@@ -107,7 +128,19 @@ func main() {
 	}()
 	tracer := tp.Tracer(svcName)
 
-	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, logger)
+	writerConfig := writers.Config{}
+	if err := env.ParseWithOptions(&writerConfig, env.Options{Prefix: envPrefixWriter}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := writerConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, writerConfig.Batch.MaxPending(), logger)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to connect to message broker: %s", err))
 		exitCode = 1
@@ -116,16 +149,106 @@ func main() {
 	defer pubSub.Close()
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
-	repo := newService(db, logger)
+	dlConfig := deadletter.Config{}
+	if err := env.ParseWithOptions(&dlConfig, env.Options{Prefix: envPrefixDL}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s dead letter configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	quotaConfig := quota.Config{}
+	if err := env.ParseWithOptions(&quotaConfig, env.Options{Prefix: envPrefixQuota}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := quotaConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	retentionConfig := retention.Config{}
+	if err := env.ParseWithOptions(&retentionConfig, env.Options{Prefix: envPrefixRetention}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s retention configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	dlPub, err := brokers.NewDeadLetterPublisher(ctx, cfg.BrokerURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to connect to message broker for dead letters: %s", err))
+		exitCode = 1
+		return
+	}
+	defer dlPub.Close()
+	dlPub = brokerstracing.NewPublisher(httpServerConfig, tracer, dlPub)
+
+	repo := newService(db, writerConfig, logger)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
-	if err = consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	dlRepo := dlpostgres.New(db)
+	dlSvc := deadletter.New(dlRepo, repo)
+	quotaSvc := quota.New(quotaConfig)
+	messages, bytes, limited := prometheus.MakeUsageMetrics("postgres", "message_writer")
+	dl := deadletter.NewDeadLetterer(dlRepo, dlPub, uuid.New(), dlConfig, logger)
+	consumer := deadletter.NewConsumer(repo, dl, dlConfig)
+	consumer = quota.NewConsumer(consumer, quotaSvc, quotaConfig, dl, messages, bytes, limited)
+
+	authnCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&authnCfg, env.Options{Prefix: envPrefixAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load auth gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	authn, authnHandler, err := authsvc.NewAuthentication(ctx, authnCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer authnHandler.Close()
+	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
+
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
+	schemasSvc := schemas.New(schemaspg.New(db))
+
+	if err = writers.Start(ctx, svcName, pubSub, consumer, cfg.ConfigPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("failed to create Postgres writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(svcName, cfg.InstanceID), logger)
+	mux := chi.NewRouter()
+	mux.Mount("/deadletters", dlapi.MakeHandler(dlSvc, authn, authz, logger))
+	mux.Mount("/usage", quotaapi.MakeHandler(quotaSvc, authn, authz, logger))
+	mux.Mount("/schemas", schemasapi.MakeHandler(schemasSvc, authn, authz, logger))
+	mux.Mount("/", httpapi.MakeHandler(svcName, cfg.InstanceID))
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, mux, logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, supermq.Version, logger, cancel)
@@ -136,6 +259,10 @@ func main() {
 		return hs.Start()
 	})
 
+	g.Go(func() error {
+		return retention.Start(ctx, writerpg.NewRetention(db), retentionConfig, logger)
+	})
+
 	g.Go(func() error {
 		return server.StopSignalHandler(ctx, cancel, logger, svcName, hs)
 	})
@@ -145,8 +272,8 @@ func main() {
 	}
 }
 
-func newService(db *sqlx.DB, logger *slog.Logger) consumers.BlockingConsumer {
-	svc := writerpg.New(db)
+func newService(db *sqlx.DB, writerConfig writers.Config, logger *slog.Logger) consumers.BlockingConsumer {
+	svc := writerpg.New(db, writerConfig)
 	svc = httpapi.LoggingMiddleware(svc, logger)
 	counter, latency := prometheus.MakeMetrics("postgres", "message_writer")
 	svc = httpapi.MetricsMiddleware(svc, counter, latency)
@@ -70,6 +70,7 @@ type config struct {
 	CacheKeyDuration time.Duration `env:"MG_RE_CACHE_KEY_DURATION"  envDefault:"10m"`
 	TraceRatio       float64       `env:"SMQ_JAEGER_TRACE_RATIO"     envDefault:"1.0"`
 	BrokerURL        string        `env:"SMQ_MESSAGE_BROKER_URL"     envDefault:"nats://localhost:4222"`
+	ReadersKey       string        `env:"MG_RE_READERS_SERVICE_KEY"  envDefault:""`
 }
 
 func main() {
@@ -230,7 +231,7 @@ func main() {
 	}
 	defer client.Close()
 
-	readersClient := grpcClient.NewReadersClient(client.Connection(), regrpcCfg.Timeout)
+	readersClient := grpcClient.NewReadersClient(client.Connection(), regrpcCfg.Timeout, cfg.ReadersKey)
 	logger.Info("Readers gRPC client successfully connected to readers gRPC server " + client.Secure())
 
 	svc, err := newService(database, runInfo, msgSub, writersPub, alarmsPub, authz, ec, logger, readersClient)
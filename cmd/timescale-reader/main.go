@@ -12,8 +12,8 @@ import (
 	"os"
 
 	chclient "github.com/absmach/callhome/pkg/client"
-	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
 	pgclient "github.com/absmach/magistrala/pkg/postgres"
+	"github.com/absmach/magistrala/readers"
 	readersgrpcapi "github.com/absmach/magistrala/readers/api/grpc"
 	httpapi "github.com/absmach/magistrala/readers/api/http"
 	middleware "github.com/absmach/magistrala/readers/middleware"
@@ -21,13 +21,14 @@ import (
 	"github.com/absmach/supermq"
 	smqlog "github.com/absmach/supermq/logger"
 	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
 	"github.com/absmach/supermq/pkg/grpcclient"
 	"github.com/absmach/supermq/pkg/prometheus"
 	"github.com/absmach/supermq/pkg/server"
 	grpcserver "github.com/absmach/supermq/pkg/server/grpc"
 	httpserver "github.com/absmach/supermq/pkg/server/http"
 	"github.com/absmach/supermq/pkg/uuid"
-	"github.com/absmach/supermq/readers"
 	"github.com/caarlos0/env/v11"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/sync/errgroup"
@@ -42,6 +43,7 @@ const (
 	envPrefixAuth     = "SMQ_AUTH_GRPC_"
 	envPrefixClients  = "SMQ_CLIENTS_GRPC_"
 	envPrefixChannels = "SMQ_CHANNELS_GRPC_"
+	envPrefixDomains  = "SMQ_DOMAINS_GRPC_"
 	defDB             = "messages"
 	defSvcHTTPPort    = "9011"
 	defSvcGRPCPort    = "7011"
@@ -101,10 +103,6 @@ func main() {
 		exitCode = 1
 		return
 	}
-	registerReadersServiceServer := func(srv *grpc.Server) {
-		reflection.Register(srv)
-		grpcReadersV1.RegisterReadersServiceServer(srv, readersgrpcapi.NewReadersServer(repo))
-	}
 
 	clientsClientCfg := grpcclient.Config{}
 	if err := env.ParseWithOptions(&clientsClientCfg, env.Options{Prefix: envPrefixClients}); err != nil {
@@ -155,19 +153,56 @@ func main() {
 	defer authnHandler.Close()
 	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
 
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
 	httpServerConfig := server.Config{Port: defSvcHTTPPort}
 	if err := env.ParseWithOptions(&httpServerConfig, env.Options{Prefix: envPrefixHTTP}); err != nil {
 		logger.Error(fmt.Sprintf("failed to load %s HTTP server configuration : %s", svcName, err))
 		exitCode = 1
 		return
 	}
-	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(repo, authn, clientsClient, channelsClient, svcName, cfg.InstanceID), logger)
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(repo, authn, authz, clientsClient, channelsClient, svcName, cfg.InstanceID), logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, supermq.Version, logger, cancel)
 		go chc.CallHome(ctx)
 	}
 
+	grpcAuthConfig := readersgrpcapi.AuthConfig{}
+	if err := env.ParseWithOptions(&grpcAuthConfig, env.Options{Prefix: envPrefixGrpc}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s gRPC auth configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	grpcAuthz := readersgrpcapi.NewAuthorizer(grpcAuthConfig, authn, authz, clientsClient, channelsClient)
+	registerReadersServiceServer := func(srv *grpc.Server) {
+		reflection.Register(srv)
+		desc := readersgrpcapi.ServiceDesc(grpcAuthz.UnaryServerInterceptor(), grpcAuthz.StreamServerInterceptor())
+		srv.RegisterService(desc, readersgrpcapi.NewReadersServer(repo))
+	}
+
 	gs := grpcserver.NewServer(ctx, cancel, svcName, grpcServerConfig, registerReadersServiceServer, logger)
 
 	g.Go(func() error {
@@ -11,43 +11,63 @@ import (
 	"log/slog"
 	"net/url"
 	"os"
+	"time"
 
 	chclient "github.com/absmach/callhome/pkg/client"
 	consumertracing "github.com/absmach/magistrala/consumers/tracing"
+	"github.com/absmach/magistrala/consumers/writers"
 	httpapi "github.com/absmach/magistrala/consumers/writers/api"
 	"github.com/absmach/magistrala/consumers/writers/brokers"
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	dlapi "github.com/absmach/magistrala/consumers/writers/deadletter/api"
+	dlpostgres "github.com/absmach/magistrala/consumers/writers/deadletter/postgres"
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	quotaapi "github.com/absmach/magistrala/consumers/writers/quota/api"
+	"github.com/absmach/magistrala/consumers/writers/retention"
 	"github.com/absmach/magistrala/consumers/writers/timescale"
 	pgclient "github.com/absmach/magistrala/pkg/postgres"
+	"github.com/absmach/magistrala/pkg/prometheus"
 	"github.com/absmach/supermq"
 	"github.com/absmach/supermq/consumers"
 	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/authn/authsvc"
+	authsvcAuthz "github.com/absmach/supermq/pkg/authz/authsvc"
+	domainsAuthz "github.com/absmach/supermq/pkg/domains/grpcclient"
+	"github.com/absmach/supermq/pkg/grpcclient"
 	jaegerclient "github.com/absmach/supermq/pkg/jaeger"
 	brokerstracing "github.com/absmach/supermq/pkg/messaging/brokers/tracing"
-	"github.com/absmach/supermq/pkg/prometheus"
 	"github.com/absmach/supermq/pkg/server"
 	httpserver "github.com/absmach/supermq/pkg/server/http"
 	"github.com/absmach/supermq/pkg/uuid"
 	"github.com/caarlos0/env/v11"
+	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
-	svcName        = "timescaledb-writer"
-	envPrefixDB    = "MG_TIMESCALE_"
-	envPrefixHTTP  = "MG_TIMESCALE_WRITER_HTTP_"
-	defDB          = "messages"
-	defSvcHTTPPort = "9012"
+	svcName            = "timescaledb-writer"
+	envPrefixDB        = "MG_TIMESCALE_"
+	envPrefixHTTP      = "MG_TIMESCALE_WRITER_HTTP_"
+	envPrefixWriter    = "MG_TIMESCALE_WRITER_"
+	envPrefixDL        = "MG_TIMESCALE_WRITER_DEADLETTER_"
+	envPrefixRetention = "MG_TIMESCALE_WRITER_RETENTION_"
+	envPrefixQuota     = "MG_TIMESCALE_WRITER_QUOTA_"
+	envPrefixAuth      = "SMQ_AUTH_GRPC_"
+	envPrefixDomains   = "SMQ_DOMAINS_GRPC_"
+	defDB              = "messages"
+	defSvcHTTPPort     = "9012"
 )
 
 type config struct {
-	LogLevel      string  `env:"MG_TIMESCALE_WRITER_LOG_LEVEL"     envDefault:"info"`
-	ConfigPath    string  `env:"MG_TIMESCALE_WRITER_CONFIG_PATH"   envDefault:"/config.toml"`
-	BrokerURL     string  `env:"SMQ_MESSAGE_BROKER_URL"            envDefault:"nats://localhost:4222"`
-	JaegerURL     url.URL `env:"SMQ_JAEGER_URL"                    envDefault:"http://localhost:4318/v1/traces"`
-	SendTelemetry bool    `env:"SMQ_SEND_TELEMETRY"                envDefault:"true"`
-	InstanceID    string  `env:"MG_TIMESCALE_WRITER_INSTANCE_ID"   envDefault:""`
-	TraceRatio    float64 `env:"SMQ_JAEGER_TRACE_RATIO"            envDefault:"1.0"`
+	LogLevel      string        `env:"MG_TIMESCALE_WRITER_LOG_LEVEL"      envDefault:"info"`
+	ConfigPath    string        `env:"MG_TIMESCALE_WRITER_CONFIG_PATH"    envDefault:"/config.toml"`
+	BrokerURL     string        `env:"SMQ_MESSAGE_BROKER_URL"             envDefault:"nats://localhost:4222"`
+	JaegerURL     url.URL       `env:"SMQ_JAEGER_URL"                     envDefault:"http://localhost:4318/v1/traces"`
+	SendTelemetry bool          `env:"SMQ_SEND_TELEMETRY"                 envDefault:"true"`
+	InstanceID    string        `env:"MG_TIMESCALE_WRITER_INSTANCE_ID"    envDefault:""`
+	CompressAfter time.Duration `env:"MG_TIMESCALE_WRITER_COMPRESS_AFTER" envDefault:"0s"`
+	TraceRatio    float64       `env:"SMQ_JAEGER_TRACE_RATIO"             envDefault:"1.0"`
 }
 
 func main() {
@@ -96,6 +116,14 @@ func main() {
 	}
 	defer db.Close()
 
+	if cfg.CompressAfter > 0 {
+		if err := timescale.Compress(ctx, db, cfg.CompressAfter); err != nil {
+			logger.Error(err.Error())
+			exitCode = 1
+			return
+		}
+	}
+
 	tp, err := jaegerclient.NewProvider(ctx, svcName, cfg.JaegerURL, cfg.InstanceID, cfg.TraceRatio)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to init Jaeger: %s", err))
@@ -109,10 +137,65 @@ func main() {
 	}()
 	tracer := tp.Tracer(svcName)
 
-	repo := newService(db, logger)
+	writerConfig := writers.Config{}
+	if err := env.ParseWithOptions(&writerConfig, env.Options{Prefix: envPrefixWriter}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := writerConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s writer configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	dlConfig := deadletter.Config{}
+	if err := env.ParseWithOptions(&dlConfig, env.Options{Prefix: envPrefixDL}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s dead letter configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	quotaConfig := quota.Config{}
+	if err := env.ParseWithOptions(&quotaConfig, env.Options{Prefix: envPrefixQuota}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+	if err := quotaConfig.Validate(); err != nil {
+		logger.Error(fmt.Sprintf("invalid %s quota configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	retentionConfig := retention.Config{}
+	if err := env.ParseWithOptions(&retentionConfig, env.Options{Prefix: envPrefixRetention}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load %s retention configuration : %s", svcName, err))
+		exitCode = 1
+		return
+	}
+
+	dlPub, err := brokers.NewDeadLetterPublisher(ctx, cfg.BrokerURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to connect to message broker for dead letters: %s", err))
+		exitCode = 1
+		return
+	}
+	defer dlPub.Close()
+	dlPub = brokerstracing.NewPublisher(httpServerConfig, tracer, dlPub)
+
+	repo := newService(db, writerConfig, logger)
 	repo = consumertracing.NewBlocking(tracer, repo, httpServerConfig)
 
-	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, logger)
+	dlRepo := dlpostgres.New(db)
+	dlSvc := deadletter.New(dlRepo, repo)
+	quotaSvc := quota.New(quotaConfig)
+	messages, bytes, limited := prometheus.MakeUsageMetrics("timescale", "message_writer")
+	dl := deadletter.NewDeadLetterer(dlRepo, dlPub, uuid.New(), dlConfig, logger)
+	consumer := deadletter.NewConsumer(repo, dl, dlConfig)
+	consumer = quota.NewConsumer(consumer, quotaSvc, quotaConfig, dl, messages, bytes, limited)
+
+	pubSub, err := brokers.NewPubSub(ctx, cfg.BrokerURL, writerConfig.Batch.MaxPending(), logger)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to connect to message broker: %s", err))
 		exitCode = 1
@@ -121,13 +204,57 @@ func main() {
 	defer pubSub.Close()
 	pubSub = brokerstracing.NewPubSub(httpServerConfig, tracer, pubSub)
 
-	if err = consumers.Start(ctx, svcName, pubSub, repo, cfg.ConfigPath, logger); err != nil {
+	authnCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&authnCfg, env.Options{Prefix: envPrefixAuth}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load auth gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	authn, authnHandler, err := authsvc.NewAuthentication(ctx, authnCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer authnHandler.Close()
+	logger.Info("authn successfully connected to auth gRPC server " + authnHandler.Secure())
+
+	domsGrpcCfg := grpcclient.Config{}
+	if err := env.ParseWithOptions(&domsGrpcCfg, env.Options{Prefix: envPrefixDomains}); err != nil {
+		logger.Error(fmt.Sprintf("failed to load domains gRPC client configuration : %s", err))
+		exitCode = 1
+		return
+	}
+
+	domAuthz, _, domainsHandler, err := domainsAuthz.NewAuthorization(ctx, domsGrpcCfg)
+	if err != nil {
+		logger.Error(err.Error())
+		exitCode = 1
+		return
+	}
+	defer domainsHandler.Close()
+
+	authz, authzHandler, err := authsvcAuthz.NewAuthorization(ctx, authnCfg, domAuthz)
+	if err != nil {
+		logger.Error("failed to create authz " + err.Error())
+		exitCode = 1
+		return
+	}
+	defer authzHandler.Close()
+	logger.Info("authz successfully connected to auth gRPC server " + authzHandler.Secure())
+
+	if err = writers.Start(ctx, svcName, pubSub, consumer, cfg.ConfigPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("failed to create Timescale writer: %s", err))
 		exitCode = 1
 		return
 	}
 
-	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, httpapi.MakeHandler(svcName, cfg.InstanceID), logger)
+	mux := chi.NewRouter()
+	mux.Mount("/deadletters", dlapi.MakeHandler(dlSvc, authn, authz, logger))
+	mux.Mount("/usage", quotaapi.MakeHandler(quotaSvc, authn, authz, logger))
+	mux.Mount("/", httpapi.MakeHandler(svcName, cfg.InstanceID))
+	hs := httpserver.NewServer(ctx, cancel, svcName, httpServerConfig, mux, logger)
 
 	if cfg.SendTelemetry {
 		chc := chclient.New(svcName, supermq.Version, logger, cancel)
@@ -138,6 +265,10 @@ func main() {
 		return hs.Start()
 	})
 
+	g.Go(func() error {
+		return retention.Start(ctx, timescale.NewRetention(db), retentionConfig, logger)
+	})
+
 	g.Go(func() error {
 		return server.StopSignalHandler(ctx, cancel, logger, svcName, hs)
 	})
@@ -147,8 +278,8 @@ func main() {
 	}
 }
 
-func newService(db *sqlx.DB, logger *slog.Logger) consumers.BlockingConsumer {
-	svc := timescale.New(db)
+func newService(db *sqlx.DB, writerConfig writers.Config, logger *slog.Logger) consumers.BlockingConsumer {
+	svc := timescale.New(db, writerConfig)
 	svc = httpapi.LoggingMiddleware(svc, logger)
 	counter, latency := prometheus.MakeMetrics("timescale", "message_writer")
 	svc = httpapi.MetricsMiddleware(svc, counter, latency)
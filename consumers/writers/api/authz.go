@@ -0,0 +1,34 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	api "github.com/absmach/supermq/api/http"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/absmach/supermq/pkg/policies"
+)
+
+// AuthorizeMember checks that the user of the request session is a member of
+// the domain of the request. The session is set by the authentication
+// middleware of the writer APIs.
+func AuthorizeMember(ctx context.Context, authz smqauthz.Authorization) error {
+	session, ok := ctx.Value(api.SessionKey).(smqauthn.Session)
+	if !ok {
+		return svcerr.ErrAuthorization
+	}
+
+	return authz.Authorize(ctx, smqauthz.PolicyReq{
+		Domain:      session.DomainID,
+		SubjectType: policies.UserType,
+		SubjectKind: policies.UsersKind,
+		Subject:     session.DomainUserID,
+		Object:      session.DomainID,
+		ObjectType:  policies.DomainType,
+		Permission:  policies.MembershipPermission,
+	})
+}
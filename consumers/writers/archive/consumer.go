@@ -0,0 +1,247 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/supermq"
+	"github.com/absmach/supermq/consumers"
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/minio/minio-go/v7"
+)
+
+// Codes of S3 errors of failures that may not happen again, so saving the
+// messages is worth retrying.
+const (
+	codeSlowDown       = "SlowDown"
+	codeRequestTimeout = "RequestTimeout"
+)
+
+var (
+	errSaveMessage   = errors.New("failed to save message to archive")
+	errInvalidFormat = errors.New("invalid message format")
+)
+
+// Config contains archive writer configuration.
+type Config struct {
+	Batch      batch.Config `envPrefix:"BATCH_"`
+	FileFormat string       `env:"FILE_FORMAT" envDefault:"ndjson"`
+}
+
+// Validate validates the archive writer configuration.
+func (cfg Config) Validate() error {
+	if !archiveclient.ValidFileFormat(cfg.FileFormat) {
+		return archiveclient.ErrInvalidFileFormat
+	}
+
+	return nil
+}
+
+var _ consumers.BlockingConsumer = (*archiveRepo)(nil)
+
+type archiveRepo struct {
+	bucket     *archiveclient.Bucket
+	fileFormat string
+	idp        supermq.IDProvider
+	senml      *batch.Batcher[record[archiveclient.SenMLRecord]]
+	json       *batch.Batcher[record[archiveclient.JSONRecord]]
+}
+
+// record is the archived row of a message with the partition it is
+// archived to.
+type record[T any] struct {
+	partition archiveclient.Partition
+	id        string
+	time      float64
+	row       T
+}
+
+// New returns new archive writer. Records of pending messages are buffered,
+// as configured by the batch config, and the records of each partition are
+// stored in a single data file, so the batch interval sets how many files the
+// archive holds. Writers handle up to the batch size of messages at once, as
+// returned by batch.Config.MaxPending, and acknowledge them once their files
+// are stored.
+//
+// Files are stored before their manifest entries, so readers never read the
+// files of failed saves. Records of messages the broker redelivers once
+// their files are stored are archived again, so readers deduplicate records
+// by their ID.
+func New(bucket *archiveclient.Bucket, cfg Config, idp supermq.IDProvider) consumers.BlockingConsumer {
+	ar := &archiveRepo{
+		bucket:     bucket,
+		fileFormat: cfg.FileFormat,
+		idp:        idp,
+	}
+	ar.senml = batch.New(cfg.Batch, flush[archiveclient.SenMLRecord](ar))
+	ar.json = batch.New(cfg.Batch, flush[archiveclient.JSONRecord](ar))
+
+	return ar
+}
+
+func (ar *archiveRepo) ConsumeBlocking(ctx context.Context, message interface{}) error {
+	switch m := message.(type) {
+	case smqjson.Messages:
+		return ar.addJSON(ctx, m)
+	default:
+		return ar.addSenml(ctx, m)
+	}
+}
+
+func (ar *archiveRepo) addSenml(ctx context.Context, messages interface{}) error {
+	msgs, ok := messages.([]senml.Message)
+	if !ok {
+		return errSaveMessage
+	}
+
+	domain := writers.Domain(ctx)
+	records := make([]record[archiveclient.SenMLRecord], 0, len(msgs))
+	for _, msg := range msgs {
+		id := writers.RecordID(msg.Channel, msg.Publisher, msg.Subtopic, msg.Name, strconv.FormatFloat(msg.Time, 'f', -1, 64))
+		records = append(records, record[archiveclient.SenMLRecord]{
+			partition: archiveclient.NewPartition(archiveclient.SenMLFormat, domain, msg.Channel, msg.Time),
+			id:        id,
+			time:      msg.Time,
+			row:       archiveclient.NewSenMLRecord(id, msg),
+		})
+	}
+
+	return ar.senml.Add(ctx, records)
+}
+
+func (ar *archiveRepo) addJSON(ctx context.Context, msgs smqjson.Messages) error {
+	format := strings.ToLower(msgs.Format)
+	if !archiveclient.ValidFormat(format) {
+		return errors.Wrap(errSaveMessage, errInvalidFormat)
+	}
+
+	domain := writers.Domain(ctx)
+	records := make([]record[archiveclient.JSONRecord], 0, len(msgs.Data))
+	for i, msg := range msgs.Data {
+		row, err := archiveclient.NewJSONRecord("", msg)
+		if err != nil {
+			return errors.Wrap(errSaveMessage, err)
+		}
+		id := writers.JSONRecordID(msg, i, row.Payload)
+		row.ID = id
+		records = append(records, record[archiveclient.JSONRecord]{
+			partition: archiveclient.NewPartition(format, domain, msg.Channel, float64(msg.Created)),
+			id:        id,
+			time:      float64(msg.Created),
+			row:       row,
+		})
+	}
+
+	return ar.json.Add(ctx, records)
+}
+
+// flush returns the function that stores the records of every partition in
+// a data file, and then the manifest entries of the files.
+func flush[T any](ar *archiveRepo) batch.FlushFunc[record[T]] {
+	return func(ctx context.Context, records []record[T]) error {
+		records = writers.Dedupe(records, func(r record[T]) string { return r.partition.Format + "\x00" + r.id })
+
+		var parts []archiveclient.Partition
+		groups := make(map[archiveclient.Partition][]record[T])
+		for _, r := range records {
+			if _, ok := groups[r.partition]; !ok {
+				parts = append(parts, r.partition)
+			}
+			groups[r.partition] = append(groups[r.partition], r)
+		}
+
+		entries := make([]archiveclient.Entry, 0, len(parts))
+		for _, p := range parts {
+			entry, err := putFile(ctx, ar, p, groups[p])
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+
+		for _, entry := range entries {
+			if err := ar.bucket.PutEntry(ctx, entry); err != nil {
+				return saveError(err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// putFile stores the data file of the records of the partition and returns
+// its manifest entry.
+func putFile[T any](ctx context.Context, ar *archiveRepo, p archiveclient.Partition, records []record[T]) (archiveclient.Entry, error) {
+	id, err := ar.idp.ID()
+	if err != nil {
+		return archiveclient.Entry{}, errors.Wrap(errSaveMessage, err)
+	}
+
+	rows := make([]T, 0, len(records))
+	minTime, maxTime := records[0].time, records[0].time
+	for _, r := range records {
+		rows = append(rows, r.row)
+		minTime = min(minTime, r.time)
+		maxTime = max(maxTime, r.time)
+	}
+
+	data, err := archiveclient.Encode(ar.fileFormat, rows)
+	if err != nil {
+		return archiveclient.Entry{}, errors.Wrap(errSaveMessage, err)
+	}
+
+	entry := archiveclient.Entry{
+		ID:         id,
+		Key:        p.DataKey(id, ar.fileFormat),
+		Format:     p.Format,
+		Domain:     p.Domain,
+		Channel:    p.Channel,
+		Date:       p.Date,
+		FileFormat: ar.fileFormat,
+		Records:    len(rows),
+		Size:       len(data),
+		MinTime:    minTime,
+		MaxTime:    maxTime,
+		Created:    time.Now().UTC(),
+	}
+	if err := ar.bucket.Put(ctx, entry.Key, data, archiveclient.ContentType(ar.fileFormat)); err != nil {
+		return archiveclient.Entry{}, saveError(err)
+	}
+
+	return entry, nil
+}
+
+// saveError wraps the error of saving messages, marking the errors that may
+// not happen again as transient, so the messages are retried.
+func saveError(err error) error {
+	if isTransient(err) {
+		err = errors.Wrap(writers.ErrTransient, err)
+	}
+
+	return errors.Wrap(errSaveMessage, err)
+}
+
+// isTransient reports whether the object storage failure may not happen
+// again, such as a lost connection, throttling or an unavailable server.
+func isTransient(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.Code == codeSlowDown, resp.Code == codeRequestTimeout:
+		return true
+	default:
+		return writers.IsTransient(err)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/archive"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	msgsNum  = 42
+	domainID = "domain"
+)
+
+var (
+	v        float64 = 5
+	stringV          = "value"
+	fileFmts         = []string{archiveclient.FileNDJSON, archiveclient.FileParquet}
+	idp              = uuid.New()
+	// 2025-06-30 23:59:40 UTC, so the messages are archived to two dates.
+	startTime = time.Date(2025, 6, 30, 23, 59, 40, 0, time.UTC)
+)
+
+func TestConfigValidate(t *testing.T) {
+	cases := []struct {
+		desc string
+		cfg  archive.Config
+		err  error
+	}{
+		{
+			desc: "validate NDJSON config",
+			cfg:  archive.Config{FileFormat: archiveclient.FileNDJSON},
+		},
+		{
+			desc: "validate Parquet config",
+			cfg:  archive.Config{FileFormat: archiveclient.FileParquet},
+		},
+		{
+			desc: "validate config with invalid file format",
+			cfg:  archive.Config{FileFormat: "csv"},
+			err:  archiveclient.ErrInvalidFileFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestSaveSenml(t *testing.T) {
+	for _, fileFmt := range fileFmts {
+		t.Run(fileFmt, func(t *testing.T) {
+			bucket := newBucket(t)
+			repo := archive.New(bucket, archive.Config{FileFormat: fileFmt}, idp)
+
+			chid, pubid := newID(t), newID(t)
+			var msgs []senml.Message
+			for i := 0; i < msgsNum; i++ {
+				msg := senml.Message{Channel: chid, Publisher: pubid, Name: "temperature", Unit: "C"}
+				if i%2 == 0 {
+					msg.Value = &v
+				} else {
+					msg.StringValue = &stringV
+				}
+				msg.Time = float64(startTime.Add(time.Duration(i) * time.Second).UnixNano())
+				msgs = append(msgs, msg)
+			}
+
+			err := repo.ConsumeBlocking(writers.WithDomain(context.Background(), domainID), msgs)
+			require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+			entries := readEntries(t, bucket)
+			require.Len(t, entries, 2, fmt.Sprintf("expected 2 manifest entries, got %d", len(entries)))
+
+			var stored []senml.Message
+			for _, e := range entries {
+				assert.Equal(t, archiveclient.SenMLFormat, e.Format, fmt.Sprintf("expected format %s, got %s", archiveclient.SenMLFormat, e.Format))
+				assert.Equal(t, domainID, e.Domain, fmt.Sprintf("expected domain %s, got %s", domainID, e.Domain))
+				assert.Equal(t, chid, e.Channel, fmt.Sprintf("expected channel %s, got %s", chid, e.Channel))
+				assert.Equal(t, fileFmt, e.FileFormat, fmt.Sprintf("expected file format %s, got %s", fileFmt, e.FileFormat))
+
+				data, err := bucket.Get(context.Background(), e.Key)
+				require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+				assert.Equal(t, e.Size, len(data), fmt.Sprintf("expected file size %d, got %d", e.Size, len(data)))
+
+				records, err := archiveclient.Decode[archiveclient.SenMLRecord](fileFmt, data)
+				require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+				assert.Len(t, records, e.Records, fmt.Sprintf("expected %d records, got %d", e.Records, len(records)))
+				for _, r := range records {
+					assert.True(t, r.Time >= e.MinTime && r.Time <= e.MaxTime, fmt.Sprintf("expected time %f within %f and %f", r.Time, e.MinTime, e.MaxTime))
+					assert.Equal(t, e.Date, time.Unix(0, int64(r.Time)).UTC().Format(time.DateOnly), fmt.Sprintf("expected record of date %s", e.Date))
+					stored = append(stored, r.Message())
+				}
+			}
+			assert.ElementsMatch(t, msgs, stored, "expected archived messages to match consumed messages")
+		})
+	}
+}
+
+func TestSaveJSON(t *testing.T) {
+	chid, pubid := newID(t), newID(t)
+	msg := json.Message{
+		Channel:   chid,
+		Publisher: pubid,
+		Created:   startTime.UnixNano(),
+		Subtopic:  "subtopic/format/some_json",
+		Protocol:  "mqtt",
+		Payload: map[string]interface{}{
+			"field_1": 123,
+			"field_2": "value",
+			"field_3": false,
+		},
+	}
+
+	cases := []struct {
+		desc    string
+		format  string
+		table   string
+		invalid bool
+	}{
+		{
+			desc:   "save JSON messages",
+			format: "some_json",
+			table:  "some_json",
+		},
+		{
+			desc:   "save JSON messages of uppercase format",
+			format: "Some_JSON",
+			table:  "some_json",
+		},
+		{
+			desc:    "save JSON messages of SenML format",
+			format:  archiveclient.SenMLFormat,
+			invalid: true,
+		},
+		{
+			desc:    "save JSON messages of invalid format",
+			format:  "some.json",
+			invalid: true,
+		},
+	}
+
+	for _, tc := range cases {
+		for _, fileFmt := range fileFmts {
+			t.Run(fmt.Sprintf("%s as %s", tc.desc, fileFmt), func(t *testing.T) {
+				bucket := newBucket(t)
+				repo := archive.New(bucket, archive.Config{FileFormat: fileFmt}, idp)
+
+				msgs := json.Messages{Format: tc.format}
+				for i := 0; i < msgsNum; i++ {
+					m := msg
+					m.Created += int64(i)
+					msgs.Data = append(msgs.Data, m)
+				}
+
+				err := repo.ConsumeBlocking(context.Background(), msgs)
+				entries := readEntries(t, bucket)
+				if tc.invalid {
+					assert.NotNil(t, err, fmt.Sprintf("%s: expected error, got nil", tc.desc))
+					assert.False(t, errors.Contains(err, writers.ErrTransient), fmt.Sprintf("%s: expected permanent error, got %s", tc.desc, err))
+					assert.Empty(t, entries, fmt.Sprintf("%s: expected no manifest entries", tc.desc))
+					return
+				}
+				require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+				require.Len(t, entries, 1, fmt.Sprintf("%s: expected 1 manifest entry, got %d", tc.desc, len(entries)))
+
+				e := entries[0]
+				assert.Equal(t, tc.table, e.Format, fmt.Sprintf("%s: expected format %s, got %s", tc.desc, tc.table, e.Format))
+				assert.Equal(t, msgsNum, e.Records, fmt.Sprintf("%s: expected %d records, got %d", tc.desc, msgsNum, e.Records))
+				assert.Equal(t, float64(msg.Created), e.MinTime, fmt.Sprintf("%s: expected min time %d, got %f", tc.desc, msg.Created, e.MinTime))
+				assert.Equal(t, float64(msg.Created+msgsNum-1), e.MaxTime, fmt.Sprintf("%s: expected max time %d, got %f", tc.desc, msg.Created+msgsNum-1, e.MaxTime))
+				assert.True(t, strings.HasPrefix(e.Key, "data/"+tc.table+"/domain=_/"), fmt.Sprintf("%s: expected key of message without domain, got %s", tc.desc, e.Key))
+
+				data, err := bucket.Get(context.Background(), e.Key)
+				require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+				records, err := archiveclient.Decode[archiveclient.JSONRecord](fileFmt, data)
+				require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+				require.Len(t, records, msgsNum, fmt.Sprintf("%s: expected %d records, got %d", tc.desc, msgsNum, len(records)))
+				assert.JSONEq(t, `{"field_1":123,"field_2":"value","field_3":false}`, string(records[0].Payload), fmt.Sprintf("%s: unexpected payload %s", tc.desc, records[0].Payload))
+			})
+		}
+	}
+}
+
+func TestSaveRedelivered(t *testing.T) {
+	bucket := newBucket(t)
+	cfg := archive.Config{
+		Batch:      batch.Config{Size: 1000, Interval: 100 * time.Millisecond},
+		FileFormat: archiveclient.FileParquet,
+	}
+	repo := archive.New(bucket, cfg, idp)
+
+	chid, pubid := newID(t), newID(t)
+	var msgs []senml.Message
+	for i := 0; i < msgsNum; i++ {
+		msgs = append(msgs, senml.Message{
+			Channel:   chid,
+			Publisher: pubid,
+			Name:      "temperature",
+			Value:     &v,
+			Time:      float64(startTime.Add(-time.Hour).Add(time.Duration(i) * time.Second).UnixNano()),
+		})
+	}
+
+	// Messages consumed concurrently are archived in the same file, where
+	// the records of redeliveries are deduplicated.
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.ConsumeBlocking(context.Background(), msgs)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	}
+
+	entries := readEntries(t, bucket)
+	require.Len(t, entries, 1, fmt.Sprintf("expected 1 manifest entry, got %d", len(entries)))
+	assert.Equal(t, msgsNum, entries[0].Records, fmt.Sprintf("expected %d records, got %d", msgsNum, entries[0].Records))
+}
+
+// newBucket returns the bucket of the test, which stores the objects under
+// a prefix of its own.
+func newBucket(t *testing.T) *archiveclient.Bucket {
+	cfg := bucketConfig
+	cfg.Prefix = newID(t)
+	bucket, err := archiveclient.Connect(cfg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return bucket
+}
+
+func readEntries(t *testing.T, bucket *archiveclient.Bucket) []archiveclient.Entry {
+	keys, err := bucket.List(context.Background(), "manifest/")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	var entries []archiveclient.Entry
+	for _, key := range keys {
+		e, err := bucket.GetEntry(context.Background(), key)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.Equal(t, e.Partition().EntryKey(e.ID), key, fmt.Sprintf("expected entry key %s, got %s", e.Partition().EntryKey(e.ID), key))
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func newID(t *testing.T) string {
+	id, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return id
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive contains the writer that archives messages to
+// S3-compatible object storage.
+package archive
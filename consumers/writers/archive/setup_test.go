@@ -0,0 +1,65 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive_test contains tests for the archive writer.
+package archive_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+var bucketConfig archiveclient.Config
+
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	container, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "minio/minio",
+		Tag:        "RELEASE.2025-04-22T22-12-26Z",
+		Cmd:        []string{"server", "/data"},
+		Env: []string{
+			"MINIO_ROOT_USER=test",
+			"MINIO_ROOT_PASSWORD=testtest",
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Fatalf("Could not start container: %s", err)
+	}
+
+	bucketConfig = archiveclient.Config{
+		Endpoint:  "localhost:" + container.GetPort("9000/tcp"),
+		AccessKey: "test",
+		SecretKey: "testtest",
+		Region:    "us-east-1",
+		Bucket:    "test",
+	}
+
+	if err := pool.Retry(func() error {
+		_, err := archiveclient.Setup(context.Background(), bucketConfig)
+		return err
+	}); err != nil {
+		log.Fatalf("Could not setup test bucket: %s", err)
+	}
+
+	code := m.Run()
+
+	// Defers will not be run when using os.Exit
+	if err := pool.Purge(container); err != nil {
+		log.Fatalf("Could not purge container: %s", err)
+	}
+
+	os.Exit(code)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Config contains the thresholds that flush buffered records.
+type Config struct {
+	// Size is the number of buffered records that triggers a flush.
+	Size int `env:"SIZE"     envDefault:"500"`
+	// Interval is how long records wait for the records of other messages
+	// before they are flushed. Zero flushes the records of each message
+	// on their own.
+	Interval time.Duration `env:"INTERVAL" envDefault:"0s"`
+}
+
+// MaxPending returns the number of messages writers handle at once, so the
+// records of pending messages are buffered together. Messages are handled
+// one at a time when records aren't buffered. Every message has a record at
+// least, so pending messages fill a batch.
+func (cfg Config) MaxPending() int {
+	if cfg.Interval <= 0 {
+		return 1
+	}
+
+	return max(cfg.Size, 1)
+}
+
+// FlushFunc writes records at once. It must write all the records or none
+// of them, so that the messages of failed flushes are safely redelivered.
+type FlushFunc[T any] func(ctx context.Context, records []T) error
+
+// Batcher buffers the records of messages consumed concurrently and flushes
+// them together once the size or the interval threshold is reached. Brokers
+// deliver messages one at a time, so writers subscribe with the maximum
+// number of pending messages of the config, and the records of pending
+// messages are buffered together.
+//
+// Add blocks until the records are flushed and returns the flush error, so
+// the message is acknowledged only after its records are stored, which
+// preserves at-least-once delivery. When a flush fails, the records of each
+// message are flushed on their own, so a single invalid message doesn't
+// fail the messages buffered with it.
+type Batcher[T any] struct {
+	cfg     Config
+	flush   FlushFunc[T]
+	mu      sync.Mutex
+	pending *batch[T]
+}
+
+type batch[T any] struct {
+	ctx    context.Context
+	groups [][]T
+	size   int
+	timer  *time.Timer
+	errs   []error
+	done   chan struct{}
+}
+
+// New returns new records batcher.
+func New[T any](cfg Config, flush FlushFunc[T]) *Batcher[T] {
+	return &Batcher[T]{
+		cfg:   cfg,
+		flush: flush,
+	}
+}
+
+// Add buffers the records of a single message and waits for them to be
+// flushed.
+func (b *Batcher[T]) Add(ctx context.Context, records []T) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if b.cfg.Interval <= 0 {
+		return b.flush(ctx, records)
+	}
+
+	b.mu.Lock()
+	bt := b.pending
+	if bt == nil {
+		bt = &batch[T]{
+			ctx:  context.WithoutCancel(ctx),
+			done: make(chan struct{}),
+		}
+		bt.timer = time.AfterFunc(b.cfg.Interval, func() {
+			b.expire(bt)
+		})
+		b.pending = bt
+	}
+	i := len(bt.groups)
+	bt.groups = append(bt.groups, records)
+	bt.size += len(records)
+
+	full := bt.size >= b.cfg.Size
+	if full {
+		bt.timer.Stop()
+		b.pending = nil
+	}
+	b.mu.Unlock()
+
+	if full {
+		b.run(bt)
+	}
+
+	select {
+	case <-bt.done:
+		return bt.errs[i]
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// expire flushes the batch once its interval passes, unless it has been
+// flushed for reaching the size threshold.
+func (b *Batcher[T]) expire(bt *batch[T]) {
+	b.mu.Lock()
+	if b.pending != bt {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+
+	b.run(bt)
+}
+
+func (b *Batcher[T]) run(bt *batch[T]) {
+	defer close(bt.done)
+
+	bt.errs = make([]error, len(bt.groups))
+	if len(bt.groups) == 1 {
+		bt.errs[0] = b.flush(bt.ctx, bt.groups[0])
+		return
+	}
+
+	records := make([]T, 0, bt.size)
+	for _, g := range bt.groups {
+		records = append(records, g...)
+	}
+	if err := b.flush(bt.ctx, records); err == nil {
+		return
+	}
+
+	for i, g := range bt.groups {
+		bt.errs[i] = b.flush(bt.ctx, g)
+	}
+}
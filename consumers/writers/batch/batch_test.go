@@ -0,0 +1,143 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/stretchr/testify/assert"
+)
+
+const invalid = -1
+
+var errFlush = errors.New("failed to flush")
+
+type store struct {
+	mu      sync.Mutex
+	flushes [][]int
+}
+
+// flush fails the whole flush when any record is invalid.
+func (s *store) flush(_ context.Context, records []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.Contains(records, invalid) {
+		return errFlush
+	}
+	s.flushes = append(s.flushes, slices.Clone(records))
+
+	return nil
+}
+
+func addAll(b *batch.Batcher[int], groups [][]int) []error {
+	errs := make([]error, len(groups))
+	var wg sync.WaitGroup
+	for i, g := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = b.Add(context.Background(), g)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestAdd(t *testing.T) {
+	cases := []struct {
+		desc    string
+		cfg     batch.Config
+		groups  [][]int
+		errs    []error
+		flushes int
+		stored  int
+	}{
+		{
+			desc:    "add records without interval",
+			cfg:     batch.Config{Size: 10},
+			groups:  [][]int{{1, 2}, {3}, {4, 5, 6}},
+			errs:    []error{nil, nil, nil},
+			flushes: 3,
+			stored:  6,
+		},
+		{
+			desc:    "add records flushed by size",
+			cfg:     batch.Config{Size: 6, Interval: time.Hour},
+			groups:  [][]int{{1, 2}, {3}, {4, 5, 6}},
+			errs:    []error{nil, nil, nil},
+			flushes: 1,
+			stored:  6,
+		},
+		{
+			desc:    "add records flushed by interval",
+			cfg:     batch.Config{Size: 100, Interval: 50 * time.Millisecond},
+			groups:  [][]int{{1, 2}, {3}, {4, 5, 6}},
+			errs:    []error{nil, nil, nil},
+			flushes: 1,
+			stored:  6,
+		},
+		{
+			desc:    "add invalid records",
+			cfg:     batch.Config{Size: 6, Interval: time.Hour},
+			groups:  [][]int{{1, 2}, {invalid}, {4, 5, 6}},
+			errs:    []error{nil, errFlush, nil},
+			flushes: 2,
+			stored:  5,
+		},
+		{
+			desc:    "add empty records",
+			cfg:     batch.Config{Size: 6, Interval: time.Hour},
+			groups:  [][]int{{}},
+			errs:    []error{nil},
+			flushes: 0,
+			stored:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &store{}
+			b := batch.New(tc.cfg, s.flush)
+
+			errs := addAll(b, tc.groups)
+			for i, err := range errs {
+				assert.Equal(t, tc.errs[i], err, fmt.Sprintf("%s: expected error %v for records %v, got %v", tc.desc, tc.errs[i], tc.groups[i], err))
+			}
+
+			stored := 0
+			for _, f := range s.flushes {
+				stored += len(f)
+			}
+			assert.Equal(t, tc.flushes, len(s.flushes), fmt.Sprintf("%s: expected %d flushes, got %d", tc.desc, tc.flushes, len(s.flushes)))
+			assert.Equal(t, tc.stored, stored, fmt.Sprintf("%s: expected %d stored records, got %d", tc.desc, tc.stored, stored))
+		})
+	}
+}
+
+func TestAddCanceled(t *testing.T) {
+	s := &store{}
+	b := batch.New(batch.Config{Size: 100, Interval: 50 * time.Millisecond}, s.flush)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := b.Add(ctx, []int{1})
+	assert.Equal(t, context.Canceled, err, fmt.Sprintf("expected error %s, got %s", context.Canceled, err))
+
+	// Records of canceled calls are still flushed, so they are only duplicated
+	// on redelivery, never lost.
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.flushes) == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package batch contains the batching of records that writers insert
+// into their data stores.
+package batch
@@ -8,18 +8,23 @@ package brokers
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/absmach/supermq/pkg/messaging"
 	broker "github.com/absmach/supermq/pkg/messaging/nats"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	AllTopic = "writers.>"
 
-	prefix = "writers"
+	prefix           = "writers"
+	deadLetterPrefix = "deadletter"
 )
 
 var cfg = jetstream.StreamConfig{
@@ -34,13 +39,54 @@ var cfg = jetstream.StreamConfig{
 	Storage:           jetstream.FileStorage,
 }
 
-func NewPubSub(ctx context.Context, url string, logger *slog.Logger) (messaging.PubSub, error) {
+// deadLetterCfg is the stream of the messages writers failed to save. It's
+// separate from the writers stream, so dead letters aren't consumed by
+// writers subscribed to all topics.
+var deadLetterCfg = jetstream.StreamConfig{
+	Name:              "deadletter",
+	Description:       "Magistrala stream for messages the writers failed to save",
+	Subjects:          []string{"deadletter.>"},
+	Retention:         jetstream.LimitsPolicy,
+	MaxMsgsPerSubject: 1e6,
+	MaxAge:            time.Hour * 24 * 7,
+	MaxMsgSize:        2 * 1024 * 1024,
+	Discard:           jetstream.DiscardOld,
+	Storage:           jetstream.FileStorage,
+}
+
+// NewPubSub returns the publisher and subscriber of the writers stream.
+// Subscribers handle up to maxPending messages at once, and acknowledge each
+// message once it's handled, so writers buffer the records of pending
+// messages together.
+func NewPubSub(ctx context.Context, url string, maxPending int, logger *slog.Logger) (messaging.PubSub, error) {
 	pb, err := broker.NewPubSub(ctx, url, logger, broker.Prefix(prefix), broker.JSStreamConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
+	if maxPending <= 1 {
+		return pb, nil
+	}
 
-	return pb, nil
+	conn, err := nats.Connect(url, nats.MaxReconnects(maxReconnects))
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := js.Stream(ctx, cfg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pubsub{
+		PubSub:     pb,
+		conn:       conn,
+		stream:     stream,
+		maxPending: maxPending,
+		logger:     logger,
+	}, nil
 }
 
 func NewPublisher(ctx context.Context, url string) (messaging.Publisher, error) {
@@ -51,3 +97,82 @@ func NewPublisher(ctx context.Context, url string) (messaging.Publisher, error)
 
 	return pb, nil
 }
+
+func NewDeadLetterPublisher(ctx context.Context, url string) (messaging.Publisher, error) {
+	pb, err := broker.NewPublisher(ctx, url, broker.Prefix(deadLetterPrefix), broker.JSStreamConfig(deadLetterCfg))
+	if err != nil {
+		return nil, err
+	}
+
+	return pb, nil
+}
+
+// maxReconnects is the number of reconnect attempts, where -1 never stops
+// reconnecting.
+const maxReconnects = -1
+
+// pubsub subscribes consumers that handle messages concurrently. Consumers
+// are named the same as the consumers of the SuperMQ subscriber, so they are
+// unsubscribed by it.
+type pubsub struct {
+	messaging.PubSub
+	conn       *nats.Conn
+	stream     jetstream.Stream
+	maxPending int
+	logger     *slog.Logger
+}
+
+func (ps *pubsub) Subscribe(ctx context.Context, cfg messaging.SubscriberConfig) error {
+	if cfg.ID == "" {
+		return broker.ErrEmptyID
+	}
+	if cfg.Topic == "" {
+		return broker.ErrEmptyTopic
+	}
+
+	name := formatConsumerName(cfg.Topic, cfg.ID)
+	consumerConfig := jetstream.ConsumerConfig{
+		Name:          name,
+		Durable:       name,
+		Description:   fmt.Sprintf("SuperMQ consumer of id %s for topic %s", cfg.ID, cfg.Topic),
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		FilterSubject: cfg.Topic,
+		MaxAckPending: ps.maxPending,
+	}
+	if cfg.DeliveryPolicy == messaging.DeliverAllPolicy {
+		consumerConfig.DeliverPolicy = jetstream.DeliverAllPolicy
+	}
+
+	consumer, err := ps.stream.CreateOrUpdateConsumer(ctx, consumerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	d := NewDispatcher(cfg, ps.maxPending, ps.logger)
+	handle := func(m jetstream.Msg) {
+		var msg messaging.Message
+		if err := proto.Unmarshal(m.Data(), &msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+			return
+		}
+		d.Dispatch(&msg, m.Ack)
+	}
+	if _, err = consumer.Consume(handle, jetstream.PullMaxMessages(ps.maxPending)); err != nil {
+		return fmt.Errorf("failed to consume: %w", err)
+	}
+
+	return nil
+}
+
+func (ps *pubsub) Close() error {
+	ps.conn.Close()
+	return ps.PubSub.Close()
+}
+
+func formatConsumerName(topic, id string) string {
+	// A durable name cannot contain whitespace, ., *, >, path separators
+	// (forward or backwards slash), and non-printable characters.
+	topic = strings.NewReplacer(" ", "_", ".", "_", "*", "_", ">", "_", "/", "_", "\\", "_").Replace(topic)
+
+	return fmt.Sprintf("%s-%s", topic, id)
+}
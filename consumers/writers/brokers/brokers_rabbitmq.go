@@ -8,10 +8,15 @@ package brokers
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 
 	"github.com/absmach/supermq/pkg/messaging"
 	broker "github.com/absmach/supermq/pkg/messaging/rabbitmq"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -19,15 +24,46 @@ const (
 
 	exchangeName = "writers"
 	prefix       = "writers"
+
+	// Dead letters are published to a separate exchange, so they aren't
+	// consumed by writers subscribed to all topics.
+	deadLetterExchange = "deadletter"
+	deadLetterPrefix   = "deadletter"
 )
 
-func NewPubSub(_ context.Context, url string, logger *slog.Logger) (messaging.PubSub, error) {
+// NewPubSub returns the publisher and subscriber of the writers exchange.
+// Subscribers handle up to maxPending messages at once, and acknowledge each
+// message once it's handled, so writers buffer the records of pending
+// messages together.
+func NewPubSub(_ context.Context, url string, maxPending int, logger *slog.Logger) (messaging.PubSub, error) {
 	pb, err := broker.NewPubSub(url, logger, broker.Prefix(prefix), broker.Exchange(exchangeName))
 	if err != nil {
 		return nil, err
 	}
+	if maxPending <= 1 {
+		return pb, nil
+	}
 
-	return pb, nil
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Qos(maxPending, 0, false); err != nil {
+		return nil, err
+	}
+
+	return &pubsub{
+		PubSub:        pb,
+		conn:          conn,
+		channel:       ch,
+		maxPending:    maxPending,
+		logger:        logger,
+		subscriptions: make(map[string]struct{}),
+	}, nil
 }
 
 func NewPublisher(_ context.Context, url string) (messaging.Publisher, error) {
@@ -38,3 +74,112 @@ func NewPublisher(_ context.Context, url string) (messaging.Publisher, error) {
 
 	return pb, nil
 }
+
+func NewDeadLetterPublisher(_ context.Context, url string) (messaging.Publisher, error) {
+	pb, err := broker.NewPublisher(url, broker.Prefix(deadLetterPrefix), broker.Exchange(deadLetterExchange))
+	if err != nil {
+		return nil, err
+	}
+
+	return pb, nil
+}
+
+// pubsub subscribes consumers that handle messages concurrently and
+// acknowledge them once they are handled.
+type pubsub struct {
+	messaging.PubSub
+	conn          *amqp.Connection
+	channel       *amqp.Channel
+	maxPending    int
+	logger        *slog.Logger
+	mu            sync.Mutex
+	subscriptions map[string]struct{}
+}
+
+func (ps *pubsub) Subscribe(ctx context.Context, cfg messaging.SubscriberConfig) error {
+	if cfg.ID == "" {
+		return broker.ErrEmptyID
+	}
+	if cfg.Topic == "" {
+		return broker.ErrEmptyTopic
+	}
+	topic := formatTopic(cfg.Topic)
+	clientID := fmt.Sprintf("%s-%s", topic, cfg.ID)
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.subscriptions[clientID]; ok {
+		if err := ps.channel.Cancel(clientID, false); err != nil {
+			return err
+		}
+	}
+
+	queue, err := ps.channel.QueueDeclare(clientID, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	if err := ps.channel.QueueBind(queue.Name, topic, exchangeName, false, nil); err != nil {
+		return err
+	}
+	deliveries, err := ps.channel.Consume(queue.Name, clientID, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	ps.subscriptions[clientID] = struct{}{}
+
+	d := NewDispatcher(cfg, ps.maxPending, ps.logger)
+	go func() {
+		for delivery := range deliveries {
+			var msg messaging.Message
+			if err := proto.Unmarshal(delivery.Body, &msg); err != nil {
+				ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+				if err := delivery.Reject(false); err != nil {
+					ps.logger.Warn(fmt.Sprintf("Failed to reject message: %s", err))
+				}
+				continue
+			}
+			d.Dispatch(&msg, func() error {
+				return delivery.Ack(false)
+			})
+		}
+	}()
+
+	return nil
+}
+
+func (ps *pubsub) Unsubscribe(ctx context.Context, id, topic string) error {
+	if id == "" {
+		return broker.ErrEmptyID
+	}
+	if topic == "" {
+		return broker.ErrEmptyTopic
+	}
+	topic = formatTopic(topic)
+	clientID := fmt.Sprintf("%s-%s", topic, id)
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.subscriptions[clientID]; !ok {
+		return broker.ErrNotSubscribed
+	}
+	if err := ps.channel.Cancel(clientID, false); err != nil {
+		return err
+	}
+	delete(ps.subscriptions, clientID)
+
+	return nil
+}
+
+func (ps *pubsub) Close() error {
+	if err := ps.conn.Close(); err != nil {
+		return err
+	}
+
+	return ps.PubSub.Close()
+}
+
+func formatTopic(topic string) string {
+	return strings.ReplaceAll(topic, ">", "#")
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package brokers
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/absmach/supermq/pkg/messaging"
+)
+
+// Dispatcher handles delivered messages concurrently, up to the maximum
+// number of pending messages, so that writers buffer the records of pending
+// messages together. Brokers deliver messages one at a time, so handling
+// them one at a time would never batch the records of different messages.
+//
+// Each message is acknowledged only once its handler returns, which is once
+// its records are stored, so the delivery stays at-least-once.
+type Dispatcher struct {
+	handler messaging.MessageHandler
+	ackErr  bool
+	pending chan struct{}
+	logger  *slog.Logger
+}
+
+// NewDispatcher returns new dispatcher of the messages of the subscription.
+func NewDispatcher(cfg messaging.SubscriberConfig, maxPending int, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		handler: cfg.Handler,
+		ackErr:  cfg.AckErr,
+		pending: make(chan struct{}, max(maxPending, 1)),
+		logger:  logger,
+	}
+}
+
+// Dispatch handles the message once fewer than the maximum number of
+// messages are pending, and acknowledges it once it's handled. Messages that
+// fail are left unacknowledged, so the broker redelivers them, unless the
+// subscription acknowledges failed messages. Dispatch blocks while the
+// maximum number of messages are pending, so brokers deliver no more.
+func (d *Dispatcher) Dispatch(msg *messaging.Message, ack func() error) {
+	d.pending <- struct{}{}
+	go func() {
+		defer func() { <-d.pending }()
+
+		if err := d.handler.Handle(msg); err != nil {
+			d.logger.Warn(fmt.Sprintf("Failed to handle SuperMQ message: %s", err))
+			if !d.ackErr {
+				return
+			}
+		}
+		if err := ack(); err != nil {
+			d.logger.Warn(fmt.Sprintf("Failed to ack message: %s", err))
+		}
+	}()
+}
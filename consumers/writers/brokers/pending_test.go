@@ -0,0 +1,215 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package brokers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/magistrala/consumers/writers/brokers"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/messaging"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	msgsNum = 10
+	invalid = "invalid"
+)
+
+var errFlush = errors.New("failed to flush")
+
+type subscriber struct {
+	messaging.Subscriber
+	cfg messaging.SubscriberConfig
+}
+
+func (s *subscriber) Subscribe(_ context.Context, cfg messaging.SubscriberConfig) error {
+	s.cfg = cfg
+	return nil
+}
+
+// store is the consumer of the writer, which stores the records of the
+// messages it consumes in batches.
+type store struct {
+	batcher *batch.Batcher[senml.Message]
+	mu      sync.Mutex
+	flushes [][]senml.Message
+	stored  int
+}
+
+func newStore(cfg batch.Config) *store {
+	s := &store{}
+	s.batcher = batch.New(cfg, s.flush)
+
+	return s
+}
+
+func (s *store) ConsumeBlocking(ctx context.Context, msgs interface{}) error {
+	return s.batcher.Add(ctx, msgs.([]senml.Message))
+}
+
+func (s *store) flush(_ context.Context, records []senml.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range records {
+		if r.Name == invalid {
+			return errFlush
+		}
+	}
+	s.flushes = append(s.flushes, records)
+	s.stored += len(records)
+
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	cases := []struct {
+		desc       string
+		cfg        batch.Config
+		invalid    int
+		flushes    int
+		stored     int
+		acked      int
+		maxPending int
+	}{
+		{
+			desc:    "dispatch messages of batch",
+			cfg:     batch.Config{Size: msgsNum, Interval: time.Hour},
+			flushes: 1,
+			stored:  msgsNum,
+			acked:   msgsNum,
+		},
+		{
+			desc:    "dispatch messages of batch flushed by interval",
+			cfg:     batch.Config{Size: 1000, Interval: 50 * time.Millisecond},
+			flushes: 1,
+			stored:  msgsNum,
+			acked:   msgsNum,
+		},
+		{
+			desc:    "dispatch messages without batch",
+			cfg:     batch.Config{Size: msgsNum},
+			flushes: msgsNum,
+			stored:  msgsNum,
+			acked:   msgsNum,
+		},
+		{
+			desc:    "dispatch messages of batch with invalid message",
+			cfg:     batch.Config{Size: msgsNum, Interval: time.Hour},
+			invalid: 1,
+			flushes: msgsNum - 1,
+			stored:  msgsNum - 1,
+			acked:   msgsNum - 1,
+		},
+		{
+			desc:       "dispatch messages one at a time",
+			cfg:        batch.Config{Size: msgsNum, Interval: 10 * time.Millisecond},
+			maxPending: 1,
+			flushes:    msgsNum,
+			stored:     msgsNum,
+			acked:      msgsNum,
+		},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	err := os.WriteFile(path, []byte("[subscriber]\nsubjects = [\"writers.>\"]\n[transformer]\nformat = \"senml\"\n"), 0o600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newStore(tc.cfg)
+			sub := &subscriber{}
+			err := writers.Start(context.Background(), "writer", sub, s, path, smqlog.NewMock())
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+			maxPending := tc.cfg.MaxPending()
+			if tc.maxPending > 0 {
+				maxPending = tc.maxPending
+			}
+			d := brokers.NewDispatcher(sub.cfg, maxPending, smqlog.NewMock())
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			acked := 0
+			// Brokers deliver messages one at a time, as the subscribers of
+			// the writers dispatch them.
+			for i := 0; i < msgsNum; i++ {
+				name := "temperature"
+				if i < tc.invalid {
+					name = invalid
+				}
+				msg := &messaging.Message{
+					Channel: "channel",
+					Payload: []byte(fmt.Sprintf(`[{"n":"%s","v":%d,"t":%d}]`, name, i, i+1)),
+				}
+				wg.Add(1)
+				handled := make(chan struct{})
+				go func() {
+					defer wg.Done()
+					select {
+					case <-handled:
+					case <-time.After(time.Second):
+					}
+				}()
+				d.Dispatch(msg, func() error {
+					mu.Lock()
+					defer mu.Unlock()
+					s.mu.Lock()
+					defer s.mu.Unlock()
+					// Messages are acknowledged once their records are stored.
+					assert.NotEmpty(t, s.flushes, fmt.Sprintf("%s: expected message acknowledged after flush", tc.desc))
+					acked++
+					close(handled)
+					return nil
+				})
+			}
+			wg.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			assert.Equal(t, tc.acked, acked, fmt.Sprintf("%s: expected %d acknowledged messages, got %d", tc.desc, tc.acked, acked))
+			assert.Equal(t, tc.flushes, len(s.flushes), fmt.Sprintf("%s: expected %d flushes, got %d", tc.desc, tc.flushes, len(s.flushes)))
+			assert.Equal(t, tc.stored, s.stored, fmt.Sprintf("%s: expected %d stored records, got %d", tc.desc, tc.stored, s.stored))
+		})
+	}
+}
+
+func TestMaxPending(t *testing.T) {
+	cases := []struct {
+		desc       string
+		cfg        batch.Config
+		maxPending int
+	}{
+		{
+			desc:       "max pending messages of batch",
+			cfg:        batch.Config{Size: 500, Interval: time.Second},
+			maxPending: 500,
+		},
+		{
+			desc:       "max pending messages without batch",
+			cfg:        batch.Config{Size: 500},
+			maxPending: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		maxPending := tc.cfg.MaxPending()
+		assert.Equal(t, tc.maxPending, maxPending, fmt.Sprintf("%s: expected %d max pending messages, got %d", tc.desc, tc.maxPending, maxPending))
+	}
+}
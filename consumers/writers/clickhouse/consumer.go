@@ -0,0 +1,355 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/supermq/consumers"
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/jmoiron/sqlx"
+)
+
+// ClickHouse exception codes the writer handles.
+const (
+	codeUnknownTable = 60
+	// Codes of failures that may not happen again, so saving the messages
+	// is worth retrying.
+	codeTimeoutExceeded            = 159
+	codeTooManySimultaneousQueries = 202
+	codeMemoryLimitExceeded        = 241
+	codeTooManyParts               = 252
+	codeNetworkError               = 210
+	codeSocketTimeout              = 209
+)
+
+var (
+	errSaveMessage   = errors.New("failed to save message to clickhouse database")
+	errTransRollback = errors.New("failed to rollback transaction")
+	errNoTable       = errors.New("table does not exist")
+	errInvalidFormat = errors.New("invalid message format")
+)
+
+// Formats are stored in tables named after the format, so the format must
+// be a lowercase identifier and mustn't be named after the SenML table.
+var tableRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+var _ consumers.BlockingConsumer = (*clickhouseRepo)(nil)
+
+type clickhouseRepo struct {
+	db         *sqlx.DB
+	onConflict string
+	senml      *batch.Batcher[senmlMessage]
+	json       *batch.Batcher[jsonMessage]
+}
+
+// New returns new ClickHouse writer. Records of messages consumed
+// concurrently are inserted together, as configured by the batch config,
+// which suits ClickHouse, since every insert creates a part of the table.
+//
+// Records of redelivered messages are deduplicated when the parts are
+// merged. Records are versioned by their insert time, so the first stored
+// record is kept or replaced by the records of redelivered messages, as
+// configured.
+func New(db *sqlx.DB, cfg writers.Config) consumers.BlockingConsumer {
+	cr := &clickhouseRepo{db: db, onConflict: cfg.OnConflict}
+	cr.senml = batch.New(cfg.Batch, cr.insertSenml)
+	cr.json = batch.New(cfg.Batch, cr.saveJSON)
+
+	return cr
+}
+
+func (cr clickhouseRepo) ConsumeBlocking(ctx context.Context, message interface{}) error {
+	switch m := message.(type) {
+	case smqjson.Messages:
+		return cr.addJSON(ctx, m)
+	default:
+		return cr.addSenml(ctx, m)
+	}
+}
+
+func (cr clickhouseRepo) addSenml(ctx context.Context, messages interface{}) error {
+	msgs, ok := messages.([]senml.Message)
+	if !ok {
+		return errSaveMessage
+	}
+
+	domain := domainOf(ctx)
+	records := make([]senmlMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		id := writers.RecordID(msg.Channel, msg.Publisher, msg.Subtopic, msg.Name, strconv.FormatFloat(msg.Time, 'f', -1, 64))
+		records = append(records, senmlMessage{Message: msg, ID: id, Domain: domain})
+	}
+
+	return cr.senml.Add(ctx, records)
+}
+
+func (cr clickhouseRepo) insertSenml(ctx context.Context, msgs []senmlMessage) error {
+	q := `INSERT INTO messages (id, domain, channel, subtopic, publisher, protocol,
+          name, unit, value, string_value, bool_value, data_value, sum,
+          time, update_time, version)`
+
+	// Records of the same block have the same version, so the records of
+	// redelivered messages are deduplicated before they are inserted.
+	msgs = writers.Dedupe(msgs, func(m senmlMessage) string { return m.ID })
+	version := cr.version()
+	return cr.insert(ctx, q, len(msgs), func(stmt *sql.Stmt, i int) error {
+		m := msgs[i]
+		_, err := stmt.ExecContext(ctx, m.ID, m.Domain, m.Channel, m.Subtopic, m.Publisher, m.Protocol,
+			m.Name, m.Unit, m.Value, m.StringValue, m.BoolValue, m.DataValue, m.Sum,
+			m.Time, m.UpdateTime, version)
+		return err
+	})
+}
+
+func (cr clickhouseRepo) addJSON(ctx context.Context, msgs smqjson.Messages) error {
+	table := strings.ToLower(msgs.Format)
+	if !tableRegexp.MatchString(table) || table == defTable {
+		return errors.Wrap(errSaveMessage, errInvalidFormat)
+	}
+
+	domain := domainOf(ctx)
+	records := make([]jsonMessage, 0, len(msgs.Data))
+	for i, m := range msgs.Data {
+		dbmsg, err := toJSONMessage(m, i)
+		if err != nil {
+			return errors.Wrap(errSaveMessage, err)
+		}
+		dbmsg.table = table
+		dbmsg.Domain = domain
+		records = append(records, dbmsg)
+	}
+
+	return cr.json.Add(ctx, records)
+}
+
+func (cr clickhouseRepo) saveJSON(ctx context.Context, msgs []jsonMessage) error {
+	err := cr.insertJSON(ctx, msgs)
+	if err != errNoTable {
+		return err
+	}
+
+	for _, table := range tables(msgs) {
+		if err := cr.createTable(ctx, table); err != nil {
+			return saveError(err)
+		}
+	}
+
+	if err := cr.insertJSON(ctx, msgs); err != nil {
+		if err == errNoTable {
+			return errors.Wrap(errSaveMessage, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr clickhouseRepo) insertJSON(ctx context.Context, msgs []jsonMessage) error {
+	q := `INSERT INTO %s (id, domain, channel, created, subtopic, publisher, protocol, payload, version)`
+
+	msgs = writers.Dedupe(msgs, func(m jsonMessage) string { return m.table + m.ID })
+	version := cr.version()
+	for _, table := range tables(msgs) {
+		var rows []jsonMessage
+		for _, m := range msgs {
+			if m.table == table {
+				rows = append(rows, m)
+			}
+		}
+
+		err := cr.insert(ctx, fmt.Sprintf(q, quote(table)), len(rows), func(stmt *sql.Stmt, i int) error {
+			r := rows[i]
+			_, err := stmt.ExecContext(ctx, r.ID, r.Domain, r.Channel, r.Created, r.Subtopic, r.Publisher, r.Protocol, r.Payload, version)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insert inserts the rows with the insert statement as a single block. The
+// exec function appends the row of the index to the block.
+func (cr clickhouseRepo) insert(ctx context.Context, q string, n int, exec func(stmt *sql.Stmt, i int) error) (err error) {
+	tx, err := cr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return saveError(err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = saveError(err)
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, q)
+	if err != nil {
+		if exceptionCode(err) == codeUnknownTable {
+			return errNoTable
+		}
+		return saveError(err)
+	}
+	defer stmt.Close()
+
+	for i := 0; i < n; i++ {
+		if err := exec(stmt, i); err != nil {
+			return saveError(err)
+		}
+	}
+
+	return nil
+}
+
+// version returns the version of the inserted records. Merges keep the
+// record of the highest version, so the versions of the records inserted
+// later are higher if they update the stored records, and lower otherwise.
+func (cr clickhouseRepo) version() uint64 {
+	now := uint64(time.Now().UnixNano())
+	if cr.onConflict == writers.OnConflictUpdate {
+		return now
+	}
+
+	return math.MaxUint64 - now
+}
+
+func (cr clickhouseRepo) createTable(ctx context.Context, table string) error {
+	q := `CREATE TABLE IF NOT EXISTS %s (
+            id            UUID,
+            domain        Nullable(String),
+            channel       String,
+            created       Int64,
+            subtopic      String,
+            publisher     String,
+            protocol      LowCardinality(String),
+            payload       String,
+            version       UInt64
+        )
+        ENGINE = ReplacingMergeTree(version)
+        PARTITION BY toYYYYMM(toDateTime(toUInt32(intDiv(created, 1000000000))))
+        ORDER BY (channel, created, id)`
+
+	_, err := cr.db.ExecContext(ctx, fmt.Sprintf(q, quote(table)))
+	return err
+}
+
+// saveError wraps the error of saving messages, marking the errors that may
+// not happen again as transient, so the messages are retried.
+func saveError(err error) error {
+	switch exceptionCode(err) {
+	case codeTimeoutExceeded, codeTooManySimultaneousQueries, codeMemoryLimitExceeded,
+		codeTooManyParts, codeNetworkError, codeSocketTimeout:
+		err = errors.Wrap(writers.ErrTransient, err)
+	default:
+		if writers.IsTransient(err) {
+			err = errors.Wrap(writers.ErrTransient, err)
+		}
+	}
+
+	return errors.Wrap(errSaveMessage, err)
+}
+
+// exceptionCode returns the code of the ClickHouse exception, or zero if
+// the error isn't an exception.
+func exceptionCode(err error) int32 {
+	var ex *clickhouse.Exception
+	if stderrors.As(err, &ex) {
+		return ex.Code
+	}
+
+	return 0
+}
+
+// quote quotes the table name, which is validated as an identifier.
+func quote(table string) string {
+	return "`" + table + "`"
+}
+
+type senmlMessage struct {
+	senml.Message
+	ID     string
+	Domain *string
+}
+
+type jsonMessage struct {
+	ID        string
+	Domain    *string
+	Channel   string
+	Created   int64
+	Subtopic  string
+	Publisher string
+	Protocol  string
+	Payload   string
+	table     string
+}
+
+func toJSONMessage(msg smqjson.Message, index int) (jsonMessage, error) {
+	data := []byte("{}")
+	if msg.Payload != nil {
+		b, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return jsonMessage{}, err
+		}
+		data = b
+	}
+
+	m := jsonMessage{
+		ID:        writers.JSONRecordID(msg, index, data),
+		Channel:   msg.Channel,
+		Created:   msg.Created,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Payload:   string(data),
+	}
+
+	return m, nil
+}
+
+// domainOf returns the domain of the messages of the context, or nil if
+// the domain is unknown, so the domain of their records is NULL.
+func domainOf(ctx context.Context) *string {
+	domain := writers.Domain(ctx)
+	if domain == "" {
+		return nil
+	}
+
+	return &domain
+}
+
+// tables returns the tables of the JSON messages in the order of their
+// first message.
+func tables(msgs []jsonMessage) []string {
+	var ret []string
+	for _, m := range msgs {
+		if !slices.Contains(ret, m.table) {
+			ret = append(ret, m.table)
+		}
+	}
+
+	return ret
+}
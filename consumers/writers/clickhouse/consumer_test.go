@@ -0,0 +1,306 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/magistrala/consumers/writers/clickhouse"
+	"github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	msgsNum     = 42
+	valueFields = 5
+	subtopic    = "topic"
+)
+
+var (
+	v       float64 = 5
+	stringV         = "value"
+	boolV           = true
+	dataV           = "base64"
+	sum     float64 = 42
+)
+
+func TestSaveSenml(t *testing.T) {
+	repo := clickhouse.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := senml.Message{Channel: chid.String(), Publisher: pubid.String()}
+	now := time.Now().Unix()
+	var msgs []senml.Message
+
+	for i := 0; i < msgsNum; i++ {
+		// Mix possible values as well as value sum.
+		count := i % valueFields
+		switch count {
+		case 0:
+			msg.Subtopic = subtopic
+			msg.Value = &v
+		case 1:
+			msg.BoolValue = &boolV
+		case 2:
+			msg.StringValue = &stringV
+		case 3:
+			msg.DataValue = &dataV
+		case 4:
+			msg.Sum = &sum
+		}
+
+		msg.Time = float64(now+int64(i)) * 1e9
+		msgs = append(msgs, msg)
+	}
+
+	err = repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	var count uint64
+	err = db.Get(&count, "SELECT COUNT(*) FROM messages FINAL WHERE publisher = ?", pubid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(msgsNum), count, fmt.Sprintf("expected %d stored messages, got %d", msgsNum, count))
+}
+
+func TestSaveJSON(t *testing.T) {
+	repo := clickhouse.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := json.Message{
+		Channel:   chid.String(),
+		Publisher: pubid.String(),
+		Subtopic:  "subtopic/format/some_json",
+		Protocol:  "mqtt",
+		Payload: map[string]interface{}{
+			"field_1": 123,
+			"field_2": "value",
+			"field_3": false,
+			"field_4": 12.344,
+			"field_5": map[string]interface{}{
+				"field_1": "value",
+				"field_2": 42,
+			},
+		},
+	}
+
+	now := time.Now().UnixNano()
+	msgs := json.Messages{Format: "Some_JSON"}
+	for i := 0; i < msgsNum; i++ {
+		msg.Created = now + int64(i)
+		msgs.Data = append(msgs.Data, msg)
+	}
+
+	// The table of the format is created by the first save.
+	err = repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	var count uint64
+	err = db.Get(&count, "SELECT COUNT(*) FROM some_json FINAL WHERE publisher = ?", pubid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(msgsNum), count, fmt.Sprintf("expected %d stored messages, got %d", msgsNum, count))
+}
+
+func TestSaveJSONInvalidFormat(t *testing.T) {
+	repo := clickhouse.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	msg := json.Message{
+		Channel:  "channel",
+		Created:  1,
+		Protocol: "mqtt",
+		Payload:  map[string]interface{}{"temperature": 20.5},
+	}
+
+	cases := []struct {
+		desc   string
+		format string
+	}{
+		{
+			desc:   "save JSON message of injected format",
+			format: "readings (id) VALUES (1); DROP TABLE messages; --",
+		},
+		{
+			desc:   "save JSON message of format of writer table",
+			format: "messages",
+		},
+		{
+			desc:   "save JSON message of format with separator",
+			format: "some-json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.ConsumeBlocking(context.Background(), json.Messages{Format: tc.format, Data: []json.Message{msg}})
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error, got nil", tc.desc))
+		})
+	}
+}
+
+func TestSaveDomain(t *testing.T) {
+	repo := clickhouse.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		ctx    context.Context
+		domain *string
+	}{
+		{
+			desc:   "save messages of domain",
+			ctx:    writers.WithDomain(context.Background(), domainID.String()),
+			domain: func() *string { d := domainID.String(); return &d }(),
+		},
+		{
+			desc: "save messages of unknown domain",
+			ctx:  context.Background(),
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			created := (time.Now().Unix() + int64(i)) * 1e9
+			senmlMsg := senml.Message{Channel: chid.String(), Publisher: "publisher", Name: "domain", Time: float64(created), Value: &v}
+			err := repo.ConsumeBlocking(tc.ctx, []senml.Message{senmlMsg})
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			jsonMsg := json.Message{Channel: chid.String(), Publisher: "publisher", Created: created, Protocol: "mqtt", Payload: map[string]interface{}{"temperature": 20.5}}
+			err = repo.ConsumeBlocking(tc.ctx, json.Messages{Format: "domain_readings", Data: []json.Message{jsonMsg}})
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			var domain *string
+			err = db.Get(&domain, `SELECT domain FROM messages FINAL WHERE channel = ? AND time = ?`, chid.String(), float64(created))
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			assert.Equal(t, tc.domain, domain, fmt.Sprintf("%s: expected SenML domain %v, got %v", tc.desc, tc.domain, domain))
+
+			err = db.Get(&domain, `SELECT domain FROM domain_readings FINAL WHERE channel = ? AND created = ?`, chid.String(), created)
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			assert.Equal(t, tc.domain, domain, fmt.Sprintf("%s: expected JSON domain %v, got %v", tc.desc, tc.domain, domain))
+		})
+	}
+}
+
+func TestSaveRedelivered(t *testing.T) {
+	cases := []struct {
+		desc       string
+		onConflict string
+		value      float64
+	}{
+		{
+			desc:       "save redelivered messages ignoring stored records",
+			onConflict: writers.OnConflictIgnore,
+			value:      v,
+		},
+		{
+			desc:       "save redelivered messages updating stored records",
+			onConflict: writers.OnConflictUpdate,
+			value:      v + 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := clickhouse.New(db, writers.Config{OnConflict: tc.onConflict})
+
+			chid, err := uuid.NewV4()
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+			pubid, err := uuid.NewV4()
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+			now := time.Now().Unix()
+			msgs := make([]senml.Message, msgsNum)
+			for i := range msgs {
+				msgs[i] = senml.Message{
+					Channel:   chid.String(),
+					Publisher: pubid.String(),
+					Subtopic:  subtopic,
+					Name:      "name",
+					Value:     &v,
+					Time:      float64(now+int64(i)) * 1e9,
+				}
+			}
+
+			err = repo.ConsumeBlocking(context.Background(), msgs)
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			// Redeliver the messages with a duplicated record and a changed value.
+			value := v + 1
+			redelivered := append(slices.Clone(msgs), msgs[0])
+			for i := range redelivered {
+				redelivered[i].Value = &value
+			}
+			err = repo.ConsumeBlocking(context.Background(), redelivered)
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			var count uint64
+			err = db.Get(&count, "SELECT COUNT(*) FROM messages FINAL WHERE publisher = ? AND value = ?", pubid.String(), tc.value)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+			assert.Equal(t, uint64(msgsNum), count, fmt.Sprintf("%s: expected %d stored messages, got %d", tc.desc, msgsNum, count))
+		})
+	}
+}
+
+func TestSaveSenmlBatched(t *testing.T) {
+	repo := clickhouse.New(db, writers.Config{Batch: batch.Config{Size: msgsNum * 10, Interval: 100 * time.Millisecond}, OnConflict: writers.OnConflictIgnore})
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	batches := 4
+	now := time.Now().Unix()
+	errs := make([]error, batches)
+	var wg sync.WaitGroup
+	for i := 0; i < batches; i++ {
+		msgs := make([]senml.Message, msgsNum)
+		for j := range msgs {
+			msgs[j] = senml.Message{
+				Channel:   chid.String(),
+				Publisher: pubid.String(),
+				Subtopic:  subtopic,
+				Name:      "name",
+				Value:     &v,
+				Time:      float64(now+int64(i*msgsNum+j)) * 1e9,
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.ConsumeBlocking(context.Background(), msgs)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		assert.Nil(t, err, fmt.Sprintf("batch %d: expected no error, got %s", i, err))
+	}
+
+	var count uint64
+	err = db.Get(&count, "SELECT COUNT(*) FROM messages FINAL WHERE publisher = ?", pubid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(batches*msgsNum), count, fmt.Sprintf("expected %d saved messages, got %d", batches*msgsNum, count))
+}
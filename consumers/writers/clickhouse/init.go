@@ -0,0 +1,41 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse
+
+// Table for SenML messages.
+const defTable = "messages"
+
+// Schema of clickhouse-writer. ClickHouse has no transactional migrations,
+// so the statements are idempotent and applied on every start.
+//
+// Tables are ReplacingMergeTree tables sorted by the key of their records,
+// so the records of redelivered messages are deduplicated by background
+// merges, keeping the record of the highest version, and readers read the
+// deduplicated records with FINAL. Time is stored in nanoseconds, the same
+// as in the other writers, and partitions hold a month of messages.
+func Schema() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS messages (
+            id            UUID,
+            domain        Nullable(String),
+            channel       String,
+            subtopic      String,
+            publisher     String,
+            protocol      LowCardinality(String),
+            name          String,
+            unit          LowCardinality(String),
+            value         Nullable(Float64),
+            string_value  Nullable(String),
+            bool_value    Nullable(Bool),
+            data_value    Nullable(String),
+            sum           Nullable(Float64),
+            time          Float64,
+            update_time   Float64,
+            version       UInt64
+        )
+        ENGINE = ReplacingMergeTree(version)
+        PARTITION BY toYYYYMM(toDateTime(toUInt32(time / 1000000000)))
+        ORDER BY (channel, subtopic, publisher, name, time)`,
+	}
+}
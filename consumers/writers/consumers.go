@@ -0,0 +1,208 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/absmach/supermq/consumers"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/messaging"
+	"github.com/absmach/supermq/pkg/messaging/brokers"
+	"github.com/absmach/supermq/pkg/transformers"
+	"github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	defContentType = "application/senml+json"
+	defFormat      = "senml"
+)
+
+var (
+	errOpenConfFile  = errors.New("unable to open configuration file")
+	errParseConfFile = errors.New("unable to parse configuration file")
+)
+
+type domainKey struct{}
+
+// WithDomain returns the context of the message of the domain.
+func WithDomain(ctx context.Context, domain string) context.Context {
+	return context.WithValue(ctx, domainKey{}, domain)
+}
+
+// Domain returns the domain of the message of the context, or an empty
+// string if the domain is unknown.
+func Domain(ctx context.Context) string {
+	domain, _ := ctx.Value(domainKey{}).(string)
+	return domain
+}
+
+type sizeKey struct{}
+
+// WithSize returns the context of the message of the payload size in bytes.
+func WithSize(ctx context.Context, size uint64) context.Context {
+	return context.WithValue(ctx, sizeKey{}, size)
+}
+
+// Size returns the payload size in bytes of the message of the context, or
+// zero if the size is unknown.
+func Size(ctx context.Context) uint64 {
+	size, _ := ctx.Value(sizeKey{}).(uint64)
+	return size
+}
+
+// Start subscribes the consumer to the subjects of the configuration file
+// and consumes the messages transformed as configured. It works as Start of
+// SuperMQ consumers, except the domain and the payload size of each message,
+// which transformed messages don't carry, are passed in the context of the
+// consumer.
+func Start(ctx context.Context, id string, sub messaging.Subscriber, consumer consumers.BlockingConsumer, configPath string, logger *slog.Logger) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
+	}
+
+	transformer := makeTransformer(cfg.TransformerCfg, logger)
+
+	for _, subject := range cfg.SubscriberCfg.Subjects {
+		subCfg := messaging.SubscriberConfig{
+			ID:             id,
+			Topic:          subject,
+			DeliveryPolicy: messaging.DeliverAllPolicy,
+			Handler:        handle(ctx, transformer, consumer),
+		}
+		if err := sub.Subscribe(ctx, subCfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func handle(ctx context.Context, t transformers.Transformer, c consumers.BlockingConsumer) handleFunc {
+	return func(msg *messaging.Message) error {
+		m := interface{}(msg)
+		var err error
+		if t != nil {
+			m, err = t.Transform(msg)
+			if err != nil {
+				return err
+			}
+		}
+
+		mctx := WithSize(WithDomain(ctx, msg.GetDomain()), uint64(len(msg.GetPayload())))
+
+		return c.ConsumeBlocking(mctx, m)
+	}
+}
+
+type handleFunc func(msg *messaging.Message) error
+
+func (h handleFunc) Handle(msg *messaging.Message) error {
+	return h(msg)
+}
+
+func (h handleFunc) Cancel() error {
+	return nil
+}
+
+type subscriberConfig struct {
+	Subjects []string `toml:"subjects"`
+}
+
+type transformerConfig struct {
+	Format      string           `toml:"format"`
+	ContentType string           `toml:"content_type"`
+	TimeFields  []json.TimeField `toml:"time_fields"`
+}
+
+type config struct {
+	SubscriberCfg  subscriberConfig  `toml:"subscriber"`
+	TransformerCfg transformerConfig `toml:"transformer"`
+}
+
+func loadConfig(configPath string) (config, error) {
+	cfg := config{
+		SubscriberCfg: subscriberConfig{
+			Subjects: []string{brokers.SubjectAllChannels},
+		},
+		TransformerCfg: transformerConfig{
+			Format:      defFormat,
+			ContentType: defContentType,
+		},
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return cfg, errors.Wrap(errOpenConfFile, err)
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return cfg, errors.Wrap(errParseConfFile, err)
+	}
+
+	return cfg, nil
+}
+
+func makeTransformer(cfg transformerConfig, logger *slog.Logger) transformers.Transformer {
+	switch strings.ToUpper(cfg.Format) {
+	case "SENML":
+		logger.Info("Using SenML transformer")
+		return senml.New(cfg.ContentType)
+	case "JSON":
+		logger.Info("Using JSON transformer")
+		return jsonTransformer{json.New(cfg.TimeFields)}
+	default:
+		logger.Warn(fmt.Sprintf("No transformer created: unknown transformer type %s", cfg.Format))
+		return nil
+	}
+}
+
+// jsonTransformer transforms each object of an array payload on its own, so
+// every JSON message gets the time of its own time field, rather than the
+// time the SuperMQ transformer takes from the previous object.
+type jsonTransformer struct {
+	transformers.Transformer
+}
+
+func (t jsonTransformer) Transform(msg *messaging.Message) (interface{}, error) {
+	var objs []stdjson.RawMessage
+	if err := stdjson.Unmarshal(msg.GetPayload(), &objs); err != nil || len(objs) == 0 {
+		return t.Transformer.Transform(msg)
+	}
+	for _, o := range objs {
+		if !bytes.HasPrefix(o, []byte("{")) {
+			return t.Transformer.Transform(msg)
+		}
+	}
+
+	ret := json.Messages{}
+	for _, o := range objs {
+		m, err := t.Transformer.Transform(&messaging.Message{
+			Channel:   msg.GetChannel(),
+			Domain:    msg.GetDomain(),
+			Subtopic:  msg.GetSubtopic(),
+			Publisher: msg.GetPublisher(),
+			Protocol:  msg.GetProtocol(),
+			Payload:   o,
+			Created:   msg.GetCreated(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		msgs := m.(json.Messages)
+		ret.Format = msgs.Format
+		ret.Data = append(ret.Data, msgs.Data...)
+	}
+
+	return ret, nil
+}
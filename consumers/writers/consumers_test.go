@@ -0,0 +1,225 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/messaging"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	domainID  = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	channelID = "c8bbd7b0-3c17-4a77-a3e3-91ed4a0a6b18"
+)
+
+type subscriber struct {
+	messaging.Subscriber
+	cfgs []messaging.SubscriberConfig
+}
+
+func (s *subscriber) Subscribe(_ context.Context, cfg messaging.SubscriberConfig) error {
+	s.cfgs = append(s.cfgs, cfg)
+	return nil
+}
+
+type consumer struct {
+	domain string
+	size   uint64
+	msg    interface{}
+}
+
+func (c *consumer) ConsumeBlocking(ctx context.Context, msg interface{}) error {
+	c.domain = writers.Domain(ctx)
+	c.size = writers.Size(ctx)
+	c.msg = msg
+	return nil
+}
+
+func TestDomain(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", writers.Domain(ctx), "expected no domain of context without domain")
+
+	ctx = writers.WithDomain(ctx, domainID)
+	domain := writers.Domain(ctx)
+	assert.Equal(t, domainID, domain, fmt.Sprintf("expected domain %s, got %s", domainID, domain))
+}
+
+func TestSize(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, uint64(0), writers.Size(ctx), "expected no size of context without size")
+
+	ctx = writers.WithSize(ctx, 42)
+	size := writers.Size(ctx)
+	assert.Equal(t, uint64(42), size, fmt.Sprintf("expected size 42, got %d", size))
+}
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := []struct {
+		desc     string
+		config   string
+		subjects []string
+		payload  string
+		msg      interface{}
+	}{
+		{
+			desc: "start with SenML transformer",
+			config: `[subscriber]
+subjects = ["writers.>"]
+[transformer]
+format = "senml"
+content_type = "application/senml+json"`,
+			subjects: []string{"writers.>"},
+			payload:  `[{"n":"temperature","v":20,"t":1}]`,
+			msg: []senml.Message{{
+				Channel:  channelID,
+				Subtopic: "readings",
+				Protocol: "http",
+				Name:     "temperature",
+				Value:    func() *float64 { v := 20.0; return &v }(),
+				Time:     1,
+			}},
+		},
+		{
+			desc: "start with JSON transformer",
+			config: `[subscriber]
+subjects = ["writers.a", "writers.b"]
+[transformer]
+format = "json"`,
+			subjects: []string{"writers.a", "writers.b"},
+			payload:  `{"temperature":20}`,
+			msg: smqjson.Messages{
+				Data: []smqjson.Message{{
+					Channel:  channelID,
+					Subtopic: "readings",
+					Protocol: "http",
+					Payload:  map[string]interface{}{"temperature": 20.0},
+				}},
+				Format: "readings",
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("config_%d.toml", i))
+			err := os.WriteFile(path, []byte(tc.config), 0o600)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+			sub := &subscriber{}
+			c := &consumer{}
+			err = writers.Start(context.Background(), "writer", sub, c, path, smqlog.NewMock())
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+			var subjects []string
+			for _, cfg := range sub.cfgs {
+				subjects = append(subjects, cfg.Topic)
+			}
+			assert.Equal(t, tc.subjects, subjects, fmt.Sprintf("%s: expected subjects %v, got %v", tc.desc, tc.subjects, subjects))
+
+			msg := &messaging.Message{
+				Domain:   domainID,
+				Channel:  channelID,
+				Subtopic: "readings",
+				Protocol: "http",
+				Payload:  []byte(tc.payload),
+			}
+			err = sub.cfgs[0].Handler.Handle(msg)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, domainID, c.domain, fmt.Sprintf("%s: expected domain %s, got %s", tc.desc, domainID, c.domain))
+			assert.Equal(t, uint64(len(tc.payload)), c.size, fmt.Sprintf("%s: expected size %d, got %d", tc.desc, len(tc.payload), c.size))
+
+			// Transformers set the time of messages without time.
+			if m, ok := c.msg.(smqjson.Messages); ok {
+				for i := range m.Data {
+					m.Data[i].Created = 0
+				}
+			}
+			assert.Equal(t, tc.msg, c.msg, fmt.Sprintf("%s: expected message %v, got %v", tc.desc, tc.msg, c.msg))
+		})
+	}
+}
+
+func TestStartTimeFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := `[transformer]
+format = "json"
+time_fields = [{ field_name = "ts", field_format = "unix", location = "UTC" }]`
+	err := os.WriteFile(path, []byte(config), 0o600)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc    string
+		payload string
+		created []int64
+		err     error
+	}{
+		{
+			desc:    "consume object with time field",
+			payload: `{"ts":1700000001,"temperature":20}`,
+			created: []int64{1700000001e9},
+		},
+		{
+			desc:    "consume array with time fields",
+			payload: `[{"ts":1700000001,"temperature":20},{"ts":1700000002,"temperature":21},{"ts":1700000003,"temperature":22}]`,
+			created: []int64{1700000001e9, 1700000002e9, 1700000003e9},
+		},
+		{
+			desc:    "consume array with and without time fields",
+			payload: `[{"temperature":20},{"ts":1700000002,"temperature":21}]`,
+			created: []int64{5, 1700000002e9},
+		},
+		{
+			desc:    "consume array with invalid time field",
+			payload: `[{"ts":1700000001,"temperature":20},{"ts":"now","temperature":21}]`,
+			err:     smqjson.ErrInvalidTimeField,
+		},
+		{
+			desc:    "consume array of non-objects",
+			payload: `[{"ts":1700000001,"temperature":20},20]`,
+			err:     smqjson.ErrTransform,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sub := &subscriber{}
+			c := &consumer{}
+			err := writers.Start(context.Background(), "writer", sub, c, path, smqlog.NewMock())
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+			msg := &messaging.Message{
+				Domain:   domainID,
+				Channel:  channelID,
+				Subtopic: "readings",
+				Protocol: "http",
+				Payload:  []byte(tc.payload),
+				Created:  5,
+			}
+			err = sub.cfgs[0].Handler.Handle(msg)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+
+			var created []int64
+			for _, m := range c.msg.(smqjson.Messages).Data {
+				created = append(created, m.Created)
+			}
+			assert.Equal(t, tc.created, created, fmt.Sprintf("%s: expected created %v, got %v", tc.desc, tc.created, created))
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api contains the HTTP API for listing, replaying and purging dead
+// letters.
+package api
@@ -0,0 +1,148 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	writersapi "github.com/absmach/magistrala/consumers/writers/api"
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func listEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.List(ctx, req.domainID, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+		res := listRes{
+			Total:       page.Total,
+			Offset:      page.Offset,
+			Limit:       page.Limit,
+			DeadLetters: []viewRes{},
+		}
+		for _, dl := range page.DeadLetters {
+			res.DeadLetters = append(res.DeadLetters, toViewRes(dl))
+		}
+
+		return res, nil
+	}
+}
+
+func viewEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		dl, err := svc.View(ctx, req.domainID, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toViewRes(dl), nil
+	}
+}
+
+func replayEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Replay(ctx, req.domainID, req.id); err != nil {
+			return nil, err
+		}
+
+		return noContentRes{}, nil
+	}
+}
+
+func replayAllEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(allReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		rep, err := svc.ReplayAll(ctx, req.domainID)
+		if err != nil {
+			return nil, err
+		}
+
+		return replayAllRes{Replayed: rep.Replayed, Failed: rep.Failed}, nil
+	}
+}
+
+func purgeEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deadLetterReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Purge(ctx, req.domainID, req.id); err != nil {
+			return nil, err
+		}
+
+		return noContentRes{}, nil
+	}
+}
+
+func purgeAllEndpoint(svc deadletter.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(allReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		if err := svc.PurgeAll(ctx, req.domainID); err != nil {
+			return nil, err
+		}
+
+		return noContentRes{}, nil
+	}
+}
+
+func toViewRes(dl deadletter.DeadLetter) viewRes {
+	return viewRes{
+		ID:        dl.ID,
+		Domain:    dl.Domain,
+		Channel:   dl.Channel,
+		Format:    dl.Format,
+		Message:   dl.Message,
+		Error:     dl.Error,
+		Attempts:  dl.Attempts,
+		CreatedAt: dl.CreatedAt,
+	}
+}
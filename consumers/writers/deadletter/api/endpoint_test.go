@@ -0,0 +1,277 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	"github.com/absmach/magistrala/consumers/writers/deadletter/api"
+	"github.com/absmach/magistrala/consumers/writers/deadletter/mocks"
+	"github.com/absmach/magistrala/internal/testsutil"
+	smqlog "github.com/absmach/supermq/logger"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	validToken   = testsutil.ValidToken
+	invalidToken = testsutil.InvalidToken
+	domainID     = "c8bbd7b0-3c17-4a77-a3e3-91ed4a0a6b18"
+	id           = "5de9b29a-feb9-11ed-be56-0242ac120002"
+)
+
+var dl = deadletter.DeadLetter{
+	ID:        id,
+	Domain:    domainID,
+	Channel:   "channel",
+	Format:    deadletter.FormatSenML,
+	Message:   []byte(`[{"channel":"channel","name":"temperature","value":20}]`),
+	Error:     "invalid message representation",
+	Attempts:  1,
+	CreatedAt: time.Now(),
+}
+
+func newServer(t *testing.T) (*httptest.Server, *mocks.Service, *authzmocks.Authorization) {
+	svc := mocks.NewService(t)
+	ts, authz := testsutil.NewAPIServer("/deadletters", func(authn smqauthn.Authentication, authz smqauthz.Authorization) http.Handler {
+		return api.MakeHandler(svc, authn, authz, smqlog.NewMock())
+	})
+
+	return ts, svc, authz
+}
+
+func TestList(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		path     string
+		token    string
+		offset   uint64
+		limit    uint64
+		authzErr error
+		status   int
+		svcErr   error
+	}{
+		{
+			desc:   "list dead letters",
+			path:   "/deadletters/" + domainID,
+			token:  validToken,
+			limit:  20,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list dead letters with offset and limit",
+			path:   "/deadletters/" + domainID + "?offset=10&limit=5",
+			token:  validToken,
+			offset: 10,
+			limit:  5,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list dead letters with invalid limit",
+			path:   "/deadletters/" + domainID + "?limit=1001",
+			token:  validToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:     "list dead letters by non-member of domain",
+			path:     "/deadletters/" + domainID,
+			token:    validToken,
+			limit:    20,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "list dead letters with invalid token",
+			path:   "/deadletters/" + domainID,
+			token:  invalidToken,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "list dead letters without token",
+			path:   "/deadletters/" + domainID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			svcCall := svc.On("List", mock.Anything, domainID, tc.offset, tc.limit).Return(deadletter.Page{Total: 1, DeadLetters: []deadletter.DeadLetter{dl}}, tc.svcErr)
+			status, _ := testsutil.Request(t, ts, http.MethodGet, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			svcCall.Unset()
+			authzCall.Unset()
+		})
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		method   string
+		path     string
+		token    string
+		svcCall  string
+		authzErr error
+		svcErr   error
+		status   int
+	}{
+		{
+			desc:    "view dead letter",
+			method:  http.MethodGet,
+			path:    "/deadletters/" + domainID + "/" + id,
+			token:   validToken,
+			svcCall: "View",
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "view non-existing dead letter",
+			method:  http.MethodGet,
+			path:    "/deadletters/" + domainID + "/" + id,
+			token:   validToken,
+			svcCall: "View",
+			svcErr:  svcerr.ErrNotFound,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "replay dead letter",
+			method:  http.MethodPost,
+			path:    "/deadletters/" + domainID + "/" + id + "/replay",
+			token:   validToken,
+			svcCall: "Replay",
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:    "replay dead letter that fails again",
+			method:  http.MethodPost,
+			path:    "/deadletters/" + domainID + "/" + id + "/replay",
+			token:   validToken,
+			svcCall: "Replay",
+			svcErr:  deadletter.ErrReplay,
+			status:  http.StatusInternalServerError,
+		},
+		{
+			desc:    "purge dead letter",
+			method:  http.MethodDelete,
+			path:    "/deadletters/" + domainID + "/" + id,
+			token:   validToken,
+			svcCall: "Purge",
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:     "purge dead letter by non-member of domain",
+			method:   http.MethodDelete,
+			path:     "/deadletters/" + domainID + "/" + id,
+			token:    validToken,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "purge dead letter with invalid token",
+			method: http.MethodDelete,
+			path:   "/deadletters/" + domainID + "/" + id,
+			token:  invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			var svcCall *mock.Call
+			switch tc.svcCall {
+			case "View":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID, id).Return(dl, tc.svcErr)
+			case "Replay", "Purge":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID, id).Return(tc.svcErr)
+			}
+			status, _ := testsutil.Request(t, ts, tc.method, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			if svcCall != nil {
+				svcCall.Unset()
+			}
+			authzCall.Unset()
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		method   string
+		path     string
+		token    string
+		svcCall  string
+		authzErr error
+		status   int
+	}{
+		{
+			desc:    "replay all dead letters",
+			method:  http.MethodPost,
+			path:    "/deadletters/" + domainID + "/replay",
+			token:   validToken,
+			svcCall: "ReplayAll",
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "purge all dead letters",
+			method:  http.MethodDelete,
+			path:    "/deadletters/" + domainID,
+			token:   validToken,
+			svcCall: "PurgeAll",
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:     "purge all dead letters by non-member of domain",
+			method:   http.MethodDelete,
+			path:     "/deadletters/" + domainID,
+			token:    validToken,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "purge all dead letters without token",
+			method: http.MethodDelete,
+			path:   "/deadletters/" + domainID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			var svcCall *mock.Call
+			switch tc.svcCall {
+			case "ReplayAll":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID).Return(deadletter.ReplayReport{Replayed: 1}, nil)
+			case "PurgeAll":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID).Return(nil)
+			}
+			status, _ := testsutil.Request(t, ts, tc.method, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			if svcCall != nil {
+				svcCall.Unset()
+			}
+			authzCall.Unset()
+		})
+	}
+}
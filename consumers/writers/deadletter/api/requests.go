@@ -0,0 +1,50 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import apiutil "github.com/absmach/supermq/api/http/util"
+
+const maxLimitSize = 1000
+
+type listReq struct {
+	domainID string
+	offset   uint64
+	limit    uint64
+}
+
+func (req listReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	if req.limit < 1 || req.limit > maxLimitSize {
+		return apiutil.ErrLimitSize
+	}
+	return nil
+}
+
+type deadLetterReq struct {
+	domainID string
+	id       string
+}
+
+func (req deadLetterReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	if req.id == "" {
+		return apiutil.ErrMissingID
+	}
+	return nil
+}
+
+type allReq struct {
+	domainID string
+}
+
+func (req allReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	return nil
+}
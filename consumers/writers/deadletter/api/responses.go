@@ -0,0 +1,92 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/absmach/supermq"
+)
+
+var (
+	_ supermq.Response = (*viewRes)(nil)
+	_ supermq.Response = (*listRes)(nil)
+	_ supermq.Response = (*replayAllRes)(nil)
+	_ supermq.Response = (*noContentRes)(nil)
+)
+
+type viewRes struct {
+	ID        string          `json:"id"`
+	Domain    string          `json:"domain,omitempty"`
+	Channel   string          `json:"channel,omitempty"`
+	Format    string          `json:"format"`
+	Message   json.RawMessage `json:"message"`
+	Error     string          `json:"error"`
+	Attempts  uint64          `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (res viewRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Total       uint64    `json:"total"`
+	Offset      uint64    `json:"offset"`
+	Limit       uint64    `json:"limit"`
+	DeadLetters []viewRes `json:"dead_letters"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type replayAllRes struct {
+	Replayed uint64 `json:"replayed"`
+	Failed   uint64 `json:"failed"`
+}
+
+func (res replayAllRes) Code() int {
+	return http.StatusOK
+}
+
+func (res replayAllRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res replayAllRes) Empty() bool {
+	return false
+}
+
+type noContentRes struct{}
+
+func (res noContentRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res noContentRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res noContentRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	api "github.com/absmach/supermq/api/http"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	offsetKey = "offset"
+	limitKey  = "limit"
+	defOffset = 0
+	defLimit  = 20
+)
+
+// MakeHandler returns a HTTP handler for dead letter API endpoints. Requests
+// are authenticated with the user token and authorized by the membership of
+// the user in the domain of the dead letters. The handler is mounted on the
+// /deadletters path of the writer's HTTP server.
+func MakeHandler(svc deadletter.Service, authn smqauthn.Authentication, authz smqauthz.Authorization, logger *slog.Logger) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, api.EncodeError)),
+	}
+
+	mux := chi.NewRouter()
+
+	mux.Route("/{domainID}", func(r chi.Router) {
+		r.Use(api.AuthenticateMiddleware(authn, true))
+
+		r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+			listEndpoint(svc, authz),
+			decodeList,
+			api.EncodeResponse,
+			opts...,
+		), "list_dead_letters").ServeHTTP)
+
+		r.Delete("/", otelhttp.NewHandler(kithttp.NewServer(
+			purgeAllEndpoint(svc, authz),
+			decodeAll,
+			api.EncodeResponse,
+			opts...,
+		), "purge_dead_letters").ServeHTTP)
+
+		r.Post("/replay", otelhttp.NewHandler(kithttp.NewServer(
+			replayAllEndpoint(svc, authz),
+			decodeAll,
+			api.EncodeResponse,
+			opts...,
+		), "replay_dead_letters").ServeHTTP)
+
+		r.Get("/{id}", otelhttp.NewHandler(kithttp.NewServer(
+			viewEndpoint(svc, authz),
+			decodeDeadLetter,
+			api.EncodeResponse,
+			opts...,
+		), "view_dead_letter").ServeHTTP)
+
+		r.Delete("/{id}", otelhttp.NewHandler(kithttp.NewServer(
+			purgeEndpoint(svc, authz),
+			decodeDeadLetter,
+			api.EncodeResponse,
+			opts...,
+		), "purge_dead_letter").ServeHTTP)
+
+		r.Post("/{id}/replay", otelhttp.NewHandler(kithttp.NewServer(
+			replayEndpoint(svc, authz),
+			decodeDeadLetter,
+			api.EncodeResponse,
+			opts...,
+		), "replay_dead_letter").ServeHTTP)
+	})
+
+	return mux
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	offset, err := apiutil.ReadNumQuery[uint64](r, offsetKey, defOffset)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	limit, err := apiutil.ReadNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	req := listReq{
+		domainID: chi.URLParam(r, "domainID"),
+		offset:   offset,
+		limit:    limit,
+	}
+
+	return req, nil
+}
+
+func decodeDeadLetter(_ context.Context, r *http.Request) (interface{}, error) {
+	req := deadLetterReq{
+		domainID: chi.URLParam(r, "domainID"),
+		id:       chi.URLParam(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeAll(_ context.Context, r *http.Request) (interface{}, error) {
+	return allReq{domainID: chi.URLParam(r, "domainID")}, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/supermq"
+	"github.com/absmach/supermq/consumers"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/messaging"
+)
+
+// protocol is the protocol of the published dead letters.
+const protocol = "deadletter"
+
+// ErrDeadLetter indicates a failure to dead-letter a message.
+var ErrDeadLetter = errors.New("failed to dead-letter message")
+
+var (
+	_ consumers.BlockingConsumer = (*consumer)(nil)
+	_ DeadLetterer               = (*deadLetterer)(nil)
+)
+
+type consumer struct {
+	consumer consumers.BlockingConsumer
+	dl       DeadLetterer
+	cfg      Config
+}
+
+// NewConsumer returns the consumer that retries the messages the consumer
+// fails to save because of transient database failures, and dead-letters
+// the messages that still can't be saved. When the message can't be
+// dead-lettered, the error is returned, so the message isn't acknowledged
+// and is redelivered by the broker.
+func NewConsumer(c consumers.BlockingConsumer, dl DeadLetterer, cfg Config) consumers.BlockingConsumer {
+	return &consumer{
+		consumer: c,
+		dl:       dl,
+		cfg:      cfg,
+	}
+}
+
+func (c *consumer) ConsumeBlocking(ctx context.Context, message interface{}) error {
+	var err error
+	var attempts uint64
+	backoff := c.cfg.Backoff
+	for {
+		attempts++
+		if err = c.consumer.ConsumeBlocking(ctx, message); err == nil {
+			return nil
+		}
+		if !errors.Contains(err, writers.ErrTransient) || attempts > c.cfg.Retries {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(2*backoff, c.cfg.MaxBackoff)
+	}
+
+	// Messages of stopped consumers are redelivered.
+	if ctx.Err() != nil {
+		return err
+	}
+
+	if dlErr := c.dl.DeadLetter(ctx, message, err, attempts); dlErr != nil {
+		return errors.Wrap(ErrDeadLetter, dlErr)
+	}
+
+	return nil
+}
+
+type deadLetterer struct {
+	repo   Repository
+	pub    messaging.Publisher
+	idp    supermq.IDProvider
+	cfg    Config
+	logger *slog.Logger
+}
+
+// NewDeadLetterer returns the dead-letterer that publishes the dead letters
+// together with the error and the number of attempts, and stores a copy of
+// them to be replayed. Dead letters are published first, since the store is
+// often the database that failed to save the message. The error is returned
+// when the dead letter can't be published, and storing the copy is best
+// effort.
+func NewDeadLetterer(repo Repository, pub messaging.Publisher, idp supermq.IDProvider, cfg Config, logger *slog.Logger) DeadLetterer {
+	return &deadLetterer{
+		repo:   repo,
+		pub:    pub,
+		idp:    idp,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (d *deadLetterer) DeadLetter(ctx context.Context, message interface{}, cause error, attempts uint64) error {
+	dl, err := encode(message)
+	if err != nil {
+		return err
+	}
+	if dl.ID, err = d.idp.ID(); err != nil {
+		return err
+	}
+	dl.Domain = writers.Domain(ctx)
+	dl.Error = cause.Error()
+	dl.Attempts = attempts
+	dl.CreatedAt = time.Now().UTC()
+
+	payload, err := json.Marshal(dl)
+	if err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	msg := &messaging.Message{
+		Domain:   dl.Domain,
+		Channel:  dl.Channel,
+		Protocol: protocol,
+		Payload:  payload,
+		Created:  dl.CreatedAt.UnixNano(),
+	}
+	if err := d.pub.Publish(ctx, d.cfg.Subject, msg); err != nil {
+		return err
+	}
+
+	args := []any{
+		slog.String("id", dl.ID),
+		slog.String("channel", dl.Channel),
+		slog.Uint64("attempts", dl.Attempts),
+		slog.String("error", dl.Error),
+	}
+	// Published dead letters are kept by the broker even if they can't be
+	// replayed from the store.
+	if err := d.repo.Save(ctx, dl); err != nil {
+		args = append(args, slog.Any("store_error", err))
+	}
+	d.logger.Warn("Message dead-lettered", args...)
+
+	return nil
+}
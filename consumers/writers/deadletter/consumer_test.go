@@ -0,0 +1,149 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	"github.com/absmach/magistrala/consumers/writers/deadletter/mocks"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/absmach/supermq/pkg/errors"
+	pubsubmocks "github.com/absmach/supermq/pkg/messaging/mocks"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const subject = "writers"
+
+var (
+	errSave        = errors.New("failed to save message")
+	errInvalid     = errors.Wrap(errSave, errors.New("invalid message representation"))
+	errTransient   = errors.Wrap(errSave, errors.Wrap(writers.ErrTransient, errors.New("connection refused")))
+	errRepository  = errors.New("repository failure")
+	errPublish     = errors.New("publish failure")
+	senmlMessages  = []senml.Message{{Channel: "channel", Name: "temperature", Value: new(float64), Time: 1}}
+	consumerConfig = deadletter.Config{
+		Subject:    subject,
+		Retries:    2,
+		Backoff:    time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+	}
+)
+
+// writer fails to save messages with its errors, in order.
+type writer struct {
+	errs  []error
+	calls int
+}
+
+func (w *writer) ConsumeBlocking(_ context.Context, _ interface{}) error {
+	w.calls++
+	if len(w.errs) == 0 {
+		return nil
+	}
+	err := w.errs[0]
+	w.errs = w.errs[1:]
+
+	return err
+}
+
+func TestConsumeBlocking(t *testing.T) {
+	cases := []struct {
+		desc         string
+		errs         []error
+		calls        int
+		deadLettered bool
+		attempts     uint64
+		saveErr      error
+		publishErr   error
+		err          error
+	}{
+		{
+			desc:  "consume saved message",
+			calls: 1,
+		},
+		{
+			desc:         "consume invalid message",
+			errs:         []error{errInvalid},
+			calls:        1,
+			deadLettered: true,
+			attempts:     1,
+		},
+		{
+			desc:  "consume message saved after transient failures",
+			errs:  []error{errTransient, errTransient},
+			calls: 3,
+		},
+		{
+			desc:         "consume message with persistent transient failures",
+			errs:         []error{errTransient, errTransient, errTransient},
+			calls:        3,
+			deadLettered: true,
+			attempts:     3,
+		},
+		{
+			desc:         "consume invalid message with failed dead letter publish",
+			errs:         []error{errInvalid},
+			calls:        1,
+			deadLettered: true,
+			attempts:     1,
+			publishErr:   errPublish,
+			err:          deadletter.ErrDeadLetter,
+		},
+		{
+			desc:         "consume invalid message with failed dead letter save",
+			errs:         []error{errInvalid},
+			calls:        1,
+			deadLettered: true,
+			attempts:     1,
+			saveErr:      errRepository,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			pub := pubsubmocks.NewPubSub(t)
+			w := &writer{errs: tc.errs}
+			c := deadletter.NewConsumer(w, deadletter.NewDeadLetterer(repo, pub, uuid.NewMock(), consumerConfig, smqlog.NewMock()), consumerConfig)
+
+			if tc.deadLettered {
+				pub.On("Publish", mock.Anything, subject, mock.Anything).Return(tc.publishErr)
+				if tc.publishErr == nil {
+					repo.On("Save", mock.Anything, mock.MatchedBy(func(dl deadletter.DeadLetter) bool {
+						return dl.Format == deadletter.FormatSenML && dl.Domain == domain && dl.Channel == "channel" && dl.Attempts == tc.attempts && dl.Error == tc.errs[len(tc.errs)-1].Error()
+					})).Return(tc.saveErr)
+				}
+			}
+
+			err := c.ConsumeBlocking(writers.WithDomain(context.Background(), domain), senmlMessages)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			assert.Equal(t, tc.calls, w.calls, fmt.Sprintf("%s: expected %d attempts, got %d", tc.desc, tc.calls, w.calls))
+		})
+	}
+}
+
+func TestConsumeBlockingCanceled(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	pub := pubsubmocks.NewPubSub(t)
+	w := &writer{errs: []error{errTransient, errTransient, errTransient}}
+	cfg := consumerConfig
+	cfg.Backoff = time.Hour
+	c := deadletter.NewConsumer(w, deadletter.NewDeadLetterer(repo, pub, uuid.NewMock(), cfg, smqlog.NewMock()), cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Messages of stopped consumers are redelivered, not dead-lettered.
+	err := c.ConsumeBlocking(ctx, senmlMessages)
+	assert.True(t, errors.Contains(err, writers.ErrTransient), fmt.Sprintf("expected error %s, got %s", writers.ErrTransient, err))
+	assert.Equal(t, 1, w.calls, fmt.Sprintf("expected 1 attempt, got %d", w.calls))
+}
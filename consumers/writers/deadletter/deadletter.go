@@ -0,0 +1,107 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Formats of the dead-lettered messages.
+const (
+	// FormatSenML is the format of SenML messages.
+	FormatSenML = "senml"
+	// FormatJSON is the format of JSON messages.
+	FormatJSON = "json"
+	// FormatUnknown is the format of messages writers don't support.
+	FormatUnknown = "unknown"
+)
+
+// Config contains the dead-letter handling configuration.
+type Config struct {
+	// Subject is the topic dead letters are published to.
+	Subject string `env:"SUBJECT"     envDefault:"writers"`
+	// Retries is the number of times transient failures are retried.
+	Retries uint64 `env:"RETRIES"     envDefault:"3"`
+	// Backoff is the wait before the first retry. It doubles on each retry,
+	// up to MaxBackoff.
+	Backoff    time.Duration `env:"BACKOFF"     envDefault:"1s"`
+	MaxBackoff time.Duration `env:"MAX_BACKOFF" envDefault:"30s"`
+}
+
+// DeadLetter represents a message the writer failed to save.
+type DeadLetter struct {
+	ID        string          `json:"id"`
+	Domain    string          `json:"domain,omitempty"`
+	Channel   string          `json:"channel,omitempty"`
+	Format    string          `json:"format"`
+	Message   json.RawMessage `json:"message"`
+	Error     string          `json:"error"`
+	Attempts  uint64          `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Page represents a page of dead letters.
+type Page struct {
+	Total       uint64
+	Offset      uint64
+	Limit       uint64
+	DeadLetters []DeadLetter
+}
+
+// ReplayReport contains the outcome of replaying all dead letters.
+type ReplayReport struct {
+	Replayed uint64
+	Failed   uint64
+}
+
+// Repository specifies a dead letter persistence API.
+type Repository interface {
+	// Save persists the dead letter.
+	Save(ctx context.Context, dl DeadLetter) error
+
+	// Retrieve retrieves the dead letter of the domain for the given ID.
+	Retrieve(ctx context.Context, domainID, id string) (DeadLetter, error)
+
+	// RetrieveAll retrieves the dead letters of the domain from the oldest
+	// to the newest.
+	RetrieveAll(ctx context.Context, domainID string, offset, limit uint64) (Page, error)
+
+	// Remove removes the dead letter of the domain for the given ID.
+	Remove(ctx context.Context, domainID, id string) error
+
+	// RemoveAll removes all the dead letters of the domain.
+	RemoveAll(ctx context.Context, domainID string) error
+}
+
+// DeadLetterer specifies an API for dead-lettering messages.
+type DeadLetterer interface {
+	// DeadLetter dead-letters the message the writer failed to save after
+	// the attempts because of the cause.
+	DeadLetter(ctx context.Context, message interface{}, cause error, attempts uint64) error
+}
+
+// Service specifies an API for recovering the dead letters of domains.
+type Service interface {
+	// List lists the dead letters of the domain from the oldest to the
+	// newest.
+	List(ctx context.Context, domainID string, offset, limit uint64) (Page, error)
+
+	// View retrieves the dead letter of the domain for the given ID.
+	View(ctx context.Context, domainID, id string) (DeadLetter, error)
+
+	// Replay saves the message of the dead letter and removes the dead
+	// letter. Dead letters that fail again are kept.
+	Replay(ctx context.Context, domainID, id string) error
+
+	// ReplayAll replays all the dead letters of the domain.
+	ReplayAll(ctx context.Context, domainID string) (ReplayReport, error)
+
+	// Purge removes the dead letter without saving its message.
+	Purge(ctx context.Context, domainID, id string) error
+
+	// PurgeAll removes all the dead letters of the domain.
+	PurgeAll(ctx context.Context, domainID string) error
+}
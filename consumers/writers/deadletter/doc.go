@@ -0,0 +1,9 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deadletter contains the handling of messages that writers fail
+// to save. Transient database failures are retried with backoff, and the
+// messages that still can't be saved are published as dead letters. Their
+// stored copies can be replayed by the members of their domain once the
+// failure is fixed.
+package deadletter
@@ -0,0 +1,50 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import migrate "github.com/rubenv/sql-migrate"
+
+// Migration of the dead letters table. Writers add it to their migrations.
+func Migration() *migrate.MemoryMigrationSource {
+	return &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "dead_letters_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS dead_letters (
+                        id          VARCHAR(36) PRIMARY KEY,
+                        channel     VARCHAR(254),
+                        format      VARCHAR(32) NOT NULL,
+                        message     JSONB NOT NULL,
+                        error       TEXT,
+                        attempts    BIGINT NOT NULL,
+                        created_at  TIMESTAMP NOT NULL
+                    )`,
+					`CREATE INDEX IF NOT EXISTS idx_dead_letters_created_at ON dead_letters (created_at, id)`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS dead_letters",
+				},
+			},
+			{
+				Id: "dead_letters_2",
+				Up: []string{
+					`ALTER TABLE dead_letters ADD COLUMN IF NOT EXISTS domain VARCHAR(36)`,
+				},
+				Down: []string{
+					`ALTER TABLE dead_letters DROP COLUMN IF EXISTS domain`,
+				},
+			},
+			{
+				Id: "dead_letters_3",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS idx_dead_letters_domain ON dead_letters (domain, created_at, id)`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS idx_dead_letters_domain`,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ deadletter.Repository = (*repository)(nil)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of dead letter repository.
+func New(db *sqlx.DB) deadletter.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (repo *repository) Save(ctx context.Context, dl deadletter.DeadLetter) error {
+	q := `INSERT INTO dead_letters (id, domain, channel, format, message, error, attempts, created_at)
+          VALUES (:id, :domain, :channel, :format, :message, :error, :attempts, :created_at)`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, toDBDeadLetter(dl)); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return errors.Wrap(repoerr.ErrConflict, err)
+		}
+		return errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (repo *repository) Retrieve(ctx context.Context, domainID, id string) (deadletter.DeadLetter, error) {
+	q := `SELECT id, domain, channel, format, message, error, attempts, created_at FROM dead_letters
+          WHERE domain = $1 AND id = $2`
+
+	var dbdl dbDeadLetter
+	if err := repo.db.QueryRowxContext(ctx, q, domainID, id).StructScan(&dbdl); err != nil {
+		if err == sql.ErrNoRows {
+			return deadletter.DeadLetter{}, errors.Wrap(repoerr.ErrNotFound, err)
+		}
+		return deadletter.DeadLetter{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return fromDBDeadLetter(dbdl), nil
+}
+
+func (repo *repository) RetrieveAll(ctx context.Context, domainID string, offset, limit uint64) (deadletter.Page, error) {
+	q := `SELECT id, domain, channel, format, message, error, attempts, created_at FROM dead_letters
+          WHERE domain = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`
+
+	rows, err := repo.db.QueryxContext(ctx, q, domainID, limit, offset)
+	if err != nil {
+		return deadletter.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	page := deadletter.Page{
+		Offset:      offset,
+		Limit:       limit,
+		DeadLetters: []deadletter.DeadLetter{},
+	}
+	for rows.Next() {
+		var dbdl dbDeadLetter
+		if err := rows.StructScan(&dbdl); err != nil {
+			return deadletter.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		page.DeadLetters = append(page.DeadLetters, fromDBDeadLetter(dbdl))
+	}
+	if err := rows.Err(); err != nil {
+		return deadletter.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	if err := repo.db.GetContext(ctx, &page.Total, `SELECT COUNT(*) FROM dead_letters WHERE domain = $1`, domainID); err != nil {
+		return deadletter.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return page, nil
+}
+
+func (repo *repository) Remove(ctx context.Context, domainID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM dead_letters WHERE domain = $1 AND id = $2`, domainID, id)
+	if err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repoerr.ErrNotFound
+	}
+
+	return nil
+}
+
+func (repo *repository) RemoveAll(ctx context.Context, domainID string) error {
+	if _, err := repo.db.ExecContext(ctx, `DELETE FROM dead_letters WHERE domain = $1`, domainID); err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbDeadLetter struct {
+	ID        string         `db:"id"`
+	Domain    sql.NullString `db:"domain"`
+	Channel   sql.NullString `db:"channel"`
+	Format    string         `db:"format"`
+	Message   []byte         `db:"message"`
+	Error     sql.NullString `db:"error"`
+	Attempts  uint64         `db:"attempts"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func toDBDeadLetter(dl deadletter.DeadLetter) dbDeadLetter {
+	return dbDeadLetter{
+		ID:        dl.ID,
+		Domain:    sql.NullString{String: dl.Domain, Valid: dl.Domain != ""},
+		Channel:   sql.NullString{String: dl.Channel, Valid: dl.Channel != ""},
+		Format:    dl.Format,
+		Message:   dl.Message,
+		Error:     sql.NullString{String: dl.Error, Valid: dl.Error != ""},
+		Attempts:  dl.Attempts,
+		CreatedAt: dl.CreatedAt,
+	}
+}
+
+func fromDBDeadLetter(dbdl dbDeadLetter) deadletter.DeadLetter {
+	return deadletter.DeadLetter{
+		ID:        dbdl.ID,
+		Domain:    dbdl.Domain.String,
+		Channel:   dbdl.Channel.String,
+		Format:    dbdl.Format,
+		Message:   dbdl.Message,
+		Error:     dbdl.Error.String,
+		Attempts:  dbdl.Attempts,
+		CreatedAt: dbdl.CreatedAt,
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	"github.com/absmach/magistrala/consumers/writers/deadletter/postgres"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	numDeadLetters = 10
+	domainID       = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	otherDomainID  = "6de9b29a-feb9-11ed-be56-0242ac120002"
+)
+
+func newDeadLetter(t *testing.T, domainID string, created time.Time) deadletter.DeadLetter {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return deadletter.DeadLetter{
+		ID:        id,
+		Domain:    domainID,
+		Channel:   id,
+		Format:    deadletter.FormatSenML,
+		Message:   []byte(`[{"channel":"` + id + `","name":"temperature","value":20}]`),
+		Error:     "invalid message representation",
+		Attempts:  1,
+		CreatedAt: created.UTC().Truncate(time.Microsecond),
+	}
+}
+
+func cleanup(t *testing.T) {
+	_, err := db.Exec("DELETE FROM dead_letters")
+	require.Nil(t, err, fmt.Sprintf("failed to clean dead letters: %s", err))
+}
+
+func TestSave(t *testing.T) {
+	t.Cleanup(func() { cleanup(t) })
+	repo := postgres.New(db)
+
+	dl := newDeadLetter(t, domainID, time.Now())
+
+	cases := []struct {
+		desc string
+		dl   deadletter.DeadLetter
+		err  error
+	}{
+		{
+			desc: "save dead letter",
+			dl:   dl,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate dead letter",
+			dl:   dl,
+			err:  repoerr.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.Save(context.Background(), tc.dl)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestRetrieve(t *testing.T) {
+	t.Cleanup(func() { cleanup(t) })
+	repo := postgres.New(db)
+
+	dl := newDeadLetter(t, domainID, time.Now())
+	err := repo.Save(context.Background(), dl)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		domain string
+		id     string
+		dl     deadletter.DeadLetter
+		err    error
+	}{
+		{
+			desc:   "retrieve existing dead letter",
+			domain: domainID,
+			id:     dl.ID,
+			dl:     dl,
+			err:    nil,
+		},
+		{
+			desc:   "retrieve non-existing dead letter",
+			domain: domainID,
+			id:     "non-existing",
+			err:    repoerr.ErrNotFound,
+		},
+		{
+			desc:   "retrieve dead letter of other domain",
+			domain: otherDomainID,
+			id:     dl.ID,
+			err:    repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := repo.Retrieve(context.Background(), tc.domain, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.err == nil {
+				assert.JSONEq(t, string(tc.dl.Message), string(ret.Message), fmt.Sprintf("%s: expected message %s, got %s", tc.desc, tc.dl.Message, ret.Message))
+				ret.Message = tc.dl.Message
+				assert.Equal(t, tc.dl, ret, fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.dl, ret))
+			}
+		})
+	}
+}
+
+func TestRetrieveAll(t *testing.T) {
+	t.Cleanup(func() { cleanup(t) })
+	repo := postgres.New(db)
+
+	now := time.Now()
+	var dls []deadletter.DeadLetter
+	for i := 0; i < numDeadLetters; i++ {
+		dl := newDeadLetter(t, domainID, now.Add(time.Duration(i)*time.Second))
+		err := repo.Save(context.Background(), dl)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		dls = append(dls, dl)
+	}
+	err := repo.Save(context.Background(), newDeadLetter(t, otherDomainID, now))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		offset uint64
+		limit  uint64
+		ids    []string
+	}{
+		{
+			desc:   "retrieve all dead letters",
+			offset: 0,
+			limit:  numDeadLetters,
+			ids:    ids(dls),
+		},
+		{
+			desc:   "retrieve dead letters with offset and limit",
+			offset: 2,
+			limit:  3,
+			ids:    ids(dls[2:5]),
+		},
+		{
+			desc:   "retrieve dead letters with offset out of range",
+			offset: numDeadLetters,
+			limit:  numDeadLetters,
+			ids:    []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := repo.RetrieveAll(context.Background(), domainID, tc.offset, tc.limit)
+			assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			assert.Equal(t, uint64(numDeadLetters), page.Total, fmt.Sprintf("%s: expected total %d, got %d", tc.desc, numDeadLetters, page.Total))
+			assert.Equal(t, tc.ids, ids(page.DeadLetters), fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.ids, ids(page.DeadLetters)))
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	t.Cleanup(func() { cleanup(t) })
+	repo := postgres.New(db)
+
+	dl := newDeadLetter(t, domainID, time.Now())
+	err := repo.Save(context.Background(), dl)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		domain string
+		id     string
+		err    error
+	}{
+		{
+			desc:   "remove dead letter of other domain",
+			domain: otherDomainID,
+			id:     dl.ID,
+			err:    repoerr.ErrNotFound,
+		},
+		{
+			desc:   "remove existing dead letter",
+			domain: domainID,
+			id:     dl.ID,
+			err:    nil,
+		},
+		{
+			desc:   "remove removed dead letter",
+			domain: domainID,
+			id:     dl.ID,
+			err:    repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.Remove(context.Background(), tc.domain, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestRemoveAll(t *testing.T) {
+	t.Cleanup(func() { cleanup(t) })
+	repo := postgres.New(db)
+
+	for i := 0; i < numDeadLetters; i++ {
+		err := repo.Save(context.Background(), newDeadLetter(t, domainID, time.Now()))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+	err := repo.Save(context.Background(), newDeadLetter(t, otherDomainID, time.Now()))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = repo.RemoveAll(context.Background(), domainID)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	page, err := repo.RetrieveAll(context.Background(), domainID, 0, numDeadLetters)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected no dead letters, got %d", page.Total))
+
+	page, err = repo.RetrieveAll(context.Background(), otherDomainID, 0, numDeadLetters)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected dead letters of other domain kept, got %d", page.Total))
+}
+
+func ids(dls []deadletter.DeadLetter) []string {
+	ret := []string{}
+	for _, dl := range dls {
+		ret = append(ret, dl.ID)
+	}
+
+	return ret
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/supermq/consumers"
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+)
+
+// replayLimit is the number of dead letters replayed at once.
+const replayLimit = 100
+
+var (
+	// ErrUnsupportedFormat indicates a dead letter of a message the writers
+	// don't support.
+	ErrUnsupportedFormat = errors.New("unsupported dead letter format")
+
+	// ErrReplay indicates a failure to save the message of a dead letter.
+	ErrReplay = errors.New("failed to replay dead letter")
+)
+
+var _ Service = (*service)(nil)
+
+type service struct {
+	repo     Repository
+	consumer consumers.BlockingConsumer
+}
+
+// New instantiates the dead letter service. Messages of replayed dead
+// letters are saved by the consumer.
+func New(repo Repository, consumer consumers.BlockingConsumer) Service {
+	return &service{
+		repo:     repo,
+		consumer: consumer,
+	}
+}
+
+func (svc *service) List(ctx context.Context, domainID string, offset, limit uint64) (Page, error) {
+	return svc.repo.RetrieveAll(ctx, domainID, offset, limit)
+}
+
+func (svc *service) View(ctx context.Context, domainID, id string) (DeadLetter, error) {
+	return svc.repo.Retrieve(ctx, domainID, id)
+}
+
+func (svc *service) Replay(ctx context.Context, domainID, id string) error {
+	dl, err := svc.repo.Retrieve(ctx, domainID, id)
+	if err != nil {
+		return err
+	}
+
+	return svc.replay(ctx, dl)
+}
+
+func (svc *service) ReplayAll(ctx context.Context, domainID string) (ReplayReport, error) {
+	var rep ReplayReport
+	for {
+		// Replayed dead letters are removed, so only the failed ones are
+		// skipped.
+		page, err := svc.repo.RetrieveAll(ctx, domainID, rep.Failed, replayLimit)
+		if err != nil {
+			return rep, err
+		}
+		for _, dl := range page.DeadLetters {
+			if err := svc.replay(ctx, dl); err != nil {
+				rep.Failed++
+				continue
+			}
+			rep.Replayed++
+		}
+		if len(page.DeadLetters) < replayLimit {
+			return rep, nil
+		}
+	}
+}
+
+func (svc *service) Purge(ctx context.Context, domainID, id string) error {
+	return svc.repo.Remove(ctx, domainID, id)
+}
+
+func (svc *service) PurgeAll(ctx context.Context, domainID string) error {
+	return svc.repo.RemoveAll(ctx, domainID)
+}
+
+func (svc *service) replay(ctx context.Context, dl DeadLetter) error {
+	msg, err := decode(dl)
+	if err != nil {
+		return errors.Wrap(ErrReplay, err)
+	}
+	// Messages are saved as consumed from the domain of the dead letter.
+	if err := svc.consumer.ConsumeBlocking(writers.WithDomain(ctx, dl.Domain), msg); err != nil {
+		return errors.Wrap(ErrReplay, err)
+	}
+
+	return svc.repo.Remove(ctx, dl.Domain, dl.ID)
+}
+
+// decode returns the message of the dead letter as consumed by writers.
+func decode(dl DeadLetter) (interface{}, error) {
+	switch dl.Format {
+	case FormatSenML:
+		var msgs []senml.Message
+		if err := json.Unmarshal(dl.Message, &msgs); err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return msgs, nil
+	case FormatJSON:
+		var msgs smqjson.Messages
+		if err := json.Unmarshal(dl.Message, &msgs); err != nil {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return msgs, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// encode returns the dead letter of the message as consumed by writers.
+func encode(message interface{}) (DeadLetter, error) {
+	var dl DeadLetter
+	switch m := message.(type) {
+	case []senml.Message:
+		dl.Format = FormatSenML
+		if len(m) > 0 {
+			dl.Channel = m[0].Channel
+		}
+	case smqjson.Messages:
+		dl.Format = FormatJSON
+		if len(m.Data) > 0 {
+			dl.Channel = m.Data[0].Channel
+		}
+	default:
+		dl.Format = FormatUnknown
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return DeadLetter{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	dl.Message = data
+
+	return dl, nil
+}
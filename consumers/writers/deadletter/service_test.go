@@ -0,0 +1,177 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/deadletter"
+	"github.com/absmach/magistrala/consumers/writers/deadletter/mocks"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	id     = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	domain = "c8bbd7b0-3c17-4a77-a3e3-91ed4a0a6b18"
+)
+
+var (
+	senmlLetter = deadletter.DeadLetter{
+		ID:        id,
+		Domain:    domain,
+		Channel:   "channel",
+		Format:    deadletter.FormatSenML,
+		Message:   []byte(`[{"channel":"channel","name":"temperature","value":20,"time":1}]`),
+		Error:     errInvalid.Error(),
+		Attempts:  1,
+		CreatedAt: time.Now(),
+	}
+	jsonLetter = deadletter.DeadLetter{
+		ID:      id,
+		Domain:  domain,
+		Channel: "channel",
+		Format:  deadletter.FormatJSON,
+		Message: []byte(`{"Data":[{"channel":"channel","created":1,"payload":{"temperature":20}}],"Format":"readings"}`),
+	}
+	unknownLetter = deadletter.DeadLetter{
+		ID:      id,
+		Domain:  domain,
+		Format:  deadletter.FormatUnknown,
+		Message: []byte(`{}`),
+	}
+)
+
+// recorder records the messages it saves and their domains.
+type recorder struct {
+	writer
+	msgs    []interface{}
+	domains []string
+}
+
+func (r *recorder) ConsumeBlocking(ctx context.Context, msg interface{}) error {
+	if err := r.writer.ConsumeBlocking(ctx, msg); err != nil {
+		return err
+	}
+	r.msgs = append(r.msgs, msg)
+	r.domains = append(r.domains, writers.Domain(ctx))
+
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	value := 20.0
+	cases := []struct {
+		desc        string
+		dl          deadletter.DeadLetter
+		retrieveErr error
+		saveErr     error
+		msg         interface{}
+		removed     bool
+		err         error
+	}{
+		{
+			desc:    "replay SenML dead letter",
+			dl:      senmlLetter,
+			msg:     []senml.Message{{Channel: "channel", Name: "temperature", Value: &value, Time: 1}},
+			removed: true,
+		},
+		{
+			desc:    "replay JSON dead letter",
+			dl:      jsonLetter,
+			msg:     smqjson.Messages{Data: []smqjson.Message{{Channel: "channel", Created: 1, Payload: map[string]interface{}{"temperature": 20.0}}}, Format: "readings"},
+			removed: true,
+		},
+		{
+			desc:        "replay non-existing dead letter",
+			retrieveErr: repoerr.ErrNotFound,
+			err:         repoerr.ErrNotFound,
+		},
+		{
+			desc:    "replay dead letter that fails again",
+			dl:      senmlLetter,
+			saveErr: errInvalid,
+			err:     deadletter.ErrReplay,
+		},
+		{
+			desc: "replay dead letter of unsupported format",
+			dl:   unknownLetter,
+			err:  deadletter.ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			w := &recorder{writer: writer{errs: []error{tc.saveErr}}}
+			svc := deadletter.New(repo, w)
+
+			repo.On("Retrieve", mock.Anything, domain, id).Return(tc.dl, tc.retrieveErr)
+			if tc.removed {
+				repo.On("Remove", mock.Anything, domain, id).Return(nil)
+			}
+
+			err := svc.Replay(context.Background(), domain, id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.msg != nil {
+				assert.Equal(t, []interface{}{tc.msg}, w.msgs, fmt.Sprintf("%s: expected message %v, got %v", tc.desc, tc.msg, w.msgs))
+				assert.Equal(t, []string{tc.dl.Domain}, w.domains, fmt.Sprintf("%s: expected domain %s, got %v", tc.desc, tc.dl.Domain, w.domains))
+			}
+		})
+	}
+}
+
+func TestReplayAll(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	failed := senmlLetter
+	failed.ID = "failed"
+	w := &recorder{writer: writer{errs: []error{nil, errInvalid, nil}}}
+	svc := deadletter.New(repo, w)
+
+	dls := []deadletter.DeadLetter{senmlLetter, failed, jsonLetter}
+	repo.On("RetrieveAll", mock.Anything, domain, uint64(0), uint64(100)).Return(deadletter.Page{Total: 3, DeadLetters: dls}, nil)
+	repo.On("Remove", mock.Anything, domain, id).Return(nil).Twice()
+
+	rep, err := svc.ReplayAll(context.Background(), domain)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	expected := deadletter.ReplayReport{Replayed: 2, Failed: 1}
+	assert.Equal(t, expected, rep, fmt.Sprintf("expected report %v, got %v", expected, rep))
+}
+
+func TestPurge(t *testing.T) {
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "purge existing dead letter",
+			id:   id,
+		},
+		{
+			desc: "purge non-existing dead letter",
+			id:   "non-existing",
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			svc := deadletter.New(repo, &writer{})
+
+			repo.On("Remove", mock.Anything, domain, tc.id).Return(tc.err)
+			err := svc.Purge(context.Background(), domain, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
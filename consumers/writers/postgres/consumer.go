@@ -7,59 +7,121 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	schemaspg "github.com/absmach/magistrala/consumers/writers/schemas/postgres"
 	"github.com/absmach/supermq/consumers"
 	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
 	smqjson "github.com/absmach/supermq/pkg/transformers/json"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/gofrs/uuid/v5"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx" // required for DB access
 )
 
+// Multi-row inserts are limited by the 65535 parameters of a PostgreSQL
+// statement, so the rows are inserted in chunks.
+const (
+	maxParams    = 65535
+	maxSenMLRows = maxParams / 15
+	maxJSONRows  = maxParams / 8
+	// jsonColumns is the number of columns of typed JSON tables, besides
+	// the columns of the schema fields.
+	jsonColumns = 8
+)
+
 var (
 	errInvalidMessage = errors.New("invalid message representation")
 	errSaveMessage    = errors.New("failed to save message to postgres database")
 	errTransRollback  = errors.New("failed to rollback transaction")
 	errNoTable        = errors.New("relation does not exist")
+	errInvalidFormat  = errors.New("invalid message format")
+	errSchema         = errors.New("failed to retrieve message format schema")
 )
 
 var _ consumers.BlockingConsumer = (*postgresRepo)(nil)
 
+// Conflict clauses of the records that are already stored.
+const (
+	ignoreConflict = ` ON CONFLICT DO NOTHING`
+	senmlUpdate    = ` ON CONFLICT (id) DO UPDATE SET protocol = EXCLUDED.protocol, unit = EXCLUDED.unit,
+          value = EXCLUDED.value, string_value = EXCLUDED.string_value, bool_value = EXCLUDED.bool_value,
+          data_value = EXCLUDED.data_value, sum = EXCLUDED.sum, update_time = EXCLUDED.update_time`
+	jsonUpdate = ` ON CONFLICT (id) DO UPDATE SET protocol = EXCLUDED.protocol, payload = EXCLUDED.payload`
+)
+
 type postgresRepo struct {
-	db *sqlx.DB
+	db         *sqlx.DB
+	onConflict string
+	senml      *batch.Batcher[senmlMessage]
+	json       *batch.Batcher[jsonMessage]
+	schemas    schemas.Repository
 }
 
-// New returns new PostgreSQL writer.
-func New(db *sqlx.DB) consumers.BlockingConsumer {
-	return &postgresRepo{db: db}
+// New returns new PostgreSQL writer. Records of messages consumed
+// concurrently are inserted together, as configured by the batch config.
+// Records have deterministic IDs, so the records of redelivered messages
+// are ignored or update the stored records, as configured.
+//
+// JSON messages of the formats with a schema registered by the domain of
+// the message are validated and stored in the typed table of the domain
+// format. JSON messages of other formats are stored in the tables named
+// after their format.
+func New(db *sqlx.DB, cfg writers.Config) consumers.BlockingConsumer {
+	pr := &postgresRepo{db: db, onConflict: cfg.OnConflict, schemas: schemaspg.New(db)}
+	pr.senml = batch.New(cfg.Batch, pr.insertSenml)
+	pr.json = batch.New(cfg.Batch, pr.saveJSON)
+
+	return pr
 }
 
 func (pr postgresRepo) ConsumeBlocking(ctx context.Context, message interface{}) (err error) {
 	switch m := message.(type) {
 	case smqjson.Messages:
-		return pr.saveJSON(ctx, m)
+		return pr.addJSON(ctx, m)
 	default:
-		return pr.saveSenml(ctx, m)
+		return pr.addSenml(ctx, m)
 	}
 }
 
-func (pr postgresRepo) saveSenml(ctx context.Context, messages interface{}) (err error) {
+func (pr postgresRepo) addSenml(ctx context.Context, messages interface{}) error {
 	msgs, ok := messages.([]senml.Message)
 	if !ok {
 		return errSaveMessage
 	}
-	q := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
+
+	domain := domainOf(ctx)
+	records := make([]senmlMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		id := writers.RecordID(msg.Channel, msg.Publisher, msg.Subtopic, msg.Name, strconv.FormatFloat(msg.Time, 'f', -1, 64))
+		records = append(records, senmlMessage{Message: msg, ID: id, Domain: domain})
+	}
+
+	return pr.senml.Add(ctx, records)
+}
+
+func (pr postgresRepo) insertSenml(ctx context.Context, msgs []senmlMessage) (err error) {
+	q := `INSERT INTO messages (id, domain, channel, subtopic, publisher, protocol,
           name, unit, value, string_value, bool_value, data_value, sum,
           time, update_time)
-          VALUES (:id, :channel, :subtopic, :publisher, :protocol, :name, :unit,
+          VALUES (:id, :domain, :channel, :subtopic, :publisher, :protocol, :name, :unit,
           :value, :string_value, :bool_value, :data_value, :sum,
-          :time, :update_time);`
+          :time, :update_time)`
+	q += pr.conflict(senmlUpdate)
+
+	// A statement can't update the same record twice.
+	msgs = writers.Dedupe(msgs, func(m senmlMessage) string { return m.ID })
 
 	tx, err := pr.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
+		return saveError(err)
 	}
 	defer func() {
 		if err != nil {
@@ -70,17 +132,12 @@ func (pr postgresRepo) saveSenml(ctx context.Context, messages interface{}) (err
 		}
 
 		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
+			err = saveError(err)
 		}
 	}()
 
-	for _, msg := range msgs {
-		id, err := uuid.NewV4()
-		if err != nil {
-			return err
-		}
-		m := senmlMessage{Message: msg, ID: id.String()}
-		if _, err := tx.NamedExec(q, m); err != nil {
+	for _, rows := range chunk(msgs, maxSenMLRows) {
+		if _, err := tx.NamedExecContext(ctx, q, rows); err != nil {
 			pgErr, ok := err.(*pgconn.PgError)
 			if ok {
 				if pgErr.Code == pgerrcode.InvalidTextRepresentation {
@@ -88,29 +145,111 @@ func (pr postgresRepo) saveSenml(ctx context.Context, messages interface{}) (err
 				}
 			}
 
+			return saveError(err)
+		}
+	}
+
+	if err := markLate(ctx, tx, msgs); err != nil {
+		return saveError(err)
+	}
+
+	return nil
+}
+
+func (pr postgresRepo) addJSON(ctx context.Context, msgs smqjson.Messages) error {
+	format := strings.ToLower(msgs.Format)
+	domain := writers.Domain(ctx)
+	schema, err := pr.schema(ctx, domain, format)
+	if err != nil {
+		return err
+	}
+
+	table := format
+	switch {
+	case schema != nil:
+		table = schema.Table()
+	case domain != "":
+		if err := schemas.ValidateFormat(format); err != nil {
+			return errors.Wrap(errSaveMessage, errInvalidFormat)
+		}
+		table = schemas.Table(domain, format)
+	case schemas.ValidateTable(format) != nil:
+		return errors.Wrap(errSaveMessage, errInvalidFormat)
+	}
+
+	dbDomain := domainOf(ctx)
+	records := make([]jsonMessage, 0, len(msgs.Data))
+	for i, m := range msgs.Data {
+		if schema != nil {
+			if err := schema.Validate(m.Payload); err != nil {
+				return errors.Wrap(errSaveMessage, err)
+			}
+		}
+		dbmsg, err := toJSONMessage(m, i)
+		if err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
+		dbmsg.table = table
+		dbmsg.Domain = dbDomain
+		dbmsg.schema = schema
+		dbmsg.data = m.Payload
+		records = append(records, dbmsg)
+	}
+
+	return pr.json.Add(ctx, records)
+}
+
+// schema returns the schema the domain registered for the format, or nil
+// if the format has no schema.
+func (pr postgresRepo) schema(ctx context.Context, domain, format string) (*schemas.Schema, error) {
+	if domain == "" || schemas.ValidateFormat(format) != nil {
+		return nil, nil
+	}
+
+	s, err := pr.schemas.Retrieve(ctx, domain, format)
+	switch {
+	case err == nil:
+		return &s, nil
+	case errors.Contains(err, repoerr.ErrNotFound):
+		return nil, nil
+	default:
+		return nil, errors.Wrap(errSaveMessage, errors.Wrap(writers.ErrTransient, errors.Wrap(errSchema, err)))
 	}
-	return err
 }
 
-func (pr postgresRepo) saveJSON(ctx context.Context, msgs smqjson.Messages) error {
+func (pr postgresRepo) saveJSON(ctx context.Context, msgs []jsonMessage) error {
+	err := pr.insertJSON(ctx, msgs)
+	if err != errNoTable {
+		return err
+	}
+
+	// Tables of schemas are created when the schemas are registered.
+	var untyped []jsonMessage
+	for _, m := range msgs {
+		if m.schema == nil {
+			untyped = append(untyped, m)
+		}
+	}
+	for _, table := range tables(untyped) {
+		if err := pr.createTable(table); err != nil {
+			return saveError(err)
+		}
+	}
+
 	if err := pr.insertJSON(ctx, msgs); err != nil {
 		if err == errNoTable {
-			if err := pr.createTable(msgs.Format); err != nil {
-				return err
-			}
-			return pr.insertJSON(ctx, msgs)
+			return errors.Wrap(errSaveMessage, err)
 		}
 		return err
 	}
+
 	return nil
 }
 
-func (pr postgresRepo) insertJSON(ctx context.Context, msgs smqjson.Messages) error {
+func (pr postgresRepo) insertJSON(ctx context.Context, msgs []jsonMessage) (err error) {
 	tx, err := pr.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
+		return saveError(err)
 	}
 	defer func() {
 		if err != nil {
@@ -121,41 +260,148 @@ func (pr postgresRepo) insertJSON(ctx context.Context, msgs smqjson.Messages) er
 		}
 
 		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
+			err = saveError(err)
 		}
 	}()
 
-	q := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload)
-          VALUES (:id, :channel, :created, :subtopic, :publisher, :protocol, :payload);`
-	q = fmt.Sprintf(q, msgs.Format)
+	q := `INSERT INTO %s (id, domain, channel, created, subtopic, publisher, protocol, payload)
+          VALUES (:id, :domain, :channel, :created, :subtopic, :publisher, :protocol, :payload)`
+	q += pr.conflict(jsonUpdate)
 
-	for _, m := range msgs.Data {
-		var dbmsg jsonMessage
-		dbmsg, err = toJSONMessage(m)
-		if err != nil {
-			return errors.Wrap(errSaveMessage, err)
+	msgs = writers.Dedupe(msgs, func(m jsonMessage) string { return m.table + m.ID })
+
+	for _, table := range tables(msgs) {
+		var rows []jsonMessage
+		var schema *schemas.Schema
+		for _, m := range msgs {
+			if m.table != table {
+				continue
+			}
+			rows = append(rows, m)
+			// Schema versions only add fields, so the latest version has
+			// the columns of all the rows.
+			if m.schema != nil && (schema == nil || m.schema.Version > schema.Version) {
+				schema = m.schema
+			}
 		}
 
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				switch pgErr.Code {
-				case pgerrcode.InvalidTextRepresentation:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				case pgerrcode.UndefinedTable:
-					return errNoTable
+		size := maxJSONRows
+		if schema != nil {
+			size = maxParams / (jsonColumns + len(schema.Fields))
+		}
+		for _, rows := range chunk(rows, size) {
+			var err error
+			switch schema {
+			case nil:
+				_, err = tx.NamedExecContext(ctx, fmt.Sprintf(q, pgx.Identifier{table}.Sanitize()), rows)
+			default:
+				err = pr.insertTyped(ctx, tx, *schema, rows)
+			}
+			if err != nil {
+				pgErr, ok := err.(*pgconn.PgError)
+				if ok {
+					switch pgErr.Code {
+					case pgerrcode.InvalidTextRepresentation, pgerrcode.DatatypeMismatch:
+						return errors.Wrap(errSaveMessage, errInvalidMessage)
+					case pgerrcode.UndefinedTable:
+						return errNoTable
+					}
 				}
+				return saveError(err)
 			}
-			return err
 		}
 	}
+
 	return nil
 }
 
-func (pr postgresRepo) createTable(name string) error {
+// insertTyped inserts the rows into the typed table of the schema, with
+// the payload values of the schema fields in their columns.
+func (pr postgresRepo) insertTyped(ctx context.Context, tx *sqlx.Tx, s schemas.Schema, rows []jsonMessage) error {
+	cols := []string{"id", "created", "domain", "channel", "subtopic", "publisher", "protocol", "payload"}
+	for _, f := range s.Fields {
+		cols = append(cols, pgx.Identifier{f.Name}.Sanitize())
+	}
+
+	values := make([]string, 0, len(rows))
+	args := make([]interface{}, 0, len(rows)*len(cols))
+	for _, r := range rows {
+		args = append(args, r.ID, r.Created, r.Domain, r.Channel, r.Subtopic, r.Publisher, r.Protocol, r.Payload)
+		for _, f := range s.Fields {
+			v, err := columnValue(f, r.data[f.Name])
+			if err != nil {
+				return err
+			}
+			args = append(args, v)
+		}
+
+		params := make([]string, len(cols))
+		for i := range params {
+			params[i] = fmt.Sprintf("$%d", len(args)-len(cols)+i+1)
+		}
+		values = append(values, "("+strings.Join(params, ", ")+")")
+	}
+
+	q := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s`, pgx.Identifier{s.Table()}.Sanitize(), strings.Join(cols, ", "), strings.Join(values, ", "))
+	update := jsonUpdate
+	for _, col := range cols[len(cols)-len(s.Fields):] {
+		update += fmt.Sprintf(", %[1]s = EXCLUDED.%[1]s", col)
+	}
+	q += pr.conflict(update)
+
+	_, err := tx.ExecContext(ctx, q, args...)
+	return err
+}
+
+// columnValue returns the value of the field column. Objects and arrays are
+// stored as JSON.
+func columnValue(f schemas.Field, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case schemas.TypeInteger:
+		if n, ok := v.(float64); ok {
+			return int64(n), nil
+		}
+	case schemas.TypeObject, schemas.TypeArray:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	return v, nil
+}
+
+// saveError wraps the error of saving messages, marking the errors that may
+// not happen again as transient, so the messages are retried.
+func saveError(err error) error {
+	if writers.IsTransient(err) {
+		err = errors.Wrap(writers.ErrTransient, err)
+	}
+
+	return errors.Wrap(errSaveMessage, err)
+}
+
+// conflict returns the update clause or the clause that ignores the records
+// that are already stored, as configured.
+func (pr postgresRepo) conflict(update string) string {
+	if pr.onConflict == writers.OnConflictUpdate {
+		return update
+	}
+
+	return ignoreConflict
+}
+
+func (pr postgresRepo) createTable(table string) error {
+	name := pgx.Identifier{table}.Sanitize()
 	q := `CREATE TABLE IF NOT EXISTS %s (
             id            UUID,
             created       BIGINT,
+            domain        UUID,
             channel       VARCHAR(254),
             subtopic      VARCHAR(254),
             publisher     VARCHAR(254),
@@ -165,31 +411,37 @@ func (pr postgresRepo) createTable(name string) error {
         )`
 	q = fmt.Sprintf(q, name)
 
+	if _, err := pr.db.Exec(q); err != nil {
+		return err
+	}
+
+	// Index the payload for containment filters of the readers.
+	q = fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (payload jsonb_path_ops)`, pgx.Identifier{schemas.Index(table, "payload")}.Sanitize(), name)
 	_, err := pr.db.Exec(q)
 	return err
 }
 
 type senmlMessage struct {
 	senml.Message
-	ID string `db:"id"`
+	ID     string  `db:"id"`
+	Domain *string `db:"domain"`
 }
 
 type jsonMessage struct {
-	ID        string `db:"id"`
-	Channel   string `db:"channel"`
-	Created   int64  `db:"created"`
-	Subtopic  string `db:"subtopic"`
-	Publisher string `db:"publisher"`
-	Protocol  string `db:"protocol"`
-	Payload   []byte `db:"payload"`
+	ID        string  `db:"id"`
+	Domain    *string `db:"domain"`
+	Channel   string  `db:"channel"`
+	Created   int64   `db:"created"`
+	Subtopic  string  `db:"subtopic"`
+	Publisher string  `db:"publisher"`
+	Protocol  string  `db:"protocol"`
+	Payload   []byte  `db:"payload"`
+	table     string
+	schema    *schemas.Schema
+	data      map[string]interface{}
 }
 
-func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
-	id, err := uuid.NewV4()
-	if err != nil {
-		return jsonMessage{}, err
-	}
-
+func toJSONMessage(msg smqjson.Message, index int) (jsonMessage, error) {
 	data := []byte("{}")
 	if msg.Payload != nil {
 		b, err := json.Marshal(msg.Payload)
@@ -200,7 +452,7 @@ func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
 	}
 
 	m := jsonMessage{
-		ID:        id.String(),
+		ID:        writers.JSONRecordID(msg, index, data),
 		Channel:   msg.Channel,
 		Created:   msg.Created,
 		Subtopic:  msg.Subtopic,
@@ -211,3 +463,38 @@ func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
 
 	return m, nil
 }
+
+// domainOf returns the domain of the messages of the context, or nil if
+// the domain is unknown, so the domain of their records is NULL.
+func domainOf(ctx context.Context) *string {
+	domain := writers.Domain(ctx)
+	if domain == "" {
+		return nil
+	}
+
+	return &domain
+}
+
+// tables returns the tables of the JSON messages in the order of their
+// first message.
+func tables(msgs []jsonMessage) []string {
+	var ret []string
+	for _, m := range msgs {
+		if !slices.Contains(ret, m.table) {
+			ret = append(ret, m.table)
+		}
+	}
+
+	return ret
+}
+
+// chunk splits the records into chunks of at most size records.
+func chunk[T any](records []T, size int) [][]T {
+	var ret [][]T
+	for len(records) > size {
+		ret = append(ret, records[:size])
+		records = records[size:]
+	}
+
+	return append(ret, records)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers/postgres"
+	pgclient "github.com/absmach/supermq/pkg/postgres"
+	"github.com/gofrs/uuid/v5"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationUpgrade(t *testing.T) {
+	_, err := db.Exec(`CREATE DATABASE upgrade`)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	cfg := dbConfig
+	cfg.Name = "upgrade"
+
+	// The schema of the writer before the migrations that followed
+	// messages_2, with the records it stored.
+	baseline := migrate.MemoryMigrationSource{Migrations: postgres.Migration().Migrations[:2]}
+	udb, err := pgclient.Setup(cfg, baseline)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	defer udb.Close()
+
+	chanID := uuid.Must(uuid.NewV4()).String()
+	pubID := uuid.Must(uuid.NewV4()).String()
+	_, err = udb.Exec(`INSERT INTO messages (id, channel, subtopic, publisher, protocol, name, value, time)
+        VALUES ($1, $2, '', $3, 'mqtt', 'temperature', 20, 1)`, uuid.Must(uuid.NewV4()).String(), chanID, pubID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	_, err = udb.Exec(`CREATE TABLE IF NOT EXISTS sensors (id UUID, created BIGINT, channel VARCHAR(254), subtopic VARCHAR(254),
+        publisher VARCHAR(254), protocol TEXT, payload JSONB, PRIMARY KEY (id))`)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	_, err = udb.Exec(`INSERT INTO sensors (id, created, channel, subtopic, publisher, protocol, payload)
+        VALUES ($1, 1, $2, '', $3, 'mqtt', '{"temperature": 20}')`, uuid.Must(uuid.NewV4()).String(), chanID, pubID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	udb, err = pgclient.Setup(cfg, *postgres.Migration())
+	require.Nil(t, err, fmt.Sprintf("expected no error upgrading from the baseline schema got %s", err))
+	defer udb.Close()
+
+	var applied []string
+	err = udb.Select(&applied, `SELECT id FROM gorp_migrations ORDER BY applied_at, id`)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, len(postgres.Migration().Migrations), len(applied), fmt.Sprintf("expected all migrations applied got %v", applied))
+
+	var n int
+	err = udb.Get(&n, `SELECT COUNT(*) FROM messages WHERE channel = $1 AND domain IS NULL`, chanID)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("expected 1 message without domain got %d", n))
+
+	err = udb.Get(&n, `SELECT COUNT(*) FROM sensors WHERE channel = $1 AND domain IS NULL`, chanID)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("expected 1 JSON message without domain got %d", n))
+}
@@ -0,0 +1,249 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/retention"
+	"github.com/absmach/magistrala/readers"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// Table for SenML messages.
+const defTable = "messages"
+
+var (
+	errDeleteMessages = errors.New("failed to delete expired messages from postgres database")
+	errRollup         = errors.New("failed to refresh rollups in postgres database")
+)
+
+var _ retention.Repository = (*retentionRepo)(nil)
+
+type retentionRepo struct {
+	db *sqlx.DB
+}
+
+// NewRetention returns new PostgreSQL retention repository, which deletes
+// expired messages and refreshes the rollups.
+func NewRetention(db *sqlx.DB) retention.Repository {
+	return &retentionRepo{db: db}
+}
+
+func (rr retentionRepo) Delete(ctx context.Context, p retention.Policy, before time.Time, exceptions []retention.Policy) (uint64, error) {
+	tables, err := rr.jsonTables(ctx)
+	if err != nil {
+		return 0, errors.Wrap(errDeleteMessages, err)
+	}
+
+	cond, args := scopeCondition(p, exceptions, before.UnixNano())
+
+	var total uint64
+	q := fmt.Sprintf(`DELETE FROM %s WHERE time < $1 AND %s`, defTable, cond)
+	n, err := rr.delete(ctx, q, args)
+	if err != nil {
+		return total, errors.Wrap(errDeleteMessages, err)
+	}
+	total += n
+
+	for _, table := range tables {
+		q := fmt.Sprintf(`DELETE FROM %s WHERE created < $1 AND %s`, pgx.Identifier{table}.Sanitize(), cond)
+		n, err := rr.delete(ctx, q, args)
+		if err != nil {
+			return total, errors.Wrap(errDeleteMessages, err)
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+func (rr retentionRepo) delete(ctx context.Context, q string, args []interface{}) (uint64, error) {
+	res, err := rr.db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(n), nil
+}
+
+// jsonTables returns the tables of JSON messages.
+func (rr retentionRepo) jsonTables(ctx context.Context) ([]string, error) {
+	var tables []string
+	q := `SELECT table_name FROM information_schema.columns
+          WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'`
+	if err := rr.db.SelectContext(ctx, &tables, q); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func (rr retentionRepo) Rollup(ctx context.Context, from, to time.Time) (err error) {
+	tx, err := rr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errRollup, err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(errRollup, err)
+		}
+	}()
+
+	// Messages missed while the rollups weren't refreshed are aggregated
+	// too, so the rollups have no gaps.
+	var refreshed int64
+	q := `SELECT refreshed FROM rollups WHERE name = $1`
+	switch err := tx.GetContext(ctx, &refreshed, q, readers.Rollups[0].Table); {
+	case err == nil:
+		from = time.Unix(0, min(from.UnixNano(), refreshed))
+	case err != sql.ErrNoRows:
+		return errors.Wrap(errRollup, err)
+	}
+
+	// Buckets of the messages stored after their buckets were aggregated
+	// are aggregated again.
+	var late []int64
+	q = `DELETE FROM rollups_pending WHERE time < $1 RETURNING time`
+	if err := tx.SelectContext(ctx, &late, q, to.UnixNano()); err != nil {
+		return errors.Wrap(errRollup, err)
+	}
+
+	source := defTable
+	since := from
+	for _, r := range readers.Rollups {
+		// Buckets are complete from the first bucket whose source rows
+		// are all aggregated.
+		start := from.Truncate(r.Bucket)
+		since = ceil(since, r.Bucket)
+		for _, s := range retention.Spans(late, r.Bucket) {
+			if !s.Start.Before(start) {
+				break
+			}
+			if _, err := tx.ExecContext(ctx, rollupQuery(r.Table, source), float64(r.Bucket.Nanoseconds()), float64(s.Start.UnixNano()), float64(s.End.UnixNano())); err != nil {
+				return errors.Wrap(errRollup, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, rollupQuery(r.Table, source), float64(r.Bucket.Nanoseconds()), float64(start.UnixNano()), float64(to.UnixNano())); err != nil {
+			return errors.Wrap(errRollup, err)
+		}
+
+		q := `INSERT INTO rollups (name, since, refreshed) VALUES ($1, $2, $3)
+              ON CONFLICT (name) DO UPDATE SET refreshed = GREATEST(rollups.refreshed, EXCLUDED.refreshed)`
+		if _, err := tx.ExecContext(ctx, q, r.Table, since.UnixNano(), to.UnixNano()); err != nil {
+			return errors.Wrap(errRollup, err)
+		}
+		source = r.Table
+	}
+
+	return nil
+}
+
+// markLate marks the buckets of the finest rollup that hold the values of
+// the messages older than the refreshed rollups, so the next refresh
+// aggregates them again.
+func markLate(ctx context.Context, tx *sqlx.Tx, msgs []senmlMessage) error {
+	var times []float64
+	for _, m := range msgs {
+		if m.Value != nil {
+			times = append(times, m.Time)
+		}
+	}
+	if len(times) == 0 {
+		return nil
+	}
+
+	q := `INSERT INTO rollups_pending (time)
+          SELECT DISTINCT CAST(FLOOR(t / $1) * $1 AS BIGINT) FROM UNNEST(CAST($2 AS FLOAT[])) t
+          WHERE t < (SELECT refreshed FROM rollups WHERE name = $3)
+          ON CONFLICT DO NOTHING`
+	_, err := tx.ExecContext(ctx, q, float64(readers.Rollups[0].Bucket.Nanoseconds()), times, readers.Rollups[0].Table)
+
+	return err
+}
+
+// rollupQuery returns the query that aggregates the rows of the source
+// stored between $2 and $3 into the buckets of length $1 of the rollup
+// table. The finest rollup is aggregated from message values, and the
+// others from the rollup finer than them.
+func rollupQuery(table, source string) string {
+	values := `COUNT(value), SUM(value), MIN(value), MAX(value),
+              (ARRAY_AGG(value ORDER BY time))[1], (ARRAY_AGG(value ORDER BY time DESC))[1]`
+	cond := `AND value IS NOT NULL`
+	if source != defTable {
+		values = `SUM(value_count), SUM(value_sum), MIN(value_min), MAX(value_max),
+              (ARRAY_AGG(value_first ORDER BY time))[1], (ARRAY_AGG(value_last ORDER BY time DESC))[1]`
+		cond = ""
+	}
+
+	return fmt.Sprintf(`INSERT INTO %s (time, domain, channel, subtopic, publisher, protocol, name, unit,
+          value_count, value_sum, value_min, value_max, value_first, value_last)
+          SELECT FLOOR(time / $1) * $1 AS bucket, (ARRAY_AGG(domain ORDER BY time DESC))[1], channel, subtopic, publisher,
+          (ARRAY_AGG(protocol ORDER BY time))[1], name, (ARRAY_AGG(unit ORDER BY time))[1], %s
+          FROM %s
+          WHERE time >= $2 AND time < $3 %s
+          GROUP BY bucket, channel, subtopic, publisher, name
+          ON CONFLICT (channel, subtopic, publisher, name, time) DO UPDATE SET domain = EXCLUDED.domain,
+          protocol = EXCLUDED.protocol, unit = EXCLUDED.unit, value_count = EXCLUDED.value_count,
+          value_sum = EXCLUDED.value_sum, value_min = EXCLUDED.value_min, value_max = EXCLUDED.value_max,
+          value_first = EXCLUDED.value_first, value_last = EXCLUDED.value_last`, table, values, source, cond)
+}
+
+// scopeCondition returns the condition that selects the messages in the
+// scope of the policy and not in the scopes of the exceptions, with its
+// arguments following the time argument.
+func scopeCondition(p retention.Policy, exceptions []retention.Policy, before int64) (string, []interface{}) {
+	args := []interface{}{before}
+	conds := []string{"TRUE"}
+	if p.Scope != "" {
+		args = append(args, p.ID)
+		conds = append(conds, fmt.Sprintf("%s = $%d", p.Scope, len(args)))
+	}
+
+	var channels, domains []string
+	for _, e := range exceptions {
+		switch e.Scope {
+		case retention.ScopeChannel:
+			channels = append(channels, e.ID)
+		case retention.ScopeDomain:
+			domains = append(domains, e.ID)
+		}
+	}
+	if len(channels) > 0 {
+		args = append(args, channels)
+		conds = append(conds, fmt.Sprintf("CAST(channel AS TEXT) <> ALL($%d)", len(args)))
+	}
+	if len(domains) > 0 {
+		args = append(args, domains)
+		conds = append(conds, fmt.Sprintf("(domain IS NULL OR CAST(domain AS TEXT) <> ALL($%d))", len(args)))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// ceil returns the time rounded up to a multiple of the duration.
+func ceil(t time.Time, d time.Duration) time.Time {
+	if tr := t.Truncate(d); !tr.Equal(t) {
+		return tr.Add(d)
+	}
+
+	return t
+}
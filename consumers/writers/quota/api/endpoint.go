@@ -0,0 +1,41 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	writersapi "github.com/absmach/magistrala/consumers/writers/api"
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func usageEndpoint(svc quota.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(usageReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		usage, err := svc.Usage(ctx, req.domainID)
+		if err != nil {
+			return nil, err
+		}
+		res := usageRes{Channels: []channelUsageRes{}}
+		for _, u := range usage {
+			res.Messages += u.Messages
+			res.Bytes += u.Bytes
+			res.Limited += u.Limited
+			res.Channels = append(res.Channels, channelUsageRes(u))
+		}
+
+		return res, nil
+	}
+}
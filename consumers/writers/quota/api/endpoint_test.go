@@ -0,0 +1,151 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	"github.com/absmach/magistrala/consumers/writers/quota/api"
+	"github.com/absmach/magistrala/consumers/writers/quota/mocks"
+	"github.com/absmach/magistrala/internal/testsutil"
+	smqlog "github.com/absmach/supermq/logger"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
+	"github.com/absmach/supermq/pkg/errors"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	validToken   = testsutil.ValidToken
+	invalidToken = testsutil.InvalidToken
+	domainID     = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	channelID    = "9b7b1b3f-b1b0-46a8-a717-b8213f9eda3b"
+)
+
+var usage = []quota.Usage{
+	{Domain: domainID, Channel: channelID, Messages: 10, Bytes: 200, Limited: 2},
+	{Domain: domainID, Channel: "other", Messages: 5, Bytes: 100},
+}
+
+type channelUsage struct {
+	Domain   string `json:"domain"`
+	Channel  string `json:"channel"`
+	Messages uint64 `json:"messages"`
+	Bytes    uint64 `json:"bytes"`
+	Limited  uint64 `json:"limited"`
+}
+
+type usageRes struct {
+	Messages uint64         `json:"messages"`
+	Bytes    uint64         `json:"bytes"`
+	Limited  uint64         `json:"limited"`
+	Channels []channelUsage `json:"channels"`
+}
+
+func newServer(t *testing.T) (*httptest.Server, *mocks.Service, *authzmocks.Authorization) {
+	svc := mocks.NewService(t)
+	ts, authz := testsutil.NewAPIServer("/usage", func(authn smqauthn.Authentication, authz smqauthz.Authorization) http.Handler {
+		return api.MakeHandler(svc, authn, authz, smqlog.NewMock())
+	})
+
+	return ts, svc, authz
+}
+
+func TestUsage(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		path     string
+		token    string
+		usage    []quota.Usage
+		authzErr error
+		svcErr   error
+		status   int
+		res      usageRes
+	}{
+		{
+			desc:   "view usage of domain",
+			path:   "/usage/" + domainID,
+			token:  validToken,
+			usage:  usage,
+			status: http.StatusOK,
+			res: usageRes{
+				Messages: 15,
+				Bytes:    300,
+				Limited:  2,
+				Channels: []channelUsage{
+					{Domain: domainID, Channel: channelID, Messages: 10, Bytes: 200, Limited: 2},
+					{Domain: domainID, Channel: "other", Messages: 5, Bytes: 100},
+				},
+			},
+		},
+		{
+			desc:   "view usage of domain without messages",
+			path:   "/usage/" + domainID,
+			token:  validToken,
+			usage:  []quota.Usage{},
+			status: http.StatusOK,
+			res:    usageRes{Channels: []channelUsage{}},
+		},
+		{
+			desc:   "view usage with service error",
+			path:   "/usage/" + domainID,
+			token:  validToken,
+			svcErr: errors.ErrMalformedEntity,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:     "view usage by non-member of domain",
+			path:     "/usage/" + domainID,
+			token:    validToken,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "view usage with invalid token",
+			path:   "/usage/" + domainID,
+			token:  invalidToken,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view usage without token",
+			path:   "/usage/" + domainID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view usage of all domains",
+			path:   "/usage",
+			token:  validToken,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			svcCall := svc.On("Usage", mock.Anything, domainID).Return(tc.usage, tc.svcErr)
+			status, data := testsutil.Request(t, ts, http.MethodGet, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+
+			if tc.status == http.StatusOK {
+				var body usageRes
+				err := json.Unmarshal(data, &body)
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+				assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected response %v got %v", tc.desc, tc.res, body))
+			}
+			svcCall.Unset()
+			authzCall.Unset()
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import apiutil "github.com/absmach/supermq/api/http/util"
+
+type usageReq struct {
+	domainID string
+}
+
+func (req usageReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/absmach/supermq"
+)
+
+var _ supermq.Response = (*usageRes)(nil)
+
+type channelUsageRes struct {
+	Domain   string `json:"domain,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Messages uint64 `json:"messages"`
+	Bytes    uint64 `json:"bytes"`
+	Limited  uint64 `json:"limited"`
+}
+
+type usageRes struct {
+	Messages uint64            `json:"messages"`
+	Bytes    uint64            `json:"bytes"`
+	Limited  uint64            `json:"limited"`
+	Channels []channelUsageRes `json:"channels"`
+}
+
+func (res usageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res usageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res usageRes) Empty() bool {
+	return false
+}
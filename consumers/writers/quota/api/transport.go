@@ -0,0 +1,48 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	api "github.com/absmach/supermq/api/http"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/go-chi/chi/v5"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// MakeHandler returns a HTTP handler for usage API endpoints. Requests are
+// authenticated with the user token and authorized by the membership of the
+// user in the domain. The handler is mounted on the /usage path of the
+// writer's HTTP server.
+func MakeHandler(svc quota.Service, authn smqauthn.Authentication, authz smqauthz.Authorization, logger *slog.Logger) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, api.EncodeError)),
+	}
+
+	mux := chi.NewRouter()
+
+	mux.Route("/{domainID}", func(r chi.Router) {
+		r.Use(api.AuthenticateMiddleware(authn, true))
+
+		r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+			usageEndpoint(svc, authz),
+			decodeUsage,
+			api.EncodeResponse,
+			opts...,
+		), "view_domain_usage").ServeHTTP)
+	})
+
+	return mux
+}
+
+func decodeUsage(_ context.Context, r *http.Request) (interface{}, error) {
+	return usageReq{domainID: chi.URLParam(r, "domainID")}, nil
+}
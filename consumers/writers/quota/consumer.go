@@ -0,0 +1,90 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/supermq/consumers"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ consumers.BlockingConsumer = (*consumer)(nil)
+
+// DeadLetterer dead-letters the messages that exceed the quota.
+type DeadLetterer interface {
+	DeadLetter(ctx context.Context, message interface{}, cause error, attempts uint64) error
+}
+
+type consumer struct {
+	consumer consumers.BlockingConsumer
+	svc      Service
+	action   string
+	dl       DeadLetterer
+	messages metrics.Counter
+	bytes    metrics.Counter
+	limited  metrics.Counter
+}
+
+// NewConsumer returns the consumer that accounts the messages to their
+// domain and channel, and passes to the consumer the messages within the
+// quota of their domain. The messages that exceed the quota are dropped,
+// sampled or dead-lettered. Without a dead-letterer, ErrQuotaExceeded is
+// returned for the messages to dead-letter.
+//
+// The consumer wraps the consumer that retries failed saves, so retried
+// messages are accounted once. Consumed messages and bytes are counted by
+// domain, and the limited messages by domain and action.
+func NewConsumer(c consumers.BlockingConsumer, svc Service, cfg Config, dl DeadLetterer, messages, bytes, limited metrics.Counter) consumers.BlockingConsumer {
+	return &consumer{
+		consumer: c,
+		svc:      svc,
+		action:   cfg.Action,
+		dl:       dl,
+		messages: messages,
+		bytes:    bytes,
+		limited:  limited,
+	}
+}
+
+func (c *consumer) ConsumeBlocking(ctx context.Context, message interface{}) error {
+	domain := writers.Domain(ctx)
+	channel := channelOf(message)
+	size := writers.Size(ctx)
+
+	c.messages.With("domain", domain).Add(1)
+	c.bytes.With("domain", domain).Add(float64(size))
+
+	if c.svc.Admit(domain, channel, size) {
+		return c.consumer.ConsumeBlocking(ctx, message)
+	}
+	c.limited.With("domain", domain, "action", c.action).Add(1)
+
+	if c.action == ActionDeadLetter {
+		if c.dl == nil {
+			return ErrQuotaExceeded
+		}
+		return c.dl.DeadLetter(ctx, message, ErrQuotaExceeded, 0)
+	}
+
+	return nil
+}
+
+func channelOf(message interface{}) string {
+	switch m := message.(type) {
+	case []senml.Message:
+		if len(m) > 0 {
+			return m[0].Channel
+		}
+	case smqjson.Messages:
+		if len(m.Data) > 0 {
+			return m.Data[0].Channel
+		}
+	}
+
+	return ""
+}
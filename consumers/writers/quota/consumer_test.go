@@ -0,0 +1,144 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	"github.com/absmach/magistrala/consumers/writers/quota/mocks"
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// counter sums the counts by their label values.
+type counter struct {
+	labels string
+	counts map[string]float64
+}
+
+func newCounter() *counter {
+	return &counter{counts: map[string]float64{}}
+}
+
+func (c *counter) With(labelValues ...string) metrics.Counter {
+	return &counter{labels: strings.Join(labelValues, ","), counts: c.counts}
+}
+
+func (c *counter) Add(delta float64) {
+	c.counts[c.labels] += delta
+}
+
+// writer records the consumed messages.
+type writer struct {
+	msgs []interface{}
+}
+
+func (w *writer) ConsumeBlocking(_ context.Context, msg interface{}) error {
+	w.msgs = append(w.msgs, msg)
+	return nil
+}
+
+// deadLetterer records the dead-lettered messages.
+type deadLetterer struct {
+	msgs []interface{}
+}
+
+func (dl *deadLetterer) DeadLetter(_ context.Context, msg interface{}, _ error, _ uint64) error {
+	dl.msgs = append(dl.msgs, msg)
+	return nil
+}
+
+func TestConsumeBlocking(t *testing.T) {
+	senmlMessages := []senml.Message{{Channel: channelID, Name: "temperature", Value: new(float64), Time: 1}}
+	jsonMessages := smqjson.Messages{Data: []smqjson.Message{{Channel: channelID, Payload: map[string]interface{}{"temperature": 20.0}}}, Format: "readings"}
+
+	cases := []struct {
+		desc     string
+		action   string
+		msg      interface{}
+		admitted bool
+		consumed bool
+		dl       bool
+		dead     bool
+		limited  float64
+		err      error
+	}{
+		{
+			desc:     "consume SenML message within quota",
+			action:   quota.ActionDrop,
+			msg:      senmlMessages,
+			admitted: true,
+			consumed: true,
+		},
+		{
+			desc:     "consume JSON message within quota",
+			action:   quota.ActionDrop,
+			msg:      jsonMessages,
+			admitted: true,
+			consumed: true,
+		},
+		{
+			desc:    "consume dropped message",
+			action:  quota.ActionDrop,
+			msg:     senmlMessages,
+			limited: 1,
+		},
+		{
+			desc:    "consume message that isn't sampled",
+			action:  quota.ActionSample,
+			msg:     senmlMessages,
+			limited: 1,
+		},
+		{
+			desc:    "consume dead-lettered message",
+			action:  quota.ActionDeadLetter,
+			msg:     jsonMessages,
+			dl:      true,
+			dead:    true,
+			limited: 1,
+		},
+		{
+			desc:    "consume dead-lettered message without dead-letterer",
+			action:  quota.ActionDeadLetter,
+			msg:     jsonMessages,
+			limited: 1,
+			err:     quota.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			w := &writer{}
+			messages, bytes, limited := newCounter(), newCounter(), newCounter()
+			dl := &deadLetterer{}
+			var sink quota.DeadLetterer
+			if tc.dl {
+				sink = dl
+			}
+			c := quota.NewConsumer(w, svc, quota.Config{Action: tc.action}, sink, messages, bytes, limited)
+
+			svc.On("Admit", domainID, channelID, uint64(42)).Return(tc.admitted)
+			ctx := writers.WithSize(writers.WithDomain(context.Background(), domainID), 42)
+			err := c.ConsumeBlocking(ctx, tc.msg)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			assert.Equal(t, tc.consumed, len(w.msgs) == 1, fmt.Sprintf("%s: expected message consumed %t, got %d messages", tc.desc, tc.consumed, len(w.msgs)))
+			assert.Equal(t, tc.dead, len(dl.msgs) == 1, fmt.Sprintf("%s: expected message dead-lettered %t, got %d messages", tc.desc, tc.dead, len(dl.msgs)))
+
+			labels := "domain," + domainID
+			assert.Equal(t, 1.0, messages.counts[labels], fmt.Sprintf("%s: expected 1 message counted, got %v", tc.desc, messages.counts[labels]))
+			assert.Equal(t, 42.0, bytes.counts[labels], fmt.Sprintf("%s: expected 42 bytes counted, got %v", tc.desc, bytes.counts[labels]))
+			lim := limited.counts["domain,"+domainID+",action,"+tc.action]
+			assert.Equal(t, tc.limited, lim, fmt.Sprintf("%s: expected %v limited messages counted, got %v", tc.desc, tc.limited, lim))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package quota contains the ingestion quotas of the writers. Messages and
+// bytes are counted per domain and channel, and the messages of a domain
+// that exceed the limits of the domain within the quota interval are
+// dropped, sampled or dead-lettered.
+package quota
@@ -0,0 +1,133 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Actions taken on the messages that exceed the quota of their domain.
+const (
+	// ActionDrop acknowledges the messages without saving them.
+	ActionDrop = "drop"
+	// ActionSample saves one of every sample rate messages and drops the
+	// others.
+	ActionSample = "sample"
+	// ActionDeadLetter dead-letters the messages, so they can be replayed.
+	ActionDeadLetter = "deadletter"
+)
+
+var (
+	// ErrInvalidLimit indicates a malformed domain limit.
+	ErrInvalidLimit = errors.New("invalid quota limit")
+
+	// ErrInvalidConfig indicates an invalid quota configuration.
+	ErrInvalidConfig = errors.New("invalid quota configuration")
+
+	// ErrQuotaExceeded indicates a message that exceeds the quota of its
+	// domain.
+	ErrQuotaExceeded = errors.New("domain quota exceeded")
+)
+
+// Limit is the number of messages and bytes of a domain the writer saves
+// within the quota interval. Zero is unlimited.
+type Limit struct {
+	Domain   string
+	Messages uint64
+	Bytes    uint64
+}
+
+// UnmarshalText parses the limit of the form <domain>=<messages>[:<bytes>],
+// such as <id>=1000:1048576.
+func (l *Limit) UnmarshalText(text []byte) error {
+	domain, limits, ok := strings.Cut(string(text), "=")
+	if !ok || domain == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidLimit, text)
+	}
+	msgs, bytes, hasBytes := strings.Cut(limits, ":")
+	m, err := strconv.ParseUint(msgs, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidLimit, text)
+	}
+	var b uint64
+	if hasBytes {
+		if b, err = strconv.ParseUint(bytes, 10, 64); err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidLimit, text)
+		}
+	}
+	*l = Limit{Domain: domain, Messages: m, Bytes: b}
+
+	return nil
+}
+
+// exceeded reports whether the messages and bytes exceed the limit.
+func (l Limit) exceeded(msgs, bytes uint64) bool {
+	return (l.Messages > 0 && msgs > l.Messages) || (l.Bytes > 0 && bytes > l.Bytes)
+}
+
+// Config contains the quota configuration.
+type Config struct {
+	// Interval is the period the limits apply to.
+	Interval time.Duration `env:"INTERVAL"    envDefault:"1m"`
+	// Messages and Bytes are the limit of the domains without a limit of
+	// their own. Zero is unlimited.
+	Messages uint64 `env:"MESSAGES"    envDefault:"0"`
+	Bytes    uint64 `env:"BYTES"       envDefault:"0"`
+	// Limits are the limits of domains, which take precedence over the
+	// default limit.
+	Limits []Limit `env:"LIMITS"      envDefault:""`
+	// Action is the action taken on the messages that exceed the limit.
+	Action string `env:"ACTION"      envDefault:"drop"`
+	// SampleRate is the rate of the sampled messages, which saves one of
+	// every SampleRate messages that exceed the limit.
+	SampleRate uint64 `env:"SAMPLE_RATE" envDefault:"10"`
+	// Retention is how long the usage of a channel is kept after its last
+	// message. Zero keeps the usage of every channel.
+	Retention time.Duration `env:"RETENTION"   envDefault:"24h"`
+}
+
+// Validate validates the quota configuration.
+func (cfg Config) Validate() error {
+	switch {
+	case cfg.Interval <= 0:
+		return fmt.Errorf("%w: non-positive interval %s", ErrInvalidConfig, cfg.Interval)
+	case cfg.Action != ActionDrop && cfg.Action != ActionSample && cfg.Action != ActionDeadLetter:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidConfig, cfg.Action)
+	case cfg.Action == ActionSample && cfg.SampleRate == 0:
+		return fmt.Errorf("%w: zero sample rate", ErrInvalidConfig)
+	case cfg.Retention < 0:
+		return fmt.Errorf("%w: negative retention %s", ErrInvalidConfig, cfg.Retention)
+	default:
+		return nil
+	}
+}
+
+// Usage is the number of messages and bytes of a channel the writer
+// consumed since it started, or since the usage was evicted after the
+// retention, and the number of the messages that exceeded
+// the quota of the domain and weren't saved.
+type Usage struct {
+	Domain   string
+	Channel  string
+	Messages uint64
+	Bytes    uint64
+	Limited  uint64
+}
+
+// Service specifies the API of the ingestion quotas.
+type Service interface {
+	// Admit accounts the message of the size in bytes to its domain and
+	// channel, and reports whether the message is saved, which is when
+	// it's within the quota of the domain, or it's sampled.
+	Admit(domain, channel string, size uint64) bool
+
+	// Usage returns the usage of the channels of the domain, or of all the
+	// channels if the domain is empty, ordered by domain and channel.
+	Usage(ctx context.Context, domain string) ([]Usage, error)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	"github.com/caarlos0/env/v11"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitUnmarshalText(t *testing.T) {
+	cases := []struct {
+		desc  string
+		text  string
+		limit quota.Limit
+		err   error
+	}{
+		{
+			desc:  "parse limit of messages",
+			text:  "d1=1000",
+			limit: quota.Limit{Domain: "d1", Messages: 1000},
+		},
+		{
+			desc:  "parse limit of messages and bytes",
+			text:  "d1=1000:1048576",
+			limit: quota.Limit{Domain: "d1", Messages: 1000, Bytes: 1048576},
+		},
+		{
+			desc:  "parse limit of bytes",
+			text:  "d1=0:1048576",
+			limit: quota.Limit{Domain: "d1", Bytes: 1048576},
+		},
+		{
+			desc: "parse limit without domain",
+			text: "=1000",
+			err:  quota.ErrInvalidLimit,
+		},
+		{
+			desc: "parse limit without messages",
+			text: "d1",
+			err:  quota.ErrInvalidLimit,
+		},
+		{
+			desc: "parse limit with invalid messages",
+			text: "d1=1k",
+			err:  quota.ErrInvalidLimit,
+		},
+		{
+			desc: "parse limit with negative messages",
+			text: "d1=-1",
+			err:  quota.ErrInvalidLimit,
+		},
+		{
+			desc: "parse limit with invalid bytes",
+			text: "d1=1000:1MB",
+			err:  quota.ErrInvalidLimit,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var l quota.Limit
+			err := l.UnmarshalText([]byte(tc.text))
+			assert.True(t, errors.Is(err, tc.err), fmt.Sprintf("%s: expected error %v, got %v", tc.desc, tc.err, err))
+			assert.Equal(t, tc.limit, l, fmt.Sprintf("%s: expected limit %v, got %v", tc.desc, tc.limit, l))
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Setenv("MG_WRITER_QUOTA_MESSAGES", "6000")
+	t.Setenv("MG_WRITER_QUOTA_LIMITS", "d1=100:65536,d2=0")
+	t.Setenv("MG_WRITER_QUOTA_ACTION", quota.ActionSample)
+
+	var cfg quota.Config
+	err := env.ParseWithOptions(&cfg, env.Options{Prefix: "MG_WRITER_QUOTA_"})
+	assert.Nil(t, err, fmt.Sprintf("expected no error, got %v", err))
+
+	expected := quota.Config{
+		Interval: time.Minute,
+		Messages: 6000,
+		Limits: []quota.Limit{
+			{Domain: "d1", Messages: 100, Bytes: 65536},
+			{Domain: "d2"},
+		},
+		Action:     quota.ActionSample,
+		SampleRate: 10,
+		Retention:  24 * time.Hour,
+	}
+	assert.Equal(t, expected, cfg, fmt.Sprintf("expected config %v, got %v", expected, cfg))
+}
+
+func TestConfigValidate(t *testing.T) {
+	cases := []struct {
+		desc string
+		cfg  quota.Config
+		err  error
+	}{
+		{
+			desc: "validate config",
+			cfg:  quota.Config{Interval: time.Minute, Action: quota.ActionDrop},
+		},
+		{
+			desc: "validate config with sampling",
+			cfg:  quota.Config{Interval: time.Minute, Action: quota.ActionSample, SampleRate: 10},
+		},
+		{
+			desc: "validate config with dead-lettering",
+			cfg:  quota.Config{Interval: time.Minute, Action: quota.ActionDeadLetter},
+		},
+		{
+			desc: "validate config without interval",
+			cfg:  quota.Config{Action: quota.ActionDrop},
+			err:  quota.ErrInvalidConfig,
+		},
+		{
+			desc: "validate config with unknown action",
+			cfg:  quota.Config{Interval: time.Minute, Action: "throttle"},
+			err:  quota.ErrInvalidConfig,
+		},
+		{
+			desc: "validate config with zero sample rate",
+			cfg:  quota.Config{Interval: time.Minute, Action: quota.ActionSample},
+			err:  quota.ErrInvalidConfig,
+		},
+		{
+			desc: "validate config with negative retention",
+			cfg:  quota.Config{Interval: time.Minute, Action: quota.ActionDrop, Retention: -time.Minute},
+			err:  quota.ErrInvalidConfig,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			assert.True(t, errors.Is(err, tc.err), fmt.Sprintf("%s: expected error %v, got %v", tc.desc, tc.err, err))
+		})
+	}
+}
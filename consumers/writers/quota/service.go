@@ -0,0 +1,151 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ Service = (*service)(nil)
+
+type channelKey struct {
+	domain  string
+	channel string
+}
+
+// window is the usage of a domain within the current quota interval.
+type window struct {
+	start    time.Time
+	messages uint64
+	bytes    uint64
+	// excess is the number of messages that exceeded the limit.
+	excess uint64
+}
+
+// channelUsage is the usage of a channel and the time of its last message.
+type channelUsage struct {
+	Usage
+	seen time.Time
+}
+
+type service struct {
+	cfg    Config
+	limits map[string]Limit
+
+	mu      sync.Mutex
+	usage   map[channelKey]*channelUsage
+	windows map[string]*window
+	// swept is the time the stale usage and windows were last evicted.
+	swept time.Time
+}
+
+// New instantiates the in-memory quota service. Usage is counted since the
+// writer started and isn't shared between writer instances, so the limits
+// apply to each instance. The usage of channels without messages within the
+// retention is evicted, so it doesn't grow with every channel ever seen.
+func New(cfg Config) Service {
+	limits := make(map[string]Limit, len(cfg.Limits))
+	for _, l := range cfg.Limits {
+		limits[l.Domain] = l
+	}
+
+	return &service{
+		cfg:     cfg,
+		limits:  limits,
+		usage:   make(map[channelKey]*channelUsage),
+		windows: make(map[string]*window),
+		swept:   time.Now(),
+	}
+}
+
+func (svc *service) Admit(domain, channel string, size uint64) bool {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	now := time.Now()
+	svc.sweep(now)
+
+	key := channelKey{domain: domain, channel: channel}
+	u, ok := svc.usage[key]
+	if !ok {
+		u = &channelUsage{Usage: Usage{Domain: domain, Channel: channel}}
+		svc.usage[key] = u
+	}
+	u.seen = now
+	u.Messages++
+	u.Bytes += size
+
+	// Messages of unknown domains aren't limited.
+	if domain == "" {
+		return true
+	}
+	limit, ok := svc.limits[domain]
+	if !ok {
+		limit = Limit{Domain: domain, Messages: svc.cfg.Messages, Bytes: svc.cfg.Bytes}
+	}
+	if limit.Messages == 0 && limit.Bytes == 0 {
+		return true
+	}
+
+	w, ok := svc.windows[domain]
+	if !ok || now.Sub(w.start) >= svc.cfg.Interval {
+		w = &window{start: now}
+		svc.windows[domain] = w
+	}
+	w.messages++
+	w.bytes += size
+	if !limit.exceeded(w.messages, w.bytes) {
+		return true
+	}
+
+	w.excess++
+	if svc.cfg.Action == ActionSample && (w.excess-1)%svc.cfg.SampleRate == 0 {
+		return true
+	}
+	u.Limited++
+
+	return false
+}
+
+func (svc *service) Usage(_ context.Context, domain string) ([]Usage, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	usage := []Usage{}
+	for _, u := range svc.usage {
+		if domain == "" || u.Domain == domain {
+			usage = append(usage, u.Usage)
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Domain != usage[j].Domain {
+			return usage[i].Domain < usage[j].Domain
+		}
+		return usage[i].Channel < usage[j].Channel
+	})
+
+	return usage, nil
+}
+
+// sweep evicts the usage of the channels without messages within the
+// retention and the windows past their interval, once per retention.
+func (svc *service) sweep(now time.Time) {
+	if svc.cfg.Retention <= 0 || now.Sub(svc.swept) < svc.cfg.Retention {
+		return
+	}
+	svc.swept = now
+	for key, u := range svc.usage {
+		if now.Sub(u.seen) >= svc.cfg.Retention {
+			delete(svc.usage, key)
+		}
+	}
+	for domain, w := range svc.windows {
+		if now.Sub(w.start) >= svc.cfg.Interval {
+			delete(svc.windows, domain)
+		}
+	}
+}
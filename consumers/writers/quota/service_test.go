@@ -0,0 +1,189 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package quota_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	domainID  = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	otherID   = "6de9b29a-feb9-11ed-be56-0242ac120002"
+	channelID = "9b7b1b3f-b1b0-46a8-a717-b8213f9eda3b"
+)
+
+func TestAdmit(t *testing.T) {
+	cases := []struct {
+		desc     string
+		cfg      quota.Config
+		domain   string
+		size     uint64
+		admitted []bool
+	}{
+		{
+			desc:     "admit messages without limit",
+			cfg:      quota.Config{Interval: time.Minute, Action: quota.ActionDrop},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, true},
+		},
+		{
+			desc:     "admit messages within default limit",
+			cfg:      quota.Config{Interval: time.Minute, Messages: 2, Action: quota.ActionDrop},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, false, false},
+		},
+		{
+			desc:     "admit messages within default bytes limit",
+			cfg:      quota.Config{Interval: time.Minute, Bytes: 25, Action: quota.ActionDrop},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, false},
+		},
+		{
+			desc: "admit messages within domain limit",
+			cfg: quota.Config{
+				Interval: time.Minute,
+				Messages: 1,
+				Limits:   []quota.Limit{{Domain: domainID, Messages: 3}},
+				Action:   quota.ActionDrop,
+			},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, true, false},
+		},
+		{
+			desc: "admit messages of domain without limit",
+			cfg: quota.Config{
+				Interval: time.Minute,
+				Messages: 1,
+				Limits:   []quota.Limit{{Domain: domainID}},
+				Action:   quota.ActionDrop,
+			},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, true},
+		},
+		{
+			desc:     "admit messages of unknown domain",
+			cfg:      quota.Config{Interval: time.Minute, Messages: 1, Action: quota.ActionDrop},
+			size:     10,
+			admitted: []bool{true, true, true},
+		},
+		{
+			desc:     "admit sampled messages",
+			cfg:      quota.Config{Interval: time.Minute, Messages: 1, Action: quota.ActionSample, SampleRate: 3},
+			domain:   domainID,
+			size:     10,
+			admitted: []bool{true, true, false, false, true, false},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc := quota.New(tc.cfg)
+			var limited uint64
+			for i, expected := range tc.admitted {
+				admitted := svc.Admit(tc.domain, channelID, tc.size)
+				assert.Equal(t, expected, admitted, fmt.Sprintf("%s: expected message %d admitted %t, got %t", tc.desc, i, expected, admitted))
+				if !expected {
+					limited++
+				}
+			}
+
+			usage, err := svc.Usage(context.Background(), tc.domain)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			expected := []quota.Usage{{
+				Domain:   tc.domain,
+				Channel:  channelID,
+				Messages: uint64(len(tc.admitted)),
+				Bytes:    uint64(len(tc.admitted)) * tc.size,
+				Limited:  limited,
+			}}
+			assert.Equal(t, expected, usage, fmt.Sprintf("%s: expected usage %v, got %v", tc.desc, expected, usage))
+		})
+	}
+}
+
+func TestAdmitInterval(t *testing.T) {
+	svc := quota.New(quota.Config{Interval: 50 * time.Millisecond, Messages: 1, Action: quota.ActionDrop})
+
+	assert.True(t, svc.Admit(domainID, channelID, 10), "expected message within limit admitted")
+	assert.False(t, svc.Admit(domainID, channelID, 10), "expected message exceeding limit rejected")
+	assert.True(t, svc.Admit(otherID, channelID, 10), "expected message of other domain admitted")
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, svc.Admit(domainID, channelID, 10), "expected message of next interval admitted")
+}
+
+func TestUsageRetention(t *testing.T) {
+	svc := quota.New(quota.Config{Interval: time.Minute, Action: quota.ActionDrop, Retention: 50 * time.Millisecond})
+	svc.Admit(domainID, "a", 10)
+	svc.Admit(domainID, "b", 10)
+
+	time.Sleep(30 * time.Millisecond)
+	svc.Admit(domainID, "b", 10)
+	time.Sleep(30 * time.Millisecond)
+	svc.Admit(domainID, "c", 10)
+
+	usage, err := svc.Usage(context.Background(), domainID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	expected := []quota.Usage{
+		{Domain: domainID, Channel: "b", Messages: 2, Bytes: 20},
+		{Domain: domainID, Channel: "c", Messages: 1, Bytes: 10},
+	}
+	assert.Equal(t, expected, usage, fmt.Sprintf("expected usage %v, got %v", expected, usage))
+}
+
+func TestUsage(t *testing.T) {
+	svc := quota.New(quota.Config{Interval: time.Minute, Action: quota.ActionDrop})
+	svc.Admit(otherID, channelID, 5)
+	svc.Admit(domainID, "b", 10)
+	svc.Admit(domainID, "a", 20)
+	svc.Admit(domainID, "a", 20)
+
+	cases := []struct {
+		desc   string
+		domain string
+		usage  []quota.Usage
+	}{
+		{
+			desc:   "view usage of domain",
+			domain: domainID,
+			usage: []quota.Usage{
+				{Domain: domainID, Channel: "a", Messages: 2, Bytes: 40},
+				{Domain: domainID, Channel: "b", Messages: 1, Bytes: 10},
+			},
+		},
+		{
+			desc:   "view usage of all domains",
+			domain: "",
+			usage: []quota.Usage{
+				{Domain: domainID, Channel: "a", Messages: 2, Bytes: 40},
+				{Domain: domainID, Channel: "b", Messages: 1, Bytes: 10},
+				{Domain: otherID, Channel: channelID, Messages: 1, Bytes: 5},
+			},
+		},
+		{
+			desc:   "view usage of domain without messages",
+			domain: "7de9b29a-feb9-11ed-be56-0242ac120002",
+			usage:  []quota.Usage{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			usage, err := svc.Usage(context.Background(), tc.domain)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.usage, usage, fmt.Sprintf("%s: expected usage %v, got %v", tc.desc, tc.usage, usage))
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retention contains the retention of the messages writers store.
+// Messages older than the age of their domain, channel or default policy
+// are deleted, and the values of SenML messages are aggregated into rollups
+// of 1 minute, 1 hour and 1 day buckets, which readers use for aggregated
+// reads of coarse intervals.
+package retention
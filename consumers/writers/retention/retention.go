@@ -0,0 +1,111 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scopes of retention policies. A policy without scope is the default
+// policy, which applies to the messages no other policy applies to.
+const (
+	ScopeDomain  = "domain"
+	ScopeChannel = "channel"
+)
+
+// ErrInvalidPolicy indicates a malformed retention policy.
+var ErrInvalidPolicy = errors.New("invalid retention policy")
+
+// Policy is the age of the messages of a domain or a channel after which
+// they are deleted. Zero age keeps the messages forever.
+type Policy struct {
+	Scope string
+	ID    string
+	Age   time.Duration
+}
+
+// UnmarshalText parses the policy of the form <scope>:<id>=<age>, such as
+// channel:<id>=720h.
+func (p *Policy) UnmarshalText(text []byte) error {
+	scoped, age, ok := strings.Cut(string(text), "=")
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidPolicy, text)
+	}
+	scope, id, ok := strings.Cut(scoped, ":")
+	if !ok || id == "" || (scope != ScopeDomain && scope != ScopeChannel) {
+		return fmt.Errorf("%w: %q", ErrInvalidPolicy, text)
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidPolicy, text)
+	}
+	*p = Policy{Scope: scope, ID: id, Age: d}
+
+	return nil
+}
+
+// Span is the range of times from Start, inclusive, to End, exclusive.
+type Span struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Spans returns the spans of the buckets of the length that hold the
+// times, given in nanoseconds, in order and with adjacent buckets merged.
+func Spans(times []int64, bucket time.Duration) []Span {
+	starts := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		starts = append(starts, time.Unix(0, t).Truncate(bucket))
+	}
+	slices.SortFunc(starts, time.Time.Compare)
+
+	var spans []Span
+	for _, s := range starts {
+		if n := len(spans); n > 0 && !s.After(spans[n-1].End) {
+			spans[n-1].End = s.Add(bucket)
+			continue
+		}
+		spans = append(spans, Span{Start: s, End: s.Add(bucket)})
+	}
+
+	return spans
+}
+
+// Config contains the retention configuration.
+type Config struct {
+	// Age is the age of the default policy. Zero keeps messages forever.
+	Age time.Duration `env:"AGE"             envDefault:"0s"`
+	// Policies are the policies of domains and channels, which take
+	// precedence over the default policy, and channel policies over
+	// domain policies.
+	Policies []Policy `env:"POLICIES"        envDefault:""`
+	// Interval is how often expired messages are deleted.
+	Interval time.Duration `env:"INTERVAL"        envDefault:"1h"`
+	// RollupInterval is how often rollups are refreshed. Zero disables
+	// rollups.
+	RollupInterval time.Duration `env:"ROLLUP_INTERVAL" envDefault:"1m"`
+	// RollupWindow is how far back each refresh aggregates messages.
+	// Messages stored later than the window are aggregated with the
+	// buckets they are stored in.
+	RollupWindow time.Duration `env:"ROLLUP_WINDOW"   envDefault:"1h"`
+}
+
+// Repository specifies the retention persistence API.
+type Repository interface {
+	// Delete removes the messages in the scope of the policy stored before
+	// the given time, except the messages in the scopes of the exceptions,
+	// and returns the number of removed messages.
+	Delete(ctx context.Context, policy Policy, before time.Time, exceptions []Policy) (uint64, error)
+
+	// Rollup aggregates the values of the messages stored between from and
+	// to into the rollups, aggregates again the buckets of the messages
+	// stored late, after their buckets were aggregated, and records the
+	// rollups are complete up to to.
+	Rollup(ctx context.Context, from, to time.Time) error
+}
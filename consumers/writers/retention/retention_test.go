@@ -0,0 +1,155 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package retention_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/retention"
+	"github.com/caarlos0/env/v11"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyUnmarshalText(t *testing.T) {
+	cases := []struct {
+		desc   string
+		text   string
+		policy retention.Policy
+		err    error
+	}{
+		{
+			desc:   "parse channel policy",
+			text:   "channel:ch1=168h",
+			policy: retention.Policy{Scope: retention.ScopeChannel, ID: "ch1", Age: 168 * time.Hour},
+		},
+		{
+			desc:   "parse domain policy",
+			text:   "domain:d1=720h",
+			policy: retention.Policy{Scope: retention.ScopeDomain, ID: "d1", Age: 720 * time.Hour},
+		},
+		{
+			desc:   "parse policy that keeps messages forever",
+			text:   "channel:ch1=0s",
+			policy: retention.Policy{Scope: retention.ScopeChannel, ID: "ch1"},
+		},
+		{
+			desc: "parse policy without age",
+			text: "channel:ch1",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc: "parse policy without scope",
+			text: "ch1=168h",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc: "parse policy with invalid scope",
+			text: "client:c1=168h",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc: "parse policy without ID",
+			text: "channel:=168h",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc: "parse policy with invalid age",
+			text: "channel:ch1=7d",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc: "parse policy with negative age",
+			text: "channel:ch1=-1h",
+			err:  retention.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var p retention.Policy
+			err := p.UnmarshalText([]byte(tc.text))
+			assert.True(t, errors.Is(err, tc.err), fmt.Sprintf("%s: expected error %v, got %v", tc.desc, tc.err, err))
+			assert.Equal(t, tc.policy, p, fmt.Sprintf("%s: expected policy %v, got %v", tc.desc, tc.policy, p))
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Setenv("MG_WRITER_RETENTION_AGE", "2160h")
+	t.Setenv("MG_WRITER_RETENTION_POLICIES", "domain:d1=720h,channel:ch1=0s")
+
+	var cfg retention.Config
+	err := env.ParseWithOptions(&cfg, env.Options{Prefix: "MG_WRITER_RETENTION_"})
+	assert.Nil(t, err, fmt.Sprintf("expected no error, got %v", err))
+
+	expected := retention.Config{
+		Age: 2160 * time.Hour,
+		Policies: []retention.Policy{
+			{Scope: retention.ScopeDomain, ID: "d1", Age: 720 * time.Hour},
+			{Scope: retention.ScopeChannel, ID: "ch1"},
+		},
+		Interval:       time.Hour,
+		RollupInterval: time.Minute,
+		RollupWindow:   time.Hour,
+	}
+	assert.Equal(t, expected, cfg, fmt.Sprintf("expected config %v, got %v", expected, cfg))
+}
+
+func TestSpans(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) int64 { return start.Add(d).UnixNano() }
+
+	cases := []struct {
+		desc   string
+		times  []int64
+		bucket time.Duration
+		spans  []retention.Span
+	}{
+		{
+			desc:   "spans of no times",
+			bucket: time.Minute,
+		},
+		{
+			desc:   "spans of times of one bucket",
+			times:  []int64{at(10 * time.Second), at(0), at(50 * time.Second)},
+			bucket: time.Minute,
+			spans:  []retention.Span{{Start: start, End: start.Add(time.Minute)}},
+		},
+		{
+			desc:   "spans of times of adjacent buckets",
+			times:  []int64{at(90 * time.Second), at(10 * time.Second), at(150 * time.Second)},
+			bucket: time.Minute,
+			spans:  []retention.Span{{Start: start, End: start.Add(3 * time.Minute)}},
+		},
+		{
+			desc:   "spans of times of separate buckets",
+			times:  []int64{at(5 * time.Minute), at(10 * time.Second), at(90 * time.Second)},
+			bucket: time.Minute,
+			spans: []retention.Span{
+				{Start: start, End: start.Add(2 * time.Minute)},
+				{Start: start.Add(5 * time.Minute), End: start.Add(6 * time.Minute)},
+			},
+		},
+		{
+			desc:   "spans of times of coarser buckets",
+			times:  []int64{at(5 * time.Minute), at(90 * time.Minute)},
+			bucket: time.Hour,
+			spans:  []retention.Span{{Start: start, End: start.Add(2 * time.Hour)}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			spans := retention.Spans(tc.times, tc.bucket)
+			assert.Equal(t, len(tc.spans), len(spans), fmt.Sprintf("%s: expected %d spans, got %d", tc.desc, len(tc.spans), len(spans)))
+			for i := range min(len(tc.spans), len(spans)) {
+				assert.True(t, tc.spans[i].Start.Equal(spans[i].Start) && tc.spans[i].End.Equal(spans[i].End),
+					fmt.Sprintf("%s: expected span %v, got %v", tc.desc, tc.spans[i], spans[i]))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Start deletes expired messages and refreshes the rollups periodically,
+// until the context is done. Both run once on start. Failures are logged
+// and retried on the next run.
+func Start(ctx context.Context, repo Repository, cfg Config, logger *slog.Logger) error {
+	var retain, rollup <-chan time.Time
+	if cfg.Interval > 0 && enforced(cfg) {
+		t := time.NewTicker(cfg.Interval)
+		defer t.Stop()
+		retain = t.C
+		enforce(ctx, repo, cfg, time.Now(), logger)
+	}
+	if cfg.RollupInterval > 0 {
+		t := time.NewTicker(cfg.RollupInterval)
+		defer t.Stop()
+		rollup = t.C
+		refresh(ctx, repo, cfg, time.Now(), logger)
+	}
+
+	for {
+		select {
+		case now := <-retain:
+			enforce(ctx, repo, cfg, now, logger)
+		case now := <-rollup:
+			refresh(ctx, repo, cfg, now, logger)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// enforced reports whether any policy deletes messages.
+func enforced(cfg Config) bool {
+	if cfg.Age > 0 {
+		return true
+	}
+	for _, p := range cfg.Policies {
+		if p.Age > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enforce deletes the messages older than the age of their policy. Channel
+// policies take precedence over domain policies, and both over the default
+// policy, so each policy excludes the scopes of the more specific ones.
+func enforce(ctx context.Context, repo Repository, cfg Config, now time.Time, logger *slog.Logger) {
+	var channels, domains []Policy
+	for _, p := range cfg.Policies {
+		switch p.Scope {
+		case ScopeChannel:
+			channels = append(channels, p)
+		case ScopeDomain:
+			domains = append(domains, p)
+		}
+	}
+
+	remove := func(p Policy, exceptions []Policy) {
+		if p.Age <= 0 {
+			return
+		}
+		n, err := repo.Delete(ctx, p, now.Add(-p.Age), exceptions)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to delete messages of %s retention policy: %s", describe(p), err))
+			return
+		}
+		if n > 0 {
+			logger.Info(fmt.Sprintf("deleted %d messages of %s retention policy", n, describe(p)))
+		}
+	}
+
+	for _, p := range channels {
+		remove(p, nil)
+	}
+	for _, p := range domains {
+		remove(p, channels)
+	}
+	remove(Policy{Age: cfg.Age}, slices.Concat(domains, channels))
+}
+
+// refresh aggregates the messages of the rollup window into the rollups.
+func refresh(ctx context.Context, repo Repository, cfg Config, now time.Time, logger *slog.Logger) {
+	if err := repo.Rollup(ctx, now.Add(-cfg.RollupWindow), now); err != nil {
+		logger.Error(fmt.Sprintf("failed to refresh rollups: %s", err))
+	}
+}
+
+func describe(p Policy) string {
+	if p.Scope == "" {
+		return "default"
+	}
+
+	return fmt.Sprintf("%s %s", p.Scope, p.ID)
+}
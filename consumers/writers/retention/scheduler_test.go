@@ -0,0 +1,121 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package retention_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/retention"
+	smqlog "github.com/absmach/supermq/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type deletion struct {
+	policy     retention.Policy
+	age        time.Duration
+	exceptions []retention.Policy
+}
+
+type repository struct {
+	mu        sync.Mutex
+	deletions []deletion
+	rollups   []time.Duration
+}
+
+func (r *repository) Delete(_ context.Context, p retention.Policy, before time.Time, exceptions []retention.Policy) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ages are rounded, since the current time isn't known.
+	r.deletions = append(r.deletions, deletion{policy: p, age: time.Since(before).Round(time.Hour), exceptions: exceptions})
+
+	return 1, nil
+}
+
+func (r *repository) Rollup(_ context.Context, from, to time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollups = append(r.rollups, to.Sub(from))
+
+	return nil
+}
+
+func (r *repository) calls() ([]deletion, []time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.deletions, r.rollups
+}
+
+func TestStart(t *testing.T) {
+	channel := retention.Policy{Scope: retention.ScopeChannel, ID: "ch1", Age: 24 * time.Hour}
+	forever := retention.Policy{Scope: retention.ScopeChannel, ID: "ch2"}
+	domain := retention.Policy{Scope: retention.ScopeDomain, ID: "d1", Age: 168 * time.Hour}
+
+	cases := []struct {
+		desc      string
+		cfg       retention.Config
+		deletions []deletion
+		rollups   []time.Duration
+	}{
+		{
+			desc: "start with all policies",
+			cfg: retention.Config{
+				Age:          720 * time.Hour,
+				Policies:     []retention.Policy{channel, forever, domain},
+				Interval:     time.Hour,
+				RollupWindow: time.Hour,
+			},
+			deletions: []deletion{
+				{policy: channel, age: 24 * time.Hour},
+				{policy: domain, age: 168 * time.Hour, exceptions: []retention.Policy{channel, forever}},
+				{policy: retention.Policy{Age: 720 * time.Hour}, age: 720 * time.Hour, exceptions: []retention.Policy{domain, channel, forever}},
+			},
+		},
+		{
+			desc: "start with policies that keep messages forever",
+			cfg: retention.Config{
+				Policies: []retention.Policy{forever},
+				Interval: time.Hour,
+			},
+		},
+		{
+			desc: "start with rollups",
+			cfg: retention.Config{
+				Interval:       time.Hour,
+				RollupInterval: time.Hour,
+				RollupWindow:   2 * time.Hour,
+			},
+			rollups: []time.Duration{2 * time.Hour},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := &repository{}
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- retention.Start(ctx, repo, tc.cfg, smqlog.NewMock())
+			}()
+
+			assert.Eventually(t, func() bool {
+				deletions, rollups := repo.calls()
+				return len(deletions) == len(tc.deletions) && len(rollups) == len(tc.rollups)
+			}, time.Second, 10*time.Millisecond)
+			cancel()
+			err := <-done
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %v", tc.desc, err))
+
+			deletions, rollups := repo.calls()
+			assert.Equal(t, tc.deletions, deletions, fmt.Sprintf("%s: expected deletions %v, got %v", tc.desc, tc.deletions, deletions))
+			assert.Equal(t, tc.rollups, rollups, fmt.Sprintf("%s: expected rollups %v, got %v", tc.desc, tc.rollups, rollups))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	writersapi "github.com/absmach/magistrala/consumers/writers/api"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func listEndpoint(svc schemas.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.List(ctx, req.domainID, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+		res := listRes{
+			Total:   page.Total,
+			Offset:  page.Offset,
+			Limit:   page.Limit,
+			Schemas: []viewRes{},
+		}
+		for _, s := range page.Schemas {
+			res.Schemas = append(res.Schemas, toViewRes(s))
+		}
+
+		return res, nil
+	}
+}
+
+func registerEndpoint(svc schemas.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(registerReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		s, err := svc.Register(ctx, req.domainID, req.format, req.document)
+		if err != nil {
+			return nil, err
+		}
+		res := toViewRes(s)
+		res.created = s.Version == 1
+
+		return res, nil
+	}
+}
+
+func viewEndpoint(svc schemas.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(schemaReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		s, err := svc.View(ctx, req.domainID, req.format)
+		if err != nil {
+			return nil, err
+		}
+
+		return toViewRes(s), nil
+	}
+}
+
+func removeEndpoint(svc schemas.Service, authz smqauthz.Authorization) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(schemaReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		if err := writersapi.AuthorizeMember(ctx, authz); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Remove(ctx, req.domainID, req.format); err != nil {
+			return nil, err
+		}
+
+		return noContentRes{}, nil
+	}
+}
+
+func toViewRes(s schemas.Schema) viewRes {
+	return viewRes{
+		Domain:     s.Domain,
+		Format:     s.Format,
+		Table:      s.Table(),
+		Version:    s.Version,
+		Schema:     s.Document,
+		Fields:     s.Fields,
+		Additional: s.Additional,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/magistrala/consumers/writers/schemas/api"
+	"github.com/absmach/magistrala/consumers/writers/schemas/mocks"
+	"github.com/absmach/magistrala/internal/testsutil"
+	smqlog "github.com/absmach/supermq/logger"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
+	"github.com/absmach/supermq/pkg/errors"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	validToken   = testsutil.ValidToken
+	invalidToken = testsutil.InvalidToken
+	domainID     = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	format       = "readings"
+	contentType  = "application/json"
+	document     = `{"type":"object","properties":{"temperature":{"type":"number"}},"required":["temperature"]}`
+)
+
+var schema = schemas.Schema{
+	Domain:    domainID,
+	Format:    format,
+	Version:   1,
+	Document:  []byte(document),
+	Fields:    []schemas.Field{{Name: "temperature", Type: schemas.TypeNumber, Required: true}},
+	CreatedAt: time.Now(),
+	UpdatedAt: time.Now(),
+}
+
+func newServer(t *testing.T) (*httptest.Server, *mocks.Service, *authzmocks.Authorization) {
+	svc := mocks.NewService(t)
+	ts, authz := testsutil.NewAPIServer("/schemas", func(authn smqauthn.Authentication, authz smqauthz.Authorization) http.Handler {
+		return api.MakeHandler(svc, authn, authz, smqlog.NewMock())
+	})
+
+	return ts, svc, authz
+}
+
+func TestRegister(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	updated := schema
+	updated.Version = 2
+
+	cases := []struct {
+		desc        string
+		path        string
+		token       string
+		contentType string
+		body        string
+		schema      schemas.Schema
+		authzErr    error
+		svcErr      error
+		status      int
+	}{
+		{
+			desc:        "register schema",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: contentType,
+			body:        document,
+			schema:      schema,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "register schema version",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: contentType,
+			body:        document,
+			schema:      updated,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "register incompatible schema",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: contentType,
+			body:        document,
+			svcErr:      errors.Wrap(errors.ErrMalformedEntity, schemas.ErrIncompatibleSchema),
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "register schema of invalid format",
+			path:        "/schemas/" + domainID + "/Readings",
+			token:       validToken,
+			contentType: contentType,
+			body:        document,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "register malformed schema",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: contentType,
+			body:        `{"type":`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "register schema with invalid content type",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: "text/plain",
+			body:        document,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "register schema with invalid token",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       invalidToken,
+			contentType: contentType,
+			body:        document,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "register schema by non-member of domain",
+			path:        "/schemas/" + domainID + "/" + format,
+			token:       validToken,
+			contentType: contentType,
+			body:        document,
+			authzErr:    svcerr.ErrAuthorization,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "register schema without token",
+			path:        "/schemas/" + domainID + "/" + format,
+			contentType: contentType,
+			body:        document,
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			svcCall := svc.On("Register", mock.Anything, domainID, format, mock.Anything).Return(tc.schema, tc.svcErr)
+			status, _ := testsutil.Request(t, ts, http.MethodPut, tc.path, tc.token, tc.contentType, tc.body)
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			svcCall.Unset()
+			authzCall.Unset()
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		path     string
+		token    string
+		offset   uint64
+		limit    uint64
+		authzErr error
+		status   int
+	}{
+		{
+			desc:   "list schemas",
+			path:   "/schemas/" + domainID,
+			token:  validToken,
+			limit:  20,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list schemas with offset and limit",
+			path:   "/schemas/" + domainID + "?offset=10&limit=5",
+			token:  validToken,
+			offset: 10,
+			limit:  5,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list schemas with invalid limit",
+			path:   "/schemas/" + domainID + "?limit=1001",
+			token:  validToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:     "list schemas by non-member of domain",
+			path:     "/schemas/" + domainID,
+			token:    validToken,
+			limit:    20,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "list schemas with invalid token",
+			path:   "/schemas/" + domainID,
+			token:  invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			svcCall := svc.On("List", mock.Anything, domainID, tc.offset, tc.limit).Return(schemas.Page{Total: 1, Schemas: []schemas.Schema{schema}}, nil)
+			status, _ := testsutil.Request(t, ts, http.MethodGet, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			svcCall.Unset()
+			authzCall.Unset()
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	ts, svc, authz := newServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		method   string
+		path     string
+		token    string
+		svcCall  string
+		authzErr error
+		svcErr   error
+		status   int
+	}{
+		{
+			desc:    "view schema",
+			method:  http.MethodGet,
+			path:    "/schemas/" + domainID + "/" + format,
+			token:   validToken,
+			svcCall: "View",
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "view non-existing schema",
+			method:  http.MethodGet,
+			path:    "/schemas/" + domainID + "/" + format,
+			token:   validToken,
+			svcCall: "View",
+			svcErr:  svcerr.ErrNotFound,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:   "view schema of invalid format",
+			method: http.MethodGet,
+			path:   "/schemas/" + domainID + "/read-ings",
+			token:  validToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:    "remove schema",
+			method:  http.MethodDelete,
+			path:    "/schemas/" + domainID + "/" + format,
+			token:   validToken,
+			svcCall: "Remove",
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:    "remove non-existing schema",
+			method:  http.MethodDelete,
+			path:    "/schemas/" + domainID + "/" + format,
+			token:   validToken,
+			svcCall: "Remove",
+			svcErr:  svcerr.ErrNotFound,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:     "remove schema by non-member of domain",
+			method:   http.MethodDelete,
+			path:     "/schemas/" + domainID + "/" + format,
+			token:    validToken,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:   "remove schema with invalid token",
+			method: http.MethodDelete,
+			path:   "/schemas/" + domainID + "/" + format,
+			token:  invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+			var svcCall *mock.Call
+			switch tc.svcCall {
+			case "View":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID, format).Return(schema, tc.svcErr)
+			case "Remove":
+				svcCall = svc.On(tc.svcCall, mock.Anything, domainID, format).Return(tc.svcErr)
+			}
+			status, _ := testsutil.Request(t, ts, tc.method, tc.path, tc.token, "", "")
+			assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, status))
+			if svcCall != nil {
+				svcCall.Unset()
+			}
+			authzCall.Unset()
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	"github.com/absmach/supermq/pkg/errors"
+)
+
+const maxLimitSize = 1000
+
+type listReq struct {
+	domainID string
+	offset   uint64
+	limit    uint64
+}
+
+func (req listReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	if req.limit < 1 || req.limit > maxLimitSize {
+		return apiutil.ErrLimitSize
+	}
+	return nil
+}
+
+type registerReq struct {
+	domainID string
+	format   string
+	document json.RawMessage
+}
+
+func (req registerReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	if err := schemas.ValidateFormat(req.format); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
+
+type schemaReq struct {
+	domainID string
+	format   string
+}
+
+func (req schemaReq) validate() error {
+	if req.domainID == "" {
+		return apiutil.ErrMissingDomainID
+	}
+	if err := schemas.ValidateFormat(req.format); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/supermq"
+)
+
+var (
+	_ supermq.Response = (*viewRes)(nil)
+	_ supermq.Response = (*listRes)(nil)
+	_ supermq.Response = (*noContentRes)(nil)
+)
+
+type viewRes struct {
+	Domain     string          `json:"domain"`
+	Format     string          `json:"format"`
+	Table      string          `json:"table"`
+	Version    uint64          `json:"version"`
+	Schema     json.RawMessage `json:"schema"`
+	Fields     []schemas.Field `json:"fields"`
+	Additional bool            `json:"additional_properties"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	created    bool
+}
+
+func (res viewRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res viewRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Total   uint64    `json:"total"`
+	Offset  uint64    `json:"offset"`
+	Limit   uint64    `json:"limit"`
+	Schemas []viewRes `json:"schemas"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type noContentRes struct{}
+
+func (res noContentRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res noContentRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res noContentRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	api "github.com/absmach/supermq/api/http"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	offsetKey = "offset"
+	limitKey  = "limit"
+	defOffset = 0
+	defLimit  = 20
+)
+
+// MakeHandler returns a HTTP handler for schema API endpoints. Requests are
+// authenticated with the user token and authorized by the membership of the
+// user in the domain. The handler is mounted on the /schemas path of the
+// writer's HTTP server.
+func MakeHandler(svc schemas.Service, authn smqauthn.Authentication, authz smqauthz.Authorization, logger *slog.Logger) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(apiutil.LoggingErrorEncoder(logger, api.EncodeError)),
+	}
+
+	mux := chi.NewRouter()
+
+	mux.Route("/{domainID}", func(r chi.Router) {
+		r.Use(api.AuthenticateMiddleware(authn, true))
+
+		r.Get("/", otelhttp.NewHandler(kithttp.NewServer(
+			listEndpoint(svc, authz),
+			decodeList,
+			api.EncodeResponse,
+			opts...,
+		), "list_schemas").ServeHTTP)
+
+		r.Put("/{format}", otelhttp.NewHandler(kithttp.NewServer(
+			registerEndpoint(svc, authz),
+			decodeRegister,
+			api.EncodeResponse,
+			opts...,
+		), "register_schema").ServeHTTP)
+
+		r.Get("/{format}", otelhttp.NewHandler(kithttp.NewServer(
+			viewEndpoint(svc, authz),
+			decodeSchema,
+			api.EncodeResponse,
+			opts...,
+		), "view_schema").ServeHTTP)
+
+		r.Delete("/{format}", otelhttp.NewHandler(kithttp.NewServer(
+			removeEndpoint(svc, authz),
+			decodeSchema,
+			api.EncodeResponse,
+			opts...,
+		), "remove_schema").ServeHTTP)
+	})
+
+	return mux
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	offset, err := apiutil.ReadNumQuery[uint64](r, offsetKey, defOffset)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	limit, err := apiutil.ReadNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	req := listReq{
+		domainID: chi.URLParam(r, "domainID"),
+		offset:   offset,
+		limit:    limit,
+	}
+
+	return req, nil
+}
+
+func decodeRegister(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), api.ContentType) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.Wrap(errors.ErrMalformedEntity, err))
+	}
+	if !json.Valid(body) {
+		return nil, errors.Wrap(apiutil.ErrValidation, errors.ErrMalformedEntity)
+	}
+
+	req := registerReq{
+		domainID: chi.URLParam(r, "domainID"),
+		format:   chi.URLParam(r, "format"),
+		document: body,
+	}
+
+	return req, nil
+}
+
+func decodeSchema(_ context.Context, r *http.Request) (interface{}, error) {
+	req := schemaReq{
+		domainID: chi.URLParam(r, "domainID"),
+		format:   chi.URLParam(r, "format"),
+	}
+
+	return req, nil
+}
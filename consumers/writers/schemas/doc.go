@@ -0,0 +1,8 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemas contains the JSON schemas domains register for the
+// formats of their JSON messages. Writers store the messages of a format
+// with a schema in a table of the domain with a typed column per schema
+// field, and reject the payloads that don't match the schema.
+package schemas
@@ -0,0 +1,41 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import migrate "github.com/rubenv/sql-migrate"
+
+// Migration of the schemas tables. Writers add it to their migrations.
+func Migration() *migrate.MemoryMigrationSource {
+	return &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "json_schemas_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS json_schemas (
+                        domain      VARCHAR(36) NOT NULL,
+                        format      VARCHAR(26) NOT NULL,
+                        version     BIGINT NOT NULL,
+                        document    JSONB NOT NULL,
+                        created_at  TIMESTAMP NOT NULL,
+                        updated_at  TIMESTAMP NOT NULL,
+                        PRIMARY KEY (domain, format)
+                    )`,
+					// Statements that migrated the tables of each schema version.
+					`CREATE TABLE IF NOT EXISTS json_schema_migrations (
+                        domain      VARCHAR(36) NOT NULL,
+                        format      VARCHAR(26) NOT NULL,
+                        version     BIGINT NOT NULL,
+                        statements  TEXT[] NOT NULL,
+                        applied_at  TIMESTAMP NOT NULL,
+                        PRIMARY KEY (domain, format, version)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS json_schema_migrations",
+					"DROP TABLE IF EXISTS json_schemas",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+var errTransRollback = errors.New("failed to rollback transaction")
+
+// columnTypes are the column types of the schema field types.
+var columnTypes = map[string]string{
+	schemas.TypeString:  "TEXT",
+	schemas.TypeNumber:  "DOUBLE PRECISION",
+	schemas.TypeInteger: "BIGINT",
+	schemas.TypeBoolean: "BOOLEAN",
+	schemas.TypeObject:  "JSONB",
+	schemas.TypeArray:   "JSONB",
+}
+
+var _ schemas.Repository = (*repository)(nil)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of schema repository.
+func New(db *sqlx.DB) schemas.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (repo *repository) Save(ctx context.Context, s schemas.Schema, added []schemas.Field) (err error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(repoerr.ErrCreateEntity, err)
+		}
+	}()
+
+	dbs := toDBSchema(s)
+	if s.Version == 1 {
+		q := `INSERT INTO json_schemas (domain, format, version, document, created_at, updated_at)
+              VALUES (:domain, :format, :version, :document, :created_at, :updated_at)`
+		if _, err := tx.NamedExecContext(ctx, q, dbs); err != nil {
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+				return errors.Wrap(repoerr.ErrConflict, err)
+			}
+			return errors.Wrap(repoerr.ErrCreateEntity, err)
+		}
+	} else {
+		// Versions are saved in order, so concurrent updates don't lose fields.
+		q := `UPDATE json_schemas SET version = :version, document = :document, updated_at = :updated_at
+              WHERE domain = :domain AND format = :format AND version = :version - 1`
+		res, err := tx.NamedExecContext(ctx, q, dbs)
+		if err != nil {
+			return errors.Wrap(repoerr.ErrUpdateEntity, err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return repoerr.ErrConflict
+		}
+	}
+
+	stmts := migration(s, added)
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return errors.Wrap(repoerr.ErrCreateEntity, err)
+		}
+	}
+
+	q := `INSERT INTO json_schema_migrations (domain, format, version, statements, applied_at)
+          VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, q, s.Domain, s.Format, s.Version, stmts, s.UpdatedAt); err != nil {
+		return errors.Wrap(repoerr.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (repo *repository) Retrieve(ctx context.Context, domain, format string) (schemas.Schema, error) {
+	q := `SELECT domain, format, version, document, created_at, updated_at FROM json_schemas
+          WHERE domain = $1 AND format = $2`
+
+	var dbs dbSchema
+	if err := repo.db.QueryRowxContext(ctx, q, domain, format).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return schemas.Schema{}, errors.Wrap(repoerr.ErrNotFound, err)
+		}
+		// Formats have no schemas until the schemas tables are migrated.
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UndefinedTable {
+			return schemas.Schema{}, errors.Wrap(repoerr.ErrNotFound, err)
+		}
+		return schemas.Schema{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return fromDBSchema(dbs)
+}
+
+func (repo *repository) RetrieveAll(ctx context.Context, domain string, offset, limit uint64) (schemas.Page, error) {
+	q := `SELECT domain, format, version, document, created_at, updated_at FROM json_schemas
+          WHERE domain = $1 ORDER BY format LIMIT $2 OFFSET $3`
+
+	rows, err := repo.db.QueryxContext(ctx, q, domain, limit, offset)
+	if err != nil {
+		return schemas.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	page := schemas.Page{
+		Offset:  offset,
+		Limit:   limit,
+		Schemas: []schemas.Schema{},
+	}
+	for rows.Next() {
+		var dbs dbSchema
+		if err := rows.StructScan(&dbs); err != nil {
+			return schemas.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+		}
+		s, err := fromDBSchema(dbs)
+		if err != nil {
+			return schemas.Page{}, err
+		}
+		page.Schemas = append(page.Schemas, s)
+	}
+	if err := rows.Err(); err != nil {
+		return schemas.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	if err := repo.db.GetContext(ctx, &page.Total, `SELECT COUNT(*) FROM json_schemas WHERE domain = $1`, domain); err != nil {
+		return schemas.Page{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+
+	return page, nil
+}
+
+func (repo *repository) Remove(ctx context.Context, domain, format string) (err error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(repoerr.ErrRemoveEntity, err)
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM json_schemas WHERE domain = $1 AND format = $2`, domain, format)
+	if err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repoerr.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM json_schema_migrations WHERE domain = $1 AND format = $2`, domain, format); err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+
+	q := fmt.Sprintf(`DROP TABLE IF EXISTS %s`, pgx.Identifier{schemas.Table(domain, format)}.Sanitize())
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		return errors.Wrap(repoerr.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+// migration returns the statements that migrate the table of the schema
+// messages. The first version creates the table, or adds the columns to the
+// table of the messages the domain stored before registering the schema,
+// and the following versions add the columns of the added fields.
+func migration(s schemas.Schema, added []schemas.Field) []string {
+	table := pgx.Identifier{s.Table()}.Sanitize()
+
+	stmts := []string{}
+	if s.Version == 1 {
+		stmts = append(stmts, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+            id            UUID,
+            created       BIGINT,
+            domain        UUID,
+            channel       VARCHAR(254),
+            subtopic      VARCHAR(254),
+            publisher     VARCHAR(254),
+            protocol      TEXT,
+            payload       JSONB,
+            PRIMARY KEY (id)
+        )`, table))
+	}
+	for _, f := range added {
+		stmts = append(stmts, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, table, pgx.Identifier{f.Name}.Sanitize(), columnTypes[f.Type]))
+	}
+	if s.Version == 1 {
+		stmts = append(stmts,
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (channel, created DESC)", pgx.Identifier{schemas.Index(s.Table(), "channel")}.Sanitize(), table),
+			// Index the payload for containment filters of the readers.
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (payload jsonb_path_ops)", pgx.Identifier{schemas.Index(s.Table(), "payload")}.Sanitize(), table),
+		)
+	}
+
+	return stmts
+}
+
+type dbSchema struct {
+	Domain    string    `db:"domain"`
+	Format    string    `db:"format"`
+	Version   uint64    `db:"version"`
+	Document  []byte    `db:"document"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBSchema(s schemas.Schema) dbSchema {
+	return dbSchema{
+		Domain:    s.Domain,
+		Format:    s.Format,
+		Version:   s.Version,
+		Document:  s.Document,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func fromDBSchema(dbs dbSchema) (schemas.Schema, error) {
+	s, err := schemas.Parse(dbs.Domain, dbs.Format, dbs.Document)
+	if err != nil {
+		return schemas.Schema{}, errors.Wrap(repoerr.ErrViewEntity, err)
+	}
+	s.Version = dbs.Version
+	s.CreatedAt = dbs.CreatedAt
+	s.UpdatedAt = dbs.UpdatedAt
+
+	return s, nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/magistrala/consumers/writers/schemas/postgres"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	format   = "readings"
+	document = `{"type":"object","properties":{"temperature":{"type":"number"}},"required":["temperature"]}`
+	updated  = `{"type":"object","properties":{"temperature":{"type":"number"},"room":{"type":"string"}},"required":["temperature"]}`
+)
+
+func newSchema(t *testing.T, domain, format, doc string, version uint64) schemas.Schema {
+	s, err := schemas.Parse(domain, format, []byte(doc))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	s.Version = version
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	return s
+}
+
+func newDomain(t *testing.T) string {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return id
+}
+
+func columns(t *testing.T, table string) []string {
+	var cols []string
+	q := `SELECT column_name FROM information_schema.columns WHERE table_name = $1 ORDER BY ordinal_position`
+	err := db.Select(&cols, q, table)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return cols
+}
+
+func TestSave(t *testing.T) {
+	repo := postgres.New(db)
+	domain := newDomain(t)
+
+	s := newSchema(t, domain, format, document, 1)
+	v2 := newSchema(t, domain, format, updated, 2)
+	added, err := v2.Added(s)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	base := []string{"id", "created", "domain", "channel", "subtopic", "publisher", "protocol", "payload"}
+
+	cases := []struct {
+		desc    string
+		schema  schemas.Schema
+		added   []schemas.Field
+		columns []string
+		err     error
+	}{
+		{
+			desc:    "save schema",
+			schema:  s,
+			added:   s.Fields,
+			columns: append(base, "temperature"),
+		},
+		{
+			desc:    "save duplicate schema",
+			schema:  s,
+			added:   s.Fields,
+			columns: append(base, "temperature"),
+			err:     repoerr.ErrConflict,
+		},
+		{
+			desc:    "save schema version",
+			schema:  v2,
+			added:   added,
+			columns: append(base, "temperature", "room"),
+		},
+		{
+			desc:    "save outdated schema version",
+			schema:  v2,
+			added:   added,
+			columns: append(base, "temperature", "room"),
+			err:     repoerr.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.Save(context.Background(), tc.schema, tc.added)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+
+			cols := columns(t, tc.schema.Table())
+			assert.Equal(t, tc.columns, cols, fmt.Sprintf("%s: expected columns %v, got %v", tc.desc, tc.columns, cols))
+		})
+	}
+
+	var versions []uint64
+	err = db.Select(&versions, `SELECT version FROM json_schema_migrations WHERE domain = $1 ORDER BY version`, domain)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, []uint64{1, 2}, versions, fmt.Sprintf("expected migrations of versions [1 2], got %v", versions))
+}
+
+func TestSaveStoredFormat(t *testing.T) {
+	repo := postgres.New(db)
+	domain := newDomain(t)
+	s := newSchema(t, domain, format, document, 1)
+
+	// Messages of formats without schemas are stored in the same table.
+	q := fmt.Sprintf(`CREATE TABLE %s (id UUID, created BIGINT, domain UUID, channel VARCHAR(254), subtopic VARCHAR(254),
+        publisher VARCHAR(254), protocol TEXT, payload JSONB, PRIMARY KEY (id))`, pgx.Identifier{s.Table()}.Sanitize())
+	_, err := db.Exec(q)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = repo.Save(context.Background(), s, s.Fields)
+	assert.Nil(t, err, fmt.Sprintf("save schema of stored format: expected no error, got %s", err))
+
+	expected := []string{"id", "created", "domain", "channel", "subtopic", "publisher", "protocol", "payload", "temperature"}
+	cols := columns(t, s.Table())
+	assert.Equal(t, expected, cols, fmt.Sprintf("save schema of stored format: expected columns %v, got %v", expected, cols))
+}
+
+func TestRetrieve(t *testing.T) {
+	repo := postgres.New(db)
+	domain := newDomain(t)
+
+	s := newSchema(t, domain, format, document, 1)
+	err := repo.Save(context.Background(), s, s.Fields)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		domain string
+		format string
+		schema schemas.Schema
+		err    error
+	}{
+		{
+			desc:   "retrieve schema",
+			domain: domain,
+			format: format,
+			schema: s,
+		},
+		{
+			desc:   "retrieve schema of other domain",
+			domain: newDomain(t),
+			format: format,
+			err:    repoerr.ErrNotFound,
+		},
+		{
+			desc:   "retrieve schema of other format",
+			domain: domain,
+			format: "other",
+			err:    repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := repo.Retrieve(context.Background(), tc.domain, tc.format)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+			assert.Equal(t, tc.schema.Fields, s.Fields, fmt.Sprintf("%s: expected fields %v, got %v", tc.desc, tc.schema.Fields, s.Fields))
+			assert.Equal(t, tc.schema.Version, s.Version, fmt.Sprintf("%s: expected version %d, got %d", tc.desc, tc.schema.Version, s.Version))
+			assert.Equal(t, tc.schema.CreatedAt, s.CreatedAt, fmt.Sprintf("%s: expected created at %s, got %s", tc.desc, tc.schema.CreatedAt, s.CreatedAt))
+		})
+	}
+}
+
+func TestRetrieveAll(t *testing.T) {
+	repo := postgres.New(db)
+	domain := newDomain(t)
+
+	formats := []string{"a_readings", "b_readings", "c_readings"}
+	for _, f := range formats {
+		s := newSchema(t, domain, f, document, 1)
+		err := repo.Save(context.Background(), s, s.Fields)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+	other := newSchema(t, newDomain(t), format, document, 1)
+	err := repo.Save(context.Background(), other, other.Fields)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		offset  uint64
+		limit   uint64
+		formats []string
+	}{
+		{
+			desc:    "retrieve all schemas",
+			limit:   10,
+			formats: formats,
+		},
+		{
+			desc:    "retrieve schemas with offset and limit",
+			offset:  1,
+			limit:   1,
+			formats: formats[1:2],
+		},
+		{
+			desc:    "retrieve schemas with offset out of range",
+			offset:  10,
+			limit:   10,
+			formats: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := repo.RetrieveAll(context.Background(), domain, tc.offset, tc.limit)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+			assert.Equal(t, uint64(len(formats)), page.Total, fmt.Sprintf("%s: expected total %d, got %d", tc.desc, len(formats), page.Total))
+
+			var got []string
+			for _, s := range page.Schemas {
+				got = append(got, s.Format)
+			}
+			assert.Equal(t, tc.formats, got, fmt.Sprintf("%s: expected formats %v, got %v", tc.desc, tc.formats, got))
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	repo := postgres.New(db)
+	domain := newDomain(t)
+
+	s := newSchema(t, domain, format, document, 1)
+	err := repo.Save(context.Background(), s, s.Fields)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{
+			desc: "remove schema",
+		},
+		{
+			desc: "remove removed schema",
+			err:  repoerr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.Remove(context.Background(), domain, format)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+
+			cols := columns(t, s.Table())
+			assert.Empty(t, cols, fmt.Sprintf("%s: expected dropped table, got columns %v", tc.desc, cols))
+		})
+	}
+}
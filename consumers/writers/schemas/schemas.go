@@ -0,0 +1,315 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/gofrs/uuid/v5"
+)
+
+// Types of the schema fields, as named by JSON Schema.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+)
+
+// maxFormatLen keeps the table names of the domain UUID, a separator and
+// the format within the 63 characters of PostgreSQL identifiers.
+const maxFormatLen = 26
+
+// maxIdentLen is the length of PostgreSQL identifiers.
+const maxIdentLen = 63
+
+var (
+	// ErrInvalidFormat indicates a format that isn't a lowercase identifier.
+	ErrInvalidFormat = errors.New("invalid message format")
+
+	// ErrInvalidDomain indicates a domain ID that isn't a UUID.
+	ErrInvalidDomain = errors.New("invalid domain ID")
+
+	// ErrInvalidSchema indicates a malformed or unsupported JSON schema.
+	ErrInvalidSchema = errors.New("invalid JSON schema")
+
+	// ErrIncompatibleSchema indicates a schema change that removes fields
+	// or changes their type, which stored messages can't follow.
+	ErrIncompatibleSchema = errors.New("schema removes or changes the type of fields")
+
+	// ErrInvalidPayload indicates a payload that doesn't match the schema.
+	ErrInvalidPayload = errors.New("payload doesn't match the schema")
+)
+
+var identRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Messages without domains are stored in the tables named after their
+// format, so the format must be a lowercase identifier and mustn't be named
+// after the tables of the writers.
+var (
+	tableRegexp    = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+	reservedTables = []string{
+		"messages", "messages_1m", "messages_1h", "messages_1d", "rollups", "rollups_pending",
+		"dead_letters", "json_schemas", "json_schema_migrations", "gorp_migrations",
+	}
+)
+
+// Columns of every message table, which fields can't be named after.
+var reserved = []string{"id", "created", "domain", "channel", "subtopic", "publisher", "protocol", "payload"}
+
+// Field is a top-level property of the schema, stored in its own column.
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// Schema represents the JSON schema of the payloads of a domain format.
+type Schema struct {
+	Domain   string
+	Format   string
+	Version  uint64
+	Document json.RawMessage
+	// Fields are sorted by name.
+	Fields []Field
+	// Additional reports whether payloads may have properties that aren't
+	// schema fields.
+	Additional bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Page represents a page of schemas.
+type Page struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Schemas []Schema
+}
+
+// document is the supported subset of JSON Schema. Properties of object
+// and array fields aren't validated.
+type document struct {
+	Type       string `json:"type"`
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+	Required             []string `json:"required"`
+	AdditionalProperties *bool    `json:"additionalProperties"`
+}
+
+// Parse returns the schema of the domain format from the JSON schema
+// document.
+func Parse(domain, format string, doc json.RawMessage) (Schema, error) {
+	if _, err := uuid.FromString(domain); err != nil {
+		return Schema{}, ErrInvalidDomain
+	}
+	if err := ValidateFormat(format); err != nil {
+		return Schema{}, err
+	}
+
+	var d document
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return Schema{}, errors.Wrap(ErrInvalidSchema, err)
+	}
+	if d.Type != TypeObject {
+		return Schema{}, errors.Wrap(ErrInvalidSchema, errors.New("schema type must be object"))
+	}
+	if len(d.Properties) == 0 {
+		return Schema{}, errors.Wrap(ErrInvalidSchema, errors.New("schema must have properties"))
+	}
+
+	s := Schema{
+		Domain:     domain,
+		Format:     format,
+		Document:   doc,
+		Additional: d.AdditionalProperties == nil || *d.AdditionalProperties,
+	}
+	for name, p := range d.Properties {
+		if !identRegexp.MatchString(name) || slices.Contains(reserved, name) {
+			return Schema{}, errors.Wrap(ErrInvalidSchema, fmt.Errorf("invalid property name %q", name))
+		}
+		switch p.Type {
+		case TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeObject, TypeArray:
+		default:
+			return Schema{}, errors.Wrap(ErrInvalidSchema, fmt.Errorf("unsupported type %q of property %q", p.Type, name))
+		}
+		s.Fields = append(s.Fields, Field{Name: name, Type: p.Type})
+	}
+	sort.Slice(s.Fields, func(i, j int) bool { return s.Fields[i].Name < s.Fields[j].Name })
+
+	for _, name := range d.Required {
+		i := slices.IndexFunc(s.Fields, func(f Field) bool { return f.Name == name })
+		if i < 0 {
+			return Schema{}, errors.Wrap(ErrInvalidSchema, fmt.Errorf("required property %q isn't defined", name))
+		}
+		s.Fields[i].Required = true
+	}
+
+	return s, nil
+}
+
+// ValidateFormat validates the format name, which must be a lowercase
+// identifier, so it's safely used in table names.
+func ValidateFormat(format string) error {
+	if len(format) > maxFormatLen || !identRegexp.MatchString(format) {
+		return ErrInvalidFormat
+	}
+
+	return nil
+}
+
+// ValidateTable validates the format of messages without domain, which
+// names the table they are stored in.
+func ValidateTable(format string) error {
+	if !tableRegexp.MatchString(format) || slices.Contains(reservedTables, format) {
+		return ErrInvalidFormat
+	}
+
+	return nil
+}
+
+// Table returns the name of the table of the domain format messages.
+// Names of domains differ, so the tables of their formats never collide.
+func Table(domain, format string) string {
+	return domain + "_" + format
+}
+
+// Index returns the name of the index of the table. Longer names are
+// truncated by PostgreSQL, so the indexes of tables with the same prefix
+// would collide, and are named after the MD5 hash of the table instead.
+func Index(table, name string) string {
+	index := "idx_" + table + "_" + name
+	if len(index) > maxIdentLen {
+		index = fmt.Sprintf("idx_%x_%s", md5.Sum([]byte(table)), name)
+	}
+
+	return index
+}
+
+// Table returns the name of the table of the schema messages.
+func (s Schema) Table() string {
+	return Table(s.Domain, s.Format)
+}
+
+// Added returns the fields the schema adds to the previous version of the
+// schema. Schemas may only add fields, so the stored messages keep their
+// columns.
+func (s Schema) Added(prev Schema) ([]Field, error) {
+	for _, f := range prev.Fields {
+		i := slices.IndexFunc(s.Fields, func(nf Field) bool { return nf.Name == f.Name })
+		if i < 0 || s.Fields[i].Type != f.Type {
+			return nil, ErrIncompatibleSchema
+		}
+	}
+
+	var added []Field
+	for _, f := range s.Fields {
+		if !slices.ContainsFunc(prev.Fields, func(pf Field) bool { return pf.Name == f.Name }) {
+			added = append(added, f)
+		}
+	}
+
+	return added, nil
+}
+
+// Validate validates the payload against the schema. Null values are
+// treated as missing values.
+func (s Schema) Validate(payload map[string]interface{}) error {
+	for _, f := range s.Fields {
+		v, ok := payload[f.Name]
+		if !ok || v == nil {
+			if f.Required {
+				return errors.Wrap(ErrInvalidPayload, fmt.Errorf("missing property %q", f.Name))
+			}
+			continue
+		}
+		if !matches(f.Type, v) {
+			return errors.Wrap(ErrInvalidPayload, fmt.Errorf("property %q isn't of type %s", f.Name, f.Type))
+		}
+	}
+
+	if !s.Additional {
+		for name := range payload {
+			if !slices.ContainsFunc(s.Fields, func(f Field) bool { return f.Name == name }) {
+				return errors.Wrap(ErrInvalidPayload, fmt.Errorf("unknown property %q", name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func matches(typ string, v interface{}) bool {
+	switch typ {
+	case TypeString:
+		_, ok := v.(string)
+		return ok
+	case TypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case TypeInteger:
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n) && math.Abs(n) <= 1<<53
+	case TypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case TypeObject:
+		_, ok := v.(map[string]interface{})
+		return ok
+	case TypeArray:
+		_, ok := v.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
+
+// Repository specifies a schema persistence API.
+type Repository interface {
+	// Save persists the schema and migrates the table of its messages. The
+	// first version of the schema creates the table, and the following
+	// versions add the columns of the added fields. Saving fails with a
+	// conflict if the previous version has been changed meanwhile.
+	Save(ctx context.Context, s Schema, added []Field) error
+
+	// Retrieve retrieves the schema of the domain format.
+	Retrieve(ctx context.Context, domain, format string) (Schema, error)
+
+	// RetrieveAll retrieves the schemas of the domain, sorted by format.
+	RetrieveAll(ctx context.Context, domain string, offset, limit uint64) (Page, error)
+
+	// Remove removes the schema of the domain format and drops the table
+	// of its messages.
+	Remove(ctx context.Context, domain, format string) error
+}
+
+// Service specifies an API for managing the schemas of domain formats.
+type Service interface {
+	// Register registers the JSON schema document for the domain format.
+	// Registering the schema of a registered format adds a schema version,
+	// which may only add fields.
+	Register(ctx context.Context, domain, format string, doc json.RawMessage) (Schema, error)
+
+	// View retrieves the schema of the domain format.
+	View(ctx context.Context, domain, format string) (Schema, error)
+
+	// List lists the schemas of the domain.
+	List(ctx context.Context, domain string, offset, limit uint64) (Page, error)
+
+	// Remove removes the schema of the domain format together with the
+	// stored messages of the format.
+	Remove(ctx context.Context, domain, format string) error
+}
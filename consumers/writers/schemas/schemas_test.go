@@ -0,0 +1,336 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	domainID = "5de9b29a-feb9-11ed-be56-0242ac120002"
+	format   = "readings"
+)
+
+var document = []byte(`{
+	"type": "object",
+	"properties": {
+		"temperature": {"type": "number"},
+		"count": {"type": "integer"},
+		"location": {"type": "string"},
+		"online": {"type": "boolean"},
+		"tags": {"type": "array"},
+		"meta": {"type": "object"}
+	},
+	"required": ["temperature"],
+	"additionalProperties": false
+}`)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc       string
+		domain     string
+		format     string
+		doc        string
+		fields     []schemas.Field
+		additional bool
+		err        error
+	}{
+		{
+			desc:   "parse schema",
+			domain: domainID,
+			format: format,
+			doc:    string(document),
+			fields: []schemas.Field{
+				{Name: "count", Type: schemas.TypeInteger},
+				{Name: "location", Type: schemas.TypeString},
+				{Name: "meta", Type: schemas.TypeObject},
+				{Name: "online", Type: schemas.TypeBoolean},
+				{Name: "tags", Type: schemas.TypeArray},
+				{Name: "temperature", Type: schemas.TypeNumber, Required: true},
+			},
+		},
+		{
+			desc:       "parse schema with additional properties",
+			domain:     domainID,
+			format:     format,
+			doc:        `{"type":"object","properties":{"temperature":{"type":"number"}}}`,
+			fields:     []schemas.Field{{Name: "temperature", Type: schemas.TypeNumber}},
+			additional: true,
+		},
+		{
+			desc:   "parse schema of invalid domain",
+			domain: "domain",
+			format: format,
+			doc:    string(document),
+			err:    schemas.ErrInvalidDomain,
+		},
+		{
+			desc:   "parse schema of injected format",
+			domain: domainID,
+			format: "readings; DROP TABLE messages",
+			doc:    string(document),
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:   "parse schema of uppercase format",
+			domain: domainID,
+			format: "Readings",
+			doc:    string(document),
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:   "parse schema of too long format",
+			domain: domainID,
+			format: "readings_of_the_temperature_sensors",
+			doc:    string(document),
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:   "parse malformed schema",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema of non-object",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"array"}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema without properties",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"object"}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema with invalid property name",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"object","properties":{"temp\"erature":{"type":"number"}}}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema with reserved property name",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"object","properties":{"channel":{"type":"string"}}}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema with unsupported type",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"object","properties":{"temperature":{"type":"null"}}}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "parse schema with undefined required property",
+			domain: domainID,
+			format: format,
+			doc:    `{"type":"object","properties":{"temperature":{"type":"number"}},"required":["humidity"]}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := schemas.Parse(tc.domain, tc.format, []byte(tc.doc))
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+			assert.Equal(t, tc.fields, s.Fields, fmt.Sprintf("%s: expected fields %v, got %v", tc.desc, tc.fields, s.Fields))
+			assert.Equal(t, tc.additional, s.Additional, fmt.Sprintf("%s: expected additional properties %t, got %t", tc.desc, tc.additional, s.Additional))
+			assert.Equal(t, domainID+"_"+format, s.Table(), fmt.Sprintf("%s: expected table %s_%s, got %s", tc.desc, domainID, format, s.Table()))
+		})
+	}
+}
+
+func TestAdded(t *testing.T) {
+	prev, err := schemas.Parse(domainID, format, []byte(`{"type":"object","properties":{"temperature":{"type":"number"}}}`))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc  string
+		doc   string
+		added []schemas.Field
+		err   error
+	}{
+		{
+			desc:  "add field",
+			doc:   `{"type":"object","properties":{"temperature":{"type":"number"},"humidity":{"type":"number"}},"required":["temperature"]}`,
+			added: []schemas.Field{{Name: "humidity", Type: schemas.TypeNumber}},
+		},
+		{
+			desc: "change required fields",
+			doc:  `{"type":"object","properties":{"temperature":{"type":"number"}},"required":["temperature"]}`,
+		},
+		{
+			desc: "change field type",
+			doc:  `{"type":"object","properties":{"temperature":{"type":"string"}}}`,
+			err:  schemas.ErrIncompatibleSchema,
+		},
+		{
+			desc: "remove field",
+			doc:  `{"type":"object","properties":{"humidity":{"type":"number"}}}`,
+			err:  schemas.ErrIncompatibleSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := schemas.Parse(domainID, format, []byte(tc.doc))
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+			added, err := s.Added(prev)
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			assert.Equal(t, tc.added, added, fmt.Sprintf("%s: expected added fields %v, got %v", tc.desc, tc.added, added))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s, err := schemas.Parse(domainID, format, document)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc    string
+		payload map[string]interface{}
+		err     error
+	}{
+		{
+			desc: "validate payload",
+			payload: map[string]interface{}{
+				"temperature": 20.5,
+				"count":       3.0,
+				"location":    "room",
+				"online":      true,
+				"tags":        []interface{}{"a"},
+				"meta":        map[string]interface{}{"a": 1.0},
+			},
+		},
+		{
+			desc:    "validate payload with required fields only",
+			payload: map[string]interface{}{"temperature": 20.5},
+		},
+		{
+			desc:    "validate payload with null field",
+			payload: map[string]interface{}{"temperature": 20.5, "location": nil},
+		},
+		{
+			desc:    "validate payload without required field",
+			payload: map[string]interface{}{"location": "room"},
+			err:     schemas.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload with null required field",
+			payload: map[string]interface{}{"temperature": nil},
+			err:     schemas.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload with invalid number",
+			payload: map[string]interface{}{"temperature": "20.5"},
+			err:     schemas.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload with fractional integer",
+			payload: map[string]interface{}{"temperature": 20.5, "count": 3.5},
+			err:     schemas.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload with invalid object",
+			payload: map[string]interface{}{"temperature": 20.5, "meta": []interface{}{}},
+			err:     schemas.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload with additional property",
+			payload: map[string]interface{}{"temperature": 20.5, "humidity": 40.0},
+			err:     schemas.ErrInvalidPayload,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := s.Validate(tc.payload)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	cases := []struct {
+		desc  string
+		table string
+		index string
+	}{
+		{
+			desc:  "index of short table",
+			table: format,
+			index: "idx_readings_payload",
+		},
+		{
+			desc:  "index of domain table",
+			table: schemas.Table(domainID, format),
+			index: "idx_" + domainID + "_readings_payload",
+		},
+		{
+			desc:  "index of long table",
+			table: schemas.Table(domainID, "temperature_readings"),
+			index: "idx_fd9ad48e2aa2d5cd694dff191f0c1dcb_payload",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			index := schemas.Index(tc.table, "payload")
+			assert.Equal(t, tc.index, index, fmt.Sprintf("%s: expected index %s, got %s", tc.desc, tc.index, index))
+			assert.LessOrEqual(t, len(index), 63, fmt.Sprintf("%s: index %s is longer than a PostgreSQL identifier", tc.desc, index))
+		})
+	}
+}
+
+func TestValidateTable(t *testing.T) {
+	cases := []struct {
+		desc   string
+		format string
+		err    error
+	}{
+		{
+			desc:   "validate lowercase format",
+			format: format,
+			err:    nil,
+		},
+		{
+			desc:   "validate format with uppercase letters",
+			format: "Readings",
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:   "validate format with a statement",
+			format: "readings; DROP TABLE messages",
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:   "validate format named after a writer table",
+			format: "messages_1h",
+			err:    schemas.ErrInvalidFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := schemas.ValidateTable(tc.format)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+)
+
+var _ Service = (*service)(nil)
+
+type service struct {
+	repo Repository
+}
+
+// New instantiates the schemas service.
+func New(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (svc *service) Register(ctx context.Context, domain, format string, doc json.RawMessage) (Schema, error) {
+	s, err := Parse(domain, format, doc)
+	if err != nil {
+		return Schema{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	now := time.Now().UTC()
+	s.Version = 1
+	s.CreatedAt = now
+	s.UpdatedAt = now
+	added := s.Fields
+
+	prev, err := svc.repo.Retrieve(ctx, domain, format)
+	switch {
+	case err == nil:
+		if added, err = s.Added(prev); err != nil {
+			return Schema{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		s.Version = prev.Version + 1
+		s.CreatedAt = prev.CreatedAt
+	case !errors.Contains(err, repoerr.ErrNotFound):
+		return Schema{}, err
+	}
+
+	if err := svc.repo.Save(ctx, s, added); err != nil {
+		return Schema{}, err
+	}
+
+	return s, nil
+}
+
+func (svc *service) View(ctx context.Context, domain, format string) (Schema, error) {
+	return svc.repo.Retrieve(ctx, domain, format)
+}
+
+func (svc *service) List(ctx context.Context, domain string, offset, limit uint64) (Page, error) {
+	return svc.repo.RetrieveAll(ctx, domain, offset, limit)
+}
+
+func (svc *service) Remove(ctx context.Context, domain, format string) error {
+	return svc.repo.Remove(ctx, domain, format)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/magistrala/consumers/writers/schemas/mocks"
+	"github.com/absmach/supermq/pkg/errors"
+	repoerr "github.com/absmach/supermq/pkg/errors/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errDB = errors.New("database failure")
+
+func TestRegister(t *testing.T) {
+	prev, err := schemas.Parse(domainID, format, []byte(`{"type":"object","properties":{"temperature":{"type":"number"}}}`))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	prev.Version = 2
+
+	cases := []struct {
+		desc        string
+		format      string
+		doc         string
+		prev        schemas.Schema
+		retrieveErr error
+		saveErr     error
+		version     uint64
+		added       []string
+		err         error
+	}{
+		{
+			desc:        "register new schema",
+			format:      format,
+			doc:         string(document),
+			retrieveErr: repoerr.ErrNotFound,
+			version:     1,
+			added:       []string{"count", "location", "meta", "online", "tags", "temperature"},
+		},
+		{
+			desc:    "register schema version",
+			format:  format,
+			doc:     `{"type":"object","properties":{"temperature":{"type":"number"},"humidity":{"type":"number"}}}`,
+			prev:    prev,
+			version: 3,
+			added:   []string{"humidity"},
+		},
+		{
+			desc:   "register incompatible schema version",
+			format: format,
+			doc:    `{"type":"object","properties":{"temperature":{"type":"string"}}}`,
+			prev:   prev,
+			err:    schemas.ErrIncompatibleSchema,
+		},
+		{
+			desc:   "register invalid schema",
+			format: format,
+			doc:    `{"type":"object"}`,
+			err:    schemas.ErrInvalidSchema,
+		},
+		{
+			desc:   "register schema of invalid format",
+			format: "Readings",
+			doc:    string(document),
+			err:    schemas.ErrInvalidFormat,
+		},
+		{
+			desc:        "register schema with failed retrieval",
+			format:      format,
+			doc:         string(document),
+			retrieveErr: errDB,
+			err:         errDB,
+		},
+		{
+			desc:        "register schema with failed save",
+			format:      format,
+			doc:         string(document),
+			retrieveErr: repoerr.ErrNotFound,
+			saveErr:     repoerr.ErrConflict,
+			err:         repoerr.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			svc := schemas.New(repo)
+
+			repo.On("Retrieve", mock.Anything, domainID, tc.format).Return(tc.prev, tc.retrieveErr).Maybe()
+			var added []schemas.Field
+			repo.On("Save", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				added = args.Get(2).([]schemas.Field)
+			}).Return(tc.saveErr).Maybe()
+
+			s, err := svc.Register(context.Background(), domainID, tc.format, []byte(tc.doc))
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			if tc.err != nil {
+				return
+			}
+			assert.Equal(t, tc.version, s.Version, fmt.Sprintf("%s: expected version %d, got %d", tc.desc, tc.version, s.Version))
+
+			var names []string
+			for _, f := range added {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, tc.added, names, fmt.Sprintf("%s: expected added fields %v, got %v", tc.desc, tc.added, names))
+		})
+	}
+}
@@ -7,58 +7,114 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
 	"github.com/absmach/supermq/consumers"
 	"github.com/absmach/supermq/pkg/errors"
 	smqjson "github.com/absmach/supermq/pkg/transformers/json"
 	"github.com/absmach/supermq/pkg/transformers/senml"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx" // required for DB access
 )
 
+// Multi-row inserts are limited by the 65535 parameters of a PostgreSQL
+// statement, so the rows are inserted in chunks.
+const (
+	maxParams    = 65535
+	maxSenMLRows = maxParams / 14
+	maxJSONRows  = maxParams / 7
+	// Chunks of the JSON hypertables span 1 day = 86400000000000 nanoseconds,
+	// as the chunks of the messages hypertable.
+	chunkInterval = 86400000000000
+)
+
 var (
 	errInvalidMessage = errors.New("invalid message representation")
 	errSaveMessage    = errors.New("failed to save message to timescale database")
 	errTransRollback  = errors.New("failed to rollback transaction")
 	errNoTable        = errors.New("relation does not exist")
+	errInvalidFormat  = errors.New("invalid message format")
 )
 
 var _ consumers.BlockingConsumer = (*timescaleRepo)(nil)
 
+// Conflict clauses of the records that are already stored. Records are
+// identified by the primary keys of the hypertables.
+const (
+	ignoreConflict = ` ON CONFLICT DO NOTHING`
+	senmlUpdate    = ` ON CONFLICT (time, channel, subtopic, protocol, publisher, name) DO UPDATE SET unit = EXCLUDED.unit,
+          value = EXCLUDED.value, string_value = EXCLUDED.string_value, bool_value = EXCLUDED.bool_value,
+          data_value = EXCLUDED.data_value, sum = EXCLUDED.sum, update_time = EXCLUDED.update_time`
+	jsonUpdate = ` ON CONFLICT (created, id) DO UPDATE SET protocol = EXCLUDED.protocol, payload = EXCLUDED.payload`
+)
+
 type timescaleRepo struct {
-	db *sqlx.DB
+	db         *sqlx.DB
+	onConflict string
+	senml      *batch.Batcher[senmlMessage]
+	json       *batch.Batcher[jsonMessage]
 }
 
-// New returns new TimescaleSQL writer.
-func New(db *sqlx.DB) consumers.BlockingConsumer {
-	return &timescaleRepo{db: db}
+// New returns new TimescaleSQL writer. Records of messages consumed
+// concurrently are inserted together, as configured by the batch config.
+// The records of redelivered messages are ignored or update the stored
+// records, as configured.
+func New(db *sqlx.DB, cfg writers.Config) consumers.BlockingConsumer {
+	tr := &timescaleRepo{db: db, onConflict: cfg.OnConflict}
+	tr.senml = batch.New(cfg.Batch, tr.insertSenml)
+	tr.json = batch.New(cfg.Batch, tr.saveJSON)
+
+	return tr
 }
 
 func (tr *timescaleRepo) ConsumeBlocking(ctx context.Context, message interface{}) (err error) {
 	switch m := message.(type) {
 	case smqjson.Messages:
-		return tr.saveJSON(ctx, m)
+		return tr.addJSON(ctx, m)
 	default:
-		return tr.saveSenml(ctx, m)
+		return tr.addSenml(ctx, m)
 	}
 }
 
-func (tr timescaleRepo) saveSenml(ctx context.Context, messages interface{}) (err error) {
+func (tr timescaleRepo) addSenml(ctx context.Context, messages interface{}) error {
 	msgs, ok := messages.([]senml.Message)
 	if !ok {
 		return errSaveMessage
 	}
-	q := `INSERT INTO messages (channel, subtopic, publisher, protocol,
+
+	domain := domainOf(ctx)
+	records := make([]senmlMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		records = append(records, senmlMessage{Message: msg, Domain: domain})
+	}
+
+	return tr.senml.Add(ctx, records)
+}
+
+func (tr timescaleRepo) insertSenml(ctx context.Context, msgs []senmlMessage) (err error) {
+	q := `INSERT INTO messages (domain, channel, subtopic, publisher, protocol,
           name, unit, value, string_value, bool_value, data_value, sum,
           time, update_time)
-          VALUES (:channel, :subtopic, :publisher, :protocol, :name, :unit,
+          VALUES (:domain, :channel, :subtopic, :publisher, :protocol, :name, :unit,
           :value, :string_value, :bool_value, :data_value, :sum,
-          :time, :update_time);`
+          :time, :update_time)`
+	q += tr.conflict(senmlUpdate)
+
+	// A statement can't update the same record twice.
+	msgs = writers.Dedupe(msgs, func(m senmlMessage) string {
+		return writers.RecordID(m.Channel, m.Subtopic, m.Protocol, m.Publisher, m.Name, strconv.FormatFloat(m.Time, 'f', -1, 64))
+	})
 
 	tx, err := tr.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
+		return saveError(err)
 	}
 	defer func() {
 		if err != nil {
@@ -69,13 +125,12 @@ func (tr timescaleRepo) saveSenml(ctx context.Context, messages interface{}) (er
 		}
 
 		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
+			err = saveError(err)
 		}
 	}()
 
-	for _, msg := range msgs {
-		m := senmlMessage{Message: msg}
-		if _, err := tx.NamedExec(q, m); err != nil {
+	for _, rows := range chunk(msgs, maxSenMLRows) {
+		if _, err := tx.NamedExecContext(ctx, q, rows); err != nil {
 			pgErr, ok := err.(*pgconn.PgError)
 			if ok {
 				if pgErr.Code == pgerrcode.InvalidTextRepresentation {
@@ -83,17 +138,53 @@ func (tr timescaleRepo) saveSenml(ctx context.Context, messages interface{}) (er
 				}
 			}
 
+			return saveError(err)
+		}
+	}
+
+	if err := markLate(ctx, tx, msgs); err != nil {
+		return saveError(err)
+	}
+
+	return nil
+}
+
+func (tr timescaleRepo) addJSON(ctx context.Context, msgs smqjson.Messages) error {
+	format := strings.ToLower(msgs.Format)
+	domain := writers.Domain(ctx)
+	table := format
+	switch {
+	case domain != "":
+		if err := schemas.ValidateFormat(format); err != nil {
+			return errors.Wrap(errSaveMessage, errInvalidFormat)
+		}
+		table = schemas.Table(domain, format)
+	case schemas.ValidateTable(format) != nil:
+		return errors.Wrap(errSaveMessage, errInvalidFormat)
+	}
+
+	dbDomain := domainOf(ctx)
+	records := make([]jsonMessage, 0, len(msgs.Data))
+	for i, m := range msgs.Data {
+		dbmsg, err := toJSONMessage(m, i)
+		if err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
+		dbmsg.table = table
+		dbmsg.Domain = dbDomain
+		records = append(records, dbmsg)
 	}
-	return err
+
+	return tr.json.Add(ctx, records)
 }
 
-func (tr timescaleRepo) saveJSON(ctx context.Context, msgs smqjson.Messages) error {
+func (tr timescaleRepo) saveJSON(ctx context.Context, msgs []jsonMessage) error {
 	if err := tr.insertJSON(ctx, msgs); err != nil {
 		if err == errNoTable {
-			if err := tr.createTable(msgs.Format); err != nil {
-				return err
+			for _, table := range tables(msgs) {
+				if err := tr.createTable(table); err != nil {
+					return saveError(err)
+				}
 			}
 			return tr.insertJSON(ctx, msgs)
 		}
@@ -102,10 +193,10 @@ func (tr timescaleRepo) saveJSON(ctx context.Context, msgs smqjson.Messages) err
 	return nil
 }
 
-func (tr timescaleRepo) insertJSON(ctx context.Context, msgs smqjson.Messages) error {
+func (tr timescaleRepo) insertJSON(ctx context.Context, msgs []jsonMessage) (err error) {
 	tx, err := tr.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
+		return saveError(err)
 	}
 	defer func() {
 		if err != nil {
@@ -116,66 +207,115 @@ func (tr timescaleRepo) insertJSON(ctx context.Context, msgs smqjson.Messages) e
 		}
 
 		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
+			err = saveError(err)
 		}
 	}()
 
-	q := `INSERT INTO %s (channel, created, subtopic, publisher, protocol, payload)
-          VALUES (:channel, :created, :subtopic, :publisher, :protocol, :payload);`
-	q = fmt.Sprintf(q, msgs.Format)
+	q := `INSERT INTO %s (id, domain, channel, created, subtopic, publisher, protocol, payload)
+          VALUES (:id, :domain, :channel, :created, :subtopic, :publisher, :protocol, :payload)`
+	q += tr.conflict(jsonUpdate)
 
-	for _, m := range msgs.Data {
-		var dbmsg jsonMessage
-		dbmsg, err = toJSONMessage(m)
-		if err != nil {
-			return errors.Wrap(errSaveMessage, err)
+	msgs = writers.Dedupe(msgs, func(m jsonMessage) string {
+		return m.table + m.ID
+	})
+
+	for _, table := range tables(msgs) {
+		var rows []jsonMessage
+		for _, m := range msgs {
+			if m.table == table {
+				rows = append(rows, m)
+			}
 		}
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
-			pgErr, ok := err.(*pgconn.PgError)
-			if ok {
-				switch pgErr.Code {
-				case pgerrcode.InvalidTextRepresentation:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				case pgerrcode.UndefinedTable:
-					return errNoTable
+
+		for _, rows := range chunk(rows, maxJSONRows) {
+			if _, err := tx.NamedExecContext(ctx, fmt.Sprintf(q, pgx.Identifier{table}.Sanitize()), rows); err != nil {
+				pgErr, ok := err.(*pgconn.PgError)
+				if ok {
+					switch pgErr.Code {
+					case pgerrcode.InvalidTextRepresentation:
+						return errors.Wrap(errSaveMessage, errInvalidMessage)
+					case pgerrcode.UndefinedTable:
+						return errNoTable
+					}
 				}
+				return saveError(err)
 			}
-			return err
 		}
 	}
+
 	return nil
 }
 
-func (tr timescaleRepo) createTable(name string) error {
+// saveError wraps the error of saving messages, marking the errors that may
+// not happen again as transient, so the messages are retried.
+func saveError(err error) error {
+	if writers.IsTransient(err) {
+		err = errors.Wrap(writers.ErrTransient, err)
+	}
+
+	return errors.Wrap(errSaveMessage, err)
+}
+
+// conflict returns the update clause or the clause that ignores the records
+// that are already stored, as configured.
+func (tr timescaleRepo) conflict(update string) string {
+	if tr.onConflict == writers.OnConflictUpdate {
+		return update
+	}
+
+	return ignoreConflict
+}
+
+// createTable creates the hypertable of the JSON messages of the format,
+// partitioned by their creation time.
+func (tr timescaleRepo) createTable(table string) error {
+	name := pgx.Identifier{table}.Sanitize()
 	q := `CREATE TABLE IF NOT EXISTS %s (
             created       BIGINT NOT NULL,
+            id            UUID NOT NULL,
+            domain        UUID,
             channel       VARCHAR(254),
             subtopic      VARCHAR(254),
             publisher     VARCHAR(254),
             protocol      TEXT,
             payload       JSONB,
-            PRIMARY KEY (created, publisher, subtopic)
+            PRIMARY KEY (created, id)
         );`
 	q = fmt.Sprintf(q, name)
 
+	if _, err := tr.db.Exec(q); err != nil {
+		return err
+	}
+
+	q = `SELECT create_hypertable($1::regclass, by_range('created', $2::BIGINT), if_not_exists => TRUE)`
+	if _, err := tr.db.Exec(q, name, chunkInterval); err != nil {
+		return err
+	}
+
+	// Index the payload for containment filters of the readers.
+	q = fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (payload jsonb_path_ops)`, pgx.Identifier{schemas.Index(table, "payload")}.Sanitize(), name)
 	_, err := tr.db.Exec(q)
 	return err
 }
 
 type senmlMessage struct {
 	senml.Message
+	Domain *string `db:"domain"`
 }
 
 type jsonMessage struct {
-	Channel   string `db:"channel"`
-	Created   int64  `db:"created"`
-	Subtopic  string `db:"subtopic"`
-	Publisher string `db:"publisher"`
-	Protocol  string `db:"protocol"`
-	Payload   []byte `db:"payload"`
+	ID        string  `db:"id"`
+	Domain    *string `db:"domain"`
+	Channel   string  `db:"channel"`
+	Created   int64   `db:"created"`
+	Subtopic  string  `db:"subtopic"`
+	Publisher string  `db:"publisher"`
+	Protocol  string  `db:"protocol"`
+	Payload   []byte  `db:"payload"`
+	table     string
 }
 
-func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
+func toJSONMessage(msg smqjson.Message, index int) (jsonMessage, error) {
 	data := []byte("{}")
 	if msg.Payload != nil {
 		b, err := json.Marshal(msg.Payload)
@@ -186,6 +326,7 @@ func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
 	}
 
 	m := jsonMessage{
+		ID:        writers.JSONRecordID(msg, index, data),
 		Channel:   msg.Channel,
 		Created:   msg.Created,
 		Subtopic:  msg.Subtopic,
@@ -196,3 +337,38 @@ func toJSONMessage(msg smqjson.Message) (jsonMessage, error) {
 
 	return m, nil
 }
+
+// domainOf returns the domain of the messages of the context, or nil if
+// the domain is unknown, so the domain of their records is NULL.
+func domainOf(ctx context.Context) *string {
+	domain := writers.Domain(ctx)
+	if domain == "" {
+		return nil
+	}
+
+	return &domain
+}
+
+// tables returns the tables of the JSON messages in the order of their
+// first message.
+func tables(msgs []jsonMessage) []string {
+	var ret []string
+	for _, m := range msgs {
+		if !slices.Contains(ret, m.table) {
+			ret = append(ret, m.table)
+		}
+	}
+
+	return ret
+}
+
+// chunk splits the records into chunks of at most size records.
+func chunk[T any](records []T, size int) [][]T {
+	var ret [][]T
+	for len(records) > size {
+		ret = append(ret, records[:size])
+		records = records[size:]
+	}
+
+	return append(ret, records)
+}
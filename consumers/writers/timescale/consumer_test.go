@@ -6,14 +6,23 @@ package timescale_test
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
 	"github.com/absmach/magistrala/consumers/writers/timescale"
+	"github.com/absmach/supermq/pkg/messaging"
 	"github.com/absmach/supermq/pkg/transformers/json"
 	"github.com/absmach/supermq/pkg/transformers/senml"
 	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -28,10 +37,13 @@ var (
 	boolV           = true
 	dataV           = "base64"
 	sum     float64 = 42
+
+	// timestamp keeps the primary keys of benchmark messages unique.
+	timestamp atomic.Int64
 )
 
 func TestSaveSenml(t *testing.T) {
-	repo := timescale.New(db)
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	chid, err := uuid.NewV4()
 	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -72,7 +84,7 @@ func TestSaveSenml(t *testing.T) {
 }
 
 func TestSaveJSON(t *testing.T) {
-	repo := timescale.New(db)
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	chid, err := uuid.NewV4()
 	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -110,3 +122,361 @@ func TestSaveJSON(t *testing.T) {
 	err = repo.ConsumeBlocking(context.TODO(), msgs)
 	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
 }
+
+func TestSaveJSONHypertable(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	msg := json.Message{
+		Channel:  "channel",
+		Created:  time.Now().UnixNano(),
+		Protocol: "mqtt",
+		Payload:  map[string]interface{}{"temperature": 20.5},
+	}
+	err := repo.ConsumeBlocking(context.Background(), json.Messages{Format: "Hyper_Readings", Data: []json.Message{msg}})
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	var dimension string
+	q := `SELECT column_name FROM timescaledb_information.dimensions WHERE hypertable_name = $1`
+	err = db.Get(&dimension, q, "hyper_readings")
+	require.Nil(t, err, fmt.Sprintf("expected hypertable of format, got %s", err))
+	assert.Equal(t, "created", dimension, fmt.Sprintf("expected hypertable partitioned by created, got %s", dimension))
+
+	var count int
+	err = db.Get(&count, `SELECT COUNT(*) FROM hyper_readings`)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 1, count, fmt.Sprintf("expected 1 stored message, got %d", count))
+}
+
+func TestSaveJSONArray(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// The objects of an array payload share the time the message was
+	// created, and may hold the same values.
+	msg := messaging.Message{
+		Channel:   chid.String(),
+		Publisher: pubid.String(),
+		Subtopic:  "array_readings",
+		Protocol:  "mqtt",
+		Created:   time.Now().UnixNano(),
+		Payload:   []byte(`[{"temperature": 20.5}, {"temperature": 20.5}, {"humidity": 40}]`),
+	}
+	msgs, err := json.New(nil).Transform(&msg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	// Redeliver the message.
+	err = repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM array_readings WHERE publisher = $1", pubid.String()).Scan(&count)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 3, count, fmt.Sprintf("expected %d stored messages, got %d", 3, count))
+}
+
+func TestSaveJSONInvalidFormat(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := json.Message{
+		Channel:  "channel",
+		Created:  1,
+		Protocol: "mqtt",
+		Payload:  map[string]interface{}{"temperature": 20.5},
+	}
+
+	cases := []struct {
+		desc   string
+		ctx    context.Context
+		format string
+	}{
+		{
+			desc:   "save JSON message of injected format",
+			ctx:    context.Background(),
+			format: "readings (created) VALUES (1); DROP TABLE messages; --",
+		},
+		{
+			desc:   "save JSON message of format of writer table",
+			ctx:    context.Background(),
+			format: "messages",
+		},
+		{
+			desc:   "save JSON message of format of rollup table",
+			ctx:    context.Background(),
+			format: "messages_1h",
+		},
+		{
+			desc:   "save JSON message of format with separator",
+			ctx:    context.Background(),
+			format: "some-json",
+		},
+		{
+			desc:   "save JSON message of domain with too long format",
+			ctx:    writers.WithDomain(context.Background(), domainID.String()),
+			format: "readings_of_the_temperature_sensors",
+		},
+		{
+			desc:   "save JSON message of domain with format starting with underscore",
+			ctx:    writers.WithDomain(context.Background(), domainID.String()),
+			format: "_readings",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := repo.ConsumeBlocking(tc.ctx, json.Messages{Format: tc.format, Data: []json.Message{msg}})
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error, got nil", tc.desc))
+		})
+	}
+}
+
+func TestSaveDomain(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		ctx    context.Context
+		domain *string
+		table  string
+	}{
+		{
+			desc:   "save messages of domain",
+			ctx:    writers.WithDomain(context.Background(), domainID.String()),
+			domain: func() *string { d := domainID.String(); return &d }(),
+			table:  schemas.Table(domainID.String(), "domain_readings"),
+		},
+		{
+			desc:  "save messages of unknown domain",
+			ctx:   context.Background(),
+			table: "domain_readings",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			created := time.Now().Unix() + int64(i)
+			senmlMsg := senml.Message{Channel: chid.String(), Publisher: "publisher", Name: "domain", Time: float64(created), Value: &v}
+			err := repo.ConsumeBlocking(tc.ctx, []senml.Message{senmlMsg})
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			jsonMsg := json.Message{Channel: chid.String(), Publisher: "publisher", Created: created, Protocol: "mqtt", Payload: map[string]interface{}{"temperature": 20.5}}
+			err = repo.ConsumeBlocking(tc.ctx, json.Messages{Format: "domain_readings", Data: []json.Message{jsonMsg}})
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			var domain *string
+			err = db.Get(&domain, `SELECT CAST(domain AS TEXT) FROM messages WHERE channel = $1 AND time = $2`, chid.String(), float64(created))
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			assert.Equal(t, tc.domain, domain, fmt.Sprintf("%s: expected SenML domain %v, got %v", tc.desc, tc.domain, domain))
+
+			q := fmt.Sprintf(`SELECT domain FROM %s WHERE channel = $1 AND created = $2`, pgx.Identifier{tc.table}.Sanitize())
+			err = db.Get(&domain, q, chid.String(), created)
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			assert.Equal(t, tc.domain, domain, fmt.Sprintf("%s: expected JSON domain %v, got %v", tc.desc, tc.domain, domain))
+		})
+	}
+}
+
+func TestSaveRedelivered(t *testing.T) {
+	cases := []struct {
+		desc       string
+		onConflict string
+		value      float64
+	}{
+		{
+			desc:       "save redelivered messages ignoring stored records",
+			onConflict: writers.OnConflictIgnore,
+			value:      v,
+		},
+		{
+			desc:       "save redelivered messages updating stored records",
+			onConflict: writers.OnConflictUpdate,
+			value:      v + 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repo := timescale.New(db, writers.Config{OnConflict: tc.onConflict})
+
+			chid, err := uuid.NewV4()
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+			pubid, err := uuid.NewV4()
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+			now := time.Now().Unix()
+			msgs := make([]senml.Message, msgsNum)
+			for i := range msgs {
+				msgs[i] = senml.Message{
+					Channel:   chid.String(),
+					Publisher: pubid.String(),
+					Subtopic:  subtopic,
+					Name:      "name",
+					Value:     &v,
+					Time:      float64(now + int64(i)),
+				}
+			}
+
+			err = repo.ConsumeBlocking(context.Background(), msgs)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			// Redeliver the messages with a duplicated record and a changed value.
+			value := v + 1
+			redelivered := append(slices.Clone(msgs), msgs[0])
+			for i := range redelivered {
+				redelivered[i].Value = &value
+			}
+			err = repo.ConsumeBlocking(context.Background(), redelivered)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			var count int
+			err = db.QueryRow("SELECT COUNT(*) FROM messages WHERE publisher = $1 AND value = $2", pubid.String(), tc.value).Scan(&count)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+			assert.Equal(t, msgsNum, count, fmt.Sprintf("%s: expected %d stored messages, got %d", tc.desc, msgsNum, count))
+		})
+	}
+}
+
+func TestSaveSenmlBatched(t *testing.T) {
+	repo := timescale.New(db, writers.Config{Batch: batch.Config{Size: msgsNum * 10, Interval: 100 * time.Millisecond}, OnConflict: writers.OnConflictIgnore})
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		channel string
+		err     bool
+	}{
+		{
+			desc:    "save valid message",
+			channel: chid.String(),
+		},
+		{
+			desc:    "save another valid message",
+			channel: chid.String(),
+		},
+		{
+			desc:    "save message with invalid channel",
+			channel: "invalid",
+			err:     true,
+		},
+		{
+			desc:    "save last valid message",
+			channel: chid.String(),
+		},
+	}
+
+	now := time.Now().Unix()
+	errs := make([]error, len(cases))
+	var wg sync.WaitGroup
+	for i, tc := range cases {
+		msgs := make([]senml.Message, msgsNum)
+		for j := range msgs {
+			msgs[j] = senml.Message{
+				Channel:   tc.channel,
+				Publisher: pubid.String(),
+				Subtopic:  subtopic,
+				Name:      "name",
+				Value:     &v,
+				Time:      float64(now + int64(i*msgsNum+j)),
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.ConsumeBlocking(context.Background(), msgs)
+		}()
+	}
+	wg.Wait()
+
+	saved := 0
+	for i, tc := range cases {
+		switch tc.err {
+		case true:
+			assert.NotNil(t, errs[i], fmt.Sprintf("%s: expected error, got nil", tc.desc))
+		default:
+			assert.Nil(t, errs[i], fmt.Sprintf("%s: expected no error, got %s", tc.desc, errs[i]))
+			saved += msgsNum
+		}
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM messages WHERE publisher = $1", pubid.String()).Scan(&count)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, saved, count, fmt.Sprintf("expected %d saved messages, got %d", saved, count))
+}
+
+func BenchmarkSaveSenml(b *testing.B) {
+	for _, size := range []int{1, 10, 100, 500} {
+		b.Run(fmt.Sprintf("pack of %d", size), func(b *testing.B) {
+			repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+			msgs := senmlPack(b, size)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				setTime(msgs)
+				if err := repo.ConsumeBlocking(context.Background(), msgs); err != nil {
+					b.Fatalf("unexpected error: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSaveSenmlBatched(b *testing.B) {
+	repo := timescale.New(db, writers.Config{Batch: batch.Config{Size: 500, Interval: 10 * time.Millisecond}, OnConflict: writers.OnConflictIgnore})
+
+	b.RunParallel(func(pb *testing.PB) {
+		msgs := senmlPack(b, 10)
+		for pb.Next() {
+			setTime(msgs)
+			if err := repo.ConsumeBlocking(context.Background(), msgs); err != nil {
+				b.Errorf("unexpected error: %s", err)
+				return
+			}
+		}
+	})
+}
+
+func senmlPack(b *testing.B, size int) []senml.Message {
+	chid, err := uuid.NewV4()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msgs := make([]senml.Message, size)
+	for i := range msgs {
+		msgs[i] = senml.Message{
+			Channel:   chid.String(),
+			Publisher: pubid.String(),
+			Subtopic:  subtopic,
+			Name:      "name",
+			Value:     &v,
+		}
+	}
+
+	return msgs
+}
+
+func setTime(msgs []senml.Message) {
+	for i := range msgs {
+		msgs[i].Time = float64(timestamp.Add(1))
+	}
+}
@@ -3,11 +3,14 @@
 
 package timescale
 
-import migrate "github.com/rubenv/sql-migrate"
+import (
+	dlpostgres "github.com/absmach/magistrala/consumers/writers/deadletter/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
 
-// Migration of timescale-writer.
+// Migration of timescale-writer, including the dead letters table.
 func Migration() *migrate.MemoryMigrationSource {
-	return &migrate.MemoryMigrationSource{
+	migrations := &migrate.MemoryMigrationSource{
 		Migrations: []*migrate.Migration{
 			{
 				Id: "messages_1",
@@ -67,6 +70,275 @@ func Migration() *migrate.MemoryMigrationSource {
 					"DROP INDEX IF EXISTS idx_channel_subtopic_publisher_name_time ;",
 				},
 			},
+			// Later migrations are numbered after messages_2 with zero-padded
+			// suffixes, since sql-migrate sorts the Ids as strings.
+			{
+				Id: "messages_2_01",
+				Up: []string{
+					"ALTER TABLE messages ADD COLUMN IF NOT EXISTS domain UUID;",
+
+					// Index on domain, time
+					"CREATE INDEX IF NOT EXISTS idx_domain_time ON messages (domain, time DESC) WITH (timescaledb.transaction_per_chunk);",
+				},
+				DisableTransactionUp: true,
+				Down: []string{
+					"DROP INDEX IF EXISTS idx_domain_time ;",
+
+					"ALTER TABLE messages DROP COLUMN IF EXISTS domain;",
+				},
+			},
+			{
+				Id: "messages_2_02",
+				Up: []string{
+					// Index the payload of the existing JSON message tables.
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+						LOOP
+							EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING GIN (payload jsonb_path_ops)', 'idx_' || t || '_payload', t);
+						END LOOP;
+					END $$;`,
+				},
+				Down: []string{
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+						LOOP
+							EXECUTE format('DROP INDEX IF EXISTS %I', 'idx_' || t || '_payload');
+						END LOOP;
+					END $$;`,
+				},
+			},
+			{
+				Id: "messages_2_03",
+				Up: []string{
+					// Rollups of SenML message values, refreshed by the retention.
+					`CREATE TABLE IF NOT EXISTS messages_1m (
+                        time          BIGINT NOT NULL,
+                        domain        UUID,
+                        channel       UUID,
+                        subtopic      VARCHAR(254),
+                        publisher     VARCHAR(254),
+                        protocol      TEXT,
+                        name          VARCHAR(254),
+                        unit          TEXT,
+                        value_count   BIGINT,
+                        value_sum     FLOAT,
+                        value_min     FLOAT,
+                        value_max     FLOAT,
+                        value_first   FLOAT,
+                        value_last    FLOAT,
+                        PRIMARY KEY (channel, subtopic, publisher, name, time)
+                    );`,
+
+					`CREATE TABLE IF NOT EXISTS messages_1h (
+                        time          BIGINT NOT NULL,
+                        domain        UUID,
+                        channel       UUID,
+                        subtopic      VARCHAR(254),
+                        publisher     VARCHAR(254),
+                        protocol      TEXT,
+                        name          VARCHAR(254),
+                        unit          TEXT,
+                        value_count   BIGINT,
+                        value_sum     FLOAT,
+                        value_min     FLOAT,
+                        value_max     FLOAT,
+                        value_first   FLOAT,
+                        value_last    FLOAT,
+                        PRIMARY KEY (channel, subtopic, publisher, name, time)
+                    );`,
+
+					`CREATE TABLE IF NOT EXISTS messages_1d (
+                        time          BIGINT NOT NULL,
+                        domain        UUID,
+                        channel       UUID,
+                        subtopic      VARCHAR(254),
+                        publisher     VARCHAR(254),
+                        protocol      TEXT,
+                        name          VARCHAR(254),
+                        unit          TEXT,
+                        value_count   BIGINT,
+                        value_sum     FLOAT,
+                        value_min     FLOAT,
+                        value_max     FLOAT,
+                        value_first   FLOAT,
+                        value_last    FLOAT,
+                        PRIMARY KEY (channel, subtopic, publisher, name, time)
+                    );`,
+
+					`CREATE TABLE IF NOT EXISTS rollups (
+                        name          VARCHAR(254),
+                        since         BIGINT,
+                        refreshed     BIGINT,
+                        PRIMARY KEY (name)
+                    );`,
+				},
+				Down: []string{
+					"DROP TABLE rollups;",
+
+					"DROP TABLE messages_1d;",
+
+					"DROP TABLE messages_1h;",
+
+					"DROP TABLE messages_1m;",
+				},
+			},
+			{
+				Id: "messages_2_04",
+				Up: []string{
+					// Convert the existing JSON message tables to hypertables,
+					// with the chunks interval of the messages hypertable.
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+						LOOP
+							PERFORM create_hypertable(quote_ident(t)::regclass, by_range('created', 86400000000000), if_not_exists => TRUE, migrate_data => TRUE);
+						END LOOP;
+					END $$;`,
+				},
+			},
+			{
+				Id: "messages_2_05",
+				Up: []string{
+					// Add the domain to the JSON message tables created
+					// before messages were stored with their domain.
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+						LOOP
+							EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS domain VARCHAR(254)', t);
+						END LOOP;
+					END $$;`,
+
+					// Backfill the domain of the records without domain from
+					// the records of the same channel with domain, such as
+					// dead letters and messages stored after the upgrade.
+					`DO $$
+					DECLARE t RECORD;
+					BEGIN
+						CREATE TEMPORARY TABLE channel_domains (channel TEXT PRIMARY KEY, domain TEXT NOT NULL) ON COMMIT DROP;
+						FOR t IN SELECT c.table_name, d.udt_name FROM information_schema.columns c
+							JOIN information_schema.columns d ON d.table_schema = c.table_schema AND d.table_name = c.table_name
+							WHERE c.table_schema = current_schema() AND c.column_name = 'channel' AND d.column_name = 'domain'
+						LOOP
+							EXECUTE format('INSERT INTO channel_domains SELECT DISTINCT CAST(channel AS TEXT), CAST(domain AS TEXT) FROM %I
+								WHERE channel IS NOT NULL AND CAST(domain AS TEXT) ~ %L ON CONFLICT DO NOTHING',
+								t.table_name, '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$');
+						END LOOP;
+						FOR t IN SELECT c.table_name, d.udt_name FROM information_schema.columns c
+							JOIN information_schema.columns d ON d.table_schema = c.table_schema AND d.table_name = c.table_name
+							WHERE c.table_schema = current_schema() AND c.column_name = 'channel' AND d.column_name = 'domain'
+						LOOP
+							EXECUTE format('UPDATE %I m SET domain = CAST(cd.domain AS %s) FROM channel_domains cd
+								WHERE (m.domain IS NULL OR CAST(m.domain AS TEXT) = '''') AND CAST(m.channel AS TEXT) = cd.channel',
+								t.table_name, t.udt_name);
+						END LOOP;
+					END $$;`,
+				},
+			},
+			{
+				Id: "messages_2_06",
+				Up: []string{
+					// Identify the records of the JSON message tables by
+					// their ID, so the records of the objects of an array
+					// payload that share their creation time are all stored.
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+						LOOP
+							EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS id UUID', t);
+							EXECUTE format('UPDATE %I SET id = CAST(md5(concat_ws(chr(0), channel, publisher, subtopic, created, payload)) AS UUID) WHERE id IS NULL', t);
+							EXECUTE format('ALTER TABLE %I ALTER COLUMN id SET NOT NULL', t);
+							EXECUTE format('ALTER TABLE %I DROP CONSTRAINT IF EXISTS %I', t, t || '_pkey');
+							EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (created, id)', t);
+						END LOOP;
+					END $$;`,
+				},
+			},
+			{
+				Id: "messages_2_07",
+				Up: []string{
+					// Store the domain of the JSON message tables as UUID,
+					// the same as the domain of the SenML messages.
+					`DO $$
+					DECLARE t TEXT;
+					BEGIN
+						FOR t IN SELECT p.table_name FROM information_schema.columns p
+							JOIN information_schema.columns d ON d.table_schema = p.table_schema AND d.table_name = p.table_name
+							WHERE p.table_schema = current_schema() AND p.column_name = 'payload' AND p.data_type = 'jsonb'
+								AND d.column_name = 'domain' AND d.data_type = 'character varying'
+						LOOP
+							EXECUTE format('ALTER TABLE %I ALTER COLUMN domain TYPE UUID USING CASE WHEN domain ~ %L THEN CAST(domain AS UUID) END',
+								t, '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$');
+						END LOOP;
+					END $$;`,
+				},
+			},
+			{
+				Id: "messages_2_08",
+				Up: []string{
+					// Buckets of the messages stored after their buckets
+					// were aggregated, which the next refresh aggregates.
+					`CREATE TABLE IF NOT EXISTS rollups_pending (
+                        time          BIGINT,
+                        PRIMARY KEY (time)
+                    );`,
+				},
+				Down: []string{
+					"DROP TABLE rollups_pending;",
+				},
+			},
+			{
+				Id: "messages_2_09",
+				Up: []string{
+					// Move the messages of the JSON message hypertables to the
+					// hypertables of their domains. Messages without domains take
+					// the domain of the other messages of their channel, or stay
+					// in place.
+					`DO $$
+					DECLARE t TEXT; d UUID; nt TEXT;
+					BEGIN
+						FOR t IN SELECT table_name FROM information_schema.columns
+							WHERE table_schema = current_schema() AND column_name = 'payload' AND data_type = 'jsonb'
+								AND table_name ~ '^[a-z][a-z0-9_]{0,25}$'
+								AND table_name NOT IN ('dead_letters')
+						LOOP
+							EXECUTE format('UPDATE %I m SET domain = c.domain FROM (SELECT DISTINCT ON (channel) channel, domain FROM %I WHERE domain IS NOT NULL) c
+								WHERE m.domain IS NULL AND m.channel = c.channel', t, t);
+							FOR d IN EXECUTE format('SELECT DISTINCT domain FROM %I WHERE domain IS NOT NULL', t)
+							LOOP
+								nt := d || '_' || t;
+								IF to_regclass(quote_ident(nt)) IS NULL THEN
+									EXECUTE format('CREATE TABLE %I (created BIGINT NOT NULL, id UUID NOT NULL, domain UUID, channel VARCHAR(254),
+										subtopic VARCHAR(254), publisher VARCHAR(254), protocol TEXT, payload JSONB, PRIMARY KEY (created, id))', nt);
+									PERFORM create_hypertable(quote_ident(nt)::regclass, by_range('created', 86400000000000), if_not_exists => TRUE);
+									EXECUTE format('CREATE INDEX %I ON %I USING GIN (payload jsonb_path_ops)',
+										CASE WHEN length(nt) <= 51 THEN 'idx_' || nt || '_payload' ELSE 'idx_' || md5(nt) || '_payload' END, nt);
+								END IF;
+								EXECUTE format('INSERT INTO %I (created, id, domain, channel, subtopic, publisher, protocol, payload)
+									SELECT created, id, domain, channel, subtopic, publisher, protocol, payload FROM %I WHERE domain = %L
+									ON CONFLICT DO NOTHING', nt, t, d);
+								EXECUTE format('DELETE FROM %I WHERE domain = %L', t, d);
+							END LOOP;
+						END LOOP;
+					END $$;`,
+				},
+			},
 		},
 	}
+
+	migrations.Migrations = append(migrations.Migrations, dlpostgres.Migration().Migrations...)
+
+	return migrations
 }
@@ -0,0 +1,78 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/magistrala/consumers/writers/timescale"
+	pgclient "github.com/absmach/supermq/pkg/postgres"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationUpgrade(t *testing.T) {
+	_, err := db.Exec(`CREATE DATABASE upgrade`)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	cfg := dbConfig
+	cfg.Name = "upgrade"
+
+	// The schema of the writer before the migrations that followed
+	// messages_2, with the records it stored.
+	baseline := migrate.MemoryMigrationSource{Migrations: timescale.Migration().Migrations[:2]}
+	udb, err := pgclient.Setup(cfg, baseline)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	defer udb.Close()
+
+	chanID := uuid.Must(uuid.NewV4()).String()
+	pubID := uuid.Must(uuid.NewV4()).String()
+	_, err = udb.Exec(`INSERT INTO messages (time, channel, subtopic, publisher, protocol, name, value)
+        VALUES (1, $1, '', $2, 'mqtt', 'temperature', 20)`, chanID, pubID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	_, err = udb.Exec(`CREATE TABLE IF NOT EXISTS sensors (created BIGINT NOT NULL, channel VARCHAR(254), subtopic VARCHAR(254),
+        publisher VARCHAR(254), protocol TEXT, payload JSONB, PRIMARY KEY (created, publisher, subtopic))`)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	_, err = udb.Exec(`INSERT INTO sensors (created, channel, subtopic, publisher, protocol, payload)
+        VALUES (1, $1, '', $2, 'mqtt', '{"temperature": 20}')`, chanID, pubID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	// Messages of domains stored before the JSON messages were stored in
+	// the tables of their domains.
+	migrations := timescale.Migration().Migrations
+	i := slices.IndexFunc(migrations, func(m *migrate.Migration) bool { return m.Id == "messages_2_09" })
+	udb, err = pgclient.Setup(cfg, migrate.MemoryMigrationSource{Migrations: migrations[:i]})
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	defer udb.Close()
+
+	domainID := uuid.Must(uuid.NewV4()).String()
+	_, err = udb.Exec(`INSERT INTO sensors (created, id, domain, channel, subtopic, publisher, protocol, payload)
+        VALUES (2, $1, $2, $3, '', $4, 'mqtt', '{"temperature": 21}')`, uuid.Must(uuid.NewV4()).String(), domainID, chanID, pubID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	udb, err = pgclient.Setup(cfg, *timescale.Migration())
+	require.Nil(t, err, fmt.Sprintf("expected no error upgrading from the baseline schema got %s", err))
+	defer udb.Close()
+
+	var applied []string
+	err = udb.Select(&applied, `SELECT id FROM gorp_migrations ORDER BY applied_at, id`)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, len(timescale.Migration().Migrations), len(applied), fmt.Sprintf("expected all migrations applied got %v", applied))
+
+	// The message without domain takes the domain of its channel.
+	var n int
+	q := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE channel = $1 AND id IS NOT NULL`, pgx.Identifier{schemas.Table(domainID, "sensors")}.Sanitize())
+	err = udb.Get(&n, q, chanID)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 2, n, fmt.Sprintf("expected 2 JSON messages of domain with IDs got %d", n))
+	err = udb.Get(&n, `SELECT COUNT(*) FROM sensors WHERE channel = $1`, chanID)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 0, n, fmt.Sprintf("expected no JSON messages left in the format table got %d", n))
+}
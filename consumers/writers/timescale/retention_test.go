@@ -0,0 +1,293 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/retention"
+	"github.com/absmach/magistrala/consumers/writers/timescale"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelete(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	rr := timescale.NewRetention(db)
+
+	now := time.Now().Truncate(time.Second)
+	expired := saveHourly(t, repo, now)
+	kept := saveHourly(t, repo, now)
+
+	cases := []struct {
+		desc       string
+		policy     retention.Policy
+		exceptions []retention.Policy
+		before     time.Time
+		expired    int
+		kept       int
+	}{
+		{
+			desc:    "delete messages of channel policy",
+			policy:  retention.Policy{Scope: retention.ScopeChannel, ID: expired, Age: 5 * time.Hour},
+			before:  now.Add(-5 * time.Hour),
+			expired: 6,
+			kept:    10,
+		},
+		{
+			desc:       "delete messages of default policy with exceptions",
+			policy:     retention.Policy{Age: 2 * time.Hour},
+			exceptions: []retention.Policy{{Scope: retention.ScopeChannel, ID: kept}},
+			before:     now.Add(-2 * time.Hour),
+			expired:    3,
+			kept:       10,
+		},
+		{
+			desc:    "delete messages of domain policy",
+			policy:  retention.Policy{Scope: retention.ScopeDomain, ID: uuid.Must(uuid.NewV4()).String(), Age: time.Hour},
+			before:  now.Add(-time.Hour),
+			expired: 3,
+			kept:    10,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := rr.Delete(context.Background(), tc.policy, tc.before, tc.exceptions)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+			assert.Equal(t, tc.expired, count(t, expired), fmt.Sprintf("%s: expected %d messages of expired channel", tc.desc, tc.expired))
+			assert.Equal(t, tc.kept, count(t, kept), fmt.Sprintf("%s: expected %d messages of kept channel", tc.desc, tc.kept))
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	rr := timescale.NewRetention(db)
+
+	chanID := uuid.Must(uuid.NewV4()).String()
+	pubID := uuid.Must(uuid.NewV4()).String()
+
+	// Messages 10 seconds apart over two minutes.
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Minute)
+	var msgs []senml.Message
+	for i := 0; i < 12; i++ {
+		val := float64(i)
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Name:      "temperature",
+			Time:      float64(start.Add(time.Duration(i) * 10 * time.Second).UnixNano()),
+			Value:     &val,
+		})
+	}
+	err := repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	err = rr.Rollup(context.Background(), start, end)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	cases := []struct {
+		desc    string
+		table   string
+		buckets []bucket
+	}{
+		{
+			desc:  "rollup of minutes",
+			table: "messages_1m",
+			buckets: []bucket{
+				{Time: start.UnixNano(), Count: 6, Sum: 15, Min: 0, Max: 5, First: 0, Last: 5},
+				{Time: start.Add(time.Minute).UnixNano(), Count: 6, Sum: 51, Min: 6, Max: 11, First: 6, Last: 11},
+			},
+		},
+		{
+			desc:  "rollup of hours",
+			table: "messages_1h",
+			buckets: []bucket{
+				{Time: start.UnixNano(), Count: 12, Sum: 66, Min: 0, Max: 11, First: 0, Last: 11},
+			},
+		},
+		{
+			desc:  "rollup of days",
+			table: "messages_1d",
+			buckets: []bucket{
+				{Time: start.UnixNano(), Count: 12, Sum: 66, Min: 0, Max: 11, First: 0, Last: 11},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buckets []bucket
+			q := fmt.Sprintf(`SELECT time, value_count, value_sum, value_min, value_max, value_first, value_last
+                FROM %s WHERE channel = $1 ORDER BY time`, tc.table)
+			err := db.Select(&buckets, q, chanID)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+			assert.Equal(t, tc.buckets, buckets, fmt.Sprintf("%s: expected buckets %v got %v", tc.desc, tc.buckets, buckets))
+
+			var since, refreshed int64
+			err = db.QueryRow(`SELECT since, refreshed FROM rollups WHERE name = $1`, tc.table).Scan(&since, &refreshed)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+			assert.Equal(t, start.UnixNano(), since, fmt.Sprintf("%s: expected rollup since %d got %d", tc.desc, start.UnixNano(), since))
+			assert.Equal(t, end.UnixNano(), refreshed, fmt.Sprintf("%s: expected rollup refreshed %d got %d", tc.desc, end.UnixNano(), refreshed))
+		})
+	}
+}
+
+func TestRollupLateMessages(t *testing.T) {
+	repo := timescale.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	rr := timescale.NewRetention(db)
+
+	chanID := uuid.Must(uuid.NewV4()).String()
+	pubID := uuid.Must(uuid.NewV4()).String()
+	message := func(at time.Time, val float64) senml.Message {
+		return senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Name:      "temperature",
+			Time:      float64(at.UnixNano()),
+			Value:     &val,
+		}
+	}
+
+	// Messages 10 seconds apart over two minutes.
+	start := time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Minute)
+	var msgs []senml.Message
+	for i := 0; i < 12; i++ {
+		msgs = append(msgs, message(start.Add(time.Duration(i)*10*time.Second), float64(i)))
+	}
+	err := repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	err = rr.Rollup(context.Background(), start, end)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	// Messages stored after their buckets were aggregated, and before the
+	// window of the next refresh.
+	late := []senml.Message{message(start.Add(5*time.Second), 100), message(start.Add(-time.Hour), 50)}
+	err = repo.ConsumeBlocking(context.Background(), late)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	err = rr.Rollup(context.Background(), end, end.Add(time.Minute))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	cases := []struct {
+		desc    string
+		table   string
+		buckets []bucket
+	}{
+		{
+			desc:  "rollup of minutes with late messages",
+			table: "messages_1m",
+			buckets: []bucket{
+				{Time: start.Add(-time.Hour).UnixNano(), Count: 1, Sum: 50, Min: 50, Max: 50, First: 50, Last: 50},
+				{Time: start.UnixNano(), Count: 7, Sum: 115, Min: 0, Max: 100, First: 0, Last: 5},
+				{Time: start.Add(time.Minute).UnixNano(), Count: 6, Sum: 51, Min: 6, Max: 11, First: 6, Last: 11},
+			},
+		},
+		{
+			desc:  "rollup of hours with late messages",
+			table: "messages_1h",
+			buckets: []bucket{
+				{Time: start.Add(-time.Hour).UnixNano(), Count: 1, Sum: 50, Min: 50, Max: 50, First: 50, Last: 50},
+				{Time: start.UnixNano(), Count: 13, Sum: 166, Min: 0, Max: 100, First: 0, Last: 11},
+			},
+		},
+		{
+			desc:  "rollup of days with late messages",
+			table: "messages_1d",
+			buckets: []bucket{
+				{Time: start.Add(-24 * time.Hour).UnixNano(), Count: 1, Sum: 50, Min: 50, Max: 50, First: 50, Last: 50},
+				{Time: start.UnixNano(), Count: 13, Sum: 166, Min: 0, Max: 100, First: 0, Last: 11},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buckets []bucket
+			q := fmt.Sprintf(`SELECT time, value_count, value_sum, value_min, value_max, value_first, value_last
+                FROM %s WHERE channel = $1 ORDER BY time`, tc.table)
+			err := db.Select(&buckets, q, chanID)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+			assert.Equal(t, tc.buckets, buckets, fmt.Sprintf("%s: expected buckets %v got %v", tc.desc, tc.buckets, buckets))
+		})
+	}
+
+	var pending int
+	err = db.Get(&pending, `SELECT COUNT(*) FROM rollups_pending`)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 0, pending, fmt.Sprintf("expected no pending buckets got %d", pending))
+}
+
+type bucket struct {
+	Time  int64   `db:"time"`
+	Count int64   `db:"value_count"`
+	Sum   float64 `db:"value_sum"`
+	Min   float64 `db:"value_min"`
+	Max   float64 `db:"value_max"`
+	First float64 `db:"value_first"`
+	Last  float64 `db:"value_last"`
+}
+
+// saveHourly saves ten messages of a new channel one hour apart, the first
+// of them at the given time, and returns the channel.
+func saveHourly(t *testing.T, repo interface {
+	ConsumeBlocking(ctx context.Context, messages interface{}) error
+}, now time.Time,
+) string {
+	chanID := uuid.Must(uuid.NewV4()).String()
+	pubID := uuid.Must(uuid.NewV4()).String()
+
+	var msgs []senml.Message
+	for i := 0; i < 10; i++ {
+		val := float64(i)
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Name:      "temperature",
+			Time:      float64(now.Add(-time.Duration(i) * time.Hour).UnixNano()),
+			Value:     &val,
+		})
+	}
+	err := repo.ConsumeBlocking(context.Background(), msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	return chanID
+}
+
+func count(t *testing.T, chanID string) int {
+	var n int
+	err := db.Get(&n, `SELECT COUNT(*) FROM messages WHERE channel = $1`, chanID)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+
+	return n
+}
+
+func TestCompress(t *testing.T) {
+	// Compression is set up again on every start of the writer.
+	for i := 0; i < 2; i++ {
+		err := timescale.Compress(context.Background(), db, 7*24*time.Hour)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	}
+
+	var enabled bool
+	err := db.Get(&enabled, `SELECT compression_enabled FROM timescaledb_information.hypertables WHERE hypertable_name = 'messages'`)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.True(t, enabled, "expected compression of messages to be enabled")
+
+	var jobs int
+	err = db.Get(&jobs, `SELECT COUNT(*) FROM timescaledb_information.jobs WHERE proc_name = 'policy_compression' AND hypertable_name = 'messages'`)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, 1, jobs, fmt.Sprintf("expected 1 compression policy got %d", jobs))
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/ory/dockertest/v3/docker"
 )
 
-var db *sqlx.DB
+var (
+	db       *sqlx.DB
+	dbConfig pgclient.Config
+)
 
 func TestMain(m *testing.M) {
 	pool, err := dockertest.NewPool("")
@@ -56,7 +59,7 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not connect to docker: %s", err)
 	}
 
-	dbConfig := pgclient.Config{
+	dbConfig = pgclient.Config{
 		Host:        "localhost",
 		Port:        port,
 		User:        "test",
@@ -0,0 +1,115 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/absmach/magistrala/consumers/writers/batch"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Resolutions of records that are already stored, such as the records of
+// messages the broker redelivers.
+const (
+	// OnConflictIgnore keeps the stored record.
+	OnConflictIgnore = "ignore"
+	// OnConflictUpdate replaces the values of the stored record.
+	OnConflictUpdate = "update"
+)
+
+var (
+	// ErrInvalidOnConflict indicates an unknown conflict resolution.
+	ErrInvalidOnConflict = errors.New("invalid conflict resolution")
+
+	// ErrTransient indicates a database failure that may not happen again,
+	// so saving the messages is worth retrying.
+	ErrTransient = errors.New("transient database failure")
+)
+
+// recordNamespace is the namespace of the record IDs.
+var recordNamespace = uuid.NewV5(uuid.NamespaceURL, "https://absmach.eu/magistrala/messages")
+
+// Config contains message writers configuration.
+type Config struct {
+	Batch      batch.Config `envPrefix:"BATCH_"`
+	OnConflict string       `env:"ON_CONFLICT" envDefault:"ignore"`
+}
+
+// Validate validates the writers configuration.
+func (cfg Config) Validate() error {
+	switch cfg.OnConflict {
+	case OnConflictIgnore, OnConflictUpdate:
+		return nil
+	default:
+		return ErrInvalidOnConflict
+	}
+}
+
+// RecordID returns the ID of the record identified by the fields, such as
+// its channel, publisher, subtopic, name and time. The same fields always
+// give the same ID, so the records of redelivered messages are detected.
+func RecordID(fields ...string) string {
+	return uuid.NewV5(recordNamespace, strings.Join(fields, "\x00")).String()
+}
+
+// JSONRecordID returns the ID of the record of the JSON message at the index
+// of the messages of a payload, with the encoded payload. The objects of an
+// array payload may share the time they were created, even with the same
+// values, so their index and payload identify the record along with the
+// channel, publisher, subtopic and time of the message.
+func JSONRecordID(msg smqjson.Message, index int, payload []byte) string {
+	return RecordID(msg.Channel, msg.Publisher, msg.Subtopic, strconv.FormatInt(msg.Created, 10), strconv.Itoa(index), string(payload))
+}
+
+// Dedupe returns the records without the records with the same key, keeping
+// the last of them in place of the first.
+func Dedupe[T any](records []T, key func(T) string) []T {
+	idx := make(map[string]int, len(records))
+	ret := make([]T, 0, len(records))
+	for _, r := range records {
+		k := key(r)
+		if i, ok := idx[k]; ok {
+			ret[i] = r
+			continue
+		}
+		idx[k] = len(ret)
+		ret = append(ret, r)
+	}
+
+	return ret
+}
+
+// IsTransient reports whether the database error may not happen again, such
+// as a lost connection, a serialization failure or exhausted resources.
+// Errors caused by the messages themselves are never transient.
+func IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgerrcode.IsConnectionException(pgErr.Code) ||
+			pgerrcode.IsTransactionRollback(pgErr.Code) ||
+			pgerrcode.IsInsufficientResources(pgErr.Code) ||
+			pgerrcode.IsOperatorIntervention(pgErr.Code) ||
+			pgerrcode.IsSystemError(pgErr.Code)
+	}
+
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &connErr), errors.As(err, &netErr):
+		return true
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return true
+	default:
+		return pgconn.Timeout(err) || pgconn.SafeToRetry(err)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		desc       string
+		onConflict string
+		err        error
+	}{
+		{
+			desc:       "validate ignore conflict resolution",
+			onConflict: writers.OnConflictIgnore,
+		},
+		{
+			desc:       "validate update conflict resolution",
+			onConflict: writers.OnConflictUpdate,
+		},
+		{
+			desc:       "validate invalid conflict resolution",
+			onConflict: "invalid",
+			err:        writers.ErrInvalidOnConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := writers.Config{OnConflict: tc.onConflict}.Validate()
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %v, got %v", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestRecordID(t *testing.T) {
+	cases := []struct {
+		desc   string
+		fields []string
+		other  []string
+		equal  bool
+	}{
+		{
+			desc:   "record ID of same fields",
+			fields: []string{"channel", "publisher", "subtopic", "name", "1"},
+			other:  []string{"channel", "publisher", "subtopic", "name", "1"},
+			equal:  true,
+		},
+		{
+			desc:   "record ID of different time",
+			fields: []string{"channel", "publisher", "subtopic", "name", "1"},
+			other:  []string{"channel", "publisher", "subtopic", "name", "2"},
+			equal:  false,
+		},
+		{
+			desc:   "record ID of fields with moved separator",
+			fields: []string{"channel", "publisher", "sub", "topic"},
+			other:  []string{"channel", "publisher", "subtopic", ""},
+			equal:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			id := writers.RecordID(tc.fields...)
+			other := writers.RecordID(tc.other...)
+			assert.Equal(t, tc.equal, id == other, fmt.Sprintf("%s: expected equal IDs to be %t, got %s and %s", tc.desc, tc.equal, id, other))
+		})
+	}
+}
+
+func TestJSONRecordID(t *testing.T) {
+	msg := smqjson.Message{Channel: "channel", Publisher: "publisher", Subtopic: "subtopic", Created: 1}
+	payload := []byte(`{"temperature":20.5}`)
+
+	cases := []struct {
+		desc    string
+		index   int
+		payload []byte
+		equal   bool
+	}{
+		{
+			desc:    "record ID of same index and payload",
+			index:   0,
+			payload: payload,
+			equal:   true,
+		},
+		{
+			desc:    "record ID of different index",
+			index:   1,
+			payload: payload,
+			equal:   false,
+		},
+		{
+			desc:    "record ID of different payload",
+			index:   0,
+			payload: []byte(`{"humidity":40}`),
+			equal:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			id := writers.JSONRecordID(msg, 0, payload)
+			other := writers.JSONRecordID(msg, tc.index, tc.payload)
+			assert.Equal(t, tc.equal, id == other, fmt.Sprintf("%s: expected equal IDs to be %t, got %s and %s", tc.desc, tc.equal, id, other))
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	type record struct {
+		key   string
+		value int
+	}
+
+	records := []record{{"a", 1}, {"b", 2}, {"a", 3}, {"c", 4}, {"b", 5}}
+	expected := []record{{"a", 3}, {"b", 5}, {"c", 4}}
+
+	ret := writers.Dedupe(records, func(r record) string { return r.key })
+	assert.Equal(t, expected, ret, fmt.Sprintf("expected %v, got %v", expected, ret))
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		desc      string
+		err       error
+		transient bool
+	}{
+		{
+			desc:      "connection failure",
+			err:       &pgconn.PgError{Code: pgerrcode.ConnectionFailure},
+			transient: true,
+		},
+		{
+			desc:      "serialization failure",
+			err:       &pgconn.PgError{Code: pgerrcode.SerializationFailure},
+			transient: true,
+		},
+		{
+			desc:      "too many connections",
+			err:       &pgconn.PgError{Code: pgerrcode.TooManyConnections},
+			transient: true,
+		},
+		{
+			desc:      "wrapped admin shutdown",
+			err:       fmt.Errorf("failed to insert: %w", &pgconn.PgError{Code: pgerrcode.AdminShutdown}),
+			transient: true,
+		},
+		{
+			desc:      "invalid text representation",
+			err:       &pgconn.PgError{Code: pgerrcode.InvalidTextRepresentation},
+			transient: false,
+		},
+		{
+			desc:      "undefined table",
+			err:       &pgconn.PgError{Code: pgerrcode.UndefinedTable},
+			transient: false,
+		},
+		{
+			desc:      "bad connection",
+			err:       driver.ErrBadConn,
+			transient: true,
+		},
+		{
+			desc:      "deadline exceeded",
+			err:       context.DeadlineExceeded,
+			transient: true,
+		},
+		{
+			desc:      "other error",
+			err:       errors.New("invalid message"),
+			transient: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			transient := writers.IsTransient(tc.err)
+			assert.Equal(t, tc.transient, transient, fmt.Sprintf("%s: expected transient %t, got %t", tc.desc, tc.transient, transient))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package testsutil
+
+import (
+	"time"
+
+	"github.com/absmach/supermq/pkg/transformers/senml"
+)
+
+// AggregationStart is the time of the first aggregated message in seconds.
+// It is a bucket boundary, so the messages fall into whole buckets.
+var AggregationStart = float64(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+
+// AggregationInterval is the interval of the buckets of the aggregated
+// messages.
+const AggregationInterval = "10s"
+
+// AggregationMessages returns the messages the readers aggregate: 30
+// messages one second apart with the values 0 to 29, which fall into three
+// 10 second buckets.
+func AggregationMessages(chanID, pubID, protocol, name string) []senml.Message {
+	messages := make([]senml.Message, 0, 30)
+	for i := 0; i < 30; i++ {
+		val := float64(i)
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  protocol,
+			Name:      name,
+			Time:      (AggregationStart + float64(i)) * 1e9,
+			Value:     &val,
+		})
+	}
+
+	return messages
+}
+
+// AggregationCase is an aggregation of the aggregation messages and the
+// values of its buckets, from the latest bucket to the first.
+type AggregationCase struct {
+	Desc        string
+	Aggregation string
+	Values      []float64
+}
+
+// AggregationCases are the aggregations every reader computes the same way.
+var AggregationCases = []AggregationCase{
+	{
+		Desc:        "read messages with AVG aggregation",
+		Aggregation: "AVG",
+		Values:      []float64{24.5, 14.5, 4.5},
+	},
+	{
+		Desc:        "read messages with MIN aggregation",
+		Aggregation: "MIN",
+		Values:      []float64{20, 10, 0},
+	},
+	{
+		Desc:        "read messages with MAX aggregation",
+		Aggregation: "max",
+		Values:      []float64{29, 19, 9},
+	},
+	{
+		Desc:        "read messages with SUM aggregation",
+		Aggregation: "SUM",
+		Values:      []float64{245, 145, 45},
+	},
+	{
+		Desc:        "read messages with COUNT aggregation",
+		Aggregation: "COUNT",
+		Values:      []float64{10, 10, 10},
+	},
+	{
+		Desc:        "read messages with P95 aggregation",
+		Aggregation: "P95",
+		Values:      []float64{28.55, 18.55, 8.55},
+	},
+	{
+		Desc:        "read messages with P99 aggregation",
+		Aggregation: "p99",
+		Values:      []float64{28.91, 18.91, 8.91},
+	},
+	{
+		Desc:        "read messages with STDDEV aggregation",
+		Aggregation: "STDDEV",
+		Values:      []float64{3.0276503540974917, 3.0276503540974917, 3.0276503540974917},
+	},
+	{
+		Desc:        "read messages with FIRST aggregation",
+		Aggregation: "FIRST",
+		Values:      []float64{20, 10, 0},
+	},
+	{
+		Desc:        "read messages with LAST aggregation",
+		Aggregation: "LAST",
+		Values:      []float64{29, 19, 9},
+	},
+	{
+		Desc:        "read messages with DELTA aggregation",
+		Aggregation: "DELTA",
+		Values:      []float64{10, 10, 9},
+	},
+	{
+		Desc:        "read messages with RATE aggregation",
+		Aggregation: "rate",
+		Values:      []float64{1, 1, 1},
+	},
+}
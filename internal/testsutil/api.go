@@ -0,0 +1,70 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package testsutil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiutil "github.com/absmach/supermq/api/http/util"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	authnmocks "github.com/absmach/supermq/pkg/authn/mocks"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tokens of the requests to the test API servers. The valid token
+// authenticates the user, and the invalid token fails authentication.
+const (
+	ValidToken   = "valid"
+	InvalidToken = "invalid"
+)
+
+// NewAPIServer returns the test server of the handler mounted on the path,
+// and the authorization mock of its requests. The handler is made with the
+// authentication and authorization of the test server.
+func NewAPIServer(path string, handler func(smqauthn.Authentication, smqauthz.Authorization) http.Handler) (*httptest.Server, *authzmocks.Authorization) {
+	authn := new(authnmocks.Authentication)
+	authn.On("Authenticate", mock.Anything, ValidToken).Return(smqauthn.Session{UserID: "userID", Role: smqauthn.UserRole}, nil)
+	authn.On("Authenticate", mock.Anything, InvalidToken).Return(smqauthn.Session{}, svcerr.ErrAuthentication)
+	authz := new(authzmocks.Authorization)
+
+	mux := chi.NewRouter()
+	mux.Mount(path, handler(authn, authz))
+
+	return httptest.NewServer(mux), authz
+}
+
+// Request sends the request with the token and the body of the content type
+// to the test server, and returns the status code and the body of the
+// response.
+func Request(t *testing.T, ts *httptest.Server, method, path, token, contentType, body string) (int, []byte) {
+	var reader io.Reader = http.NoBody
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	if token != "" {
+		req.Header.Set("Authorization", apiutil.BearerPrefix+token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	res, err := ts.Client().Do(req)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	return res.StatusCode, data
+}
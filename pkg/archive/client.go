@@ -0,0 +1,163 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// codeNoSuchKey is the code of the S3 error of reads of missing objects.
+const codeNoSuchKey = "NoSuchKey"
+
+var (
+	// ErrNotFound indicates a missing object.
+	ErrNotFound = errors.New("object not found")
+
+	errBucket = errors.New("failed to create bucket")
+)
+
+// Config defines the options that are used when connecting to an
+// S3-compatible object storage, such as MinIO.
+type Config struct {
+	Endpoint   string `env:"ENDPOINT"    envDefault:"localhost:9000"`
+	AccessKey  string `env:"ACCESS_KEY"  envDefault:""`
+	SecretKey  string `env:"SECRET_KEY"  envDefault:""`
+	Region     string `env:"REGION"      envDefault:"us-east-1"`
+	Bucket     string `env:"BUCKET"      envDefault:"messages"`
+	Prefix     string `env:"PREFIX"      envDefault:""`
+	Secure     bool   `env:"SECURE"      envDefault:"false"`
+	SkipVerify bool   `env:"SKIP_VERIFY" envDefault:"false"`
+	PathStyle  bool   `env:"PATH_STYLE"  envDefault:"false"`
+}
+
+// Bucket stores objects under the key prefix of a bucket. Keys of the
+// objects are relative to the prefix.
+type Bucket struct {
+	client *minio.Client
+	name   string
+	prefix string
+}
+
+// Setup connects to the object storage and creates the bucket if it
+// doesn't exist. A non-nil error is returned to indicate failure.
+func Setup(ctx context.Context, cfg Config) (*Bucket, error) {
+	b, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := b.client.BucketExists(ctx, b.name)
+	if err != nil {
+		return nil, errors.Wrap(errBucket, err)
+	}
+	if exists {
+		return b, nil
+	}
+	if err := b.client.MakeBucket(ctx, b.name, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+		// The bucket may have been created by another instance meanwhile.
+		if exists, existsErr := b.client.BucketExists(ctx, b.name); existsErr == nil && exists {
+			return b, nil
+		}
+		return nil, errors.Wrap(errBucket, err)
+	}
+
+	return b, nil
+}
+
+// Connect creates the client of the bucket. Connections are established
+// lazily, when the first request is sent.
+func Connect(cfg Config) (*Bucket, error) {
+	transport, err := minio.DefaultTransport(cfg.Secure)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Secure && cfg.SkipVerify {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	lookup := minio.BucketLookupAuto
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.Secure,
+		Transport:    transport,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bucket{
+		client: client,
+		name:   cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+// Put stores the object with the key.
+func (b *Bucket) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	opts := minio.PutObjectOptions{ContentType: contentType}
+	_, err := b.client.PutObject(ctx, b.name, b.key(key), bytes.NewReader(data), int64(len(data)), opts)
+
+	return err
+}
+
+// Get returns the object with the key, or ErrNotFound if it doesn't exist.
+func (b *Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	obj, err := b.client.GetObject(ctx, b.name, b.key(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, objectError(err)
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, objectError(err)
+	}
+
+	return data, nil
+}
+
+// List returns the keys of the objects with the key prefix, in ascending
+// order.
+func (b *Bucket) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	root := b.key("")
+	for obj := range b.client.ListObjects(ctx, b.name, minio.ListObjectsOptions{Prefix: b.key(prefix), Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		keys = append(keys, strings.TrimPrefix(obj.Key, root))
+	}
+
+	return keys, nil
+}
+
+// key returns the key of the object in the bucket.
+func (b *Bucket) key(key string) string {
+	if b.prefix == "" {
+		return key
+	}
+
+	return b.prefix + "/" + key
+}
+
+func objectError(err error) error {
+	if minio.ToErrorResponse(err).Code == codeNoSuchKey {
+		return ErrNotFound
+	}
+
+	return err
+}
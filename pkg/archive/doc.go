@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive contains the S3 client, the object layout and the manifest
+// shared by the archive writer and reader.
+package archive
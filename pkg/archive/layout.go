@@ -0,0 +1,185 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/absmach/supermq/pkg/errors"
+)
+
+// Objects are laid out in Hive-style partitions, so the archived files can
+// also be queried by engines that read partitioned object storage:
+//
+//	data/<format>/domain=<domain>/channel=<channel>/date=<date>/<id>.<ext>
+//	manifest/<format>/domain=<domain>/channel=<channel>/date=<date>/<id>.json
+const (
+	dataDir     = "data"
+	manifestDir = "manifest"
+	dateLayout  = time.DateOnly
+)
+
+// SenMLFormat is the format SenML messages are archived as. JSON messages
+// are archived as their lowercase format.
+const SenMLFormat = "messages"
+
+// NoDomain is the domain of the partitions of messages with unknown domain.
+const NoDomain = "_"
+
+var (
+	// ErrInvalidKey indicates an object key outside of the archive layout.
+	ErrInvalidKey = errors.New("invalid archive key")
+
+	errEntry = errors.New("failed to save manifest entry")
+)
+
+// Formats name the partitions of their messages, so the format must be a
+// lowercase identifier.
+var formatRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// ValidFormat reports whether the messages of the JSON format can be
+// archived.
+func ValidFormat(format string) bool {
+	return formatRegexp.MatchString(format) && format != SenMLFormat
+}
+
+// Partition holds the archived messages of a format, domain and channel
+// created on the same UTC date.
+type Partition struct {
+	Format  string
+	Domain  string
+	Channel string
+	Date    string
+}
+
+// NewPartition returns the partition of the message with the nanosecond
+// time.
+func NewPartition(format, domain, channel string, t float64) Partition {
+	return Partition{
+		Format:  format,
+		Domain:  domain,
+		Channel: channel,
+		Date:    time.Unix(0, int64(t)).UTC().Format(dateLayout),
+	}
+}
+
+// DataKey returns the key of the data file of the partition.
+func (p Partition) DataKey(id, fileFormat string) string {
+	return fmt.Sprintf("%s/%s/%s.%s", dataDir, p.path(), id, extensions[fileFormat])
+}
+
+// EntryKey returns the key of the manifest entry of the data file of the
+// partition.
+func (p Partition) EntryKey(id string) string {
+	return fmt.Sprintf("%s/%s/%s.json", manifestDir, p.path(), id)
+}
+
+func (p Partition) path() string {
+	return fmt.Sprintf("%sdate=%s", partitionPrefix(p.Format, p.Domain, p.Channel), p.Date)
+}
+
+// EntriesPrefix returns the key prefix of the manifest entries of the
+// format, domain and channel.
+func EntriesPrefix(format, domain, channel string) string {
+	return fmt.Sprintf("%s/%s", manifestDir, partitionPrefix(format, domain, channel))
+}
+
+// FormatEntriesPrefix returns the key prefix of the manifest entries of all
+// the domains and channels of the format.
+func FormatEntriesPrefix(format string) string {
+	return fmt.Sprintf("%s/%s/", manifestDir, url.PathEscape(format))
+}
+
+func partitionPrefix(format, domain, channel string) string {
+	if domain == "" {
+		domain = NoDomain
+	}
+
+	return fmt.Sprintf("%s/domain=%s/channel=%s/", url.PathEscape(format), url.PathEscape(domain), url.PathEscape(channel))
+}
+
+// ParseEntryKey returns the partition of the manifest entry key.
+func ParseEntryKey(key string) (Partition, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 6 || parts[0] != manifestDir {
+		return Partition{}, ErrInvalidKey
+	}
+
+	values := make([]string, 0, 3)
+	for i, name := range []string{"domain", "channel", "date"} {
+		value, ok := strings.CutPrefix(parts[i+2], name+"=")
+		if !ok {
+			return Partition{}, ErrInvalidKey
+		}
+		value, err := url.PathUnescape(value)
+		if err != nil {
+			return Partition{}, errors.Wrap(ErrInvalidKey, err)
+		}
+		values = append(values, value)
+	}
+	format, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return Partition{}, errors.Wrap(ErrInvalidKey, err)
+	}
+	if values[0] == NoDomain {
+		values[0] = ""
+	}
+
+	return Partition{Format: format, Domain: values[0], Channel: values[1], Date: values[2]}, nil
+}
+
+// Entry is the manifest entry of an archived data file. Entries are stored
+// once their data files are stored, so readers read complete files only.
+// Entries hold the time range of their files, so readers skip the files
+// that hold no message of a read.
+type Entry struct {
+	ID         string    `json:"id"`
+	Key        string    `json:"key"`
+	Format     string    `json:"format"`
+	Domain     string    `json:"domain,omitempty"`
+	Channel    string    `json:"channel"`
+	Date       string    `json:"date"`
+	FileFormat string    `json:"file_format"`
+	Records    int       `json:"records"`
+	Size       int       `json:"size"`
+	MinTime    float64   `json:"min_time"`
+	MaxTime    float64   `json:"max_time"`
+	Created    time.Time `json:"created"`
+}
+
+// Partition returns the partition of the data file of the entry.
+func (e Entry) Partition() Partition {
+	return Partition{Format: e.Format, Domain: e.Domain, Channel: e.Channel, Date: e.Date}
+}
+
+// PutEntry stores the manifest entry.
+func (b *Bucket) PutEntry(ctx context.Context, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(errEntry, err)
+	}
+
+	return b.Put(ctx, e.Partition().EntryKey(e.ID), data, "application/json")
+}
+
+// GetEntry returns the manifest entry with the key.
+func (b *Bucket) GetEntry(ctx context.Context, key string) (Entry, error) {
+	data, err := b.Get(ctx, key)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, err
+	}
+
+	return e, nil
+}
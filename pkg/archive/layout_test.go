@@ -0,0 +1,109 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// time of 2025-06-30 23:59:59.5 UTC in nanoseconds.
+const msgTime = 1751327999500000000
+
+func TestPartitionKeys(t *testing.T) {
+	cases := []struct {
+		desc      string
+		partition archive.Partition
+		dataKey   string
+		entryKey  string
+	}{
+		{
+			desc:      "keys of SenML partition",
+			partition: archive.NewPartition(archive.SenMLFormat, "d1", "ch1", msgTime),
+			dataKey:   "data/messages/domain=d1/channel=ch1/date=2025-06-30/id.parquet",
+			entryKey:  "manifest/messages/domain=d1/channel=ch1/date=2025-06-30/id.json",
+		},
+		{
+			desc:      "keys of partition without domain",
+			partition: archive.NewPartition("readings", "", "ch1", msgTime),
+			dataKey:   "data/readings/domain=_/channel=ch1/date=2025-06-30/id.parquet",
+			entryKey:  "manifest/readings/domain=_/channel=ch1/date=2025-06-30/id.json",
+		},
+		{
+			desc:      "keys of partition with escaped channel",
+			partition: archive.NewPartition("readings", "d1", "a/b", msgTime),
+			dataKey:   "data/readings/domain=d1/channel=a%2Fb/date=2025-06-30/id.parquet",
+			entryKey:  "manifest/readings/domain=d1/channel=a%2Fb/date=2025-06-30/id.json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dataKey := tc.partition.DataKey("id", archive.FileParquet)
+			assert.Equal(t, tc.dataKey, dataKey, fmt.Sprintf("%s: expected data key %s, got %s", tc.desc, tc.dataKey, dataKey))
+			entryKey := tc.partition.EntryKey("id")
+			assert.Equal(t, tc.entryKey, entryKey, fmt.Sprintf("%s: expected entry key %s, got %s", tc.desc, tc.entryKey, entryKey))
+
+			p, err := archive.ParseEntryKey(entryKey)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %s", tc.desc, err))
+			assert.Equal(t, tc.partition, p, fmt.Sprintf("%s: expected partition %v, got %v", tc.desc, tc.partition, p))
+		})
+	}
+}
+
+func TestParseEntryKey(t *testing.T) {
+	cases := []struct {
+		desc string
+		key  string
+		err  error
+	}{
+		{
+			desc: "parse entry key",
+			key:  "manifest/messages/domain=d1/channel=ch1/date=2025-06-30/id.json",
+		},
+		{
+			desc: "parse data key",
+			key:  "data/messages/domain=d1/channel=ch1/date=2025-06-30/id.parquet",
+			err:  archive.ErrInvalidKey,
+		},
+		{
+			desc: "parse entry key without date",
+			key:  "manifest/messages/domain=d1/channel=ch1/id.json",
+			err:  archive.ErrInvalidKey,
+		},
+		{
+			desc: "parse entry key with invalid escape",
+			key:  "manifest/messages/domain=d1/channel=%zz/date=2025-06-30/id.json",
+			err:  archive.ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := archive.ParseEntryKey(tc.key)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		})
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	cases := map[string]bool{
+		"readings":    true,
+		"_raw_v2":     true,
+		"messages":    false,
+		"Readings":    false,
+		"2readings":   false,
+		"sensor.data": false,
+		"../readings": false,
+		"":            false,
+	}
+
+	for format, valid := range cases {
+		assert.Equal(t, valid, archive.ValidFormat(format), fmt.Sprintf("expected format %q valid %t", format, valid))
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Formats of the archived data files.
+const (
+	// FileNDJSON stores records as gzip compressed newline delimited JSON.
+	FileNDJSON = "ndjson"
+	// FileParquet stores records as Parquet columns compressed with zstd.
+	FileParquet = "parquet"
+)
+
+var extensions = map[string]string{
+	FileNDJSON:  "ndjson.gz",
+	FileParquet: "parquet",
+}
+
+var contentTypes = map[string]string{
+	FileNDJSON:  "application/gzip",
+	FileParquet: "application/vnd.apache.parquet",
+}
+
+// ErrInvalidFileFormat indicates an unknown format of data files.
+var ErrInvalidFileFormat = errors.New("invalid archive file format")
+
+// ContentType returns the content type of the data files of the format.
+func ContentType(fileFormat string) string {
+	return contentTypes[fileFormat]
+}
+
+// ValidFileFormat reports whether the data files can be stored in the format.
+func ValidFileFormat(fileFormat string) bool {
+	_, ok := extensions[fileFormat]
+	return ok
+}
+
+// SenMLRecord is an archived SenML message. The domain of the message is
+// kept by its partition.
+type SenMLRecord struct {
+	ID          string   `json:"id"                     parquet:"id"`
+	Channel     string   `json:"channel"                parquet:"channel"`
+	Subtopic    string   `json:"subtopic,omitempty"     parquet:"subtopic"`
+	Publisher   string   `json:"publisher"              parquet:"publisher"`
+	Protocol    string   `json:"protocol"               parquet:"protocol"`
+	Name        string   `json:"name,omitempty"         parquet:"name"`
+	Unit        string   `json:"unit,omitempty"         parquet:"unit"`
+	Time        float64  `json:"time"                   parquet:"time"`
+	UpdateTime  float64  `json:"update_time,omitempty"  parquet:"update_time"`
+	Value       *float64 `json:"value,omitempty"        parquet:"value,optional"`
+	StringValue *string  `json:"string_value,omitempty" parquet:"string_value,optional"`
+	BoolValue   *bool    `json:"bool_value,omitempty"   parquet:"bool_value,optional"`
+	DataValue   *string  `json:"data_value,omitempty"   parquet:"data_value,optional"`
+	Sum         *float64 `json:"sum,omitempty"          parquet:"sum,optional"`
+}
+
+// NewSenMLRecord returns the record of the SenML message.
+func NewSenMLRecord(id string, msg senml.Message) SenMLRecord {
+	return SenMLRecord{
+		ID:          id,
+		Channel:     msg.Channel,
+		Subtopic:    msg.Subtopic,
+		Publisher:   msg.Publisher,
+		Protocol:    msg.Protocol,
+		Name:        msg.Name,
+		Unit:        msg.Unit,
+		Time:        msg.Time,
+		UpdateTime:  msg.UpdateTime,
+		Value:       msg.Value,
+		StringValue: msg.StringValue,
+		BoolValue:   msg.BoolValue,
+		DataValue:   msg.DataValue,
+		Sum:         msg.Sum,
+	}
+}
+
+// Message returns the SenML message of the record.
+func (r SenMLRecord) Message() senml.Message {
+	return senml.Message{
+		Channel:     r.Channel,
+		Subtopic:    r.Subtopic,
+		Publisher:   r.Publisher,
+		Protocol:    r.Protocol,
+		Name:        r.Name,
+		Unit:        r.Unit,
+		Time:        r.Time,
+		UpdateTime:  r.UpdateTime,
+		Value:       r.Value,
+		StringValue: r.StringValue,
+		BoolValue:   r.BoolValue,
+		DataValue:   r.DataValue,
+		Sum:         r.Sum,
+	}
+}
+
+// JSONRecord is an archived JSON message. The payload is kept as JSON
+// text, since payloads don't share a schema.
+type JSONRecord struct {
+	ID        string          `json:"id"        parquet:"id"`
+	Channel   string          `json:"channel"   parquet:"channel"`
+	Created   int64           `json:"created"   parquet:"created"`
+	Subtopic  string          `json:"subtopic"  parquet:"subtopic"`
+	Publisher string          `json:"publisher" parquet:"publisher"`
+	Protocol  string          `json:"protocol"  parquet:"protocol"`
+	Payload   json.RawMessage `json:"payload"   parquet:"payload,json"`
+}
+
+// NewJSONRecord returns the record of the JSON message.
+func NewJSONRecord(id string, msg smqjson.Message) (JSONRecord, error) {
+	payload := json.RawMessage("{}")
+	if msg.Payload != nil {
+		data, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return JSONRecord{}, err
+		}
+		payload = data
+	}
+
+	return JSONRecord{
+		ID:        id,
+		Channel:   msg.Channel,
+		Created:   msg.Created,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Payload:   payload,
+	}, nil
+}
+
+// Encode returns the data file of the records in the file format.
+func Encode[T any](fileFormat string, records []T) ([]byte, error) {
+	var buf bytes.Buffer
+	switch fileFormat {
+	case FileNDJSON:
+		zw := gzip.NewWriter(&buf)
+		enc := json.NewEncoder(zw)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return nil, err
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	case FileParquet:
+		w := parquet.NewGenericWriter[T](&buf, parquet.Compression(&parquet.Zstd))
+		if _, err := w.Write(records); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidFileFormat
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode returns the records of the data file in the file format.
+func Decode[T any](fileFormat string, data []byte) ([]T, error) {
+	switch fileFormat {
+	case FileNDJSON:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		var records []T
+		dec := json.NewDecoder(zr)
+		for {
+			var r T
+			if err := dec.Decode(&r); err != nil {
+				if err == io.EOF {
+					return records, nil
+				}
+				return nil, err
+			}
+			records = append(records, r)
+		}
+	case FileParquet:
+		r := parquet.NewGenericReader[T](bytes.NewReader(data))
+		defer r.Close()
+
+		records := make([]T, r.NumRows())
+		for n := 0; n < len(records); {
+			m, err := r.Read(records[n:])
+			n += m
+			if err == io.EOF {
+				return records[:n], nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return records, nil
+	default:
+		return nil, ErrInvalidFileFormat
+	}
+}
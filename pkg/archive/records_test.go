@@ -0,0 +1,79 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/supermq/pkg/errors"
+	smqjson "github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+var fileFormats = []string{archive.FileNDJSON, archive.FileParquet}
+
+func TestSenMLRecords(t *testing.T) {
+	v, sum := 12.5, 3.0
+	s, b, d := "on", true, "base64"
+	msgs := []senml.Message{
+		{Channel: "ch1", Publisher: "p1", Protocol: "mqtt", Name: "temp", Unit: "C", Time: msgTime, Value: &v, Sum: &sum},
+		{Channel: "ch1", Subtopic: "sub", Publisher: "p1", Protocol: "http", Name: "state", Time: msgTime + 1, UpdateTime: 10, StringValue: &s},
+		{Channel: "ch1", Publisher: "p2", Protocol: "coap", Name: "ok", Time: msgTime + 2, BoolValue: &b},
+		{Channel: "ch1", Publisher: "p2", Protocol: "coap", Name: "raw", Time: msgTime + 3, DataValue: &d},
+	}
+	records := make([]archive.SenMLRecord, 0, len(msgs))
+	for i, msg := range msgs {
+		records = append(records, archive.NewSenMLRecord(fmt.Sprint(i), msg))
+	}
+
+	for _, fileFormat := range fileFormats {
+		data, err := archive.Encode(fileFormat, records)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %s", fileFormat, err))
+
+		decoded, err := archive.Decode[archive.SenMLRecord](fileFormat, data)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %s", fileFormat, err))
+		assert.Equal(t, records, decoded, fmt.Sprintf("%s: expected records %v, got %v", fileFormat, records, decoded))
+		for i, r := range decoded {
+			assert.Equal(t, msgs[i], r.Message(), fmt.Sprintf("%s: expected message %v, got %v", fileFormat, msgs[i], r.Message()))
+		}
+	}
+}
+
+func TestJSONRecords(t *testing.T) {
+	msgs := []smqjson.Message{
+		{Channel: "ch1", Publisher: "p1", Protocol: "mqtt", Created: msgTime, Payload: map[string]interface{}{"temp": 12.5, "engine": map[string]interface{}{"on": true}}},
+		{Channel: "ch1", Subtopic: "sub", Publisher: "p2", Protocol: "http", Created: msgTime + 1},
+	}
+	records := make([]archive.JSONRecord, 0, len(msgs))
+	for i, msg := range msgs {
+		r, err := archive.NewJSONRecord(fmt.Sprint(i), msg)
+		assert.Nil(t, err, fmt.Sprintf("expected no error, got %s", err))
+		records = append(records, r)
+	}
+	assert.Equal(t, json.RawMessage("{}"), records[1].Payload, "expected empty payload of message without payload")
+
+	for _, fileFormat := range fileFormats {
+		data, err := archive.Encode(fileFormat, records)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %s", fileFormat, err))
+
+		decoded, err := archive.Decode[archive.JSONRecord](fileFormat, data)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error, got %s", fileFormat, err))
+		for i, r := range decoded {
+			assert.JSONEq(t, string(records[i].Payload), string(r.Payload), fmt.Sprintf("%s: expected payload %s, got %s", fileFormat, records[i].Payload, r.Payload))
+			r.Payload = records[i].Payload
+			assert.Equal(t, records[i], r, fmt.Sprintf("%s: expected record %v, got %v", fileFormat, records[i], r))
+		}
+	}
+}
+
+func TestInvalidFileFormat(t *testing.T) {
+	_, err := archive.Encode("csv", []archive.SenMLRecord{{}})
+	assert.True(t, errors.Contains(err, archive.ErrInvalidFileFormat), fmt.Sprintf("expected error %s, got %s", archive.ErrInvalidFileFormat, err))
+	_, err = archive.Decode[archive.SenMLRecord]("csv", nil)
+	assert.True(t, errors.Contains(err, archive.ErrInvalidFileFormat), fmt.Sprintf("expected error %s, got %s", archive.ErrInvalidFileFormat, err))
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+// DriverName is the driver name of the ClickHouse connections. Queries of
+// the connections use ? placeholders, which sqlx binds named parameters to.
+const DriverName = "clickhouse"
+
+var errSchema = errors.New("failed to apply schema")
+
+// Config defines the options that are used when connecting to a ClickHouse
+// instance.
+type Config struct {
+	Host         string        `env:"HOST"           envDefault:"localhost"`
+	Port         string        `env:"PORT"           envDefault:"9000"`
+	User         string        `env:"USER"           envDefault:"supermq"`
+	Pass         string        `env:"PASS"           envDefault:"supermq"`
+	Name         string        `env:"NAME"           envDefault:""`
+	Secure       bool          `env:"SECURE"         envDefault:"false"`
+	SkipVerify   bool          `env:"SKIP_VERIFY"    envDefault:"false"`
+	DialTimeout  time.Duration `env:"DIAL_TIMEOUT"   envDefault:"5s"`
+	MaxOpenConns int           `env:"MAX_OPEN_CONNS" envDefault:"10"`
+	MaxIdleConns int           `env:"MAX_IDLE_CONNS" envDefault:"5"`
+}
+
+// Setup creates a connection to the ClickHouse instance and applies the
+// schema statements, which must be idempotent, such as CREATE TABLE IF NOT
+// EXISTS. A non-nil error is returned to indicate failure.
+func Setup(cfg Config, schema []string) (*sqlx.DB, error) {
+	db := Connect(cfg)
+	for _, q := range schema {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			db.Close()
+			return nil, errors.Wrap(errSchema, err)
+		}
+	}
+
+	return db, nil
+}
+
+// Connect creates a connection to the ClickHouse instance. Connections are
+// established lazily, when the first query is executed.
+func Connect(cfg Config) *sqlx.DB {
+	opts := &clickhouse.Options{
+		Addr: []string{net.JoinHostPort(cfg.Host, cfg.Port)},
+		Auth: clickhouse.Auth{
+			Database: cfg.Name,
+			Username: cfg.User,
+			Password: cfg.Pass,
+		},
+		DialTimeout:  cfg.DialTimeout,
+		MaxOpenConns: cfg.MaxOpenConns,
+		MaxIdleConns: cfg.MaxIdleConns,
+		Compression:  &clickhouse.Compression{Method: clickhouse.CompressionLZ4},
+	}
+	if cfg.Secure {
+		opts.TLS = &tls.Config{InsecureSkipVerify: cfg.SkipVerify}
+	}
+
+	return sqlx.NewDb(clickhouse.OpenDB(opts), DriverName)
+}
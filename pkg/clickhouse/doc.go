@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clickhouse contains the ClickHouse client setup shared by the
+// ClickHouse writer and reader.
+package clickhouse
@@ -29,3 +29,32 @@ func MakeMetrics(namespace, subsystem string) (*kitprometheus.Counter, *kitprome
 
 	return counter, latency
 }
+
+// MakeUsageMetrics returns an instance of Prometheus implementations for
+// ingestion usage metrics. It returns the counters of the consumed messages
+// and bytes, labeled by domain, and of the messages that
+// exceeded the quota of their domain, labeled by domain and action.
+//
+//	messages, bytes, limited := metrics.MakeUsageMetrics("timescale", "message_writer")
+func MakeUsageMetrics(namespace, subsystem string) (*kitprometheus.Counter, *kitprometheus.Counter, *kitprometheus.Counter) {
+	messages := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "messages_total",
+		Help:      "Number of messages consumed.",
+	}, []string{"domain"})
+	bytes := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "bytes_total",
+		Help:      "Number of payload bytes consumed.",
+	}, []string{"domain"})
+	limited := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "limited_messages_total",
+		Help:      "Number of messages that exceeded the quota of their domain.",
+	}, []string{"domain", "action"})
+
+	return messages, bytes, limited
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// CSVWriter writes CSV reports table by table and row by row, so large
+// reports can be streamed without being kept in memory.
+type CSVWriter struct {
+	w      *csv.Writer
+	title  string
+	tables int
+}
+
+// NewCSVWriter returns a writer of the report with the given title.
+func NewCSVWriter(w io.Writer, title string) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w), title: title}
+}
+
+// Begin starts a new table by writing its details and column headers.
+// Table rows are ignored; write them using Write.
+func (cw *CSVWriter) Begin(t Table) error {
+	header := [][]string{{cw.title}, {""}}
+	if cw.tables > 0 {
+		header = [][]string{{""}, {"=== NEW REPORT ==="}, {""}}
+	}
+	cw.tables++
+
+	if len(t.Details) > 0 {
+		header = append(header, []string{"Report Information:"})
+		for _, d := range t.Details {
+			header = append(header, []string{d.Name, d.Value})
+		}
+		header = append(header, []string{""})
+	}
+	header = append(header, columnNames(t.Columns))
+
+	return cw.w.WriteAll(header)
+}
+
+// Write writes a single table row.
+func (cw *CSVWriter) Write(row []string) error {
+	return cw.w.Write(row)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
+
+// CSV renders the report with the given title as a CSV file.
+func CSV(title string, tables []Table) ([]byte, error) {
+	var buf bytes.Buffer
+	cw := NewCSVWriter(&buf, title)
+
+	for _, t := range tables {
+		if err := cw.Begin(t); err != nil {
+			return nil, err
+		}
+		for _, row := range t.Rows {
+			if err := cw.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cw.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
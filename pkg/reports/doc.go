@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reports renders tabular reports as PDF and CSV files so that all
+// Magistrala services produce reports with the same look.
+package reports
@@ -0,0 +1,255 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package reports
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+)
+
+var (
+	primaryColor   = color.Color{Red: 41, Green: 128, Blue: 185}  // Blue
+	secondaryColor = color.Color{Red: 26, Green: 82, Blue: 118}   // Darker blue
+	subtleColor    = color.Color{Red: 189, Green: 195, Blue: 199} // Light gray
+	tableHeaderBg  = color.Color{Red: 236, Green: 240, Blue: 241} // Very light gray
+	alternateRow   = color.Color{Red: 245, Green: 247, Blue: 249} // Even lighter gray
+	textPrimary    = color.Color{Red: 44, Green: 62, Blue: 80}    // Dark blue-gray
+	textSecondary  = color.Color{Red: 127, Green: 140, Blue: 141} // Medium gray
+	white          = color.NewWhite()
+)
+
+// PDF renders the report with the given title as a PDF file. Each table
+// starts on a new page.
+func PDF(title string, tables []Table) ([]byte, error) {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+
+	registerHeader(m, title)
+	registerFooter(m)
+
+	for i, t := range tables {
+		if i > 0 {
+			m.AddPage()
+		}
+		addTable(m, t)
+	}
+
+	buf, err := m.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func registerHeader(m pdf.Maroto, title string) {
+	m.RegisterHeader(func() {
+		m.SetBackgroundColor(primaryColor)
+		m.Row(2, func() { m.Col(12, func() {}) })
+		m.SetBackgroundColor(white)
+
+		m.Row(20, func() {
+			m.Col(2, func() {})
+
+			m.Col(8, func() {
+				m.Text(title, props.Text{
+					Size:  20,
+					Style: consts.Bold,
+					Color: primaryColor,
+					Align: consts.Center,
+					Top:   6,
+				})
+			})
+
+			m.Col(2, func() {
+				m.Text(time.Now().Format("02 Jan 2006"), props.Text{
+					Size:  10,
+					Style: consts.Italic,
+					Align: consts.Right,
+					Color: textSecondary,
+					Top:   8,
+				})
+			})
+		})
+
+		m.SetBackgroundColor(subtleColor)
+		m.Row(0.5, func() { m.Col(12, func() {}) })
+		m.SetBackgroundColor(white)
+		m.Row(0.25, func() {})
+		m.SetBackgroundColor(subtleColor)
+		m.Row(0.25, func() { m.Col(12, func() {}) })
+		m.SetBackgroundColor(white)
+
+		m.Row(5, func() {})
+	})
+}
+
+func registerFooter(m pdf.Maroto) {
+	m.RegisterFooter(func() {
+		currentPage := m.GetCurrentPage()
+
+		m.Row(5, func() {})
+		m.SetBackgroundColor(subtleColor)
+		m.Row(0.25, func() { m.Col(12, func() {}) })
+		m.SetBackgroundColor(white)
+		m.Row(0.25, func() {})
+		m.SetBackgroundColor(subtleColor)
+		m.Row(0.5, func() { m.Col(12, func() {}) })
+		m.SetBackgroundColor(white)
+
+		m.Row(10, func() {
+			m.Col(4, func() {
+				m.Text("Generated: "+time.Now().Format("15:04:05"), props.Text{
+					Size:  8,
+					Style: consts.Italic,
+					Align: consts.Left,
+					Color: textSecondary,
+					Top:   3,
+				})
+			})
+
+			m.Col(4, func() {
+				m.Text(fmt.Sprintf("Page %d", currentPage+1), props.Text{
+					Size:  9,
+					Style: consts.Bold,
+					Align: consts.Center,
+					Color: textPrimary,
+					Top:   3,
+				})
+			})
+		})
+	})
+}
+
+func addTable(m pdf.Maroto, t Table) {
+	m.Row(0.5, func() {
+		m.Col(1, func() {})
+	})
+	m.SetBackgroundColor(white)
+
+	m.Row(10, func() {
+		m.Col(12, func() {
+			m.Text(t.Title, props.Text{
+				Size:  16,
+				Style: consts.Bold,
+				Color: secondaryColor,
+				Top:   2,
+			})
+		})
+	})
+
+	m.SetBackgroundColor(alternateRow)
+	m.Row(0.5, func() { m.Col(12, func() {}) })
+
+	for _, d := range t.Details {
+		m.Row(8, func() {
+			m.Col(2, func() {
+				m.Text(d.Name+":", props.Text{
+					Size:  11,
+					Style: consts.Bold,
+					Align: consts.Left,
+					Color: textPrimary,
+					Top:   1,
+				})
+			})
+
+			m.Col(10, func() {
+				m.Text(d.Value, props.Text{
+					Size:  11,
+					Style: consts.Italic,
+					Color: textPrimary,
+					Top:   1,
+				})
+			})
+		})
+	}
+
+	m.SetBackgroundColor(alternateRow)
+	m.Row(0.5, func() { m.Col(12, func() {}) })
+	m.SetBackgroundColor(white)
+
+	m.Row(10, func() {
+		m.Col(12, func() {
+			m.Text(fmt.Sprintf("Total Records: %d", len(t.Rows)), props.Text{
+				Size:  10,
+				Style: consts.Italic,
+				Align: consts.Right,
+				Color: textSecondary,
+				Top:   2,
+			})
+		})
+	})
+
+	m.SetBackgroundColor(primaryColor)
+	m.Row(1, func() { m.Col(12, func() {}) })
+	m.SetBackgroundColor(tableHeaderBg)
+	m.Row(10, func() {
+		for _, c := range t.Columns {
+			m.Col(c.Width, func() {
+				m.Text(c.Name, props.Text{
+					Size:  11,
+					Style: consts.Bold,
+					Align: consts.Center,
+					Top:   2,
+					Color: secondaryColor,
+				})
+			})
+		}
+	})
+	m.SetBackgroundColor(subtleColor)
+	m.Row(0.5, func() { m.Col(12, func() {}) })
+	m.SetBackgroundColor(white)
+
+	useAlternateColor := false
+	for _, row := range t.Rows {
+		if useAlternateColor {
+			m.SetBackgroundColor(alternateRow)
+		}
+
+		m.Row(9, func() {
+			for i, c := range t.Columns {
+				var value string
+				if i < len(row) {
+					value = row[i]
+				}
+				m.Col(c.Width, func() {
+					m.Text(value, cellProps(c.Style))
+				})
+			}
+		})
+
+		if !useAlternateColor {
+			m.Row(0.2, func() {
+				m.Col(12, func() {})
+			})
+		}
+
+		useAlternateColor = !useAlternateColor
+		m.SetBackgroundColor(white)
+	}
+}
+
+func cellProps(s Style) props.Text {
+	p := props.Text{
+		Size:  10,
+		Style: consts.Normal,
+		Align: consts.Center,
+		Top:   2,
+		Color: textPrimary,
+	}
+	switch s {
+	case Muted:
+		p.Style = consts.Italic
+		p.Color = textSecondary
+	case Accent:
+		p.Color = secondaryColor
+	}
+
+	return p
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package reports
+
+// Style is the style a column's values are rendered with in PDF reports.
+type Style uint8
+
+const (
+	// Plain renders values in the primary text color.
+	Plain Style = iota
+	// Muted renders values in italic secondary text color.
+	Muted
+	// Accent renders values in the accent color.
+	Accent
+)
+
+// Detail is a labelled value describing a table.
+type Detail struct {
+	Name  string
+	Value string
+}
+
+// Column describes a table column. Width is the share of the PDF page width
+// out of 12 and is ignored for CSV reports.
+type Column struct {
+	Name  string
+	Width uint
+	Style Style
+}
+
+// Table is a section of a report.
+type Table struct {
+	Title   string
+	Details []Detail
+	Columns []Column
+	Rows    [][]string
+}
+
+func columnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+
+	return names
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package reports_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala/pkg/reports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var columns = []reports.Column{
+	{Name: "Time", Width: 6},
+	{Name: "Value", Width: 6, Style: reports.Muted},
+}
+
+func TestCSV(t *testing.T) {
+	cases := []struct {
+		desc   string
+		tables []reports.Table
+		csv    string
+	}{
+		{
+			desc: "single table",
+			tables: []reports.Table{
+				{
+					Title:   "Metrics",
+					Details: []reports.Detail{{Name: "Name", Value: "temperature"}},
+					Columns: columns,
+					Rows:    [][]string{{"2025-01-01 00:00:00", "21.50"}},
+				},
+			},
+			csv: "Report\n\nReport Information:\nName,temperature\n\nTime,Value\n2025-01-01 00:00:00,21.50\n",
+		},
+		{
+			desc: "multiple tables without details",
+			tables: []reports.Table{
+				{Columns: columns, Rows: [][]string{{"2025-01-01 00:00:00", "1"}}},
+				{Columns: columns, Rows: [][]string{{"2025-01-02 00:00:00", "2"}}},
+			},
+			csv: "Report\n\nTime,Value\n2025-01-01 00:00:00,1\n\n=== NEW REPORT ===\n\nTime,Value\n2025-01-02 00:00:00,2\n",
+		},
+		{
+			desc:   "no tables",
+			tables: nil,
+			csv:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := reports.CSV("Report", tc.tables)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+			assert.Equal(t, tc.csv, string(data), fmt.Sprintf("%s: expected %q got %q\n", tc.desc, tc.csv, string(data)))
+		})
+	}
+}
+
+func TestPDF(t *testing.T) {
+	data, err := reports.PDF("Report", []reports.Table{
+		{
+			Title:   "Metrics",
+			Details: []reports.Detail{{Name: "Name", Value: "temperature"}},
+			Columns: columns,
+			Rows:    [][]string{{"2025-01-01 00:00:00", "21.50"}, {"2025-01-01 00:01:00"}},
+		},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")), "expected PDF document")
+}
@@ -10,18 +10,19 @@ import (
 	"testing"
 
 	sdk "github.com/absmach/magistrala/pkg/sdk"
+	"github.com/absmach/magistrala/readers"
 	readersapi "github.com/absmach/magistrala/readers/api/http"
+	readersmocks "github.com/absmach/magistrala/readers/mocks"
 	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
 	apiutil "github.com/absmach/supermq/api/http/util"
 	chmocks "github.com/absmach/supermq/channels/mocks"
 	climocks "github.com/absmach/supermq/clients/mocks"
 	smqauthn "github.com/absmach/supermq/pkg/authn"
 	authnmocks "github.com/absmach/supermq/pkg/authn/mocks"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
 	"github.com/absmach/supermq/pkg/errors"
 	svcerr "github.com/absmach/supermq/pkg/errors/service"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
-	readersmocks "github.com/absmach/supermq/readers/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -37,7 +38,7 @@ func setupReaders() (*httptest.Server, *authnmocks.Authentication, *readersmocks
 	clientsGRPCClient = new(climocks.ClientsServiceClient)
 	channelsGRPCClient = new(chmocks.ChannelsServiceClient)
 
-	mux := readersapi.MakeHandler(repo, authn, clientsGRPCClient, channelsGRPCClient, "test", "")
+	mux := readersapi.MakeHandler(repo, authn, new(authzmocks.Authorization), clientsGRPCClient, channelsGRPCClient, "test", "")
 	return httptest.NewServer(mux), authn, repo
 }
 
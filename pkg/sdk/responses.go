@@ -23,6 +23,7 @@ type SubscriptionPage struct {
 }
 
 type MessagesPage struct {
-	Messages []senml.Message `json:"messages,omitempty"`
+	Messages   []senml.Message `json:"messages,omitempty"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 	PageRes
 }
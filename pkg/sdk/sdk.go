@@ -54,6 +54,8 @@ type MessagePageMetadata struct {
 	Interval    string  `json:"interval,omitempty"`
 	Value       float64 `json:"value,omitempty"`
 	Protocol    string  `json:"protocol,omitempty"`
+	Cursor      string  `json:"cursor,omitempty"`
+	SkipTotal   bool    `json:"skip_total,omitempty"`
 }
 
 // SDK contains Magistrala API.
@@ -0,0 +1,112 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package re
+
+import (
+	"strings"
+	"testing"
+
+	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamed(publisher string, times ...float64) []*grpcReadersV1.Message {
+	msgs := make([]*grpcReadersV1.Message, 0, len(times))
+	for _, t := range times {
+		v := t
+		msgs = append(msgs, &grpcReadersV1.Message{
+			Payload: &grpcReadersV1.Message_Senml{
+				Senml: &grpcReadersV1.SenMLMessage{
+					Base:  &grpcReadersV1.BaseMessage{Publisher: publisher},
+					Name:  "temperature",
+					Time:  t,
+					Value: &v,
+				},
+			},
+		})
+	}
+
+	return msgs
+}
+
+func TestReportBuilder(t *testing.T) {
+	metric := Metric{ChannelID: "channel", Name: "temperature"}
+	client := Metric{ChannelID: "channel", ClientID: "client", Name: "temperature"}
+
+	cases := []struct {
+		desc    string
+		metric  Metric
+		batches [][]*grpcReadersV1.Message
+		clients []string
+		counts  []int
+	}{
+		{
+			desc:    "group batches by publisher",
+			metric:  metric,
+			batches: [][]*grpcReadersV1.Message{append(streamed("p1", 3, 2), streamed("p2", 2)...), streamed("p1", 1)},
+			clients: []string{"p1", "p2"},
+			counts:  []int{3, 1},
+		},
+		{
+			desc:    "report client metric",
+			metric:  client,
+			batches: [][]*grpcReadersV1.Message{streamed("other", 2), streamed("other", 1)},
+			clients: []string{"client"},
+			counts:  []int{2},
+		},
+		{
+			desc:    "report client metric with no messages",
+			metric:  client,
+			clients: []string{"client"},
+			counts:  []int{0},
+		},
+		{
+			desc:   "report metric with no messages",
+			metric: metric,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, file := range []bool{false, true} {
+				b := newReportBuilder(file)
+				b.begin(tc.metric)
+				for _, batch := range tc.batches {
+					b.add(tc.metric, batch)
+				}
+
+				require.Len(t, b.reports, len(tc.clients))
+				for i, r := range b.reports {
+					assert.Equal(t, tc.clients[i], r.Metric.ClientID)
+					switch file {
+					case true:
+						assert.Empty(t, r.Messages)
+						assert.Len(t, b.tables[i].table.Rows, tc.counts[i])
+						assert.Len(t, b.tables[i].times, tc.counts[i])
+					default:
+						assert.Len(t, r.Messages, tc.counts[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCSVReport(t *testing.T) {
+	b := newReportBuilder(true)
+	metric := Metric{ChannelID: "channel", ClientID: "client", Name: "temperature"}
+	b.begin(metric)
+	b.add(metric, streamed("client", 1700000300, 1700000200))
+	b.add(metric, streamed("client", 1700000100))
+
+	data, err := generateCSVReport("Report", b.tables)
+	require.Nil(t, err, "unexpected error generating report")
+
+	csv := string(data)
+	v1 := strings.Index(csv, "1700000100.00")
+	v2 := strings.Index(csv, "1700000200.00")
+	v3 := strings.Index(csv, "1700000300.00")
+	assert.True(t, v1 >= 0 && v1 < v2 && v2 < v3, "rows are ordered from the earliest")
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala/pkg/reltime"
+	"github.com/absmach/magistrala/readers"
 	"github.com/absmach/supermq/pkg/errors"
 	"github.com/absmach/supermq/pkg/transformers/senml"
 )
@@ -23,6 +24,7 @@ var (
 	errInvalidToTime              = errors.New("invalid \"to time\"")
 	errAggIntervalTimeNotProvided = errors.New("aggregation interval time not provided")
 	errInvalidAggInterval         = errors.New("invalid aggregation interval time")
+	errInvalidAggFill             = errors.New("invalid aggregation fill")
 	errNoToEmail                  = errors.New("no \"To\" email address found")
 	errChannelIDNotProvided       = errors.New("channel id not provided")
 	errNameNotProvided            = errors.New("name not provided")
@@ -58,8 +60,10 @@ type ReportFile struct {
 }
 
 type AggConfig struct {
-	AggType  Aggregation `json:"agg_type,omitempty"` // Optional field
-	Interval string      `json:"interval,omitempty"` // Mandatory field if "AggType" field is set MAX, MIN, COUNT, SUM, AVG
+	AggType   Aggregation `json:"agg_type,omitempty"`   // Optional field
+	Interval  string      `json:"interval,omitempty"`   // Mandatory field if "AggType" field is set MAX, MIN, COUNT, SUM, AVG
+	Fill      string      `json:"fill,omitempty"`       // Optional field: none, null, previous, linear or constant
+	FillValue float64     `json:"fill_value,omitempty"` // Optional field, used if "Fill" field is set to constant
 }
 
 func (ac AggConfig) Validate() error {
@@ -72,6 +76,16 @@ func (ac AggConfig) Validate() error {
 			return errInvalidAggInterval
 		}
 	}
+
+	switch ac.Fill {
+	case "", readers.FillNone:
+	case readers.FillNull, readers.FillPrevious, readers.FillLinear, readers.FillConstant:
+		if ac.AggType == AggregationNONE {
+			return errInvalidAggFill
+		}
+	default:
+		return errInvalidAggFill
+	}
 	return nil
 }
 
@@ -311,15 +325,29 @@ const (
 	AggregationSUM
 	AggregationCOUNT
 	AggregationAVG
+	AggregationP95
+	AggregationP99
+	AggregationSTDDEV
+	AggregationFIRST
+	AggregationLAST
+	AggregationRATE
+	AggregationDELTA
 )
 
 const (
-	aggregationNONE  = "none"
-	aggregationMAX   = "max"
-	aggregationMIN   = "min"
-	aggregationSUM   = "sum"
-	aggregationCOUNT = "count"
-	aggregationAVG   = "avg"
+	aggregationNONE   = "none"
+	aggregationMAX    = "max"
+	aggregationMIN    = "min"
+	aggregationSUM    = "sum"
+	aggregationCOUNT  = "count"
+	aggregationAVG    = "avg"
+	aggregationP95    = "p95"
+	aggregationP99    = "p99"
+	aggregationSTDDEV = "stddev"
+	aggregationFIRST  = "first"
+	aggregationLAST   = "last"
+	aggregationRATE   = "rate"
+	aggregationDELTA  = "delta"
 )
 
 func (a Aggregation) String() string {
@@ -336,6 +364,20 @@ func (a Aggregation) String() string {
 		return aggregationCOUNT
 	case AggregationAVG:
 		return aggregationAVG
+	case AggregationP95:
+		return aggregationP95
+	case AggregationP99:
+		return aggregationP99
+	case AggregationSTDDEV:
+		return aggregationSTDDEV
+	case AggregationFIRST:
+		return aggregationFIRST
+	case AggregationLAST:
+		return aggregationLAST
+	case AggregationRATE:
+		return aggregationRATE
+	case AggregationDELTA:
+		return aggregationDELTA
 	default:
 		return fmt.Sprintf(errUnknownAggregationFmt, a)
 	}
@@ -355,6 +397,20 @@ func ToAggregation(agg string) (Aggregation, error) {
 		return AggregationCOUNT, nil
 	case aggregationAVG:
 		return AggregationAVG, nil
+	case aggregationP95:
+		return AggregationP95, nil
+	case aggregationP99:
+		return AggregationP99, nil
+	case aggregationSTDDEV:
+		return AggregationSTDDEV, nil
+	case aggregationFIRST:
+		return AggregationFIRST, nil
+	case aggregationLAST:
+		return AggregationLAST, nil
+	case aggregationRATE:
+		return AggregationRATE, nil
+	case aggregationDELTA:
+		return AggregationDELTA, nil
 	default:
 		return Aggregation(0), fmt.Errorf(errUnknownAggregationStringFmt, agg)
 	}
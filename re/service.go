@@ -6,6 +6,7 @@ package re
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -375,6 +376,20 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 		agg = grpcReadersV1.Aggregation_AVG
 	case AggregationSUM:
 		agg = grpcReadersV1.Aggregation_SUM
+	case AggregationP95:
+		agg = grpcReadersV1.Aggregation_P95
+	case AggregationP99:
+		agg = grpcReadersV1.Aggregation_P99
+	case AggregationSTDDEV:
+		agg = grpcReadersV1.Aggregation_STDDEV
+	case AggregationFIRST:
+		agg = grpcReadersV1.Aggregation_FIRST
+	case AggregationLAST:
+		agg = grpcReadersV1.Aggregation_LAST
+	case AggregationRATE:
+		agg = grpcReadersV1.Aggregation_RATE
+	case AggregationDELTA:
+		agg = grpcReadersV1.Aggregation_DELTA
 	}
 
 	from, err := reltime.Parse(cfg.Config.From)
@@ -391,10 +406,11 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 		From:        float64(from.UnixMicro()),
 		To:          float64(to.UnixNano()),
 		Interval:    cfg.Config.Aggregation.Interval,
+		Fill:        cfg.Config.Aggregation.Fill,
+		FillValue:   cfg.Config.Aggregation.FillValue,
 	}
 
 	var mets []Metric
-	var reports []Report
 	for _, metric := range cfg.Metrics {
 		switch {
 		case len(metric.ClientIDs) != 0:
@@ -419,8 +435,9 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 		}
 	}
 
+	builder := newReportBuilder(genReportFile != nil)
 	for _, metric := range mets {
-		sMsgs := []senml.Message{}
+		builder.begin(metric)
 
 		pm.Offset = uint64(0)
 		pm.Name = metric.Name
@@ -437,7 +454,7 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 			pm.Format = metric.Format
 		}
 
-		msgs, err := re.readers.ReadMessages(ctx, &grpcReadersV1.ReadMessagesReq{
+		stream, err := re.readers.StreamMessages(ctx, &grpcReadersV1.ReadMessagesReq{
 			ChannelId:    metric.ChannelID,
 			DomainId:     cfg.DomainID,
 			PageMetadata: pm,
@@ -445,31 +462,21 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 		if err != nil {
 			return ReportPage{}, err
 		}
-		for _, msg := range msgs.Messages {
-			sMsgs = append(sMsgs, convertToSenml(msg.GetSenml()))
-		}
-
-		for msgs.GetTotal() > (pm.Offset + pm.Limit) {
-			pm.Offset = pm.Offset + pm.Limit
-			msgs, err := re.readers.ReadMessages(ctx, &grpcReadersV1.ReadMessagesReq{
-				ChannelId:    metric.ChannelID,
-				DomainId:     cfg.DomainID,
-				PageMetadata: pm,
-			})
+		for {
+			msgs, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
 			if err != nil {
 				return ReportPage{}, err
 			}
-			for _, msg := range msgs.Messages {
-				sMsgs = append(sMsgs, convertToSenml(msg.GetSenml()))
-			}
+			builder.add(metric, msgs.GetMessages())
 		}
-
-		reports = append(reports, convertToReports(metric, sMsgs)...)
 	}
 
 	switch {
 	case genReportFile != nil:
-		data, err := genReportFile(cfg.Config.Title, reports)
+		data, err := genReportFile(cfg.Config.Title, builder.tables)
 		if err != nil {
 			return ReportPage{}, err
 		}
@@ -504,13 +511,13 @@ func (re *re) generateReport(ctx context.Context, cfg ReportConfig, action Repor
 			From:        from,
 			To:          to,
 			Aggregation: cfg.Config.Aggregation,
-			Total:       uint64(len(reports)),
-			Reports:     reports,
+			Total:       uint64(len(builder.reports)),
+			Reports:     builder.reports,
 		}, nil
 	}
 }
 
-func generateFileFunc(action ReportAction, format Format) (func(string, []Report) ([]byte, error), error) {
+func generateFileFunc(action ReportAction, format Format) (func(string, []reportTable) ([]byte, error), error) {
 	switch action {
 	case DownloadReport, EmailReport:
 		switch format {
@@ -570,36 +577,3 @@ func convertToSenml(g *grpcReadersV1.SenMLMessage) senml.Message {
 		Sum:         g.Sum,
 	}
 }
-
-func convertToReports(metric Metric, senmlMsgs []senml.Message) []Report {
-	if metric.ClientID != "" {
-		return []Report{
-			{
-				Metric:   metric,
-				Messages: senmlMsgs,
-			},
-		}
-	}
-
-	return groupReportsByPublisher(metric, senmlMsgs)
-}
-
-func groupReportsByPublisher(metric Metric, sMsgs []senml.Message) []Report {
-	publishers := map[string][]senml.Message{}
-
-	for _, msg := range sMsgs {
-		publishers[msg.Publisher] = append(publishers[msg.Publisher], msg)
-	}
-
-	var groupedReports []Report
-	for publisher, messages := range publishers {
-		gMetric := metric
-		gMetric.ClientID = publisher
-		groupedReports = append(groupedReports, Report{
-			Metric:   gMetric,
-			Messages: messages,
-		})
-	}
-
-	return groupedReports
-}
@@ -0,0 +1,337 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+	"strings"
+
+	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
+	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
+	grpcClientsV1 "github.com/absmach/supermq/api/grpc/clients/v1"
+	grpcCommonV1 "github.com/absmach/supermq/api/grpc/common/v1"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	grpcapi "github.com/absmach/supermq/auth/api/grpc"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
+	"github.com/absmach/supermq/pkg/connections"
+	"github.com/absmach/supermq/pkg/errors"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/absmach/supermq/pkg/policies"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// authorizationKey is the metadata key of the caller credentials.
+	authorizationKey = "authorization"
+
+	// ServicePrefix represents the key prefix for Service authentication scheme.
+	ServicePrefix = "Service "
+
+	channelSubscribePermission = "channel_subscribe_permission"
+)
+
+// AuthConfig contains the trusted callers of the readers gRPC server, which
+// read messages of any domain.
+type AuthConfig struct {
+	// ServiceKeys are the keys that services send with the Service prefix.
+	ServiceKeys []string `env:"SERVICE_KEYS" envSeparator:","`
+	// ClientNames are the certificate common names of mTLS clients.
+	ClientNames []string `env:"CLIENT_NAMES" envSeparator:","`
+}
+
+// Authorizer authorizes the callers of the readers gRPC server. Trusted
+// services are identified by their mTLS client certificate or service key,
+// while users and clients send a token or a client secret and need the
+// subscribe permission on the channel. Reading the groups of all the channels
+// of a domain needs the subscribe permission on the domain.
+type Authorizer struct {
+	cfg      AuthConfig
+	authn    smqauthn.Authentication
+	authz    smqauthz.Authorization
+	clients  grpcClientsV1.ClientsServiceClient
+	channels grpcChannelsV1.ChannelsServiceClient
+}
+
+// NewAuthorizer returns new readers gRPC server authorizer.
+func NewAuthorizer(cfg AuthConfig, authn smqauthn.Authentication, authz smqauthz.Authorization, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) *Authorizer {
+	return &Authorizer{
+		cfg:      cfg,
+		authn:    authn,
+		authz:    authz,
+		clients:  clients,
+		channels: channels,
+	}
+}
+
+// UnaryServerInterceptor authorizes unary read requests. Requests of other
+// types or methods are denied.
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authorize, ok := a.authorizer(info.FullMethod)
+		if !ok {
+			return nil, grpcapi.EncodeError(svcerr.ErrAuthorization)
+		}
+		r, ok := req.(*grpcReadersV1.ReadMessagesReq)
+		if !ok {
+			return nil, grpcapi.EncodeError(svcerr.ErrAuthorization)
+		}
+		if err := authorize(ctx, r); err != nil {
+			return nil, grpcapi.EncodeError(err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authorizes the request of server streams. Streams
+// of other methods are denied.
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		authorize, ok := a.authorizer(info.FullMethod)
+		if !ok {
+			return grpcapi.EncodeError(svcerr.ErrAuthorization)
+		}
+
+		return handler(srv, &authorizedStream{ServerStream: ss, authorize: authorize})
+	}
+}
+
+// authorizer returns the authorization of the requests of the readers
+// service method, and false for other methods. Groups are read with the
+// same request type as messages, so the method tells them apart.
+func (a *Authorizer) authorizer(method string) (func(context.Context, *grpcReadersV1.ReadMessagesReq) error, bool) {
+	switch method {
+	case grpcReadersV1.ReadersService_ReadMessages_FullMethodName,
+		grpcReadersV1.ReadersService_ReadLatest_FullMethodName,
+		grpcReadersV1.ReadersService_StreamMessages_FullMethodName:
+		return a.authorizeMessages, true
+	case grpcReadersV1.ReadersService_ReadGroups_FullMethodName:
+		return a.authorizeGroups, true
+	default:
+		return nil, false
+	}
+}
+
+// authorizedStream authorizes the request the client sends to open the stream.
+type authorizedStream struct {
+	grpc.ServerStream
+	authorize func(context.Context, *grpcReadersV1.ReadMessagesReq) error
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	r, ok := m.(*grpcReadersV1.ReadMessagesReq)
+	if !ok {
+		return grpcapi.EncodeError(svcerr.ErrAuthorization)
+	}
+	if err := s.authorize(s.Context(), r); err != nil {
+		return grpcapi.EncodeError(err)
+	}
+
+	return nil
+}
+
+// ServiceDesc returns the readers service description with the handlers
+// wrapped by the interceptors, which applies them to the readers service
+// of a server created without them.
+func ServiceDesc(unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) *grpc.ServiceDesc {
+	desc := grpcReadersV1.ReadersService_ServiceDesc
+
+	desc.Methods = slices.Clone(desc.Methods)
+	for i, m := range desc.Methods {
+		handler := m.Handler
+		desc.Methods[i].Handler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, next grpc.UnaryServerInterceptor) (interface{}, error) {
+			return handler(srv, ctx, dec, chainUnary(unary, next))
+		}
+	}
+
+	desc.Streams = slices.Clone(desc.Streams)
+	for i, s := range desc.Streams {
+		handler := s.Handler
+		info := &grpc.StreamServerInfo{
+			FullMethod:     "/" + desc.ServiceName + "/" + s.StreamName,
+			IsServerStream: s.ServerStreams,
+			IsClientStream: s.ClientStreams,
+		}
+		desc.Streams[i].Handler = func(srv interface{}, ss grpc.ServerStream) error {
+			return stream(srv, ss, info, handler)
+		}
+	}
+
+	return &desc
+}
+
+// chainUnary runs the interceptor before the server interceptor, if any.
+func chainUnary(first, next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	if next == nil {
+		return first
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return first(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return next(ctx, req, info, handler)
+		})
+	}
+}
+
+// authorizeMessages authorizes reads of the messages of a channel.
+func (a *Authorizer) authorizeMessages(ctx context.Context, req *grpcReadersV1.ReadMessagesReq) error {
+	if req.GetChannelId() == "" || req.GetDomainId() == "" {
+		return apiutil.ErrMissingID
+	}
+
+	return a.authorize(ctx, req.GetDomainId(), []string{req.GetChannelId()})
+}
+
+// authorizeGroups authorizes reads of the message groups of the channels, or
+// of all the channels of the domain if no channel is set.
+func (a *Authorizer) authorizeGroups(ctx context.Context, req *grpcReadersV1.ReadMessagesReq) error {
+	if req.GetDomainId() == "" {
+		return apiutil.ErrMissingID
+	}
+	for _, id := range req.GetChannelIds() {
+		if id == "" {
+			return apiutil.ErrMissingID
+		}
+	}
+
+	return a.authorize(ctx, req.GetDomainId(), req.GetChannelIds())
+}
+
+func (a *Authorizer) authorize(ctx context.Context, domainID string, chanIDs []string) error {
+	if a.trustedClient(ctx) {
+		return a.authorizeDomain(ctx, chanIDs, domainID)
+	}
+
+	var creds string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(authorizationKey); len(vals) > 0 {
+			creds = vals[0]
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(creds, ServicePrefix):
+		key := strings.TrimPrefix(creds, ServicePrefix)
+		for _, k := range a.cfg.ServiceKeys {
+			if k != "" && subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				return a.authorizeDomain(ctx, chanIDs, domainID)
+			}
+		}
+		return svcerr.ErrAuthentication
+	case strings.HasPrefix(creds, apiutil.BearerPrefix):
+		session, err := a.authn.Authenticate(ctx, strings.TrimPrefix(creds, apiutil.BearerPrefix))
+		if err != nil {
+			return errors.Wrap(svcerr.ErrAuthentication, err)
+		}
+		if len(chanIDs) == 0 {
+			return a.authorizeMember(ctx, session, domainID)
+		}
+		return a.authorizeChannels(ctx, session.UserID, policies.UserType, chanIDs, domainID)
+	case strings.HasPrefix(creds, apiutil.ClientPrefix):
+		// Reading all the channels of a domain requires a user token.
+		if len(chanIDs) == 0 {
+			return apiutil.ErrBearerToken
+		}
+		res, err := a.clients.Authenticate(ctx, &grpcClientsV1.AuthnReq{
+			ClientSecret: strings.TrimPrefix(creds, apiutil.ClientPrefix),
+		})
+		if err != nil {
+			return errors.Wrap(svcerr.ErrAuthentication, err)
+		}
+		if !res.GetAuthenticated() {
+			return svcerr.ErrAuthentication
+		}
+		return a.authorizeChannels(ctx, res.GetId(), policies.ClientType, chanIDs, domainID)
+	default:
+		return svcerr.ErrAuthentication
+	}
+}
+
+// trustedClient reports whether the caller presented a verified client
+// certificate with one of the trusted common names.
+func (a *Authorizer) trustedClient(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return false
+	}
+	name := info.State.VerifiedChains[0][0].Subject.CommonName
+
+	return name != "" && slices.Contains(a.cfg.ClientNames, name)
+}
+
+// authorizeChannels checks that the user or client may subscribe to the
+// channels of the domain.
+func (a *Authorizer) authorizeChannels(ctx context.Context, clientID, clientType string, chanIDs []string, domainID string) error {
+	for _, chanID := range chanIDs {
+		res, err := a.channels.Authorize(ctx, &grpcChannelsV1.AuthzReq{
+			ClientId:   clientID,
+			ClientType: clientType,
+			Type:       uint32(connections.Subscribe),
+			ChannelId:  chanID,
+			DomainId:   domainID,
+		})
+		if err != nil {
+			return errors.Wrap(svcerr.ErrAuthorization, err)
+		}
+		if !res.GetAuthorized() {
+			return svcerr.ErrAuthorization
+		}
+	}
+
+	return nil
+}
+
+// authorizeMember checks that the user may subscribe to all the channels of
+// the domain.
+func (a *Authorizer) authorizeMember(ctx context.Context, session smqauthn.Session, domainID string) error {
+	subject := policies.EncodeDomainUserID(domainID, session.UserID)
+	if session.Role == smqauthn.AdminRole {
+		subject = session.UserID
+	}
+
+	err := a.authz.Authorize(ctx, smqauthz.PolicyReq{
+		Domain:      domainID,
+		SubjectType: policies.UserType,
+		SubjectKind: policies.UsersKind,
+		Subject:     subject,
+		Permission:  channelSubscribePermission,
+		ObjectType:  policies.DomainType,
+		Object:      domainID,
+	})
+	if err != nil {
+		return errors.Wrap(svcerr.ErrAuthorization, err)
+	}
+
+	return nil
+}
+
+// authorizeDomain checks that the channels belong to the domain, since
+// trusted services may read messages of any domain.
+func (a *Authorizer) authorizeDomain(ctx context.Context, chanIDs []string, domainID string) error {
+	for _, chanID := range chanIDs {
+		res, err := a.channels.RetrieveEntity(ctx, &grpcCommonV1.RetrieveEntityReq{Id: chanID})
+		if err != nil {
+			return errors.Wrap(svcerr.ErrAuthorization, err)
+		}
+		if res.GetEntity().GetDomainId() != domainID {
+			return svcerr.ErrAuthorization
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,404 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/readers"
+	grpcapi "github.com/absmach/magistrala/readers/api/grpc"
+	"github.com/absmach/magistrala/readers/mocks"
+	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
+	grpcClientsV1 "github.com/absmach/supermq/api/grpc/clients/v1"
+	grpcCommonV1 "github.com/absmach/supermq/api/grpc/common/v1"
+	apiutil "github.com/absmach/supermq/api/http/util"
+	chmocks "github.com/absmach/supermq/channels/mocks"
+	climocks "github.com/absmach/supermq/clients/mocks"
+	smqauthn "github.com/absmach/supermq/pkg/authn"
+	authnmocks "github.com/absmach/supermq/pkg/authn/mocks"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
+	svcerr "github.com/absmach/supermq/pkg/errors/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authzPort   = 7072
+	groupsPort  = 7073
+	serviceKey  = "serviceKey"
+	clientKey   = "clientKey"
+	otherDomain = "otherDomain"
+)
+
+func startAuthzGRPCServer(repo readers.MessageRepository, authz *grpcapi.Authorizer, port int) *grpc.Server {
+	listener, _ := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	server := grpc.NewServer()
+	server.RegisterService(grpcapi.ServiceDesc(authz.UnaryServerInterceptor(), authz.StreamServerInterceptor()), grpcapi.NewReadersServer(repo))
+	go func() {
+		err := server.Serve(listener)
+		assert.Nil(&testing.T{}, err, fmt.Sprintf(`"Unexpected error creating reader server %s"`, err))
+	}()
+
+	return server
+}
+
+func TestAuthorization(t *testing.T) {
+	repo := new(mocks.MessageRepository)
+	authn := new(authnmocks.Authentication)
+	clients := new(climocks.ClientsServiceClient)
+	channels := new(chmocks.ChannelsServiceClient)
+	authz := grpcapi.NewAuthorizer(grpcapi.AuthConfig{ServiceKeys: []string{serviceKey}}, authn, new(authzmocks.Authorization), clients, channels)
+	server := startAuthzGRPCServer(repo, authz, authzPort)
+	defer server.GracefulStop()
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", authzPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
+
+	cases := []struct {
+		desc         string
+		key          string
+		token        string
+		clientSecret string
+		domainID     string
+		authnRes     smqauthn.Session
+		authnErr     error
+		clientsRes   *grpcClientsV1.AuthnRes
+		clientsErr   error
+		authzRes     *grpcChannelsV1.AuthzRes
+		authzErr     error
+		entityRes    *grpcCommonV1.RetrieveEntityRes
+		entityErr    error
+		err          error
+	}{
+		{
+			desc:      "read messages with valid service key",
+			key:       serviceKey,
+			domainID:  domain,
+			entityRes: &grpcCommonV1.RetrieveEntityRes{Entity: &grpcCommonV1.EntityBasic{Id: channelID, DomainId: domain}},
+		},
+		{
+			desc:      "read messages with valid service key of channel in other domain",
+			key:       serviceKey,
+			domainID:  otherDomain,
+			entityRes: &grpcCommonV1.RetrieveEntityRes{Entity: &grpcCommonV1.EntityBasic{Id: channelID, DomainId: domain}},
+			err:       svcerr.ErrAuthorization,
+		},
+		{
+			desc:      "read messages with valid service key of non-existing channel",
+			key:       serviceKey,
+			domainID:  domain,
+			entityErr: svcerr.ErrNotFound,
+			err:       svcerr.ErrAuthorization,
+		},
+		{
+			desc:     "read messages with invalid service key",
+			key:      "invalid",
+			domainID: domain,
+			err:      svcerr.ErrAuthentication,
+		},
+		{
+			desc:     "read messages with authorized user token",
+			token:    apiutil.BearerPrefix + validToken,
+			domainID: domain,
+			authnRes: smqauthn.Session{UserID: validID},
+			authzRes: &grpcChannelsV1.AuthzRes{Authorized: true},
+		},
+		{
+			desc:     "read messages with unauthorized user token",
+			token:    apiutil.BearerPrefix + validToken,
+			domainID: domain,
+			authnRes: smqauthn.Session{UserID: validID},
+			authzRes: &grpcChannelsV1.AuthzRes{Authorized: false},
+			err:      svcerr.ErrAuthorization,
+		},
+		{
+			desc:     "read messages with invalid user token",
+			token:    apiutil.BearerPrefix + inValidToken,
+			domainID: domain,
+			authnErr: svcerr.ErrAuthentication,
+			err:      svcerr.ErrAuthentication,
+		},
+		{
+			desc:         "read messages with authorized client secret",
+			clientSecret: clientKey,
+			domainID:     domain,
+			clientsRes:   &grpcClientsV1.AuthnRes{Authenticated: true, Id: validID},
+			authzRes:     &grpcChannelsV1.AuthzRes{Authorized: true},
+		},
+		{
+			desc:         "read messages with invalid client secret",
+			clientSecret: clientKey,
+			domainID:     domain,
+			clientsRes:   &grpcClientsV1.AuthnRes{Authenticated: false},
+			err:          svcerr.ErrAuthentication,
+		},
+		{
+			desc:     "read messages without credentials",
+			domainID: domain,
+			err:      svcerr.ErrAuthentication,
+		},
+		{
+			desc: "read messages without domain",
+			key:  serviceKey,
+			err:  apiutil.ErrMissingID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			key := ""
+			switch {
+			case tc.key != "":
+				key = tc.key
+			case tc.token != "":
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.token)
+			case tc.clientSecret != "":
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", apiutil.ClientPrefix+tc.clientSecret)
+			}
+			client := grpcapi.NewReadersClient(conn, time.Second, key)
+
+			authnCall := authn.On("Authenticate", mock.Anything, validToken).Return(tc.authnRes, tc.authnErr)
+			authnCall1 := authn.On("Authenticate", mock.Anything, inValidToken).Return(tc.authnRes, tc.authnErr)
+			clientsCall := clients.On("Authenticate", mock.Anything, &grpcClientsV1.AuthnReq{ClientSecret: clientKey}).Return(tc.clientsRes, tc.clientsErr)
+			authzCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzRes, tc.authzErr)
+			entityCall := channels.On("RetrieveEntity", mock.Anything, &grpcCommonV1.RetrieveEntityReq{Id: channelID}).Return(tc.entityRes, tc.entityErr)
+			repoCall := repo.On("ReadAll", channelID, mock.Anything).Return(readers.MessagesPage{}, nil)
+			streamCall := repo.On("StreamAll", mock.Anything, channelID, mock.Anything, mock.Anything).Return(nil)
+
+			req := &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  tc.domainID,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit: testLimit,
+				},
+			}
+			_, err := client.ReadMessages(ctx, req)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+
+			stream, err := client.StreamMessages(ctx, req)
+			if err == nil {
+				_, err = stream.Recv()
+				if err == io.EOF {
+					err = nil
+				}
+			}
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected stream error %s, got %s", tc.desc, tc.err, err))
+
+			authnCall.Unset()
+			authnCall1.Unset()
+			clientsCall.Unset()
+			authzCall.Unset()
+			entityCall.Unset()
+			repoCall.Unset()
+			streamCall.Unset()
+		})
+	}
+}
+
+func TestAuthorizeGroups(t *testing.T) {
+	repo := new(mocks.MessageRepository)
+	authn := new(authnmocks.Authentication)
+	policies := new(authzmocks.Authorization)
+	clients := new(climocks.ClientsServiceClient)
+	channels := new(chmocks.ChannelsServiceClient)
+	authz := grpcapi.NewAuthorizer(grpcapi.AuthConfig{ServiceKeys: []string{serviceKey}}, authn, policies, clients, channels)
+	server := startAuthzGRPCServer(repo, authz, groupsPort)
+	defer server.GracefulStop()
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", groupsPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
+
+	cases := []struct {
+		desc         string
+		key          string
+		token        string
+		clientSecret string
+		chanIDs      []string
+		authnRes     smqauthn.Session
+		authnErr     error
+		clientsRes   *grpcClientsV1.AuthnRes
+		authzRes     *grpcChannelsV1.AuthzRes
+		policyErr    error
+		entityRes    *grpcCommonV1.RetrieveEntityRes
+		err          error
+	}{
+		{
+			desc:      "read groups of channels with valid service key",
+			key:       serviceKey,
+			chanIDs:   []string{channelID},
+			entityRes: &grpcCommonV1.RetrieveEntityRes{Entity: &grpcCommonV1.EntityBasic{Id: channelID, DomainId: domain}},
+		},
+		{
+			desc:      "read groups of channels of other domain with valid service key",
+			key:       serviceKey,
+			chanIDs:   []string{channelID},
+			entityRes: &grpcCommonV1.RetrieveEntityRes{Entity: &grpcCommonV1.EntityBasic{Id: channelID, DomainId: otherDomain}},
+			err:       svcerr.ErrAuthorization,
+		},
+		{
+			desc: "read groups of domain with valid service key",
+			key:  serviceKey,
+		},
+		{
+			desc:     "read groups of channels with authorized user token",
+			token:    apiutil.BearerPrefix + validToken,
+			chanIDs:  []string{channelID},
+			authnRes: smqauthn.Session{UserID: validID},
+			authzRes: &grpcChannelsV1.AuthzRes{Authorized: true},
+		},
+		{
+			desc:     "read groups of channels with unauthorized user token",
+			token:    apiutil.BearerPrefix + validToken,
+			chanIDs:  []string{channelID},
+			authnRes: smqauthn.Session{UserID: validID},
+			authzRes: &grpcChannelsV1.AuthzRes{Authorized: false},
+			err:      svcerr.ErrAuthorization,
+		},
+		{
+			desc:     "read groups of domain with token of domain member",
+			token:    apiutil.BearerPrefix + validToken,
+			authnRes: smqauthn.Session{UserID: validID},
+		},
+		{
+			desc:      "read groups of domain with token of non-member",
+			token:     apiutil.BearerPrefix + validToken,
+			authnRes:  smqauthn.Session{UserID: validID},
+			policyErr: svcerr.ErrAuthorization,
+			err:       svcerr.ErrAuthorization,
+		},
+		{
+			desc:     "read groups of domain with invalid user token",
+			token:    apiutil.BearerPrefix + inValidToken,
+			authnErr: svcerr.ErrAuthentication,
+			err:      svcerr.ErrAuthentication,
+		},
+		{
+			desc:         "read groups of channels with authorized client secret",
+			clientSecret: clientKey,
+			chanIDs:      []string{channelID},
+			clientsRes:   &grpcClientsV1.AuthnRes{Authenticated: true, Id: validID},
+			authzRes:     &grpcChannelsV1.AuthzRes{Authorized: true},
+		},
+		{
+			desc:         "read groups of domain with client secret",
+			clientSecret: clientKey,
+			err:          apiutil.ErrBearerToken,
+		},
+		{
+			desc: "read groups without credentials",
+			err:  svcerr.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			key := ""
+			switch {
+			case tc.key != "":
+				key = tc.key
+			case tc.token != "":
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.token)
+			case tc.clientSecret != "":
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", apiutil.ClientPrefix+tc.clientSecret)
+			}
+			client := grpcapi.NewReadersClient(conn, time.Second, key)
+
+			authnCall := authn.On("Authenticate", mock.Anything, validToken).Return(tc.authnRes, tc.authnErr)
+			authnCall1 := authn.On("Authenticate", mock.Anything, inValidToken).Return(tc.authnRes, tc.authnErr)
+			clientsCall := clients.On("Authenticate", mock.Anything, &grpcClientsV1.AuthnReq{ClientSecret: clientKey}).Return(tc.clientsRes, nil)
+			authzCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzRes, nil)
+			entityCall := channels.On("RetrieveEntity", mock.Anything, &grpcCommonV1.RetrieveEntityReq{Id: channelID}).Return(tc.entityRes, nil)
+			policyCall := policies.On("Authorize", mock.Anything, mock.Anything).Return(tc.policyErr)
+			repoCall := repo.On("ReadGroups", domain, mock.Anything, mock.Anything).Return(readers.GroupsPage{}, nil)
+
+			_, err := client.ReadGroups(ctx, &grpcReadersV1.ReadMessagesReq{
+				ChannelIds: tc.chanIDs,
+				DomainId:   domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit: testLimit,
+				},
+			})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+
+			authnCall.Unset()
+			authnCall1.Unset()
+			clientsCall.Unset()
+			authzCall.Unset()
+			entityCall.Unset()
+			policyCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestAuthorizeUnknownRequests(t *testing.T) {
+	authz := grpcapi.NewAuthorizer(grpcapi.AuthConfig{ServiceKeys: []string{serviceKey}}, new(authnmocks.Authentication), new(authzmocks.Authorization), new(climocks.ClientsServiceClient), new(chmocks.ChannelsServiceClient))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", grpcapi.ServicePrefix+serviceKey))
+	unary := authz.UnaryServerInterceptor()
+	stream := authz.StreamServerInterceptor()
+
+	cases := []struct {
+		desc   string
+		method string
+		req    interface{}
+	}{
+		{
+			desc:   "request of other type",
+			method: grpcReadersV1.ReadersService_ReadMessages_FullMethodName,
+			req:    &grpcReadersV1.ReadMessagesRes{},
+		},
+		{
+			desc:   "request of other method",
+			method: "/readers.v1.ReadersService/Other",
+			req:    &grpcReadersV1.ReadMessagesReq{ChannelId: channelID, DomainId: domain},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			handled := false
+			handler := func(context.Context, interface{}) (interface{}, error) {
+				handled = true
+				return nil, nil
+			}
+			_, err := unary(ctx, tc.req, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err), fmt.Sprintf("%s: expected permission denied error, got %s", tc.desc, err))
+			assert.False(t, handled, fmt.Sprintf("%s: expected request not to be handled", tc.desc))
+
+			streamHandler := func(_ interface{}, ss grpc.ServerStream) error {
+				return ss.RecvMsg(tc.req)
+			}
+			err = stream(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tc.method, IsServerStream: true}, streamHandler)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err), fmt.Sprintf("%s: expected permission denied stream error, got %s", tc.desc, err))
+		})
+	}
+}
+
+// serverStream is the server stream of a request received without errors.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(interface{}) error {
+	return nil
+}
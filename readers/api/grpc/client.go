@@ -7,18 +7,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
 	"github.com/absmach/magistrala/pkg/errors"
+	readers "github.com/absmach/magistrala/readers"
 	svcerr "github.com/absmach/supermq/pkg/errors/service"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	readers "github.com/absmach/supermq/readers"
 	"github.com/go-kit/kit/endpoint"
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -28,11 +30,33 @@ var _ grpcReadersV1.ReadersServiceClient = (*readersGrpcClient)(nil)
 
 type readersGrpcClient struct {
 	readMessages endpoint.Endpoint
+	readLatest   endpoint.Endpoint
+	readGroups   endpoint.Endpoint
+	stream       grpcReadersV1.ReadersServiceClient
 	timeout      time.Duration
+	serviceKey   string
 }
 
-// NewReadersClient returns new readers gRPC client instance.
-func NewReadersClient(conn *grpc.ClientConn, timeout time.Duration) grpcReadersV1.ReadersServiceClient {
+// NewReadersClient returns new readers gRPC client instance. A non-empty
+// service key is sent with every request to authorize the client as a
+// trusted service. Otherwise, the authorization of the outgoing context
+// metadata, such as a user token, is sent.
+func NewReadersClient(conn *grpc.ClientConn, timeout time.Duration, serviceKey string) grpcReadersV1.ReadersServiceClient {
+	opts := []kitgrpc.ClientOption{
+		kitgrpc.ClientBefore(func(ctx context.Context, md *metadata.MD) context.Context {
+			if serviceKey != "" {
+				md.Set(authorizationKey, ServicePrefix+serviceKey)
+				return ctx
+			}
+			if out, ok := metadata.FromOutgoingContext(ctx); ok {
+				if vals := out.Get(authorizationKey); len(vals) > 0 {
+					md.Set(authorizationKey, vals[0])
+				}
+			}
+			return ctx
+		}),
+	}
+
 	return &readersGrpcClient{
 		readMessages: kitgrpc.NewClient(
 			conn,
@@ -41,36 +65,105 @@ func NewReadersClient(conn *grpc.ClientConn, timeout time.Duration) grpcReadersV
 			encodeReadMessagesRequest,
 			decodeReadMessagesResponse,
 			grpcReadersV1.ReadMessagesRes{},
+			opts...,
+		).Endpoint(),
+		readLatest: kitgrpc.NewClient(
+			conn,
+			readersSvcName,
+			"ReadLatest",
+			encodeReadMessagesRequest,
+			decodeReadMessagesResponse,
+			grpcReadersV1.ReadMessagesRes{},
+			opts...,
+		).Endpoint(),
+		readGroups: kitgrpc.NewClient(
+			conn,
+			readersSvcName,
+			"ReadGroups",
+			encodeReadGroupsRequest,
+			decodeReadGroupsResponse,
+			grpcReadersV1.ReadGroupsRes{},
+			opts...,
 		).Endpoint(),
-		timeout: timeout,
+		stream:     grpcReadersV1.NewReadersServiceClient(conn),
+		timeout:    timeout,
+		serviceKey: serviceKey,
 	}
 }
 
 func (client readersGrpcClient) ReadMessages(ctx context.Context, in *grpcReadersV1.ReadMessagesReq, opts ...grpc.CallOption) (*grpcReadersV1.ReadMessagesRes, error) {
+	return client.read(ctx, client.readMessages, in)
+}
+
+func (client readersGrpcClient) ReadLatest(ctx context.Context, in *grpcReadersV1.ReadMessagesReq, opts ...grpc.CallOption) (*grpcReadersV1.ReadMessagesRes, error) {
+	return client.read(ctx, client.readLatest, in)
+}
+
+func (client readersGrpcClient) ReadGroups(ctx context.Context, in *grpcReadersV1.ReadMessagesReq, opts ...grpc.CallOption) (*grpcReadersV1.ReadGroupsRes, error) {
 	ctx, cancel := context.WithTimeout(ctx, client.timeout)
 	defer cancel()
 
-	res, err := client.readMessages(ctx, readMessagesReq{
-		chanID: in.GetChannelId(),
-		domain: in.GetDomainId(),
-		pageMeta: readers.PageMetadata{
-			Offset:      in.GetPageMetadata().GetOffset(),
-			Limit:       in.GetPageMetadata().GetLimit(),
-			Comparator:  in.GetPageMetadata().GetComparator(),
-			Aggregation: in.GetPageMetadata().GetAggregation().String(),
-			From:        in.GetPageMetadata().GetFrom(),
-			To:          in.GetPageMetadata().GetTo(),
-			Interval:    in.GetPageMetadata().GetInterval(),
-			Subtopic:    in.GetPageMetadata().GetSubtopic(),
-			Publisher:   in.GetPageMetadata().GetPublisher(),
-			Protocol:    in.GetPageMetadata().GetProtocol(),
-			Name:        in.GetPageMetadata().GetName(),
-			Value:       in.GetPageMetadata().GetValue(),
-			BoolValue:   in.GetPageMetadata().GetBoolValue(),
-			StringValue: in.GetPageMetadata().GetStringValue(),
-			DataValue:   in.GetPageMetadata().GetDataValue(),
-			Format:      in.GetPageMetadata().GetFormat(),
+	res, err := client.readGroups(ctx, readGroupsReq{
+		chanIDs:  in.GetChannelIds(),
+		domain:   in.GetDomainId(),
+		pageMeta: toPageMetadata(in),
+	})
+	if err != nil {
+		return &grpcReadersV1.ReadGroupsRes{}, decodeError(err)
+	}
+
+	rgr := res.(readGroupsRes)
+	return &grpcReadersV1.ReadGroupsRes{
+		Groups: toResponseGroups(rgr.Groups),
+		PageMetadata: &grpcReadersV1.PageMetadata{
+			Offset: rgr.PageMetadata.Offset,
+			Limit:  rgr.PageMetadata.Limit,
 		},
+	}, nil
+}
+
+// StreamMessages isn't bound by the client timeout, since a stream lasts
+// until all the messages are read. Cancel the context to stop it early.
+func (client readersGrpcClient) StreamMessages(ctx context.Context, in *grpcReadersV1.ReadMessagesReq, opts ...grpc.CallOption) (grpc.ServerStreamingClient[grpcReadersV1.ReadMessagesRes], error) {
+	if client.serviceKey != "" {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set(authorizationKey, ServicePrefix+client.serviceKey)
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	stream, err := client.stream.StreamMessages(ctx, in, opts...)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	return messagesStream{stream}, nil
+}
+
+// messagesStream decodes the errors of the received batches.
+type messagesStream struct {
+	grpc.ServerStreamingClient[grpcReadersV1.ReadMessagesRes]
+}
+
+func (s messagesStream) Recv() (*grpcReadersV1.ReadMessagesRes, error) {
+	res, err := s.ServerStreamingClient.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, decodeError(err)
+	}
+
+	return res, nil
+}
+
+func (client readersGrpcClient) read(ctx context.Context, read endpoint.Endpoint, in *grpcReadersV1.ReadMessagesReq) (*grpcReadersV1.ReadMessagesRes, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := read(ctx, readMessagesReq{
+		chanID:   in.GetChannelId(),
+		domain:   in.GetDomainId(),
+		pageMeta: toPageMetadata(in),
 	})
 	if err != nil {
 		return &grpcReadersV1.ReadMessagesRes{}, decodeError(err)
@@ -84,9 +177,35 @@ func (client readersGrpcClient) ReadMessages(ctx context.Context, in *grpcReader
 			Offset: dpr.PageMetadata.Offset,
 			Limit:  dpr.PageMetadata.Limit,
 		},
+		NextCursor: dpr.NextCursor,
 	}, nil
 }
 
+func toPageMetadata(in *grpcReadersV1.ReadMessagesReq) readers.PageMetadata {
+	return readers.PageMetadata{
+		Offset:      in.GetPageMetadata().GetOffset(),
+		Limit:       in.GetPageMetadata().GetLimit(),
+		Comparator:  in.GetPageMetadata().GetComparator(),
+		Aggregation: in.GetPageMetadata().GetAggregation().String(),
+		From:        in.GetPageMetadata().GetFrom(),
+		To:          in.GetPageMetadata().GetTo(),
+		Interval:    in.GetPageMetadata().GetInterval(),
+		Subtopic:    in.GetPageMetadata().GetSubtopic(),
+		Publisher:   in.GetPageMetadata().GetPublisher(),
+		Protocol:    in.GetPageMetadata().GetProtocol(),
+		Name:        in.GetPageMetadata().GetName(),
+		Value:       in.GetPageMetadata().GetValue(),
+		BoolValue:   in.GetPageMetadata().GetBoolValue(),
+		StringValue: in.GetPageMetadata().GetStringValue(),
+		DataValue:   in.GetPageMetadata().GetDataValue(),
+		Format:      in.GetPageMetadata().GetFormat(),
+		Fill:        in.GetPageMetadata().GetFill(),
+		FillValue:   in.GetPageMetadata().GetFillValue(),
+		Cursor:      in.GetPageMetadata().GetCursor(),
+		SkipTotal:   in.GetPageMetadata().GetSkipTotal(),
+	}
+}
+
 func decodeReadMessagesResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*grpcReadersV1.ReadMessagesRes)
 	return readMessagesRes{
@@ -96,35 +215,77 @@ func decodeReadMessagesResponse(_ context.Context, grpcRes interface{}) (interfa
 			Offset: res.GetPageMetadata().GetOffset(),
 			Limit:  res.GetPageMetadata().GetLimit(),
 		},
+		NextCursor: res.GetNextCursor(),
 	}, nil
 }
 
 func encodeReadMessagesRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(readMessagesReq)
 	return &grpcReadersV1.ReadMessagesReq{
-		ChannelId: req.chanID,
-		DomainId:  req.domain,
-		PageMetadata: &grpcReadersV1.PageMetadata{
-			Offset:      req.pageMeta.Offset,
-			Limit:       req.pageMeta.Limit,
-			Comparator:  req.pageMeta.Comparator,
-			Aggregation: parseAggregation(req.pageMeta.Aggregation),
-			From:        req.pageMeta.From,
-			To:          req.pageMeta.To,
-			Interval:    req.pageMeta.Interval,
-			Subtopic:    req.pageMeta.Subtopic,
-			Publisher:   req.pageMeta.Publisher,
-			Protocol:    req.pageMeta.Protocol,
-			Name:        req.pageMeta.Name,
-			Value:       req.pageMeta.Value,
-			BoolValue:   req.pageMeta.BoolValue,
-			StringValue: req.pageMeta.StringValue,
-			DataValue:   req.pageMeta.DataValue,
-			Format:      req.pageMeta.Format,
+		ChannelId:    req.chanID,
+		DomainId:     req.domain,
+		PageMetadata: fromPageMetadata(req.pageMeta),
+	}, nil
+}
+
+func decodeReadGroupsResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*grpcReadersV1.ReadGroupsRes)
+	return readGroupsRes{
+		Groups: fromResponseGroups(res.GetGroups()),
+		PageMetadata: readers.PageMetadata{
+			Offset: res.GetPageMetadata().GetOffset(),
+			Limit:  res.GetPageMetadata().GetLimit(),
 		},
 	}, nil
 }
 
+func encodeReadGroupsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(readGroupsReq)
+	return &grpcReadersV1.ReadMessagesReq{
+		ChannelIds:   req.chanIDs,
+		DomainId:     req.domain,
+		PageMetadata: fromPageMetadata(req.pageMeta),
+	}, nil
+}
+
+func fromPageMetadata(pm readers.PageMetadata) *grpcReadersV1.PageMetadata {
+	return &grpcReadersV1.PageMetadata{
+		Offset:      pm.Offset,
+		Limit:       pm.Limit,
+		Comparator:  pm.Comparator,
+		Aggregation: parseAggregation(pm.Aggregation),
+		From:        pm.From,
+		To:          pm.To,
+		Interval:    pm.Interval,
+		Subtopic:    pm.Subtopic,
+		Publisher:   pm.Publisher,
+		Protocol:    pm.Protocol,
+		Name:        pm.Name,
+		Value:       pm.Value,
+		BoolValue:   pm.BoolValue,
+		StringValue: pm.StringValue,
+		DataValue:   pm.DataValue,
+		Format:      pm.Format,
+		Fill:        pm.Fill,
+		FillValue:   pm.FillValue,
+		Cursor:      pm.Cursor,
+		SkipTotal:   pm.SkipTotal,
+	}
+}
+
+func fromResponseGroups(protoGroups []*grpcReadersV1.MessagesGroup) []readers.MessagesGroup {
+	var groups []readers.MessagesGroup
+	for _, g := range protoGroups {
+		groups = append(groups, readers.MessagesGroup{
+			Channel:   g.GetChannel(),
+			Publisher: g.GetPublisher(),
+			Total:     g.GetTotal(),
+			Messages:  fromResponseMessages(g.GetMessages()),
+		})
+	}
+	return groups
+}
+
 func fromResponseMessages(protoMessages []*grpcReadersV1.Message) []readers.Message {
 	var messages []readers.Message
 	for _, m := range protoMessages {
@@ -180,6 +341,20 @@ func parseAggregation(agg string) grpcReadersV1.Aggregation {
 		return grpcReadersV1.Aggregation_COUNT
 	case "AVG":
 		return grpcReadersV1.Aggregation_AVG
+	case "P95":
+		return grpcReadersV1.Aggregation_P95
+	case "P99":
+		return grpcReadersV1.Aggregation_P99
+	case "STDDEV":
+		return grpcReadersV1.Aggregation_STDDEV
+	case "FIRST":
+		return grpcReadersV1.Aggregation_FIRST
+	case "LAST":
+		return grpcReadersV1.Aggregation_LAST
+	case "RATE":
+		return grpcReadersV1.Aggregation_RATE
+	case "DELTA":
+		return grpcReadersV1.Aggregation_DELTA
 	default:
 		return grpcReadersV1.Aggregation_AGGREGATION_UNSPECIFIED
 	}
@@ -6,7 +6,8 @@ package grpc
 import (
 	"context"
 
-	readers "github.com/absmach/supermq/readers"
+	readers "github.com/absmach/magistrala/readers"
+	apiutil "github.com/absmach/supermq/api/http/util"
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -26,6 +27,71 @@ func readMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 			PageMetadata: page.PageMetadata,
 			Total:        page.Total,
 			Messages:     page.Messages,
+			NextCursor:   page.NextCursor,
 		}, nil
 	}
 }
+
+func readLatestEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(readMessagesReq)
+		if err := req.validate(); err != nil {
+			return readMessagesRes{}, err
+		}
+
+		// Latest values are read from raw SenML messages only.
+		if req.pageMeta.Aggregation != "" {
+			return readMessagesRes{}, apiutil.ErrInvalidAggregation
+		}
+
+		if req.pageMeta.Cursor != "" {
+			return readMessagesRes{}, readers.ErrInvalidCursor
+		}
+
+		page, err := svc.ReadLatest(req.chanID, req.pageMeta)
+		if err != nil {
+			return readMessagesRes{}, err
+		}
+
+		return readMessagesRes{
+			PageMetadata: page.PageMetadata,
+			Total:        page.Total,
+			Messages:     page.Messages,
+		}, nil
+	}
+}
+
+func readGroupsEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(readGroupsReq)
+		if err := req.validate(); err != nil {
+			return readGroupsRes{}, err
+		}
+
+		page, err := svc.ReadGroups(req.domain, req.chanIDs, req.pageMeta)
+		if err != nil {
+			return readGroupsRes{}, err
+		}
+
+		return readGroupsRes{
+			PageMetadata: page.PageMetadata,
+			Groups:       page.Groups,
+		}, nil
+	}
+}
+
+// streamMessages reads the requested messages with a single query and sends
+// them in batches of the request limit as they are read.
+func streamMessages(ctx context.Context, svc readers.MessageRepository, req readMessagesReq, send func(readMessagesRes) error) error {
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	return svc.StreamAll(ctx, req.chanID, req.pageMeta, func(page readers.MessagesPage) error {
+		return send(readMessagesRes{
+			PageMetadata: page.PageMetadata,
+			Messages:     page.Messages,
+			NextCursor:   page.NextCursor,
+		})
+	})
+}
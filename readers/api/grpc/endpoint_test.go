@@ -7,16 +7,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"testing"
 	"time"
 
 	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
 	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/absmach/magistrala/readers"
 	grpcapi "github.com/absmach/magistrala/readers/api/grpc"
 	apiutil "github.com/absmach/supermq/api/http/util"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -52,7 +53,7 @@ func startGRPCServer(svc readers.MessageRepository, port int) *grpc.Server {
 func TestReadMessages(t *testing.T) {
 	conn, err := grpc.NewClient(authAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
-	grpcClient := grpcapi.NewReadersClient(conn, time.Second)
+	grpcClient := grpcapi.NewReadersClient(conn, time.Second, "")
 
 	tmp := readers.MessagesPage{
 		Total: 1,
@@ -125,6 +126,61 @@ func TestReadMessages(t *testing.T) {
 			ReadMessagesRes: expectedRes,
 			err:             nil,
 		},
+		{
+			desc:  "read with cursor",
+			token: validToken,
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit:     testLimit,
+					Cursor:    "cursor",
+					SkipTotal: true,
+				},
+			},
+			svcRes: readers.MessagesPage{
+				PageMetadata: tmp.PageMetadata,
+				Messages:     tmp.Messages,
+				NextCursor:   "nextCursor",
+			},
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{
+				Messages:   expectedRes.Messages,
+				NextCursor: "nextCursor",
+			},
+		},
+		{
+			desc:  "read with cursor and offset",
+			token: validToken,
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Offset: 10,
+					Limit:  testLimit,
+					Cursor: "cursor",
+				},
+			},
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{},
+			err:             readers.ErrInvalidCursor,
+		},
+		{
+			desc:  "read aggregated messages of JSON format",
+			token: validToken,
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit:       testLimit,
+					Format:      "json",
+					Aggregation: grpcReadersV1.Aggregation_MAX,
+					Interval:    "1h",
+					From:        1,
+					To:          2,
+				},
+			},
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{},
+			err:             apiutil.ErrInvalidAggregation,
+		},
 		{
 			desc:  " read missing channel id",
 			token: validToken,
@@ -211,12 +267,424 @@ func TestReadMessages(t *testing.T) {
 		repoCall := svc.On("ReadAll", mock.Anything, mock.Anything).Return(tc.svcRes, tc.err)
 		dpr, err := grpcClient.ReadMessages(context.Background(), tc.ReadMessagesReq)
 		assert.Equal(t, tc.ReadMessagesRes.Messages, dpr.Messages, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.ReadMessagesRes.Messages, dpr.Messages))
+		assert.Equal(t, tc.ReadMessagesRes.NextCursor, dpr.NextCursor, fmt.Sprintf("%s: expected cursor %s got %s", tc.desc, tc.ReadMessagesRes.NextCursor, dpr.NextCursor))
 
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 		repoCall.Unset()
 	}
 }
 
+func TestReadLatest(t *testing.T) {
+	conn, err := grpc.NewClient(authAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
+	grpcClient := grpcapi.NewReadersClient(conn, time.Second, "")
+
+	svcRes := readers.MessagesPage{
+		Total: 1,
+		PageMetadata: readers.PageMetadata{
+			Offset: 0,
+			Limit:  10,
+		},
+		Messages: []readers.Message{
+			senml.Message{
+				Channel:   channelID,
+				Subtopic:  "senmlSub",
+				Publisher: "senmlPublisher",
+				Protocol:  "mqtt",
+				Name:      "temperature",
+				Unit:      "C",
+				Time:      1672531200,
+				Value:     float64Ptr(22.5),
+			},
+		},
+	}
+	expectedRes := &grpcReadersV1.ReadMessagesRes{
+		Total: 1,
+		PageMetadata: &grpcReadersV1.PageMetadata{
+			Offset: 0,
+			Limit:  10,
+		},
+		Messages: []*grpcReadersV1.Message{
+			{
+				Payload: &grpcReadersV1.Message_Senml{
+					Senml: &grpcReadersV1.SenMLMessage{
+						Base: &grpcReadersV1.BaseMessage{
+							Channel:   channelID,
+							Subtopic:  "senmlSub",
+							Publisher: "senmlPublisher",
+							Protocol:  "mqtt",
+						},
+						Name:  "temperature",
+						Unit:  "C",
+						Time:  1672531200,
+						Value: float64Ptr(22.5),
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		desc            string
+		svcRes          readers.MessagesPage
+		svcErr          error
+		ReadMessagesReq *grpcReadersV1.ReadMessagesReq
+		ReadMessagesRes *grpcReadersV1.ReadMessagesRes
+		err             error
+	}{
+		{
+			desc: "read latest messages",
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Offset: testOffset,
+					Limit:  testLimit,
+				},
+			},
+			svcRes:          svcRes,
+			ReadMessagesRes: expectedRes,
+		},
+		{
+			desc: "read latest messages with missing channel id",
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				DomainId: domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Offset: testOffset,
+					Limit:  testLimit,
+				},
+			},
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{},
+			err:             apiutil.ErrMissingID,
+		},
+		{
+			desc: "read latest messages with aggregation",
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Offset:      testOffset,
+					Limit:       testLimit,
+					Aggregation: grpcReadersV1.Aggregation_MAX,
+					Interval:    "1h",
+					From:        1,
+					To:          2,
+				},
+			},
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{},
+			err:             apiutil.ErrInvalidAggregation,
+		},
+		{
+			desc: "read latest messages with repository error",
+			ReadMessagesReq: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Offset: testOffset,
+					Limit:  testLimit,
+				},
+			},
+			svcErr:          readers.ErrReadMessages,
+			ReadMessagesRes: &grpcReadersV1.ReadMessagesRes{},
+			err:             readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := svc.On("ReadLatest", mock.Anything, mock.Anything).Return(tc.svcRes, tc.svcErr)
+		res, err := grpcClient.ReadLatest(context.Background(), tc.ReadMessagesReq)
+		assert.Equal(t, tc.ReadMessagesRes.Messages, res.Messages, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.ReadMessagesRes.Messages, res.Messages))
+		assert.Equal(t, tc.ReadMessagesRes.Total, res.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.ReadMessagesRes.Total, res.Total))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		repoCall.Unset()
+	}
+}
+
+func TestStreamMessages(t *testing.T) {
+	conn, err := grpc.NewClient(authAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
+	grpcClient := grpcapi.NewReadersClient(conn, time.Second, "")
+
+	msg := senml.Message{
+		Channel:   channelID,
+		Publisher: "senmlPublisher",
+		Protocol:  "mqtt",
+		Name:      "temperature",
+		Time:      1672531200,
+		Value:     float64Ptr(22.5),
+	}
+	page := func(n int, cursor string) readers.MessagesPage {
+		msgs := make([]readers.Message, n)
+		for i := range msgs {
+			msgs[i] = msg
+		}
+		return readers.MessagesPage{
+			PageMetadata: readers.PageMetadata{Limit: 2},
+			Messages:     msgs,
+			NextCursor:   cursor,
+		}
+	}
+
+	cases := []struct {
+		desc     string
+		req      *grpcReadersV1.ReadMessagesReq
+		svcRes   []readers.MessagesPage
+		svcErr   error
+		batches  int
+		messages int
+		err      error
+	}{
+		{
+			desc: "stream messages by cursor",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelId:    channelID,
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: 2},
+			},
+			svcRes:   []readers.MessagesPage{page(2, "c1"), page(2, "c2"), page(1, "c3")},
+			batches:  3,
+			messages: 5,
+		},
+		{
+			desc: "stream aggregated messages by offset",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelId: channelID,
+				DomainId:  domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit:       2,
+					Aggregation: grpcReadersV1.Aggregation_MAX,
+					Interval:    "1h",
+					From:        1,
+					To:          2,
+				},
+			},
+			svcRes:   []readers.MessagesPage{page(2, ""), page(2, "")},
+			batches:  2,
+			messages: 4,
+		},
+		{
+			desc: "stream messages with no messages",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelId:    channelID,
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: 2},
+			},
+		},
+		{
+			desc: "stream messages with invalid limit",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelId:    channelID,
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{},
+			},
+			err: apiutil.ErrLimitSize,
+		},
+		{
+			desc: "stream messages with repository error",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelId:    channelID,
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: 2},
+			},
+			svcRes:   []readers.MessagesPage{page(2, "c1")},
+			svcErr:   readers.ErrReadMessages,
+			batches:  1,
+			messages: 2,
+			err:      readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := svc.On("StreamAll", mock.Anything, channelID, mock.Anything, mock.Anything).Return(func(_ context.Context, _ string, _ readers.PageMetadata, send func(readers.MessagesPage) error) error {
+			for _, res := range tc.svcRes {
+				if err := send(res); err != nil {
+					return err
+				}
+			}
+			return tc.svcErr
+		})
+		stream, err := grpcClient.StreamMessages(context.Background(), tc.req)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error opening stream: %s", tc.desc, err))
+		var batches, messages int
+		for {
+			res, rerr := stream.Recv()
+			if rerr == io.EOF {
+				break
+			}
+			if rerr != nil {
+				err = rerr
+				break
+			}
+			batches++
+			messages += len(res.GetMessages())
+		}
+		assert.Equal(t, tc.batches, batches, fmt.Sprintf("%s: expected %d batches got %d", tc.desc, tc.batches, batches))
+		assert.Equal(t, tc.messages, messages, fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.messages, messages))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		repoCall.Unset()
+	}
+}
+
+func TestReadGroups(t *testing.T) {
+	conn, err := grpc.NewClient(authAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err, fmt.Sprintf("Unexpected error creating client connection %s", err))
+	grpcClient := grpcapi.NewReadersClient(conn, time.Second, "")
+
+	msg := senml.Message{
+		Channel:   channelID,
+		Publisher: "senmlPublisher",
+		Protocol:  "mqtt",
+		Name:      "temperature",
+		Time:      1672531200,
+		Value:     float64Ptr(22.5),
+	}
+	svcRes := readers.GroupsPage{
+		PageMetadata: readers.PageMetadata{Limit: testLimit},
+		Groups: []readers.MessagesGroup{
+			{
+				Channel:   channelID,
+				Publisher: "senmlPublisher",
+				Total:     1,
+				Messages:  []readers.Message{msg},
+			},
+		},
+	}
+	expectedGroups := []*grpcReadersV1.MessagesGroup{
+		{
+			Channel:   channelID,
+			Publisher: "senmlPublisher",
+			Total:     1,
+			Messages: []*grpcReadersV1.Message{
+				{
+					Payload: &grpcReadersV1.Message_Senml{
+						Senml: &grpcReadersV1.SenMLMessage{
+							Base: &grpcReadersV1.BaseMessage{
+								Channel:   channelID,
+								Publisher: "senmlPublisher",
+								Protocol:  "mqtt",
+							},
+							Name:  "temperature",
+							Time:  1672531200,
+							Value: float64Ptr(22.5),
+						},
+					},
+				},
+			},
+		},
+	}
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = channelID
+	}
+
+	cases := []struct {
+		desc    string
+		req     *grpcReadersV1.ReadMessagesReq
+		chanIDs []string
+		svcRes  readers.GroupsPage
+		svcErr  error
+		groups  []*grpcReadersV1.MessagesGroup
+		err     error
+	}{
+		{
+			desc: "read groups of channels",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelIds:   []string{channelID, "otherChannelID"},
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			chanIDs: []string{channelID, "otherChannelID"},
+			svcRes:  svcRes,
+			groups:  expectedGroups,
+		},
+		{
+			desc: "read groups of domain",
+			req: &grpcReadersV1.ReadMessagesReq{
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			svcRes: svcRes,
+			groups: expectedGroups,
+		},
+		{
+			desc: "read groups without domain",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelIds:   []string{channelID},
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			err: apiutil.ErrMissingID,
+		},
+		{
+			desc: "read groups with empty channel id",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelIds:   []string{channelID, ""},
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			err: apiutil.ErrMissingID,
+		},
+		{
+			desc: "read groups of too many channels",
+			req: &grpcReadersV1.ReadMessagesReq{
+				ChannelIds:   tooMany,
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			err: apiutil.ErrLimitSize,
+		},
+		{
+			desc: "read groups with aggregation",
+			req: &grpcReadersV1.ReadMessagesReq{
+				DomainId: domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit:       testLimit,
+					Aggregation: grpcReadersV1.Aggregation_MAX,
+					Interval:    "1h",
+					From:        1,
+					To:          2,
+				},
+			},
+			err: apiutil.ErrInvalidAggregation,
+		},
+		{
+			desc: "read groups with cursor",
+			req: &grpcReadersV1.ReadMessagesReq{
+				DomainId: domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{
+					Limit:  testLimit,
+					Cursor: "cursor",
+				},
+			},
+			err: readers.ErrInvalidCursor,
+		},
+		{
+			desc: "read groups with invalid limit",
+			req: &grpcReadersV1.ReadMessagesReq{
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{},
+			},
+			err: apiutil.ErrLimitSize,
+		},
+		{
+			desc: "read groups with repository error",
+			req: &grpcReadersV1.ReadMessagesReq{
+				DomainId:     domain,
+				PageMetadata: &grpcReadersV1.PageMetadata{Limit: testLimit},
+			},
+			svcErr: readers.ErrReadMessages,
+			err:    readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		repoCall := svc.On("ReadGroups", domain, tc.chanIDs, mock.Anything).Return(tc.svcRes, tc.svcErr)
+		res, err := grpcClient.ReadGroups(context.Background(), tc.req)
+		assert.Equal(t, tc.groups, res.GetGroups(), fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.groups, res.GetGroups()))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		repoCall.Unset()
+	}
+}
+
 func float64Ptr(v float64) *float64 {
 	return &v
 }
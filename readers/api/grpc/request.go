@@ -8,13 +8,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/absmach/magistrala/readers"
 	apiutil "github.com/absmach/supermq/api/http/util"
-	"github.com/absmach/supermq/readers"
 )
 
-const maxLimitSize = 1000
+const (
+	maxLimitSize    = 1000
+	maxChannelsSize = 100
+	defFormat       = "messages"
+)
+
+var validAggregations = []string{"MAX", "MIN", "AVG", "SUM", "COUNT", "P95", "P99", "STDDEV", "FIRST", "LAST", "RATE", "DELTA"}
 
-var validAggregations = []string{"MAX", "MIN", "AVG", "SUM", "COUNT"}
+var validFills = []string{readers.FillNone, readers.FillNull, readers.FillPrevious, readers.FillLinear, readers.FillConstant}
 
 type readMessagesReq struct {
 	chanID   string
@@ -30,39 +36,97 @@ func (req readMessagesReq) validate() error {
 		return apiutil.ErrMissingID
 	}
 
-	if req.pageMeta.Limit < 1 || req.pageMeta.Limit > maxLimitSize {
+	return validatePageMetadata(req.pageMeta)
+}
+
+type readGroupsReq struct {
+	chanIDs  []string
+	domain   string
+	pageMeta readers.PageMetadata
+}
+
+func (req readGroupsReq) validate() error {
+	if req.domain == "" {
+		return apiutil.ErrMissingID
+	}
+
+	if len(req.chanIDs) > maxChannelsSize {
+		return apiutil.ErrLimitSize
+	}
+
+	for _, id := range req.chanIDs {
+		if id == "" {
+			return apiutil.ErrMissingID
+		}
+	}
+
+	// Groups contain raw messages only.
+	if req.pageMeta.Aggregation != "" {
+		return apiutil.ErrInvalidAggregation
+	}
+
+	if req.pageMeta.Cursor != "" {
+		return readers.ErrInvalidCursor
+	}
+
+	return validatePageMetadata(req.pageMeta)
+}
+
+func validatePageMetadata(pm readers.PageMetadata) error {
+	if pm.Limit < 1 || pm.Limit > maxLimitSize {
 		return apiutil.ErrLimitSize
 	}
 
-	if req.pageMeta.Comparator != "" &&
-		req.pageMeta.Comparator != readers.EqualKey &&
-		req.pageMeta.Comparator != readers.LowerThanKey &&
-		req.pageMeta.Comparator != readers.LowerThanEqualKey &&
-		req.pageMeta.Comparator != readers.GreaterThanKey &&
-		req.pageMeta.Comparator != readers.GreaterThanEqualKey {
+	if pm.Comparator != "" &&
+		pm.Comparator != readers.EqualKey &&
+		pm.Comparator != readers.LowerThanKey &&
+		pm.Comparator != readers.LowerThanEqualKey &&
+		pm.Comparator != readers.GreaterThanKey &&
+		pm.Comparator != readers.GreaterThanEqualKey {
 		return apiutil.ErrInvalidComparator
 	}
 
-	if req.pageMeta.Aggregation == "AGGREGATION_UNSPECIFIED" {
-		req.pageMeta.Aggregation = ""
+	if pm.Aggregation == "AGGREGATION_UNSPECIFIED" {
+		pm.Aggregation = ""
 	}
 
-	if agg := strings.ToUpper(req.pageMeta.Aggregation); agg != "" && agg != "AGGREGATION_UNSPECIFIED" {
-		if req.pageMeta.From == 0 {
+	if agg := strings.ToUpper(pm.Aggregation); agg != "" && agg != "AGGREGATION_UNSPECIFIED" {
+		if pm.From == 0 {
 			return apiutil.ErrMissingFrom
 		}
 
-		if req.pageMeta.To == 0 {
+		if pm.To == 0 {
 			return apiutil.ErrMissingTo
 		}
 
-		if !slices.Contains(validAggregations, strings.ToUpper(req.pageMeta.Aggregation)) {
+		if !slices.Contains(validAggregations, strings.ToUpper(pm.Aggregation)) {
 			return apiutil.ErrInvalidAggregation
 		}
 
-		if _, err := time.ParseDuration(req.pageMeta.Interval); err != nil {
+		if _, err := time.ParseDuration(pm.Interval); err != nil {
 			return apiutil.ErrInvalidInterval
 		}
+
+		// Aggregation applies to SenML messages only, since JSON messages
+		// have no common value column.
+		if pm.Format != "" && pm.Format != defFormat {
+			return apiutil.ErrInvalidAggregation
+		}
+	}
+
+	if pm.Fill != "" {
+		if !slices.Contains(validFills, pm.Fill) {
+			return readers.ErrInvalidFill
+		}
+
+		if pm.Fill != readers.FillNone && pm.Aggregation == "" {
+			return readers.ErrInvalidFill
+		}
+	}
+
+	// Cursor replaces offset and pages raw messages only.
+	if pm.Cursor != "" && (pm.Offset != 0 || pm.Aggregation != "") {
+		return readers.ErrInvalidCursor
 	}
 
 	return nil
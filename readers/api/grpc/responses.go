@@ -4,12 +4,18 @@
 package grpc
 
 import (
-	"github.com/absmach/supermq/readers"
+	"github.com/absmach/magistrala/readers"
 )
 
 type readMessagesRes struct {
-	Total    uint64
-	Messages []readers.Message
+	Total      uint64
+	Messages   []readers.Message
+	NextCursor string
+	readers.PageMetadata
+}
+
+type readGroupsRes struct {
+	Groups []readers.MessagesGroup
 	readers.PageMetadata
 }
 
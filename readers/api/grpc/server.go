@@ -8,10 +8,11 @@ import (
 	"encoding/json"
 
 	grpcReadersV1 "github.com/absmach/magistrala/api/grpc/readers/v1"
+	"github.com/absmach/magistrala/readers"
 	grpcapi "github.com/absmach/supermq/auth/api/grpc"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc"
 )
 
 var _ grpcReadersV1.ReadersServiceServer = (*readersGrpcServer)(nil)
@@ -19,44 +20,76 @@ var _ grpcReadersV1.ReadersServiceServer = (*readersGrpcServer)(nil)
 type readersGrpcServer struct {
 	grpcReadersV1.UnimplementedReadersServiceServer
 	readMessages kitgrpc.Handler
+	readLatest   kitgrpc.Handler
+	readGroups   kitgrpc.Handler
+	svc          readers.MessageRepository
 }
 
 func NewReadersServer(svc readers.MessageRepository) grpcReadersV1.ReadersServiceServer {
 	return &readersGrpcServer{
+		svc: svc,
 		readMessages: kitgrpc.NewServer(
 			(readMessagesEndpoint(svc)),
 			decodeReadMessagesRequest,
 			encodeReadMessagesResponse,
 		),
+		readLatest: kitgrpc.NewServer(
+			readLatestEndpoint(svc),
+			decodeReadMessagesRequest,
+			encodeReadMessagesResponse,
+		),
+		readGroups: kitgrpc.NewServer(
+			readGroupsEndpoint(svc),
+			decodeReadGroupsRequest,
+			encodeReadGroupsResponse,
+		),
 	}
 }
 
 func decodeReadMessagesRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*grpcReadersV1.ReadMessagesReq)
 	return readMessagesReq{
-		chanID: req.GetChannelId(),
-		domain: req.GetDomainId(),
-		pageMeta: readers.PageMetadata{
-			Offset:      req.GetPageMetadata().GetOffset(),
-			Limit:       req.GetPageMetadata().GetLimit(),
-			Comparator:  req.GetPageMetadata().GetComparator(),
-			Aggregation: stringifyAggregation(req.GetPageMetadata().GetAggregation()),
-			From:        req.GetPageMetadata().GetFrom(),
-			To:          req.GetPageMetadata().GetTo(),
-			Interval:    req.GetPageMetadata().GetInterval(),
-			Subtopic:    req.GetPageMetadata().GetSubtopic(),
-			Publisher:   req.GetPageMetadata().GetPublisher(),
-			Protocol:    req.GetPageMetadata().GetProtocol(),
-			Name:        req.GetPageMetadata().GetName(),
-			Value:       req.GetPageMetadata().GetValue(),
-			BoolValue:   req.GetPageMetadata().GetBoolValue(),
-			StringValue: req.GetPageMetadata().GetStringValue(),
-			DataValue:   req.GetPageMetadata().GetDataValue(),
-			Format:      req.GetPageMetadata().GetFormat(),
-		},
+		chanID:   req.GetChannelId(),
+		domain:   req.GetDomainId(),
+		pageMeta: decodePageMetadata(req),
 	}, nil
 }
 
+func decodeReadGroupsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*grpcReadersV1.ReadMessagesReq)
+	return readGroupsReq{
+		chanIDs:  req.GetChannelIds(),
+		domain:   req.GetDomainId(),
+		pageMeta: decodePageMetadata(req),
+	}, nil
+}
+
+func decodePageMetadata(req *grpcReadersV1.ReadMessagesReq) readers.PageMetadata {
+	return readers.PageMetadata{
+		Offset:      req.GetPageMetadata().GetOffset(),
+		Limit:       req.GetPageMetadata().GetLimit(),
+		Domain:      req.GetDomainId(),
+		Comparator:  req.GetPageMetadata().GetComparator(),
+		Aggregation: stringifyAggregation(req.GetPageMetadata().GetAggregation()),
+		From:        req.GetPageMetadata().GetFrom(),
+		To:          req.GetPageMetadata().GetTo(),
+		Interval:    req.GetPageMetadata().GetInterval(),
+		Subtopic:    req.GetPageMetadata().GetSubtopic(),
+		Publisher:   req.GetPageMetadata().GetPublisher(),
+		Protocol:    req.GetPageMetadata().GetProtocol(),
+		Name:        req.GetPageMetadata().GetName(),
+		Value:       req.GetPageMetadata().GetValue(),
+		BoolValue:   req.GetPageMetadata().GetBoolValue(),
+		StringValue: req.GetPageMetadata().GetStringValue(),
+		DataValue:   req.GetPageMetadata().GetDataValue(),
+		Format:      req.GetPageMetadata().GetFormat(),
+		Fill:        req.GetPageMetadata().GetFill(),
+		FillValue:   req.GetPageMetadata().GetFillValue(),
+		Cursor:      req.GetPageMetadata().GetCursor(),
+		SkipTotal:   req.GetPageMetadata().GetSkipTotal(),
+	}
+}
+
 func encodeReadMessagesResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(readMessagesRes)
 
@@ -67,10 +100,23 @@ func encodeReadMessagesResponse(_ context.Context, grpcRes interface{}) (interfa
 			Offset: res.PageMetadata.Offset,
 			Limit:  res.PageMetadata.Limit,
 		},
+		NextCursor: res.NextCursor,
 	}
 	return resp, nil
 }
 
+func encodeReadGroupsResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(readGroupsRes)
+
+	return &grpcReadersV1.ReadGroupsRes{
+		Groups: toResponseGroups(res.Groups),
+		PageMetadata: &grpcReadersV1.PageMetadata{
+			Offset: res.PageMetadata.Offset,
+			Limit:  res.PageMetadata.Limit,
+		},
+	}, nil
+}
+
 func (s *readersGrpcServer) ReadMessages(ctx context.Context, req *grpcReadersV1.ReadMessagesReq) (*grpcReadersV1.ReadMessagesRes, error) {
 	_, res, err := s.readMessages.ServeGRPC(ctx, req)
 	if err != nil {
@@ -79,6 +125,43 @@ func (s *readersGrpcServer) ReadMessages(ctx context.Context, req *grpcReadersV1
 	return res.(*grpcReadersV1.ReadMessagesRes), nil
 }
 
+func (s *readersGrpcServer) ReadLatest(ctx context.Context, req *grpcReadersV1.ReadMessagesReq) (*grpcReadersV1.ReadMessagesRes, error) {
+	_, res, err := s.readLatest.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcapi.EncodeError(err)
+	}
+	return res.(*grpcReadersV1.ReadMessagesRes), nil
+}
+
+func (s *readersGrpcServer) ReadGroups(ctx context.Context, req *grpcReadersV1.ReadMessagesReq) (*grpcReadersV1.ReadGroupsRes, error) {
+	_, res, err := s.readGroups.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcapi.EncodeError(err)
+	}
+	return res.(*grpcReadersV1.ReadGroupsRes), nil
+}
+
+func (s *readersGrpcServer) StreamMessages(req *grpcReadersV1.ReadMessagesReq, stream grpc.ServerStreamingServer[grpcReadersV1.ReadMessagesRes]) error {
+	ctx := stream.Context()
+	r, err := decodeReadMessagesRequest(ctx, req)
+	if err != nil {
+		return grpcapi.EncodeError(err)
+	}
+
+	send := func(res readMessagesRes) error {
+		msg, err := encodeReadMessagesResponse(ctx, res)
+		if err != nil {
+			return err
+		}
+		return stream.Send(msg.(*grpcReadersV1.ReadMessagesRes))
+	}
+	if err := streamMessages(ctx, s.svc, r.(readMessagesReq), send); err != nil {
+		return grpcapi.EncodeError(err)
+	}
+
+	return nil
+}
+
 func toResponseMessages(messages []readers.Message) []*grpcReadersV1.Message {
 	var res []*grpcReadersV1.Message
 	for _, m := range messages {
@@ -130,6 +213,19 @@ func toResponseMessages(messages []readers.Message) []*grpcReadersV1.Message {
 	return res
 }
 
+func toResponseGroups(groups []readers.MessagesGroup) []*grpcReadersV1.MessagesGroup {
+	var res []*grpcReadersV1.MessagesGroup
+	for _, g := range groups {
+		res = append(res, &grpcReadersV1.MessagesGroup{
+			Channel:   g.Channel,
+			Publisher: g.Publisher,
+			Total:     g.Total,
+			Messages:  toResponseMessages(g.Messages),
+		})
+	}
+	return res
+}
+
 func stringifyAggregation(agg grpcReadersV1.Aggregation) string {
 	switch agg {
 	case grpcReadersV1.Aggregation_AGGREGATION_UNSPECIFIED:
@@ -144,6 +240,20 @@ func stringifyAggregation(agg grpcReadersV1.Aggregation) string {
 		return "SUM"
 	case grpcReadersV1.Aggregation_COUNT:
 		return "COUNT"
+	case grpcReadersV1.Aggregation_P95:
+		return "P95"
+	case grpcReadersV1.Aggregation_P99:
+		return "P99"
+	case grpcReadersV1.Aggregation_STDDEV:
+		return "STDDEV"
+	case grpcReadersV1.Aggregation_FIRST:
+		return "FIRST"
+	case grpcReadersV1.Aggregation_LAST:
+		return "LAST"
+	case grpcReadersV1.Aggregation_RATE:
+		return "RATE"
+	case grpcReadersV1.Aggregation_DELTA:
+		return "DELTA"
 	default:
 		return ""
 	}
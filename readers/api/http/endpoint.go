@@ -6,13 +6,14 @@ package http
 import (
 	"context"
 
+	"github.com/absmach/magistrala/readers"
 	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
 	grpcClientsV1 "github.com/absmach/supermq/api/grpc/clients/v1"
 	apiutil "github.com/absmach/supermq/api/http/util"
 	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
 	"github.com/absmach/supermq/pkg/errors"
 	svcerr "github.com/absmach/supermq/pkg/errors/service"
-	"github.com/absmach/supermq/readers"
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -36,6 +37,87 @@ func listMessagesEndpoint(svc readers.MessageRepository, authn smqauthn.Authenti
 			PageMetadata: page.PageMetadata,
 			Total:        page.Total,
 			Messages:     page.Messages,
+			NextCursor:   page.NextCursor,
+		}, nil
+	}
+}
+
+func listLatestEndpoint(svc readers.MessageRepository, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listLatestReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := authnAuthz(ctx, req.listMessagesReq, authn, clients, channels); err != nil {
+			return nil, errors.Wrap(svcerr.ErrAuthorization, err)
+		}
+
+		page, err := svc.ReadLatest(req.chanID, req.pageMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		return pageRes{
+			PageMetadata: page.PageMetadata,
+			Total:        page.Total,
+			Messages:     page.Messages,
+		}, nil
+	}
+}
+
+func listGroupsEndpoint(svc readers.MessageRepository, authn smqauthn.Authentication, authz smqauthz.Authorization, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listGroupsReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := authnAuthzGroups(ctx, req, authn, authz, clients, channels); err != nil {
+			return nil, errors.Wrap(svcerr.ErrAuthorization, err)
+		}
+
+		page, err := svc.ReadGroups(req.domain, req.chanIDs, req.pageMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		return groupsPageRes{
+			PageMetadata: page.PageMetadata,
+			Groups:       page.Groups,
+		}, nil
+	}
+}
+
+func exportMessagesEndpoint(svc readers.MessageRepository, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportMessagesReq)
+		if err := req.validate(); err != nil {
+			return nil, errors.Wrap(apiutil.ErrValidation, err)
+		}
+
+		if err := authnAuthz(ctx, req.listMessagesReq, authn, clients, channels); err != nil {
+			return nil, errors.Wrap(svcerr.ErrAuthorization, err)
+		}
+
+		// The first page is read upfront, so that read errors are still
+		// reported with an error status.
+		pm := req.pageMeta
+		pm.SkipTotal = true
+		page, err := svc.ReadAll(req.chanID, pm)
+		if err != nil {
+			return nil, err
+		}
+
+		return exportRes{
+			fileFormat: req.fileFormat,
+			isSenML:    pm.Format == defFormat,
+			page:       page,
+			next: func(cursor string) (readers.MessagesPage, error) {
+				pm.Offset = 0
+				pm.Cursor = cursor
+				return svc.ReadAll(req.chanID, pm)
+			},
 		}, nil
 	}
 }
@@ -4,15 +4,20 @@
 package http_test
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/readers"
 	customhttp "github.com/absmach/magistrala/readers/api/http"
+	"github.com/absmach/magistrala/readers/mocks"
 	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
 	grpcClientsV1 "github.com/absmach/supermq/api/grpc/clients/v1"
 	apiutil "github.com/absmach/supermq/api/http/util"
@@ -20,10 +25,10 @@ import (
 	climocks "github.com/absmach/supermq/clients/mocks"
 	smqauthn "github.com/absmach/supermq/pkg/authn"
 	authnmocks "github.com/absmach/supermq/pkg/authn/mocks"
+	authzmocks "github.com/absmach/supermq/pkg/authz/mocks"
 	svcerr "github.com/absmach/supermq/pkg/errors/service"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
-	"github.com/absmach/supermq/readers/mocks"
+	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -54,8 +59,8 @@ var (
 	validSession         = smqauthn.Session{UserID: testsutil.GenerateUUID(&testing.T{})}
 )
 
-func newServer(repo *mocks.MessageRepository, authn *authnmocks.Authentication, clients *climocks.ClientsServiceClient, channels *chmocks.ChannelsServiceClient) *httptest.Server {
-	mux := customhttp.MakeHandler(repo, authn, clients, channels, svcName, instanceID)
+func newServer(repo *mocks.MessageRepository, authn *authnmocks.Authentication, authz *authzmocks.Authorization, clients *climocks.ClientsServiceClient, channels *chmocks.ChannelsServiceClient) *httptest.Server {
+	mux := customhttp.MakeHandler(repo, authn, authz, clients, channels, svcName, instanceID)
 	return httptest.NewServer(mux)
 }
 
@@ -132,11 +137,13 @@ func TestReadAll(t *testing.T) {
 		messages = append(messages, msg)
 	}
 
+	cursor := "WzE3MDAwMDAwMDAsInB1Ymxpc2hlciJd"
+
 	repo := new(mocks.MessageRepository)
 	authn := new(authnmocks.Authentication)
 	clients := new(climocks.ClientsServiceClient)
 	channels := new(chmocks.ChannelsServiceClient)
-	ts := newServer(repo, authn, clients, channels)
+	ts := newServer(repo, authn, new(authzmocks.Authorization), clients, channels)
 	defer ts.Close()
 
 	cases := []struct {
@@ -175,6 +182,61 @@ func TestReadAll(t *testing.T) {
 				Messages:     messages[0:10],
 			},
 		},
+		{
+			desc:         "read page with payload filters and fields",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=format1&filter=payload.engine.rpm%%3E3000&filter=payload.gps.fix=true&fields=engine.rpm,gps", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{
+					Limit:  10,
+					Format: "format1",
+					PayloadFilters: []readers.PayloadFilter{
+						{Path: []string{"engine", "rpm"}, Operator: readers.PayloadGreaterThan, Value: 3000.0},
+						{Path: []string{"gps", "fix"}, Operator: readers.PayloadEqual, Value: true},
+					},
+					Fields: []string{"engine.rpm", "gps"},
+				},
+				Total:    uint64(len(messages)),
+				Messages: messages[0:10],
+			},
+		},
+		{
+			desc:         "read page with payload filter without payload prefix",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=format1&filter=engine.rpm%%3E3000", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with payload filter without operator",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=format1&filter=payload.engine.rpm", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with range payload filter on boolean",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=format1&filter=payload.gps.fix%%3Etrue", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with invalid payload field",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=format1&fields=engine..rpm", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read SenML page with payload filter",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?filter=payload.engine.rpm%%3E3000", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
 		{
 			desc:         "read page with negative offset as client",
 			url:          fmt.Sprintf("%s/%s/channels/%s/messages?offset=-1&limit=10", ts.URL, domainID, chanID),
@@ -529,6 +591,58 @@ func TestReadAll(t *testing.T) {
 				Messages:     messages[5:15],
 			},
 		},
+		{
+			desc:         "read page with aggregation of JSON format as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?format=json&aggregation=MAX&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with percentile aggregation, interval, to and from as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=P95&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Aggregation: "P95", Interval: "10h", From: messages[19].Time, To: messages[4].Time},
+				Total:        uint64(len(messages[5:20])),
+				Messages:     messages[5:15],
+			},
+		},
+		{
+			desc:         "read page with aggregation, interval, fill, to and from as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=MAX&interval=10h&fill=constant&fill_value=5&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Aggregation: "MAX", Interval: "10h", Fill: "constant", FillValue: 5, From: messages[19].Time, To: messages[4].Time},
+				Total:        uint64(len(messages[5:20])),
+				Messages:     messages[5:15],
+			},
+		},
+		{
+			desc:         "read page with invalid fill as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=MAX&interval=10h&fill=invalid&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with fill without aggregation as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?fill=linear", ts.URL, domainID, chanID),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with invalid fill value as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=MAX&interval=10h&fill=constant&fill_value=abc&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
 		{
 			desc:         "read page with invalid aggregation and valid interval, to and from as client",
 			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=invalid&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
@@ -937,6 +1051,18 @@ func TestReadAll(t *testing.T) {
 				Messages:     messages[5:15],
 			},
 		},
+		{
+			desc:         "read page with percentile aggregation, interval, to and from as user",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=P95&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
+			key:          userToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Aggregation: "P95", Interval: "10h", From: messages[19].Time, To: messages[4].Time},
+				Total:        uint64(len(messages[5:20])),
+				Messages:     messages[5:15],
+			},
+		},
 		{
 			desc:         "read page with invalid aggregation and valid interval, to and from as user",
 			url:          fmt.Sprintf("%s/%s/channels/%s/messages?aggregation=invalid&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, messages[19].Time, messages[4].Time),
@@ -972,6 +1098,52 @@ func TestReadAll(t *testing.T) {
 			authResponse: true,
 			status:       http.StatusBadRequest,
 		},
+		{
+			desc:         "read page with cursor as user",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?limit=10&cursor=%s", ts.URL, domainID, chanID, cursor),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Cursor: cursor},
+				Total:        uint64(len(messages)),
+				Messages:     messages[10:20],
+				NextCursor:   cursor,
+			},
+		},
+		{
+			desc:         "read page with cursor and skip total as client",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?limit=10&cursor=%s&skip_total=true", ts.URL, domainID, chanID, cursor),
+			key:          clientToken,
+			authResponse: true,
+			status:       http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Cursor: cursor, SkipTotal: true},
+				Messages:     messages[10:20],
+				NextCursor:   cursor,
+			},
+		},
+		{
+			desc:         "read page with cursor and offset as user",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?offset=10&cursor=%s", ts.URL, domainID, chanID, cursor),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with cursor and aggregation as user",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?cursor=%s&aggregation=MAX&interval=10h&from=%f&to=%f", ts.URL, domainID, chanID, cursor, messages[19].Time, messages[4].Time),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
+		{
+			desc:         "read page with invalid skip total as user",
+			url:          fmt.Sprintf("%s/%s/channels/%s/messages?skip_total=invalid", ts.URL, domainID, chanID),
+			token:        userToken,
+			authResponse: true,
+			status:       http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range cases {
@@ -982,7 +1154,7 @@ func TestReadAll(t *testing.T) {
 			}).Return(&grpcClientsV1.AuthnRes{Id: testsutil.GenerateUUID(t), Authenticated: true}, tc.authnErr)
 		}
 		authzCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(&grpcChannelsV1.AuthzRes{Authorized: true}, tc.err)
-		repoCall := repo.On("ReadAll", chanID, tc.res.PageMetadata).Return(readers.MessagesPage{Total: tc.res.Total, Messages: fromSenml(tc.res.Messages)}, nil)
+		repoCall := repo.On("ReadAll", chanID, withDomain(tc.res.PageMetadata)).Return(readers.MessagesPage{Total: tc.res.Total, Messages: fromSenml(tc.res.Messages), NextCursor: tc.res.NextCursor}, nil)
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
@@ -1000,16 +1172,537 @@ func TestReadAll(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.Equal(t, tc.res.Total, page.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.res.Total, page.Total))
 		assert.ElementsMatch(t, tc.res.Messages, page.Messages, fmt.Sprintf("%s: got incorrect body from response", tc.desc))
+		assert.Equal(t, tc.res.NextCursor, page.NextCursor, fmt.Sprintf("%s: expected cursor %s got %s", tc.desc, tc.res.NextCursor, page.NextCursor))
 		authzCall.Unset()
 		authnCall.Unset()
 		repoCall.Unset()
 	}
 }
 
+func TestReadLatest(t *testing.T) {
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := time.Now().Unix()
+
+	messages := []senml.Message{
+		{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(now),
+			Value:     &v,
+		},
+		{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Subtopic:  subtopic,
+			Name:      "humidity",
+			Time:      float64(now - 1),
+			Value:     &sum,
+		},
+	}
+
+	repo := new(mocks.MessageRepository)
+	authn := new(authnmocks.Authentication)
+	clients := new(climocks.ClientsServiceClient)
+	channels := new(chmocks.ChannelsServiceClient)
+	ts := newServer(repo, authn, new(authzmocks.Authorization), clients, channels)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		url      string
+		token    string
+		key      string
+		pm       readers.PageMetadata
+		status   int
+		res      pageRes
+		authnErr error
+		authzErr error
+	}{
+		{
+			desc:   "read latest messages as user",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest", ts.URL, domainID, chanID),
+			token:  userToken,
+			pm:     readers.PageMetadata{Limit: 10, Format: "messages"},
+			status: http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages"},
+				Total:        uint64(len(messages)),
+				Messages:     messages,
+			},
+		},
+		{
+			desc:   "read latest messages as client",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest", ts.URL, domainID, chanID),
+			key:    clientToken,
+			pm:     readers.PageMetadata{Limit: 10, Format: "messages"},
+			status: http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages"},
+				Total:        uint64(len(messages)),
+				Messages:     messages,
+			},
+		},
+		{
+			desc:   "read latest messages with name and subtopic",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest?name=humidity&subtopic=%s", ts.URL, domainID, chanID, subtopic),
+			token:  userToken,
+			pm:     readers.PageMetadata{Limit: 10, Format: "messages", Name: "humidity", Subtopic: subtopic},
+			status: http.StatusOK,
+			res: pageRes{
+				PageMetadata: readers.PageMetadata{Limit: 10, Format: "messages", Name: "humidity", Subtopic: subtopic},
+				Total:        1,
+				Messages:     messages[1:],
+			},
+		},
+		{
+			desc:   "read latest messages with aggregation",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest?aggregation=MAX&interval=1h&from=1&to=2", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read latest messages with JSON format",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest?format=format1", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read latest messages with invalid limit",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest?limit=0", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read latest messages without credentials",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/latest", ts.URL, domainID, chanID),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:     "read latest messages of unauthorized channel",
+			url:      fmt.Sprintf("%s/%s/channels/%s/messages/latest", ts.URL, domainID, chanID),
+			token:    userToken,
+			pm:       readers.PageMetadata{Limit: 10, Format: "messages"},
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		authnCall := authn.On("Authenticate", mock.Anything, tc.token).Return(validSession, tc.authnErr)
+		clientsCall := clients.On("Authenticate", mock.Anything, &grpcClientsV1.AuthnReq{
+			ClientSecret: tc.key,
+		}).Return(&grpcClientsV1.AuthnRes{Id: testsutil.GenerateUUID(t), Authenticated: true}, tc.authnErr)
+		authzCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(&grpcChannelsV1.AuthzRes{Authorized: tc.authzErr == nil}, tc.authzErr)
+		repoCall := repo.On("ReadLatest", chanID, withDomain(tc.pm)).Return(readers.MessagesPage{PageMetadata: tc.res.PageMetadata, Total: tc.res.Total, Messages: fromSenml(tc.res.Messages)}, nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+			key:    tc.key,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var page pageRes
+			err = json.NewDecoder(res.Body).Decode(&page)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error while decoding response body: %s", tc.desc, err))
+			assert.Equal(t, tc.res.Total, page.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.res.Total, page.Total))
+			assert.ElementsMatch(t, tc.res.Messages, page.Messages, fmt.Sprintf("%s: got incorrect body from response", tc.desc))
+		}
+		authnCall.Unset()
+		clientsCall.Unset()
+		authzCall.Unset()
+		repoCall.Unset()
+	}
+}
+
+func TestReadGroups(t *testing.T) {
+	chanID := testsutil.GenerateUUID(t)
+	chanID2 := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	now := time.Now().Unix()
+
+	var messages []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Time:      float64(now - int64(i)),
+			Name:      "name",
+			Value:     &v,
+		})
+	}
+
+	repo := new(mocks.MessageRepository)
+	authn := new(authnmocks.Authentication)
+	authz := new(authzmocks.Authorization)
+	clients := new(climocks.ClientsServiceClient)
+	channels := new(chmocks.ChannelsServiceClient)
+	ts := newServer(repo, authn, authz, clients, channels)
+	defer ts.Close()
+
+	groups := []readers.MessagesGroup{
+		{
+			Channel:   chanID,
+			Publisher: pubID,
+			Total:     uint64(len(messages)),
+			Messages:  fromSenml(messages[0:10]),
+		},
+	}
+
+	cases := []struct {
+		desc     string
+		url      string
+		token    string
+		key      string
+		chanIDs  []string
+		pm       readers.PageMetadata
+		status   int
+		authnErr error
+		authzErr error
+		chanErr  error
+	}{
+		{
+			desc:    "read groups of channels as user",
+			url:     fmt.Sprintf("%s/%s/messages?channel=%s&channel=%s", ts.URL, domainID, chanID, chanID2),
+			token:   userToken,
+			chanIDs: []string{chanID, chanID2},
+			pm:      readers.PageMetadata{Limit: 10, Format: "messages"},
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "read groups of channels as client",
+			url:     fmt.Sprintf("%s/%s/messages?channel=%s", ts.URL, domainID, chanID),
+			key:     clientToken,
+			chanIDs: []string{chanID},
+			pm:      readers.PageMetadata{Limit: 10, Format: "messages"},
+			status:  http.StatusOK,
+		},
+		{
+			desc:   "read groups of domain as user",
+			url:    fmt.Sprintf("%s/%s/messages?limit=5&publisher=%s", ts.URL, domainID, pubID),
+			token:  userToken,
+			pm:     readers.PageMetadata{Limit: 5, Format: "messages", Publisher: pubID},
+			status: http.StatusOK,
+		},
+		{
+			desc:   "read groups of domain as client",
+			url:    fmt.Sprintf("%s/%s/messages", ts.URL, domainID),
+			key:    clientToken,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:     "read groups of domain as unauthorized user",
+			url:      fmt.Sprintf("%s/%s/messages", ts.URL, domainID),
+			token:    userToken,
+			pm:       readers.PageMetadata{Limit: 10, Format: "messages"},
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusUnauthorized,
+		},
+		{
+			desc:     "read groups with invalid token",
+			url:      fmt.Sprintf("%s/%s/messages?channel=%s", ts.URL, domainID, chanID),
+			token:    invalidToken,
+			chanIDs:  []string{chanID},
+			pm:       readers.PageMetadata{Limit: 10, Format: "messages"},
+			authnErr: svcerr.ErrAuthentication,
+			status:   http.StatusUnauthorized,
+		},
+		{
+			desc:    "read groups of unauthorized channel",
+			url:     fmt.Sprintf("%s/%s/messages?channel=%s", ts.URL, domainID, chanID),
+			token:   userToken,
+			chanIDs: []string{chanID},
+			pm:      readers.PageMetadata{Limit: 10, Format: "messages"},
+			chanErr: svcerr.ErrAuthorization,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			desc:   "read groups with empty channel",
+			url:    fmt.Sprintf("%s/%s/messages?channel=", ts.URL, domainID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read groups with aggregation",
+			url:    fmt.Sprintf("%s/%s/messages?channel=%s&aggregation=MAX&interval=10h&from=1&to=2", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read groups with limit too large",
+			url:    fmt.Sprintf("%s/%s/messages?channel=%s&limit=1001", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read groups without credentials",
+			url:    fmt.Sprintf("%s/%s/messages?channel=%s", ts.URL, domainID, chanID),
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		authnCall := authn.On("Authenticate", mock.Anything, tc.token).Return(validSession, tc.authnErr)
+		clientsCall := clients.On("Authenticate", mock.Anything, &grpcClientsV1.AuthnReq{
+			ClientSecret: tc.key,
+		}).Return(&grpcClientsV1.AuthnRes{Id: testsutil.GenerateUUID(t), Authenticated: true}, tc.authnErr)
+		authzCall := authz.On("Authorize", mock.Anything, mock.Anything).Return(tc.authzErr)
+		chanCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(&grpcChannelsV1.AuthzRes{Authorized: tc.chanErr == nil}, tc.chanErr)
+		repoCall := repo.On("ReadGroups", domainID, tc.chanIDs, withDomain(tc.pm)).Return(readers.GroupsPage{PageMetadata: tc.pm, Groups: groups}, nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+			key:    tc.key,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			var page groupsPageRes
+			err = json.NewDecoder(res.Body).Decode(&page)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error while decoding response body: %s", tc.desc, err))
+			assert.Len(t, page.Groups, len(groups), fmt.Sprintf("%s: got incorrect groups from response", tc.desc))
+			assert.Equal(t, groups[0].Total, page.Groups[0].Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, groups[0].Total, page.Groups[0].Total))
+			assert.ElementsMatch(t, messages[0:10], page.Groups[0].Messages, fmt.Sprintf("%s: got incorrect body from response", tc.desc))
+		}
+		authnCall.Unset()
+		clientsCall.Unset()
+		authzCall.Unset()
+		chanCall.Unset()
+		repoCall.Unset()
+	}
+}
+
+func TestExport(t *testing.T) {
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := time.Now().Unix()
+
+	var messages []senml.Message
+	for i := 0; i < 3; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(now - int64(i)),
+			Value:     &v,
+		})
+	}
+	jsonMsg := map[string]interface{}{
+		"channel":   chanID,
+		"publisher": pubID,
+		"protocol":  mqttProt,
+		"created":   now,
+		"payload":   map[string]interface{}{"rpm": 3000.0},
+	}
+
+	senmlPM := readers.PageMetadata{Limit: 1000, Domain: domainID, Format: "messages", SkipTotal: true}
+	nextPM := senmlPM
+	nextPM.Cursor = "next"
+	jsonPM := readers.PageMetadata{Limit: 1000, Domain: domainID, Format: "format1", SkipTotal: true}
+	pages := map[string]readers.MessagesPage{
+		"messages": {PageMetadata: senmlPM, Messages: fromSenml(messages[:2]), NextCursor: "next"},
+		"next":     {PageMetadata: nextPM, Messages: fromSenml(messages[2:])},
+		"format1":  {PageMetadata: jsonPM, Messages: []readers.Message{jsonMsg}},
+	}
+
+	repo := new(mocks.MessageRepository)
+	authn := new(authnmocks.Authentication)
+	clients := new(climocks.ClientsServiceClient)
+	channels := new(chmocks.ChannelsServiceClient)
+	ts := newServer(repo, authn, new(authzmocks.Authorization), clients, channels)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		url         string
+		token       string
+		key         string
+		nextErr     error
+		status      int
+		contentType string
+		body        func(t *testing.T, body []byte)
+		readErr     bool
+		authzErr    error
+	}{
+		{
+			desc:        "export SenML messages as CSV",
+			url:         fmt.Sprintf("%s/%s/channels/%s/messages/export?format=csv", ts.URL, domainID, chanID),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body: func(t *testing.T, body []byte) {
+				records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+				assert.Nil(t, err, fmt.Sprintf("unexpected error parsing CSV: %s", err))
+				assert.Len(t, records, len(messages)+1)
+				assert.Equal(t, []string{"channel", "subtopic", "publisher", "protocol", "name", "unit", "time", "update_time", "value", "string_value", "bool_value", "data_value", "sum"}, records[0])
+				assert.Equal(t, pubID, records[1][2])
+				assert.Equal(t, "5", records[1][8])
+			},
+		},
+		{
+			desc:        "export SenML messages as CSV by default",
+			url:         fmt.Sprintf("%s/%s/channels/%s/messages/export", ts.URL, domainID, chanID),
+			key:         clientToken,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body: func(t *testing.T, body []byte) {
+				assert.Equal(t, len(messages)+1, bytes.Count(body, []byte("\n")))
+			},
+		},
+		{
+			desc:        "export SenML messages as NDJSON",
+			url:         fmt.Sprintf("%s/%s/channels/%s/messages/export?format=ndjson", ts.URL, domainID, chanID),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			body: func(t *testing.T, body []byte) {
+				dec := json.NewDecoder(bytes.NewReader(body))
+				var got []senml.Message
+				for dec.More() {
+					var msg senml.Message
+					err := dec.Decode(&msg)
+					assert.Nil(t, err, fmt.Sprintf("unexpected error decoding NDJSON: %s", err))
+					got = append(got, msg)
+				}
+				assert.Equal(t, messages, got)
+			},
+		},
+		{
+			desc:        "export SenML messages as Parquet",
+			url:         fmt.Sprintf("%s/%s/channels/%s/messages/export?format=parquet", ts.URL, domainID, chanID),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "application/vnd.apache.parquet",
+			body: func(t *testing.T, body []byte) {
+				rows, err := parquet.Read[struct {
+					Publisher string   `parquet:"publisher"`
+					Value     *float64 `parquet:"value,optional"`
+				}](bytes.NewReader(body), int64(len(body)))
+				assert.Nil(t, err, fmt.Sprintf("unexpected error reading Parquet: %s", err))
+				assert.Len(t, rows, len(messages))
+				for _, row := range rows {
+					assert.Equal(t, pubID, row.Publisher)
+					assert.Equal(t, &v, row.Value)
+				}
+			},
+		},
+		{
+			desc:        "export JSON messages as CSV",
+			url:         fmt.Sprintf("%s/%s/channels/%s/messages/export?format=csv&message_format=format1", ts.URL, domainID, chanID),
+			token:       userToken,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body: func(t *testing.T, body []byte) {
+				records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+				assert.Nil(t, err, fmt.Sprintf("unexpected error parsing CSV: %s", err))
+				assert.Equal(t, [][]string{
+					{"channel", "subtopic", "publisher", "protocol", "created", "payload"},
+					{chanID, "", pubID, mqttProt, fmt.Sprint(now), `{"rpm":3000}`},
+				}, records)
+			},
+		},
+		{
+			desc:    "export messages with failing second page",
+			url:     fmt.Sprintf("%s/%s/channels/%s/messages/export?format=ndjson", ts.URL, domainID, chanID),
+			token:   userToken,
+			nextErr: readers.ErrReadMessages,
+			status:  http.StatusOK,
+			readErr: true,
+		},
+		{
+			desc:   "export messages with invalid file format",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/export?format=xml", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export messages with aggregation",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/export?aggregation=MAX&interval=1h&from=1&to=2", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export messages with cursor",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/export?cursor=next", ts.URL, domainID, chanID),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export messages without credentials",
+			url:    fmt.Sprintf("%s/%s/channels/%s/messages/export", ts.URL, domainID, chanID),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:     "export messages of unauthorized channel",
+			url:      fmt.Sprintf("%s/%s/channels/%s/messages/export", ts.URL, domainID, chanID),
+			token:    userToken,
+			authzErr: svcerr.ErrAuthorization,
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		authnCall := authn.On("Authenticate", mock.Anything, tc.token).Return(validSession, nil)
+		clientsCall := clients.On("Authenticate", mock.Anything, &grpcClientsV1.AuthnReq{
+			ClientSecret: tc.key,
+		}).Return(&grpcClientsV1.AuthnRes{Id: testsutil.GenerateUUID(t), Authenticated: true}, nil)
+		authzCall := channels.On("Authorize", mock.Anything, mock.Anything).Return(&grpcChannelsV1.AuthzRes{Authorized: tc.authzErr == nil}, tc.authzErr)
+		senmlCall := repo.On("ReadAll", chanID, senmlPM).Return(pages["messages"], nil)
+		nextCall := repo.On("ReadAll", chanID, nextPM).Return(pages["next"], tc.nextErr)
+		jsonCall := repo.On("ReadAll", chanID, jsonPM).Return(pages["format1"], nil)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+			key:    tc.key,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			body, err := io.ReadAll(res.Body)
+			assert.Equal(t, tc.readErr, err != nil, fmt.Sprintf("%s: unexpected read error %v", tc.desc, err))
+			if tc.body != nil {
+				assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: got incorrect content type", tc.desc))
+				tc.body(t, body)
+			}
+		}
+		res.Body.Close()
+		authnCall.Unset()
+		clientsCall.Unset()
+		authzCall.Unset()
+		senmlCall.Unset()
+		nextCall.Unset()
+		jsonCall.Unset()
+	}
+}
+
 type pageRes struct {
 	readers.PageMetadata
-	Total    uint64          `json:"total"`
-	Messages []senml.Message `json:"messages"`
+	Total      uint64          `json:"total"`
+	Messages   []senml.Message `json:"messages"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// withDomain returns the page metadata of the reads of the domain of the
+// request path.
+func withDomain(pm readers.PageMetadata) readers.PageMetadata {
+	pm.Domain = domainID
+	return pm
 }
 
 func fromSenml(in []senml.Message) []readers.Message {
@@ -1019,3 +1712,13 @@ func fromSenml(in []senml.Message) []readers.Message {
 	}
 	return ret
 }
+
+type groupsPageRes struct {
+	readers.PageMetadata
+	Groups []struct {
+		Channel   string          `json:"channel"`
+		Publisher string          `json:"publisher"`
+		Total     uint64          `json:"total"`
+		Messages  []senml.Message `json:"messages"`
+	} `json:"groups"`
+}
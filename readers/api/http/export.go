@@ -0,0 +1,232 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/absmach/magistrala/readers"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Supported export file formats.
+const (
+	csvFormat     = "csv"
+	ndjsonFormat  = "ndjson"
+	parquetFormat = "parquet"
+)
+
+var exportContentTypes = map[string]string{
+	csvFormat:     "text/csv",
+	ndjsonFormat:  "application/x-ndjson",
+	parquetFormat: "application/vnd.apache.parquet",
+}
+
+var (
+	senmlColumns = []string{"channel", "subtopic", "publisher", "protocol", "name", "unit", "time", "update_time", "value", "string_value", "bool_value", "data_value", "sum"}
+	jsonColumns  = []string{"channel", "subtopic", "publisher", "protocol", "created", "payload"}
+)
+
+// exportWriter writes batches of exported messages to the response.
+type exportWriter interface {
+	write(msgs []readers.Message) error
+	close() error
+}
+
+// newExportWriter returns the writer of the file format. Messages are SenML
+// messages when isSenML is set and JSON messages otherwise.
+func newExportWriter(w io.Writer, fileFormat string, isSenML bool) exportWriter {
+	switch fileFormat {
+	case ndjsonFormat:
+		return ndjsonWriter{enc: json.NewEncoder(w)}
+	case parquetFormat:
+		if isSenML {
+			return &parquetWriter[senmlRow]{w: parquet.NewGenericWriter[senmlRow](w), row: toSenMLRow}
+		}
+		return &parquetWriter[jsonRow]{w: parquet.NewGenericWriter[jsonRow](w), row: toJSONRow}
+	default:
+		cols := jsonColumns
+		if isSenML {
+			cols = senmlColumns
+		}
+		return &csvWriter{w: csv.NewWriter(w), header: cols}
+	}
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func (cw *csvWriter) write(msgs []readers.Message) error {
+	if cw.header != nil {
+		if err := cw.w.Write(cw.header); err != nil {
+			return err
+		}
+		cw.header = nil
+	}
+
+	for _, msg := range msgs {
+		var record []string
+		switch m := msg.(type) {
+		case senml.Message:
+			r := toSenMLRow(m)
+			record = []string{
+				r.Channel, r.Subtopic, r.Publisher, r.Protocol, r.Name, r.Unit,
+				formatFloat(&r.Time), formatFloat(r.UpdateTime), formatFloat(r.Value),
+				formatString(r.StringValue), formatBool(r.BoolValue), formatString(r.DataValue), formatFloat(r.Sum),
+			}
+		default:
+			r := toJSONRow(m)
+			record = []string{r.Channel, r.Subtopic, r.Publisher, r.Protocol, strconv.FormatInt(r.Created, 10), r.Payload}
+		}
+		if err := cw.w.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) close() error {
+	// Empty exports still carry the header.
+	return cw.write(nil)
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (nw ndjsonWriter) write(msgs []readers.Message) error {
+	for _, msg := range msgs {
+		if err := nw.enc.Encode(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (nw ndjsonWriter) close() error {
+	return nil
+}
+
+type parquetWriter[T any] struct {
+	w   *parquet.GenericWriter[T]
+	row func(readers.Message) T
+	buf []T
+}
+
+func (pw *parquetWriter[T]) write(msgs []readers.Message) error {
+	pw.buf = pw.buf[:0]
+	for _, msg := range msgs {
+		pw.buf = append(pw.buf, pw.row(msg))
+	}
+	_, err := pw.w.Write(pw.buf)
+
+	return err
+}
+
+func (pw *parquetWriter[T]) close() error {
+	return pw.w.Close()
+}
+
+type senmlRow struct {
+	Channel     string   `parquet:"channel"`
+	Subtopic    string   `parquet:"subtopic"`
+	Publisher   string   `parquet:"publisher"`
+	Protocol    string   `parquet:"protocol"`
+	Name        string   `parquet:"name"`
+	Unit        string   `parquet:"unit"`
+	Time        float64  `parquet:"time"`
+	UpdateTime  *float64 `parquet:"update_time,optional"`
+	Value       *float64 `parquet:"value,optional"`
+	StringValue *string  `parquet:"string_value,optional"`
+	BoolValue   *bool    `parquet:"bool_value,optional"`
+	DataValue   *string  `parquet:"data_value,optional"`
+	Sum         *float64 `parquet:"sum,optional"`
+}
+
+func toSenMLRow(msg readers.Message) senmlRow {
+	m, _ := msg.(senml.Message)
+	row := senmlRow{
+		Channel:     m.Channel,
+		Subtopic:    m.Subtopic,
+		Publisher:   m.Publisher,
+		Protocol:    m.Protocol,
+		Name:        m.Name,
+		Unit:        m.Unit,
+		Time:        m.Time,
+		Value:       m.Value,
+		StringValue: m.StringValue,
+		BoolValue:   m.BoolValue,
+		DataValue:   m.DataValue,
+		Sum:         m.Sum,
+	}
+	if m.UpdateTime != 0 {
+		row.UpdateTime = &m.UpdateTime
+	}
+
+	return row
+}
+
+// jsonRow holds a JSON message with the payload kept as JSON text, since
+// payloads don't share a schema.
+type jsonRow struct {
+	Channel   string `parquet:"channel"`
+	Subtopic  string `parquet:"subtopic"`
+	Publisher string `parquet:"publisher"`
+	Protocol  string `parquet:"protocol"`
+	Created   int64  `parquet:"created"`
+	Payload   string `parquet:"payload"`
+}
+
+func toJSONRow(msg readers.Message) jsonRow {
+	m, _ := msg.(map[string]interface{})
+	row := jsonRow{
+		Channel:   stringField(m["channel"]),
+		Subtopic:  stringField(m["subtopic"]),
+		Publisher: stringField(m["publisher"]),
+		Protocol:  stringField(m["protocol"]),
+	}
+	if created, ok := m["created"].(int64); ok {
+		row.Created = created
+	}
+	if payload, err := json.Marshal(m["payload"]); err == nil {
+		row.Payload = string(payload)
+	}
+
+	return row
+}
+
+func stringField(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func formatString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func formatBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
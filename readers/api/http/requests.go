@@ -8,13 +8,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/absmach/magistrala/readers"
 	apiutil "github.com/absmach/supermq/api/http/util"
-	"github.com/absmach/supermq/readers"
 )
 
-const maxLimitSize = 1000
+const (
+	maxLimitSize    = 1000
+	maxChannelsSize = 100
+)
+
+var validAggregations = []string{"MAX", "MIN", "AVG", "SUM", "COUNT", "P95", "P99", "STDDEV", "FIRST", "LAST", "RATE", "DELTA"}
 
-var validAggregations = []string{"MAX", "MIN", "AVG", "SUM", "COUNT"}
+var validFills = []string{readers.FillNone, readers.FillNull, readers.FillPrevious, readers.FillLinear, readers.FillConstant}
 
 type listMessagesReq struct {
 	chanID   string
@@ -62,6 +67,145 @@ func (req listMessagesReq) validate() error {
 		if _, err := time.ParseDuration(req.pageMeta.Interval); err != nil {
 			return apiutil.ErrInvalidInterval
 		}
+
+		// Aggregation applies to SenML messages only, since JSON messages
+		// have no common value column.
+		if req.pageMeta.Format != defFormat {
+			return apiutil.ErrInvalidAggregation
+		}
+	}
+
+	if req.pageMeta.Fill != "" {
+		if !slices.Contains(validFills, req.pageMeta.Fill) {
+			return readers.ErrInvalidFill
+		}
+
+		if req.pageMeta.Fill != readers.FillNone && req.pageMeta.Aggregation == "" {
+			return readers.ErrInvalidFill
+		}
+	}
+
+	// Cursor replaces offset and pages raw messages only.
+	if req.pageMeta.Cursor != "" && (req.pageMeta.Offset != 0 || req.pageMeta.Aggregation != "") {
+		return readers.ErrInvalidCursor
+	}
+
+	return validatePayloadQuery(req.pageMeta)
+}
+
+type listLatestReq struct {
+	listMessagesReq
+}
+
+func (req listLatestReq) validate() error {
+	if err := req.listMessagesReq.validate(); err != nil {
+		return err
+	}
+
+	// Latest values are read from raw SenML messages only.
+	if req.pageMeta.Aggregation != "" {
+		return apiutil.ErrInvalidAggregation
+	}
+
+	if req.pageMeta.Format != defFormat {
+		return apiutil.ErrInvalidQueryParams
+	}
+
+	if req.pageMeta.Cursor != "" {
+		return readers.ErrInvalidCursor
+	}
+
+	return nil
+}
+
+type listGroupsReq struct {
+	chanIDs  []string
+	token    string
+	domain   string
+	key      string
+	pageMeta readers.PageMetadata
+}
+
+func (req listGroupsReq) validate() error {
+	if req.token == "" && req.key == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.domain == "" {
+		return apiutil.ErrMissingDomainID
+	}
+
+	// Reading all the channels of a domain requires a user token.
+	if len(req.chanIDs) == 0 && req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if len(req.chanIDs) > maxChannelsSize {
+		return apiutil.ErrLimitSize
+	}
+
+	for _, id := range req.chanIDs {
+		if id == "" {
+			return apiutil.ErrMissingID
+		}
+	}
+
+	if req.pageMeta.Limit < 1 || req.pageMeta.Limit > maxLimitSize {
+		return apiutil.ErrLimitSize
+	}
+
+	if req.pageMeta.Comparator != "" &&
+		req.pageMeta.Comparator != readers.EqualKey &&
+		req.pageMeta.Comparator != readers.LowerThanKey &&
+		req.pageMeta.Comparator != readers.LowerThanEqualKey &&
+		req.pageMeta.Comparator != readers.GreaterThanKey &&
+		req.pageMeta.Comparator != readers.GreaterThanEqualKey {
+		return apiutil.ErrInvalidComparator
+	}
+
+	// Groups contain raw messages only.
+	if req.pageMeta.Aggregation != "" {
+		return apiutil.ErrInvalidAggregation
+	}
+
+	if req.pageMeta.Cursor != "" {
+		return readers.ErrInvalidCursor
+	}
+
+	return validatePayloadQuery(req.pageMeta)
+}
+
+type exportMessagesReq struct {
+	listMessagesReq
+	fileFormat string
+}
+
+func (req exportMessagesReq) validate() error {
+	if err := req.listMessagesReq.validate(); err != nil {
+		return err
+	}
+
+	if _, ok := exportContentTypes[req.fileFormat]; !ok {
+		return apiutil.ErrInvalidQueryParams
+	}
+
+	// Exports contain raw messages read from the start.
+	if req.pageMeta.Aggregation != "" {
+		return apiutil.ErrInvalidAggregation
+	}
+
+	if req.pageMeta.Cursor != "" {
+		return readers.ErrInvalidCursor
+	}
+
+	return nil
+}
+
+// validatePayloadQuery checks that payload filters and fields are used with
+// JSON messages, since SenML messages have no payload.
+func validatePayloadQuery(pm readers.PageMetadata) error {
+	if (len(pm.PayloadFilters) > 0 || len(pm.Fields) > 0) && pm.Format == defFormat {
+		return readers.ErrInvalidPayloadFilter
 	}
 
 	return nil
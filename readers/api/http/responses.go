@@ -6,16 +6,17 @@ package http
 import (
 	"net/http"
 
+	"github.com/absmach/magistrala/readers"
 	"github.com/absmach/supermq"
-	"github.com/absmach/supermq/readers"
 )
 
 var _ supermq.Response = (*pageRes)(nil)
 
 type pageRes struct {
 	readers.PageMetadata
-	Total    uint64            `json:"total"`
-	Messages []readers.Message `json:"messages"`
+	Total      uint64            `json:"total"`
+	Messages   []readers.Message `json:"messages"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 func (res pageRes) Headers() map[string]string {
@@ -29,3 +30,30 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+var _ supermq.Response = (*groupsPageRes)(nil)
+
+type groupsPageRes struct {
+	readers.PageMetadata
+	Groups []readers.MessagesGroup `json:"groups"`
+}
+
+func (res groupsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res groupsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res groupsPageRes) Empty() bool {
+	return false
+}
+
+// exportRes streams the exported messages page by page.
+type exportRes struct {
+	fileFormat string
+	isSenML    bool
+	page       readers.MessagesPage
+	next       func(cursor string) (readers.MessagesPage, error)
+}
@@ -6,23 +6,29 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/absmach/magistrala/readers"
 	"github.com/absmach/supermq"
 	grpcChannelsV1 "github.com/absmach/supermq/api/grpc/channels/v1"
 	grpcClientsV1 "github.com/absmach/supermq/api/grpc/clients/v1"
 	apiutil "github.com/absmach/supermq/api/http/util"
 	smqauthn "github.com/absmach/supermq/pkg/authn"
+	smqauthz "github.com/absmach/supermq/pkg/authz"
 	"github.com/absmach/supermq/pkg/connections"
 	"github.com/absmach/supermq/pkg/errors"
 	svcerr "github.com/absmach/supermq/pkg/errors/service"
 	"github.com/absmach/supermq/pkg/policies"
-	"github.com/absmach/supermq/readers"
 	"github.com/go-chi/chi/v5"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// channelSubscribePermission allows subscribing to every channel of a domain.
+const channelSubscribePermission = "channel_subscribe_permission"
+
 const (
 	contentType    = "application/json"
 	offsetKey      = "offset"
@@ -41,14 +47,24 @@ const (
 	toKey          = "to"
 	aggregationKey = "aggregation"
 	intervalKey    = "interval"
+	fillKey        = "fill"
+	fillValueKey   = "fill_value"
+	channelKey     = "channel"
+	cursorKey      = "cursor"
+	skipTotalKey   = "skip_total"
+	msgFormatKey   = "message_format"
+	filterKey      = "filter"
+	fieldsKey      = "fields"
 	defInterval    = "1s"
 	defLimit       = 10
 	defOffset      = 0
 	defFormat      = "messages"
+	defFileFormat  = csvFormat
+	exportLimit    = 1000
 )
 
 // MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc readers.MessageRepository, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient, svcName, instanceID string) http.Handler {
+func MakeHandler(svc readers.MessageRepository, authn smqauthn.Authentication, authz smqauthz.Authorization, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient, svcName, instanceID string) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
 	}
@@ -61,6 +77,27 @@ func MakeHandler(svc readers.MessageRepository, authn smqauthn.Authentication, c
 		opts...,
 	).ServeHTTP)
 
+	mux.Get("/{domainID}/channels/{chanID}/messages/latest", kithttp.NewServer(
+		listLatestEndpoint(svc, authn, clients, channels),
+		decodeLatest,
+		encodeResponse,
+		opts...,
+	).ServeHTTP)
+
+	mux.Get("/{domainID}/channels/{chanID}/messages/export", kithttp.NewServer(
+		exportMessagesEndpoint(svc, authn, clients, channels),
+		decodeExport,
+		encodeExport,
+		opts...,
+	).ServeHTTP)
+
+	mux.Get("/{domainID}/messages", kithttp.NewServer(
+		listGroupsEndpoint(svc, authn, authz, clients, channels),
+		decodeGroups,
+		encodeResponse,
+		opts...,
+	).ServeHTTP)
+
 	mux.Get("/health", supermq.Health(svcName, instanceID))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -68,114 +105,225 @@ func MakeHandler(svc readers.MessageRepository, authn smqauthn.Authentication, c
 }
 
 func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
-	offset, err := apiutil.ReadNumQuery[uint64](r, offsetKey, defOffset)
+	pm, err := decodePageMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	pm.Domain = chi.URLParam(r, "domainID")
+
+	req := listMessagesReq{
+		chanID:   chi.URLParam(r, "chanID"),
+		token:    apiutil.ExtractBearerToken(r),
+		domain:   chi.URLParam(r, "domainID"),
+		key:      apiutil.ExtractClientSecret(r),
+		pageMeta: pm,
+	}
+	return req, nil
+}
+
+func decodeLatest(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeList(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return listLatestReq{listMessagesReq: req.(listMessagesReq)}, nil
+}
+
+// decodeExport reads the file format from the format query parameter, so
+// the message format is read from the message_format parameter instead.
+func decodeExport(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeList(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	fileFormat, err := apiutil.ReadStringQuery(r, formatKey, defFileFormat)
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
-	limit, err := apiutil.ReadNumQuery[uint64](r, limitKey, defLimit)
+	msgFormat, err := apiutil.ReadStringQuery(r, msgFormatKey, defFormat)
 	if err != nil {
 		return nil, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
+	lr := req.(listMessagesReq)
+	lr.pageMeta.Format = msgFormat
+	lr.pageMeta.Limit = exportLimit
+
+	return exportMessagesReq{listMessagesReq: lr, fileFormat: fileFormat}, nil
+}
+
+func decodeGroups(_ context.Context, r *http.Request) (interface{}, error) {
+	pm, err := decodePageMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	pm.Domain = chi.URLParam(r, "domainID")
+
+	req := listGroupsReq{
+		chanIDs:  r.URL.Query()[channelKey],
+		token:    apiutil.ExtractBearerToken(r),
+		domain:   chi.URLParam(r, "domainID"),
+		key:      apiutil.ExtractClientSecret(r),
+		pageMeta: pm,
+	}
+	return req, nil
+}
+
+func decodePageMetadata(r *http.Request) (readers.PageMetadata, error) {
+	offset, err := apiutil.ReadNumQuery[uint64](r, offsetKey, defOffset)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	limit, err := apiutil.ReadNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
 	format, err := apiutil.ReadStringQuery(r, formatKey, defFormat)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	subtopic, err := apiutil.ReadStringQuery(r, subtopicKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	publisher, err := apiutil.ReadStringQuery(r, publisherKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	protocol, err := apiutil.ReadStringQuery(r, protocolKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	name, err := apiutil.ReadStringQuery(r, nameKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	v, err := apiutil.ReadNumQuery[float64](r, valueKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	comparator, err := apiutil.ReadStringQuery(r, comparatorKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vs, err := apiutil.ReadStringQuery(r, stringValueKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vd, err := apiutil.ReadStringQuery(r, dataValueKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	vb, err := apiutil.ReadBoolQuery(r, boolValueKey, false)
 	if err != nil && err != apiutil.ErrNotFoundParam {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
 	from, err := apiutil.ReadNumQuery[float64](r, fromKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	to, err := apiutil.ReadNumQuery[float64](r, toKey, 0)
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	aggregation, err := apiutil.ReadStringQuery(r, aggregationKey, "")
 	if err != nil {
-		return nil, errors.Wrap(apiutil.ErrValidation, err)
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
 
 	var interval string
 	if aggregation != "" {
 		interval, err = apiutil.ReadStringQuery(r, intervalKey, defInterval)
 		if err != nil {
-			return nil, errors.Wrap(apiutil.ErrValidation, err)
+			return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 		}
 	}
 
-	req := listMessagesReq{
-		chanID: chi.URLParam(r, "chanID"),
-		token:  apiutil.ExtractBearerToken(r),
-		domain: chi.URLParam(r, "domainID"),
-		key:    apiutil.ExtractClientSecret(r),
-		pageMeta: readers.PageMetadata{
-			Offset:      offset,
-			Limit:       limit,
-			Format:      format,
-			Subtopic:    subtopic,
-			Publisher:   publisher,
-			Protocol:    protocol,
-			Name:        name,
-			Value:       v,
-			Comparator:  comparator,
-			StringValue: vs,
-			DataValue:   vd,
-			BoolValue:   vb,
-			From:        from,
-			To:          to,
-			Aggregation: aggregation,
-			Interval:    interval,
-		},
+	fill, err := apiutil.ReadStringQuery(r, fillKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
 	}
-	return req, nil
+
+	fillValue, err := apiutil.ReadNumQuery[float64](r, fillValueKey, 0)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	cursor, err := apiutil.ReadStringQuery(r, cursorKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	skipTotal, err := apiutil.ReadBoolQuery(r, skipTotalKey, false)
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+
+	var filters []readers.PayloadFilter
+	for _, expr := range r.URL.Query()[filterKey] {
+		filter, err := readers.ParsePayloadFilter(expr)
+		if err != nil {
+			return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	fields, err := apiutil.ReadStringQuery(r, fieldsKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+	}
+	var projection []string
+	if fields != "" {
+		projection = strings.Split(fields, ",")
+		for _, field := range projection {
+			if _, err := readers.ParsePayloadPath(field); err != nil {
+				return readers.PageMetadata{}, errors.Wrap(apiutil.ErrValidation, err)
+			}
+		}
+	}
+
+	pm := readers.PageMetadata{
+		Offset:         offset,
+		Limit:          limit,
+		Format:         format,
+		Subtopic:       subtopic,
+		Publisher:      publisher,
+		Protocol:       protocol,
+		Name:           name,
+		Value:          v,
+		Comparator:     comparator,
+		StringValue:    vs,
+		DataValue:      vd,
+		BoolValue:      vb,
+		From:           from,
+		To:             to,
+		Aggregation:    aggregation,
+		Interval:       interval,
+		Fill:           fill,
+		FillValue:      fillValue,
+		Cursor:         cursor,
+		SkipTotal:      skipTotal,
+		PayloadFilters: filters,
+		Fields:         projection,
+	}
+	return pm, nil
 }
 
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
@@ -196,6 +344,48 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
+// encodeExport streams the exported messages with chunked transfer, flushing
+// every page as it is read.
+func encodeExport(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+
+	w.Header().Set("Content-Type", exportContentTypes[res.fileFormat])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="messages.%s"`, res.fileFormat))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	ew := newExportWriter(w, res.fileFormat, res.isSenML)
+	page := res.page
+	for {
+		if err := ew.write(page.Messages); err != nil {
+			abortExport()
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if page.NextCursor == "" {
+			break
+		}
+
+		var err error
+		if page, err = res.next(page.NextCursor); err != nil {
+			abortExport()
+		}
+	}
+
+	if err := ew.close(); err != nil {
+		abortExport()
+	}
+
+	return nil
+}
+
+// abortExport aborts the response once the status has been sent, so that
+// clients see a truncated transfer instead of a complete file.
+func abortExport() {
+	panic(http.ErrAbortHandler)
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	var wrapper error
 	if errors.Contains(err, apiutil.ErrValidation) {
@@ -212,6 +402,9 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		errors.Contains(err, apiutil.ErrInvalidComparator),
 		errors.Contains(err, apiutil.ErrInvalidAggregation),
 		errors.Contains(err, apiutil.ErrInvalidInterval),
+		errors.Contains(err, readers.ErrInvalidFill),
+		errors.Contains(err, readers.ErrInvalidCursor),
+		errors.Contains(err, readers.ErrInvalidPayloadFilter),
 		errors.Contains(err, apiutil.ErrMissingFrom),
 		errors.Contains(err, apiutil.ErrMissingTo),
 		errors.Contains(err, apiutil.ErrMissingDomainID):
@@ -238,7 +431,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 }
 
 func authnAuthz(ctx context.Context, req listMessagesReq, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) error {
-	clientID, clientType, err := authenticate(ctx, req, authn, clients)
+	clientID, clientType, err := authenticate(ctx, req.token, req.key, authn, clients)
 	if err != nil {
 		return nil
 	}
@@ -248,18 +441,35 @@ func authnAuthz(ctx context.Context, req listMessagesReq, authn smqauthn.Authent
 	return nil
 }
 
-func authenticate(ctx context.Context, req listMessagesReq, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient) (clientID string, clientType string, err error) {
+func authnAuthzGroups(ctx context.Context, req listGroupsReq, authn smqauthn.Authentication, authz smqauthz.Authorization, clients grpcClientsV1.ClientsServiceClient, channels grpcChannelsV1.ChannelsServiceClient) error {
+	if len(req.chanIDs) == 0 {
+		return authorizeDomain(ctx, req.token, req.domain, authn, authz)
+	}
+
+	clientID, clientType, err := authenticate(ctx, req.token, req.key, authn, clients)
+	if err != nil {
+		return err
+	}
+	for _, chanID := range req.chanIDs {
+		if err := authorize(ctx, clientID, clientType, chanID, req.domain, channels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func authenticate(ctx context.Context, token, key string, authn smqauthn.Authentication, clients grpcClientsV1.ClientsServiceClient) (clientID string, clientType string, err error) {
 	switch {
-	case req.token != "":
-		session, err := authn.Authenticate(ctx, req.token)
+	case token != "":
+		session, err := authn.Authenticate(ctx, token)
 		if err != nil {
 			return "", "", err
 		}
 
 		return session.UserID, policies.UserType, nil
-	case req.key != "":
+	case key != "":
 		res, err := clients.Authenticate(ctx, &grpcClientsV1.AuthnReq{
-			ClientSecret: req.key,
+			ClientSecret: key,
 		})
 		if err != nil {
 			return "", "", err
@@ -289,3 +499,26 @@ func authorize(ctx context.Context, clientID, clientType, chanID, domain string,
 	}
 	return nil
 }
+
+// authorizeDomain checks that the user may subscribe to all the channels of the domain.
+func authorizeDomain(ctx context.Context, token, domain string, authn smqauthn.Authentication, authz smqauthz.Authorization) error {
+	session, err := authn.Authenticate(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	subject := policies.EncodeDomainUserID(domain, session.UserID)
+	if session.Role == smqauthn.AdminRole {
+		subject = session.UserID
+	}
+
+	return authz.Authorize(ctx, smqauthz.PolicyReq{
+		Domain:      domain,
+		SubjectType: policies.UserType,
+		SubjectKind: policies.UsersKind,
+		Subject:     subject,
+		Permission:  channelSubscribePermission,
+		ObjectType:  policies.DomainType,
+		Object:      domain,
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"reflect"
+	"strings"
+
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/magistrala/readers"
+)
+
+// matchMessage reports whether the message matches the filters of the read
+// that apply to all the formats. Filters are matched as the other readers
+// match them, so zero values of the read don't filter messages.
+func matchMessage(rpm readers.PageMetadata, subtopic, publisher, protocol string, t float64) bool {
+	switch {
+	case rpm.Subtopic != "" && subtopic != rpm.Subtopic,
+		rpm.Publisher != "" && publisher != rpm.Publisher,
+		rpm.Protocol != "" && protocol != rpm.Protocol,
+		rpm.From != 0 && t < rpm.From,
+		rpm.To != 0 && t >= rpm.To:
+		return false
+	default:
+		return true
+	}
+}
+
+// matchValues reports whether the SenML record matches the name and value
+// filters of the read.
+func matchValues(rpm readers.PageMetadata, r archiveclient.SenMLRecord) bool {
+	comparator := readers.ParseValueComparator(map[string]interface{}{"comparator": rpm.Comparator})
+	switch {
+	case rpm.Name != "" && r.Name != rpm.Name:
+		return false
+	case rpm.Value != 0 && (r.Value == nil || !compare(*r.Value, rpm.Value, comparator)):
+		return false
+	case rpm.BoolValue && (r.BoolValue == nil || !*r.BoolValue):
+		return false
+	case rpm.StringValue != "" && (r.StringValue == nil || !matchString(*r.StringValue, rpm.StringValue, comparator)):
+		return false
+	case rpm.DataValue != "" && (r.DataValue == nil || !compare(*r.DataValue, rpm.DataValue, comparator)):
+		return false
+	default:
+		return true
+	}
+}
+
+// matchString compares string values the way the other readers do: ordering
+// comparators match the values that contain the query or that the query
+// contains.
+func matchString(value, query, comparator string) bool {
+	switch comparator {
+	case ">":
+		return strings.Contains(value, query) && value != query
+	case ">=":
+		return strings.Contains(value, query)
+	case "<=":
+		return strings.Contains(query, value)
+	case "<":
+		return strings.Contains(query, value) && value != query
+	default:
+		return value == query
+	}
+}
+
+func compare[T float64 | string](value, query T, comparator string) bool {
+	switch comparator {
+	case "<":
+		return value < query
+	case "<=":
+		return value <= query
+	case ">":
+		return value > query
+	case ">=":
+		return value >= query
+	default:
+		return value == query
+	}
+}
+
+func validatePayloadFilter(f readers.PayloadFilter) error {
+	switch f.Operator {
+	case readers.PayloadEqual, readers.PayloadNotEqual:
+		return nil
+	case readers.PayloadLowerThan, readers.PayloadLowerThanEqual, readers.PayloadGreaterThan, readers.PayloadGreaterThanEqual:
+		switch f.Value.(type) {
+		case float64, string:
+			return nil
+		}
+	}
+
+	return readers.ErrInvalidPayloadFilter
+}
+
+// matchPayload reports whether the payload matches the filter. Equality
+// matches the values that contain the filter value, as the Postgres reader
+// matches them, and messages without the value match inequality. Ordering
+// operators compare values of the same JSON type only.
+func matchPayload(payload map[string]interface{}, f readers.PayloadFilter) bool {
+	var value interface{} = payload
+	found := true
+	for _, key := range f.Path {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			found = false
+			break
+		}
+		if value, ok = obj[key]; !ok {
+			found = false
+			break
+		}
+	}
+
+	switch f.Operator {
+	case readers.PayloadEqual:
+		return found && contains(value, f.Value)
+	case readers.PayloadNotEqual:
+		return !found || !contains(value, f.Value)
+	}
+	if !found {
+		return false
+	}
+
+	comparator := f.Operator
+	switch v := f.Value.(type) {
+	case float64:
+		n, ok := value.(float64)
+		return ok && compare(n, v, comparator)
+	case string:
+		s, ok := value.(string)
+		return ok && compare(s, v, comparator)
+	default:
+		return false
+	}
+}
+
+// contains reports whether the JSON value contains the other value, which
+// objects do if they hold all its keys with the values they contain.
+func contains(value, other interface{}) bool {
+	obj, ok := value.(map[string]interface{})
+	otherObj, otherOK := other.(map[string]interface{})
+	if !ok || !otherOK {
+		return reflect.DeepEqual(value, other)
+	}
+
+	for key, v := range otherObj {
+		if ov, ok := obj[key]; !ok || !contains(ov, v) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,416 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/magistrala/readers"
+	"github.com/absmach/supermq/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentGets is the number of manifest entries that are fetched at
+// once.
+const maxConcurrentGets = 16
+
+// errUnsupported indicates a read the archive can't serve, such as an
+// aggregated read.
+var errUnsupported = errors.New("unsupported archive read")
+
+var _ readers.MessageRepository = (*archiveRepository)(nil)
+
+type archiveRepository struct {
+	bucket *archiveclient.Bucket
+}
+
+// New returns new archive reader. Reads list the manifest entries of the
+// channel, and read the data files whose time range overlaps the read,
+// starting from the latest. Reads that skip the total stop once the files
+// left hold no message of the page.
+func New(bucket *archiveclient.Bucket) readers.MessageRepository {
+	return &archiveRepository{
+		bucket: bucket,
+	}
+}
+
+func (ar archiveRepository) ReadAll(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	if rpm.Cursor != "" {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, readers.ErrInvalidCursor)
+	}
+	if rpm.Aggregation != "" {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errUnsupported)
+	}
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+
+	format := archiveclient.SenMLFormat
+	if rpm.Format != "" && rpm.Format != archiveclient.SenMLFormat {
+		format = strings.ToLower(rpm.Format)
+		// Messages of invalid formats are never archived.
+		if !archiveclient.ValidFormat(format) {
+			return page, nil
+		}
+	}
+
+	ctx := context.Background()
+	entries, err := ar.entries(ctx, format, chanID, rpm)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	var rows []row
+	var total int
+	switch format {
+	case archiveclient.SenMLFormat:
+		rows, total, err = read(ctx, ar.bucket, entries, rpm, senmlFilter(rpm))
+	default:
+		var match func(archiveclient.JSONRecord) (row, bool, error)
+		if match, err = jsonFilter(rpm); err == nil {
+			rows, total, err = read(ctx, ar.bucket, entries, rpm, match)
+		}
+	}
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	if rpm.Offset < uint64(len(rows)) {
+		for _, r := range rows[rpm.Offset:] {
+			page.Messages = append(page.Messages, r.msg)
+		}
+	}
+	if !rpm.SkipTotal {
+		page.Total = uint64(total)
+	}
+
+	return page, nil
+}
+
+// StreamAll sends the messages file by file, starting from the file with
+// the latest message. Messages are ordered from the latest within a file
+// only, since the time ranges of the files may overlap.
+func (ar archiveRepository) StreamAll(ctx context.Context, chanID string, rpm readers.PageMetadata, send func(readers.MessagesPage) error) error {
+	if rpm.Cursor != "" {
+		return errors.Wrap(readers.ErrReadMessages, readers.ErrInvalidCursor)
+	}
+	if rpm.Aggregation != "" {
+		return errors.Wrap(readers.ErrReadMessages, errUnsupported)
+	}
+
+	format := archiveclient.SenMLFormat
+	if rpm.Format != "" && rpm.Format != archiveclient.SenMLFormat {
+		format = strings.ToLower(rpm.Format)
+		if !archiveclient.ValidFormat(format) {
+			return nil
+		}
+	}
+
+	entries, err := ar.entries(ctx, format, chanID, rpm)
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	// Errors of the sends are returned as they are.
+	var sendErr error
+	sendPage := func(page readers.MessagesPage) error {
+		sendErr = send(page)
+		return sendErr
+	}
+	switch format {
+	case archiveclient.SenMLFormat:
+		err = stream(ctx, ar.bucket, entries, rpm, senmlFilter(rpm), sendPage)
+	default:
+		var match func(archiveclient.JSONRecord) (row, bool, error)
+		if match, err = jsonFilter(rpm); err == nil {
+			err = stream(ctx, ar.bucket, entries, rpm, match, sendPage)
+		}
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return nil
+}
+
+// ReadLatest isn't served by the archive, since it would read the whole
+// history of the channel.
+func (ar archiveRepository) ReadLatest(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, errUnsupported)
+}
+
+// ReadGroups isn't served by the archive, since it would read the whole
+// history of the domain.
+func (ar archiveRepository) ReadGroups(domainID string, chanIDs []string, rpm readers.PageMetadata) (readers.GroupsPage, error) {
+	return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, errUnsupported)
+}
+
+// entries returns the manifest entries of the files of the channel that may
+// hold messages of the read, ordered by their latest message.
+func (ar archiveRepository) entries(ctx context.Context, format, chanID string, rpm readers.PageMetadata) ([]archiveclient.Entry, error) {
+	// Messages stored without a domain are read with the messages of the
+	// domain, and messages of all the domains are read without a domain.
+	prefixes := []string{archiveclient.FormatEntriesPrefix(format)}
+	if rpm.Domain != "" {
+		prefixes = []string{
+			archiveclient.EntriesPrefix(format, rpm.Domain, chanID),
+			archiveclient.EntriesPrefix(format, "", chanID),
+		}
+	}
+
+	var fromDate, toDate string
+	if rpm.From != 0 {
+		fromDate = archiveclient.NewPartition(format, "", chanID, rpm.From).Date
+	}
+	if rpm.To != 0 {
+		toDate = archiveclient.NewPartition(format, "", chanID, rpm.To).Date
+	}
+
+	var keys []string
+	for _, prefix := range prefixes {
+		listed, err := ar.bucket.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range listed {
+			p, err := archiveclient.ParseEntryKey(key)
+			if err != nil {
+				continue
+			}
+			if p.Channel != chanID || (fromDate != "" && p.Date < fromDate) || (toDate != "" && p.Date > toDate) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	entries := make([]archiveclient.Entry, len(keys))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentGets)
+	for i, key := range keys {
+		g.Go(func() error {
+			e, err := ar.bucket.GetEntry(gctx, key)
+			if err != nil {
+				return err
+			}
+			entries[i] = e
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	entries = slices.DeleteFunc(entries, func(e archiveclient.Entry) bool {
+		return (rpm.From != 0 && e.MaxTime < rpm.From) || (rpm.To != 0 && e.MinTime >= rpm.To)
+	})
+	slices.SortFunc(entries, func(a, b archiveclient.Entry) int {
+		return compareTime(a.MaxTime, b.MaxTime)
+	})
+
+	return entries, nil
+}
+
+// row is a message of a read.
+type row struct {
+	id   string
+	time float64
+	msg  readers.Message
+}
+
+// read returns the latest messages of the page the filter matches, read
+// from the files of the entries, and the number of the messages the filter
+// matches. Records of redelivered messages are read once. The total is
+// counted only if the read doesn't skip it, since it takes reading all
+// the files.
+func read[T any](ctx context.Context, bucket *archiveclient.Bucket, entries []archiveclient.Entry, rpm readers.PageMetadata, match func(T) (row, bool, error)) ([]row, int, error) {
+	size := rpm.Offset + rpm.Limit
+	seen := make(map[string]struct{})
+	var rows []row
+	for _, e := range entries {
+		// Entries are ordered by their latest message, so files left hold
+		// no message of the page once the page holds later messages.
+		if rpm.SkipTotal && uint64(len(rows)) >= size && (size == 0 || rows[size-1].time > e.MaxTime) {
+			break
+		}
+
+		data, err := bucket.Get(ctx, e.Key)
+		if err != nil {
+			return nil, 0, err
+		}
+		records, err := archiveclient.Decode[T](e.FileFormat, data)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, rec := range records {
+			r, ok, err := match(rec)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !ok {
+				continue
+			}
+			if _, ok := seen[r.id]; ok {
+				continue
+			}
+			seen[r.id] = struct{}{}
+			rows = append(rows, r)
+		}
+
+		// Messages past the end of the page are never returned, so they are
+		// dropped to keep at most a page and a file in memory.
+		slices.SortFunc(rows, func(a, b row) int {
+			if c := compareTime(a.time, b.time); c != 0 {
+				return c
+			}
+			return strings.Compare(a.id, b.id)
+		})
+		if uint64(len(rows)) > size {
+			rows = rows[:size]
+		}
+	}
+
+	return rows, len(seen), nil
+}
+
+// stream sends the messages the filter matches in batches of the page limit,
+// read from the files of the entries from the latest. The offset is skipped
+// once, and records of redelivered messages are sent once.
+func stream[T any](ctx context.Context, bucket *archiveclient.Bucket, entries []archiveclient.Entry, rpm readers.PageMetadata, match func(T) (row, bool, error), send func(readers.MessagesPage) error) error {
+	skip := rpm.Offset
+	seen := make(map[string]struct{})
+	page := readers.MessagesPage{PageMetadata: rpm, Messages: []readers.Message{}}
+	flush := func() error {
+		if len(page.Messages) == 0 {
+			return nil
+		}
+		if err := send(page); err != nil {
+			return err
+		}
+		page.Messages = []readers.Message{}
+		return nil
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		data, err := bucket.Get(ctx, e.Key)
+		if err != nil {
+			return err
+		}
+		records, err := archiveclient.Decode[T](e.FileFormat, data)
+		if err != nil {
+			return err
+		}
+		var rows []row
+		for _, rec := range records {
+			r, ok, err := match(rec)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if _, ok := seen[r.id]; ok {
+				continue
+			}
+			seen[r.id] = struct{}{}
+			rows = append(rows, r)
+		}
+		slices.SortFunc(rows, func(a, b row) int {
+			if c := compareTime(a.time, b.time); c != 0 {
+				return c
+			}
+			return strings.Compare(a.id, b.id)
+		})
+
+		for _, r := range rows {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			page.Messages = append(page.Messages, r.msg)
+			if uint64(len(page.Messages)) == rpm.Limit {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return flush()
+}
+
+// compareTime orders times from the latest.
+func compareTime(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// senmlFilter returns the filter of the SenML records of the read.
+func senmlFilter(rpm readers.PageMetadata) func(archiveclient.SenMLRecord) (row, bool, error) {
+	return func(r archiveclient.SenMLRecord) (row, bool, error) {
+		if !matchMessage(rpm, r.Subtopic, r.Publisher, r.Protocol, r.Time) || !matchValues(rpm, r) {
+			return row{}, false, nil
+		}
+
+		return row{id: r.ID, time: r.Time, msg: r.Message()}, true, nil
+	}
+}
+
+// jsonFilter returns the filter of the JSON records of the read, which
+// projects the payloads of the messages to the fields of the read.
+func jsonFilter(rpm readers.PageMetadata) (func(archiveclient.JSONRecord) (row, bool, error), error) {
+	for _, f := range rpm.PayloadFilters {
+		if err := validatePayloadFilter(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(r archiveclient.JSONRecord) (row, bool, error) {
+		created := float64(r.Created)
+		if !matchMessage(rpm, r.Subtopic, r.Publisher, r.Protocol, created) {
+			return row{}, false, nil
+		}
+
+		pld := make(map[string]interface{})
+		if err := json.Unmarshal(r.Payload, &pld); err != nil {
+			return row{}, false, err
+		}
+		for _, f := range rpm.PayloadFilters {
+			if !matchPayload(pld, f) {
+				return row{}, false, nil
+			}
+		}
+		if len(rpm.Fields) > 0 {
+			pld = readers.ProjectPayload(pld, rpm.Fields)
+		}
+
+		msg := map[string]interface{}{
+			"id":        r.ID,
+			"channel":   r.Channel,
+			"created":   r.Created,
+			"subtopic":  r.Subtopic,
+			"publisher": r.Publisher,
+			"protocol":  r.Protocol,
+			"payload":   pld,
+		}
+
+		return row{id: r.ID, time: created, msg: msg}, true, nil
+	}, nil
+}
@@ -0,0 +1,845 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	awriter "github.com/absmach/magistrala/consumers/writers/archive"
+	archiveclient "github.com/absmach/magistrala/pkg/archive"
+	"github.com/absmach/magistrala/readers"
+	areader "github.com/absmach/magistrala/readers/archive"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/absmach/supermq/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	subtopic    = "subtopic"
+	msgsNum     = 100
+	filesNum    = 4
+	limit       = 10
+	valueFields = 5
+	mqttProt    = "mqtt"
+	httpProt    = "http"
+	msgName     = "temperature"
+	format1     = "format1"
+	format2     = "format2"
+	wrongID     = "wrong-id"
+)
+
+var (
+	v   float64 = 5
+	vs          = "stringValue"
+	vb          = true
+	vd          = "dataValue"
+	sum float64 = 42
+	idp         = uuid.New()
+	// 2025-06-30 23:59:00 UTC, so the messages are archived to two dates.
+	startTime = time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC)
+)
+
+func TestReadSenml(t *testing.T) {
+	for _, fileFmt := range []string{archiveclient.FileNDJSON, archiveclient.FileParquet} {
+		t.Run(fileFmt, func(t *testing.T) {
+			testReadSenml(t, fileFmt)
+		})
+	}
+}
+
+func testReadSenml(t *testing.T, fileFmt string) {
+	bucket := newBucket(t)
+	writer := awriter.New(bucket, awriter.Config{FileFormat: fileFmt}, idp)
+
+	chanID := newID(t)
+	pubID := newID(t)
+	pubID2 := newID(t)
+
+	m := senml.Message{
+		Channel:    chanID,
+		Publisher:  pubID,
+		Protocol:   mqttProt,
+		Name:       "name",
+		Unit:       "U",
+		UpdateTime: 1234,
+	}
+
+	messages := []senml.Message{}
+	valueMsgs := []senml.Message{}
+	boolMsgs := []senml.Message{}
+	stringMsgs := []senml.Message{}
+	dataMsgs := []senml.Message{}
+	queryMsgs := []senml.Message{}
+	subtopicMsgs := []senml.Message{}
+
+	for i := 0; i < msgsNum; i++ {
+		// Mix possible values as well as value sum.
+		msg := m
+		msg.Time = float64(startTime.Add(time.Duration(msgsNum-i) * time.Second).UnixNano())
+
+		count := i % valueFields
+		switch count {
+		case 0:
+			msg.Subtopic = subtopic
+			msg.Value = &v
+			valueMsgs = append(valueMsgs, msg)
+		case 1:
+			msg.BoolValue = &vb
+			boolMsgs = append(boolMsgs, msg)
+		case 2:
+			msg.StringValue = &vs
+			stringMsgs = append(stringMsgs, msg)
+		case 3:
+			msg.DataValue = &vd
+			dataMsgs = append(dataMsgs, msg)
+		case 4:
+			msg.Sum = &sum
+			msg.Subtopic = subtopic
+			msg.Protocol = httpProt
+			msg.Publisher = pubID2
+			msg.Name = msgName
+			queryMsgs = append(queryMsgs, msg)
+		}
+
+		if msg.Subtopic == subtopic {
+			subtopicMsgs = append(subtopicMsgs, msg)
+		}
+		messages = append(messages, msg)
+	}
+
+	// Messages are consumed in chunks, so they are archived in several files
+	// of each date.
+	chunk := msgsNum / filesNum
+	for i := 0; i < msgsNum; i += chunk {
+		err := writer.ConsumeBlocking(context.TODO(), messages[i:i+chunk])
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	}
+	// Redelivered messages are archived in files of their own.
+	err := writer.ConsumeBlocking(context.TODO(), messages[0:limit])
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := areader.New(bucket)
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+	}{
+		{
+			desc:   "read message page for existing channel",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromSenml(messages),
+			},
+		},
+		{
+			desc:   "read message page for non-existent channel",
+			chanID: wrongID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message last page",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: msgsNum - 20,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromSenml(messages[msgsNum-20 : msgsNum]),
+			},
+		},
+		{
+			desc:   "read message page skipping total",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    limit,
+				Limit:     limit,
+				SkipTotal: true,
+			},
+			page: readers.MessagesPage{
+				Messages: fromSenml(messages[limit : 2*limit]),
+			},
+		},
+		{
+			desc:   "read message with non-existent subtopic",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    msgsNum,
+				Subtopic: "not-present",
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message with subtopic",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    limit,
+				Subtopic: subtopic,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(subtopicMsgs)),
+				Messages: fromSenml(subtopicMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with publisher",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     uint64(len(queryMsgs)),
+				Publisher: pubID2,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs),
+			},
+		},
+		{
+			desc:   "read message with protocol",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    uint64(len(queryMsgs)),
+				Protocol: httpProt,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs),
+			},
+		},
+		{
+			desc:   "read message with name",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				Name:   msgName,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				Value:  v,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and lower-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v + 1,
+				Comparator: readers.LowerThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and greater-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v,
+				Comparator: readers.GreaterThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v,
+				Comparator: readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message with boolean value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     limit,
+				BoolValue: vb,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(boolMsgs)),
+				Messages: fromSenml(boolMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: vs,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and lower-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: "a stringValues b",
+				Comparator:  readers.LowerThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: "alu",
+				Comparator:  readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     limit,
+				DataValue: vd,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				DataValue:  vd[:len(vd)-1],
+				Comparator: readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with from",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  uint64(len(messages[0:21])),
+				From:   messages[20].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages[0:21])),
+				Messages: fromSenml(messages[0:21]),
+			},
+		},
+		{
+			desc:   "read message with to",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  uint64(len(messages[21:])),
+				To:     messages[20].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages[21:])),
+				Messages: fromSenml(messages[21:]),
+			},
+		},
+		{
+			desc:   "read message with from/to",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				From:   messages[5].Time,
+				To:     messages[0].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    5,
+				Messages: fromSenml(messages[1:6]),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(tc.chanID, tc.pageMeta)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.page.Total, result.Total))
+	}
+}
+
+func TestReadUnsupported(t *testing.T) {
+	bucket := newBucket(t)
+	reader := areader.New(bucket)
+	chanID := newID(t)
+
+	cases := []struct {
+		desc     string
+		pageMeta readers.PageMetadata
+		err      error
+	}{
+		{
+			desc:     "read messages with aggregation",
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: "AVG", Interval: "10s"},
+			err:      readers.ErrReadMessages,
+		},
+		{
+			desc:     "read messages with cursor",
+			pageMeta: readers.PageMetadata{Limit: limit, Cursor: "cursor"},
+			err:      readers.ErrInvalidCursor,
+		},
+		{
+			desc: "read messages with invalid payload filter",
+			pageMeta: readers.PageMetadata{
+				Limit:  limit,
+				Format: format1,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_1"}, Operator: readers.PayloadGreaterThan, Value: false},
+				},
+			},
+			err: readers.ErrInvalidPayloadFilter,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := reader.ReadAll(chanID, tc.pageMeta)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	_, err := reader.ReadLatest(chanID, readers.PageMetadata{Limit: limit})
+	assert.True(t, errors.Contains(err, readers.ErrReadMessages), fmt.Sprintf("expected %s got %s", readers.ErrReadMessages, err))
+	_, err = reader.ReadGroups(newID(t), []string{chanID}, readers.PageMetadata{Limit: limit})
+	assert.True(t, errors.Contains(err, readers.ErrReadMessages), fmt.Sprintf("expected %s got %s", readers.ErrReadMessages, err))
+}
+
+func TestReadSenmlOfDomain(t *testing.T) {
+	bucket := newBucket(t)
+	writer := awriter.New(bucket, awriter.Config{FileFormat: archiveclient.FileNDJSON}, idp)
+
+	domainID := newID(t)
+	chanID := newID(t)
+	pubID := newID(t)
+
+	var msgs []senml.Message
+	for i := 0; i < 3; i++ {
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(startTime.Add(-time.Duration(i) * time.Second).UnixNano()),
+			Value:     &v,
+		})
+	}
+	domainMsgs, otherMsgs, legacyMsgs := msgs[0:1], msgs[1:2], msgs[2:3]
+
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), domainMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(writers.WithDomain(context.TODO(), newID(t)), otherMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), legacyMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := areader.New(bucket)
+
+	cases := []struct {
+		desc     string
+		pm       readers.PageMetadata
+		messages []senml.Message
+	}{
+		{
+			desc:     "read messages of channel",
+			pm:       readers.PageMetadata{Limit: limit},
+			messages: msgs,
+		},
+		{
+			desc:     "read messages of channel of domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: domainID},
+			messages: append(append([]senml.Message{}, domainMsgs...), legacyMsgs...),
+		},
+		{
+			desc:     "read messages of channel of other domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: newID(t)},
+			messages: legacyMsgs,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, uint64(len(tc.messages)), result.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, len(tc.messages), result.Total))
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	bucket := newBucket(t)
+	writer := awriter.New(bucket, awriter.Config{FileFormat: archiveclient.FileParquet}, idp)
+
+	id1 := newID(t)
+	m := json.Message{
+		Channel:   id1,
+		Publisher: id1,
+		Created:   startTime.UnixNano(),
+		Subtopic:  "subtopic/format/some_json",
+		Protocol:  "coap",
+		Payload: map[string]interface{}{
+			"field_1": 123.0,
+			"field_2": "value",
+			"field_3": false,
+			"field_4": 12.344,
+			"field_5": map[string]interface{}{
+				"field_1": "value",
+				"field_2": 42.0,
+			},
+		},
+	}
+	messages1 := json.Messages{
+		Format: format1,
+	}
+	msgs1 := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i++ {
+		msg := m
+		messages1.Data = append(messages1.Data, msg)
+		m := toMap(msg)
+		msgs1 = append(msgs1, m)
+	}
+
+	err := writer.ConsumeBlocking(context.TODO(), messages1)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	id2 := newID(t)
+	m = json.Message{
+		Channel:   id2,
+		Publisher: id2,
+		Created:   startTime.UnixNano(),
+		Subtopic:  "subtopic/other_format/some_other_json",
+		Protocol:  "udp",
+		Payload: map[string]interface{}{
+			"field_1":     "other_value",
+			"false_value": false,
+			"field_pi":    3.14159265,
+		},
+	}
+	messages2 := json.Messages{
+		Format: format2,
+	}
+	msgs2 := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i++ {
+		msg := m
+		if i%2 == 0 {
+			msg.Protocol = httpProt
+		}
+		messages2.Data = append(messages2.Data, msg)
+		m := toMap(msg)
+		msgs2 = append(msgs2, m)
+	}
+
+	err = writer.ConsumeBlocking(context.TODO(), messages2)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	httpMsgs := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i += 2 {
+		httpMsgs = append(httpMsgs, msgs2[i])
+	}
+
+	projected := []map[string]interface{}{}
+	for _, m := range msgs1 {
+		pm := map[string]interface{}{}
+		for k, v := range m {
+			pm[k] = v
+		}
+		pm["payload"] = map[string]interface{}{
+			"field_1": 123.0,
+			"field_5": map[string]interface{}{"field_2": 42.0},
+		}
+		projected = append(projected, pm)
+	}
+
+	reader := areader.New(bucket)
+
+	cases := map[string]struct {
+		chanID   string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+	}{
+		"read message page for existing channel": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message page of uppercase format": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: "FORMAT1",
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message page of invalid format": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: "format.1",
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message page for non-existent channel": {
+			chanID: wrongID,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message last page": {
+			chanID: id2,
+			pageMeta: readers.PageMetadata{
+				Format: messages2.Format,
+				Offset: msgsNum - 20,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromJSON(msgs2[msgsNum-20 : msgsNum]),
+			},
+		},
+		"read message with payload equality filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_1"}, Operator: readers.PayloadEqual, Value: "value"},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with payload object equality filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5"}, Operator: readers.PayloadEqual, Value: map[string]interface{}{"field_2": 42.0}},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with payload range filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_2"}, Operator: readers.PayloadGreaterThan, Value: 40.0},
+					{Path: []string{"field_4"}, Operator: readers.PayloadLowerThanEqual, Value: 12.344},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with non-matching payload filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_1"}, Operator: readers.PayloadNotEqual, Value: 123.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload range filter on string value": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_2"}, Operator: readers.PayloadGreaterThan, Value: 1.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload fields": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				Fields: []string{"field_1", "field_5.field_2", "missing"},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(projected[:10]),
+			},
+		},
+		"read message with protocol": {
+			chanID: id2,
+			pageMeta: readers.PageMetadata{
+				Format:   messages2.Format,
+				Offset:   0,
+				Limit:    uint64(msgsNum / 2),
+				Protocol: httpProt,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(msgsNum / 2),
+				Messages: fromJSON(httpMsgs),
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.ReadAll(tc.chanID, tc.pageMeta)
+		for i := 0; i < len(result.Messages); i++ {
+			m := result.Messages[i]
+			// Remove id as it is not sent by the client.
+			delete(m.(map[string]interface{}), "id")
+			result.Messages[i] = m
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: got incorrect list of json Messages from ReadAll()", desc))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
+	}
+}
+
+// newBucket returns the bucket of the test, which stores the objects under
+// a prefix of its own.
+func newBucket(t *testing.T) *archiveclient.Bucket {
+	cfg := bucketConfig
+	cfg.Prefix = newID(t)
+	bucket, err := archiveclient.Connect(cfg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return bucket
+}
+
+func newID(t *testing.T) string {
+	id, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return id
+}
+
+func fromSenml(msg []senml.Message) []readers.Message {
+	ret := []readers.Message{}
+	for _, m := range msg {
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func fromJSON(msg []map[string]interface{}) []readers.Message {
+	ret := []readers.Message{}
+	for _, m := range msg {
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func toMap(msg json.Message) map[string]interface{} {
+	return map[string]interface{}{
+		"channel":   msg.Channel,
+		"created":   msg.Created,
+		"subtopic":  msg.Subtopic,
+		"publisher": msg.Publisher,
+		"protocol":  msg.Protocol,
+		"payload":   map[string]interface{}(msg.Payload),
+	}
+}
@@ -0,0 +1,656 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/absmach/magistrala/readers"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// Table for SenML messages.
+	defTable = "messages"
+	// Message time is stored in nanoseconds.
+	timeDivisor = 1000000000
+	// bucketOrigin is the default origin of TimescaleDB time_bucket
+	// (2000-01-03 00:00:00 UTC) in seconds, so all readers return the
+	// same buckets.
+	bucketOrigin = 946857600
+	// codeUnknownTable is the code of the ClickHouse exception of reads of
+	// tables that don't exist, such as the tables of unknown formats.
+	codeUnknownTable = 60
+)
+
+// Columns of the messages, without the version the writer deduplicates
+// records by.
+const (
+	senmlColumns = `id, domain, channel, subtopic, publisher, protocol, name, unit,
+        value, string_value, bool_value, data_value, sum, time, update_time`
+	jsonColumns = `id, domain, channel, created, subtopic, publisher, protocol, payload`
+)
+
+// bucket is the start of the interval message time falls in, in nanoseconds.
+var bucket = bucketTime(bucketIndex("time"))
+
+// bucketIndex returns the number of intervals between the bucket origin and
+// the bucket the nanosecond time expression falls in.
+func bucketIndex(t string) string {
+	return fmt.Sprintf(`floor((%[1]s / %[2]d - %[3]d) / :interval)`, t, timeDivisor, bucketOrigin)
+}
+
+// bucketTime returns the start of the bucket with the given index, in nanoseconds.
+func bucketTime(idx string) string {
+	return fmt.Sprintf(`((%[1]d + %[2]s * :interval) * %[3]d)`, bucketOrigin, idx, timeDivisor)
+}
+
+// Conditions that scope a read to a channel, a list of channels or a domain.
+const (
+	channelScope  = `channel = :channel`
+	channelsScope = `has(:channels, channel)`
+	domainScope   = `domain = :domain`
+)
+
+var (
+	errInvalidAggregation = errors.New("invalid aggregation")
+	errInvalidInterval    = errors.New("invalid interval")
+)
+
+var _ readers.MessageRepository = (*clickhouseRepository)(nil)
+
+type clickhouseRepository struct {
+	db *sqlx.DB
+}
+
+// New returns new ClickHouse reader. Records are read with FINAL, so the
+// records of redelivered messages the writer stored are deduplicated even
+// before they are merged.
+func New(db *sqlx.DB) readers.MessageRepository {
+	return &clickhouseRepository{
+		db: db,
+	}
+}
+
+func (cr clickhouseRepository) ReadAll(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	rq, err := readAllQuery(chanID, rpm, `LIMIT :limit OFFSET :offset`)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	rows, err := cr.db.NamedQuery(rq.query, rq.params)
+	if err != nil {
+		if exceptionCode(err) == codeUnknownTable {
+			return readers.MessagesPage{}, nil
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	var cursor string
+	for rows.Next() {
+		msg, c, err := scanMessage(rows, rq.format, rpm.Fields)
+		if err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, msg)
+		cursor = c
+	}
+	if err := rows.Err(); err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	if !rq.aggregated && uint64(len(page.Messages)) == rpm.Limit {
+		page.NextCursor = encodeCursor(cursor)
+	}
+
+	if rpm.SkipTotal {
+		return page, nil
+	}
+
+	rows, err = cr.db.NamedQuery(rq.totalQuery, rq.params)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return page, err
+		}
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (cr clickhouseRepository) StreamAll(ctx context.Context, chanID string, rpm readers.PageMetadata, send func(readers.MessagesPage) error) error {
+	rq, err := readAllQuery(chanID, rpm, `OFFSET :offset ROWS`)
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	// Blocks are received from the server as the rows are iterated, so the
+	// messages are read with a single query and sent in batches.
+	rows, err := cr.db.NamedQueryContext(ctx, rq.query, rq.params)
+	if err != nil {
+		if exceptionCode(err) == codeUnknownTable {
+			return nil
+		}
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{PageMetadata: rpm, Messages: []readers.Message{}}
+	var cursor string
+	flush := func() error {
+		if len(page.Messages) == 0 {
+			return nil
+		}
+		if !rq.aggregated {
+			page.NextCursor = encodeCursor(cursor)
+		}
+		if err := send(page); err != nil {
+			return err
+		}
+		page.Messages = []readers.Message{}
+		return nil
+	}
+
+	for rows.Next() {
+		msg, c, err := scanMessage(rows, rq.format, rpm.Fields)
+		if err != nil {
+			return errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, msg)
+		cursor = c
+		if uint64(len(page.Messages)) == rpm.Limit {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return flush()
+}
+
+// readQuery is the query of the messages ReadAll and StreamAll read.
+type readQuery struct {
+	query      string
+	totalQuery string
+	params     map[string]interface{}
+	format     string
+	aggregated bool
+}
+
+// readAllQuery returns the query of the messages of the channel, which
+// reads the page the page clause selects.
+func readAllQuery(chanID string, rpm readers.PageMetadata, page string) (readQuery, error) {
+	key := senmlCursor
+	format := defTable
+	columns := senmlColumns
+
+	if rpm.Format != "" && rpm.Format != defTable {
+		key = jsonCursor
+		format = strings.ToLower(rpm.Format)
+		columns = jsonColumns
+	}
+	cond := fmtCondition(channelScope, rpm)
+
+	params := queryParams(rpm)
+	params["channel"] = chanID
+
+	if format != defTable {
+		var err error
+		if cond, err = payloadCondition(cond, rpm.PayloadFilters, params); err != nil {
+			return readQuery{}, err
+		}
+	}
+
+	pageCond := cond
+	if rpm.Cursor != "" {
+		if err := decodeCursor(rpm.Cursor, key, params); err != nil {
+			return readQuery{}, err
+		}
+		pageCond = fmt.Sprintf(`%s AND %s`, cond, cursorCondition(key))
+	}
+
+	table := quote(format)
+	q := fmt.Sprintf(`SELECT %s, %s AS cursor FROM %s FINAL
+    WHERE %s ORDER BY %s
+	%s`, columns, cursorValue(key), table, pageCond, cursorOrder(key), page)
+	totalQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s FINAL WHERE %s`, table, cond)
+
+	// Aggregation applies to SenML messages only, since JSON messages
+	// have no common value column.
+	aggregated := rpm.Aggregation != "" && format == defTable
+	if aggregated {
+		if rpm.Cursor != "" {
+			return readQuery{}, readers.ErrInvalidCursor
+		}
+		// Empty buckets aren't filled, since ClickHouse has no series
+		// to join the buckets of the read to.
+		if rpm.Fill != "" && rpm.Fill != readers.FillNone {
+			return readQuery{}, readers.ErrInvalidFill
+		}
+		agg, err := aggregation(rpm.Aggregation)
+		if err != nil {
+			return readQuery{}, err
+		}
+		interval, err := time.ParseDuration(rpm.Interval)
+		if err != nil || interval <= 0 {
+			return readQuery{}, errInvalidInterval
+		}
+		params["interval"] = interval.Seconds()
+
+		// Aggregates are aliased apart from the columns, since ClickHouse
+		// resolves column names to the aliases of the same query.
+		q = fmt.Sprintf(`
+			SELECT
+				agg_time AS time,
+				agg_value AS value,
+				agg_publisher AS publisher,
+				agg_protocol AS protocol,
+				agg_subtopic AS subtopic,
+				agg_name AS name,
+				agg_unit AS unit
+			FROM (
+				SELECT
+					%s AS agg_time,
+					%s AS agg_value,
+					argMin(publisher, time) AS agg_publisher,
+					argMin(protocol, time) AS agg_protocol,
+					argMin(subtopic, time) AS agg_subtopic,
+					argMin(name, time) AS agg_name,
+					argMin(unit, time) AS agg_unit
+				FROM %s FINAL
+				WHERE %s
+				GROUP BY agg_time
+			)
+			ORDER BY time DESC
+			%s`, bucket, agg, table, cond, page)
+		totalQuery = fmt.Sprintf(`SELECT COUNT(DISTINCT %s) FROM %s FINAL WHERE %s`, bucket, table, cond)
+	}
+
+	return readQuery{
+		query:      q,
+		totalQuery: totalQuery,
+		params:     params,
+		format:     format,
+		aggregated: aggregated,
+	}, nil
+}
+
+// scanMessage returns the message of the row and its cursor.
+func scanMessage(rows *sqlx.Rows, format string, fields []string) (readers.Message, string, error) {
+	switch format {
+	case defTable:
+		msg := senmlMessage{Message: senml.Message{}}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, "", err
+		}
+		return msg.Message, msg.Cursor, nil
+	default:
+		msg := jsonMessage{}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, "", err
+		}
+		m, err := msg.toMap(fields)
+		if err != nil {
+			return nil, "", err
+		}
+		return m, msg.Cursor, nil
+	}
+}
+
+func (cr clickhouseRepository) ReadLatest(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	cond := fmtCondition(channelScope, rpm)
+
+	// Latest values are read from SenML messages only, since JSON messages
+	// have no common name to distinguish the readings.
+	q := fmt.Sprintf(`
+		SELECT %[1]s
+		FROM %[2]s FINAL
+		WHERE %[3]s
+		ORDER BY time DESC
+		LIMIT 1 BY subtopic, publisher, name
+		LIMIT :limit OFFSET :offset`, senmlColumns, defTable, cond)
+	totalQuery := fmt.Sprintf(`
+		SELECT uniqExact(subtopic, publisher, name)
+		FROM %[1]s
+		WHERE %[2]s`, defTable, cond)
+
+	params := queryParams(rpm)
+	params["channel"] = chanID
+	rows, err := cr.db.NamedQuery(q, params)
+	if err != nil {
+		if exceptionCode(err) == codeUnknownTable {
+			return readers.MessagesPage{}, nil
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	for rows.Next() {
+		msg := senmlMessage{Message: senml.Message{}}
+		if err := rows.StructScan(&msg); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, msg.Message)
+	}
+
+	rows, err = cr.db.NamedQuery(totalQuery, params)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&page.Total); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	return page, nil
+}
+
+func (cr clickhouseRepository) ReadGroups(domainID string, chanIDs []string, rpm readers.PageMetadata) (readers.GroupsPage, error) {
+	order := "time"
+	format := defTable
+	columns := senmlColumns
+
+	if rpm.Format != "" && rpm.Format != defTable {
+		order = "created"
+		format = strings.ToLower(rpm.Format)
+		columns = jsonColumns
+	}
+
+	params := queryParams(rpm)
+	scope := domainScope
+	params["domain"] = domainID
+	if len(chanIDs) > 0 {
+		scope = channelsScope
+		params["channels"] = chanIDs
+	}
+
+	cond := fmtCondition(scope, rpm)
+	if format != defTable {
+		var err error
+		if cond, err = payloadCondition(cond, rpm.PayloadFilters, params); err != nil {
+			return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	q := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT
+				%[1]s,
+				row_number() OVER (PARTITION BY channel, publisher ORDER BY %[2]s DESC) AS group_row,
+				count() OVER (PARTITION BY channel, publisher) AS group_total
+			FROM %[3]s FINAL
+			WHERE %[4]s
+		)
+		WHERE group_row > :offset AND group_row <= :offset + :limit
+		ORDER BY channel, publisher, %[2]s DESC`, columns, order, quote(format), cond)
+
+	rows, err := cr.db.NamedQuery(q, params)
+	if err != nil {
+		if exceptionCode(err) == codeUnknownTable {
+			return readers.GroupsPage{}, nil
+		}
+		return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.GroupsPage{
+		PageMetadata: rpm,
+		Groups:       []readers.MessagesGroup{},
+	}
+	for rows.Next() {
+		var channel, publisher string
+		var total uint64
+		var msg readers.Message
+		switch format {
+		case defTable:
+			row := struct {
+				senmlMessage
+				groupRow
+			}{}
+			if err := rows.StructScan(&row); err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			channel, publisher, total, msg = row.Channel, row.Publisher, row.Total, row.Message
+		default:
+			row := struct {
+				jsonMessage
+				groupRow
+			}{}
+			if err := rows.StructScan(&row); err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			m, err := row.toMap(rpm.Fields)
+			if err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			channel, publisher, total, msg = row.Channel, row.Publisher, row.Total, m
+		}
+
+		last := len(page.Groups) - 1
+		if last < 0 || page.Groups[last].Channel != channel || page.Groups[last].Publisher != publisher {
+			page.Groups = append(page.Groups, readers.MessagesGroup{
+				Channel:   channel,
+				Publisher: publisher,
+				Total:     total,
+				Messages:  []readers.Message{},
+			})
+			last++
+		}
+		page.Groups[last].Messages = append(page.Groups[last].Messages, msg)
+	}
+
+	return page, nil
+}
+
+const (
+	firstValue = `argMin(value, time)`
+	lastValue  = `argMax(value, time)`
+)
+
+// previous returns the SQL expression of the aggregate of the previous
+// bucket, the same as the other readers. The aggregate is nullable, so the
+// first bucket of the read has no previous value instead of a zero.
+func previous(agg string) string {
+	return fmt.Sprintf("lagInFrame(toNullable(%s)) OVER (ORDER BY min(time) ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)", agg)
+}
+
+// aggregation returns the SQL expression that aggregates message values
+// of a single time bucket for the aggregation name. Aggregate functions
+// skip NULL values, so values of messages without a numeric value are
+// left out, as they are by the other readers.
+func aggregation(name string) (string, error) {
+	switch agg := strings.ToUpper(name); agg {
+	case "MIN", "MAX", "AVG", "SUM":
+		return fmt.Sprintf("%s(value)", strings.ToLower(agg)), nil
+	case "COUNT":
+		return "toFloat64(count(value))", nil
+	case "P95":
+		// The inclusive exact quantile interpolates the same as percentile_cont.
+		return "quantileExactInclusive(0.95)(value)", nil
+	case "P99":
+		return "quantileExactInclusive(0.99)(value)", nil
+	case "STDDEV":
+		return "stddevSamp(value)", nil
+	case "FIRST":
+		return firstValue, nil
+	case "LAST":
+		return lastValue, nil
+	case "DELTA":
+		return fmt.Sprintf("(%s - coalesce(%s, %s))", lastValue, previous(lastValue), firstValue), nil
+	case "RATE":
+		// Change per second since the last value of the previous bucket.
+		return fmt.Sprintf("((%s - coalesce(%s, %s)) / nullIf((max(time) - coalesce(%s, min(time))) / %d, 0))",
+			lastValue, previous(lastValue), firstValue, previous("max(time)"), timeDivisor), nil
+	default:
+		return "", errInvalidAggregation
+	}
+}
+
+func queryParams(rpm readers.PageMetadata) map[string]interface{} {
+	return map[string]interface{}{
+		"limit":        rpm.Limit,
+		"offset":       rpm.Offset,
+		"domain":       rpm.Domain,
+		"subtopic":     rpm.Subtopic,
+		"publisher":    rpm.Publisher,
+		"name":         rpm.Name,
+		"protocol":     rpm.Protocol,
+		"value":        rpm.Value,
+		"bool_value":   rpm.BoolValue,
+		"string_value": rpm.StringValue,
+		"data_value":   rpm.DataValue,
+		"from":         rpm.From,
+		"to":           rpm.To,
+	}
+}
+
+// fmtCondition returns the query condition that narrows scope, the set of
+// channels the messages are read from, with page metadata filters.
+func fmtCondition(scope string, rpm readers.PageMetadata) string {
+	condition := scope
+
+	var query map[string]interface{}
+	meta, err := json.Marshal(rpm)
+	if err != nil {
+		return condition
+	}
+	if err := json.Unmarshal(meta, &query); err != nil {
+		return condition
+	}
+
+	for name := range query {
+		switch name {
+		case
+			"subtopic",
+			"publisher",
+			"name",
+			"protocol":
+			condition = fmt.Sprintf(`%s AND %s = :%s`, condition, name, name)
+		case "domain":
+			// Messages stored before their domain was stored have no domain.
+			condition = fmt.Sprintf(`%s AND (domain = :domain OR domain IS NULL)`, condition)
+		case "v":
+			comparator := readers.ParseValueComparator(query)
+			condition = fmt.Sprintf(`%s AND value %s :value`, condition, comparator)
+		case "vb":
+			condition = fmt.Sprintf(`%s AND bool_value = :bool_value`, condition)
+		case "vs":
+			comparator := readers.ParseValueComparator(query)
+			switch comparator {
+			case "=":
+				condition = fmt.Sprintf("%s AND string_value = :string_value ", condition)
+			case ">":
+				condition = fmt.Sprintf("%s AND position(string_value, :string_value) > 0 AND string_value <> :string_value", condition)
+			case ">=":
+				condition = fmt.Sprintf("%s AND position(string_value, :string_value) > 0", condition)
+			case "<=":
+				condition = fmt.Sprintf("%s AND position(:string_value, string_value) > 0", condition)
+			case "<":
+				condition = fmt.Sprintf("%s AND position(:string_value, string_value) > 0 AND string_value <> :string_value", condition)
+			}
+		case "vd":
+			comparator := readers.ParseValueComparator(query)
+			condition = fmt.Sprintf(`%s AND data_value %s :data_value`, condition, comparator)
+		case "from":
+			condition = fmt.Sprintf(`%s AND time >= :from`, condition)
+		case "to":
+			condition = fmt.Sprintf(`%s AND time < :to`, condition)
+		}
+	}
+	return condition
+}
+
+// exceptionCode returns the code of the ClickHouse exception, or zero if
+// the error isn't an exception.
+func exceptionCode(err error) int32 {
+	var ex *clickhouse.Exception
+	if stderrors.As(err, &ex) {
+		return ex.Code
+	}
+
+	return 0
+}
+
+// quote quotes the table name of the format, so names of formats that
+// aren't identifiers can't inject SQL, and read no table. Formats are
+// stored in tables named after the lowercase format.
+func quote(table string) string {
+	return "`" + strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(table) + "`"
+}
+
+type senmlMessage struct {
+	ID     string  `db:"id"`
+	Domain *string `db:"domain"`
+	Cursor string  `db:"cursor"`
+	senml.Message
+}
+
+// groupRow holds the columns a grouped read adds to every message row.
+type groupRow struct {
+	Row   uint64 `db:"group_row"`
+	Total uint64 `db:"group_total"`
+}
+
+type jsonMessage struct {
+	ID        string  `db:"id"`
+	Domain    *string `db:"domain"`
+	Cursor    string  `db:"cursor"`
+	Channel   string  `db:"channel"`
+	Created   int64   `db:"created"`
+	Subtopic  string  `db:"subtopic"`
+	Publisher string  `db:"publisher"`
+	Protocol  string  `db:"protocol"`
+	Payload   []byte  `db:"payload"`
+}
+
+// toMap returns the message with the payload projected to the fields, or
+// with the whole payload if no field is given.
+func (msg jsonMessage) toMap(fields []string) (map[string]interface{}, error) {
+	ret := map[string]interface{}{
+		"id":        msg.ID,
+		"channel":   msg.Channel,
+		"created":   msg.Created,
+		"subtopic":  msg.Subtopic,
+		"publisher": msg.Publisher,
+		"protocol":  msg.Protocol,
+		"payload":   map[string]interface{}{},
+	}
+	pld := make(map[string]interface{})
+	if err := json.Unmarshal(msg.Payload, &pld); err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		pld = readers.ProjectPayload(pld, fields)
+	}
+	ret["payload"] = pld
+	return ret, nil
+}
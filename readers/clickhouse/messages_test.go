@@ -0,0 +1,1182 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/consumers/writers"
+	chwriter "github.com/absmach/magistrala/consumers/writers/clickhouse"
+	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/readers"
+	chreader "github.com/absmach/magistrala/readers/clickhouse"
+	"github.com/absmach/supermq/pkg/errors"
+	"github.com/absmach/supermq/pkg/transformers/json"
+	"github.com/absmach/supermq/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	subtopic    = "subtopic"
+	msgsNum     = 100
+	limit       = 10
+	valueFields = 5
+	mqttProt    = "mqtt"
+	httpProt    = "http"
+	msgName     = "temperature"
+	format1     = "format1"
+	format2     = "format2"
+	wrongID     = "0"
+)
+
+var (
+	v   float64 = 5
+	vs          = "stringValue"
+	vb          = true
+	vd          = "dataValue"
+	sum float64 = 42
+)
+
+func TestReadSenml(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	wrongID := testsutil.GenerateUUID(t)
+
+	m := senml.Message{
+		Channel:   chanID,
+		Publisher: pubID,
+		Protocol:  mqttProt,
+	}
+
+	messages := []senml.Message{}
+	valueMsgs := []senml.Message{}
+	boolMsgs := []senml.Message{}
+	stringMsgs := []senml.Message{}
+	dataMsgs := []senml.Message{}
+	queryMsgs := []senml.Message{}
+
+	now := float64(time.Now().Unix())
+	for i := 0; i < msgsNum; i++ {
+		// Mix possible values as well as value sum.
+		msg := m
+		msg.Time = now - float64(i)
+
+		count := i % valueFields
+		switch count {
+		case 0:
+			msg.Value = &v
+			valueMsgs = append(valueMsgs, msg)
+		case 1:
+			msg.BoolValue = &vb
+			boolMsgs = append(boolMsgs, msg)
+		case 2:
+			msg.StringValue = &vs
+			stringMsgs = append(stringMsgs, msg)
+		case 3:
+			msg.DataValue = &vd
+			dataMsgs = append(dataMsgs, msg)
+		case 4:
+			msg.Sum = &sum
+			msg.Subtopic = subtopic
+			msg.Protocol = httpProt
+			msg.Publisher = pubID2
+			msg.Name = msgName
+			queryMsgs = append(queryMsgs, msg)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	// Since messages are not saved in natural order,
+	// cases that return subset of messages are only
+	// checking data result set size, but not content.
+	cases := []struct {
+		desc     string
+		chanID   string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+	}{
+		{
+			desc:   "read message page for existing channel",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromSenml(messages),
+			},
+		},
+		{
+			desc:   "read message page for non-existent channel",
+			chanID: wrongID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message last page",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: msgsNum - 20,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromSenml(messages[msgsNum-20 : msgsNum]),
+			},
+		},
+		{
+			desc:   "read message with non-existent subtopic",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    msgsNum,
+				Subtopic: "not-present",
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message with subtopic",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    uint64(len(queryMsgs)),
+				Subtopic: subtopic,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs),
+			},
+		},
+		{
+			desc:   "read message with publisher",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     uint64(len(queryMsgs)),
+				Publisher: pubID2,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs),
+			},
+		},
+		{
+			desc:   "read message with wrong format",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Format:    "messagess",
+				Offset:    0,
+				Limit:     uint64(len(queryMsgs)),
+				Publisher: pubID2,
+			},
+			page: readers.MessagesPage{
+				Total:    0,
+				Messages: []readers.Message{},
+			},
+		},
+		{
+			desc:   "read message with protocol",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:   0,
+				Limit:    uint64(len(queryMsgs)),
+				Protocol: httpProt,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs),
+			},
+		},
+		{
+			desc:   "read message with name",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				Name:   msgName,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(queryMsgs)),
+				Messages: fromSenml(queryMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				Value:  v,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v,
+				Comparator: readers.EqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and lower-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v + 1,
+				Comparator: readers.LowerThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and lower-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v + 1,
+				Comparator: readers.LowerThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v - 1,
+				Comparator: readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with value and greater-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				Value:      v - 1,
+				Comparator: readers.GreaterThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(valueMsgs)),
+				Messages: fromSenml(valueMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with boolean value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     limit,
+				BoolValue: vb,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(boolMsgs)),
+				Messages: fromSenml(boolMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: vs,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: vs,
+				Comparator:  readers.EqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and lower-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: "a stringValues b",
+				Comparator:  readers.LowerThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and lower-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: vs,
+				Comparator:  readers.LowerThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: "alu",
+				Comparator:  readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with string value and greater-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:      0,
+				Limit:       limit,
+				StringValue: vs,
+				Comparator:  readers.GreaterThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(stringMsgs)),
+				Messages: fromSenml(stringMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:    0,
+				Limit:     limit,
+				DataValue: vd,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value and lower-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				DataValue:  vd + string(rune(1)),
+				Comparator: readers.LowerThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value and lower-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				DataValue:  vd + string(rune(1)),
+				Comparator: readers.LowerThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value and greater-than comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				DataValue:  vd[:len(vd)-1],
+				Comparator: readers.GreaterThanKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with data value and greater-than-or-equal comparator",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset:     0,
+				Limit:      limit,
+				DataValue:  vd[:len(vd)-1],
+				Comparator: readers.GreaterThanEqualKey,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(dataMsgs)),
+				Messages: fromSenml(dataMsgs[0:limit]),
+			},
+		},
+		{
+			desc:   "read message with from",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  uint64(len(messages[0:21])),
+				From:   messages[20].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages[0:21])),
+				Messages: fromSenml(messages[0:21]),
+			},
+		},
+		{
+			desc:   "read message with to",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  uint64(len(messages[21:])),
+				To:     messages[20].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages[21:])),
+				Messages: fromSenml(messages[21:]),
+			},
+		},
+		{
+			desc:   "read message with from/to",
+			chanID: chanID,
+			pageMeta: readers.PageMetadata{
+				Offset: 0,
+				Limit:  limit,
+				From:   messages[5].Time,
+				To:     messages[0].Time,
+			},
+			page: readers.MessagesPage{
+				Total:    5,
+				Messages: fromSenml(messages[1:6]),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(tc.chanID, tc.pageMeta)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.page.Total, result.Total))
+	}
+}
+
+func TestReadSenmlWithAggregation(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// The aggregations are checked against the values all readers share.
+	start := testsutil.AggregationStart
+	messages := testsutil.AggregationMessages(chanID, pubID, mqttProt, msgName)
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	for _, tc := range testsutil.AggregationCases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        start * 1e9,
+			To:          (start + 30) * 1e9,
+			Aggregation: tc.Aggregation,
+			Interval:    testsutil.AggregationInterval,
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.Desc, err))
+		assert.Equal(t, uint64(len(tc.Values)), result.Total, fmt.Sprintf("%s: expected %v got %v", tc.Desc, len(tc.Values), result.Total))
+		require.Len(t, result.Messages, len(tc.Values), fmt.Sprintf("%s: expected %d buckets got %d", tc.Desc, len(tc.Values), len(result.Messages)))
+		for i, value := range tc.Values {
+			msg := result.Messages[i].(senml.Message)
+			bucket := (start + float64((len(tc.Values)-1-i)*10)) * 1e9
+			assert.Equal(t, bucket, msg.Time, fmt.Sprintf("%s: expected bucket %v got %v", tc.Desc, bucket, msg.Time))
+			require.NotNil(t, msg.Value, fmt.Sprintf("%s: expected value of bucket %v", tc.Desc, bucket))
+			assert.InDelta(t, value, *msg.Value, 1e-9, fmt.Sprintf("%s: expected value %v got %v", tc.Desc, value, *msg.Value))
+		}
+	}
+
+	bucket := func(i int, value float64) senml.Message {
+		return senml.Message{
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i*10)) * 1e9,
+			Value:     &value,
+		}
+	}
+
+	cases := []struct {
+		desc        string
+		aggregation string
+		interval    string
+		offset      uint64
+		limit       uint64
+		total       uint64
+		fill        string
+		messages    []senml.Message
+		err         error
+	}{
+		{
+			desc:        "read messages with aggregation over a single bucket",
+			aggregation: "COUNT",
+			interval:    "1m",
+			limit:       limit,
+			total:       1,
+			messages:    []senml.Message{bucket(0, 30)},
+		},
+		{
+			desc:        "read messages page with aggregation",
+			aggregation: "MAX",
+			interval:    "10s",
+			offset:      1,
+			limit:       1,
+			total:       3,
+			messages:    []senml.Message{bucket(1, 19)},
+		},
+		{
+			desc:        "read messages with invalid aggregation",
+			aggregation: "MEDIAN",
+			interval:    "10s",
+			limit:       limit,
+			err:         readers.ErrReadMessages,
+		},
+		{
+			desc:        "read messages with invalid interval",
+			aggregation: "MAX",
+			interval:    "1 minute",
+			limit:       limit,
+			err:         readers.ErrReadMessages,
+		},
+		{
+			desc:        "read messages with gap fill",
+			aggregation: "MAX",
+			interval:    "10s",
+			fill:        readers.FillNull,
+			limit:       limit,
+			err:         readers.ErrInvalidFill,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Offset:      tc.offset,
+			Limit:       tc.limit,
+			From:        start * 1e9,
+			To:          (start + 30) * 1e9,
+			Aggregation: tc.aggregation,
+			Interval:    tc.interval,
+			Fill:        tc.fill,
+		})
+		if tc.err != nil {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of aggregated Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlWithCursor(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Messages of both publishers share timestamps, so pages are split
+	// within the same time as well.
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i/2)*1e9,
+			Value:     &v,
+		}
+		if i%2 == 1 {
+			msg.Publisher = pubID2
+		}
+		messages = append(messages, msg)
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	var read []readers.Message
+	pm := readers.PageMetadata{Limit: limit, SkipTotal: true}
+	for pages := 0; pages < 4; pages++ {
+		page, err := reader.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected no total got %d", page.Total))
+		read = append(read, page.Messages...)
+		if page.NextCursor == "" {
+			break
+		}
+		pm.Cursor = page.NextCursor
+	}
+	assert.ElementsMatch(t, fromSenml(messages), read, "got incorrect list of Messages from ReadAll() with cursor")
+	for i := 1; i < len(read); i++ {
+		assert.GreaterOrEqual(t, read[i-1].(senml.Message).Time, read[i].(senml.Message).Time, "expected messages ordered by time")
+	}
+
+	page, err := reader.ReadAll(chanID, readers.PageMetadata{Limit: limit})
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, uint64(len(messages)), page.Total, fmt.Sprintf("expected %d got %d", len(messages), page.Total))
+	assert.NotEmpty(t, page.NextCursor, "expected cursor of a full page")
+
+	_, err = reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: "invalid"})
+	assert.True(t, errors.Contains(err, readers.ErrInvalidCursor), fmt.Sprintf("expected %s got %s", readers.ErrInvalidCursor, err))
+}
+
+func TestReadLatest(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Every publisher sends a reading of two names, older readings first.
+	var messages []senml.Message
+	latest := map[string]senml.Message{}
+	for i := 0; i < 10; i++ {
+		val := float64(i)
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now + float64(i)*1e9,
+			Value:     &val,
+		}
+		switch i % 4 {
+		case 1:
+			msg.Name = "humidity"
+		case 2:
+			msg.Publisher = pubID2
+		case 3:
+			msg.Publisher = pubID2
+			msg.Name = "humidity"
+			msg.Subtopic = subtopic
+		}
+		messages = append(messages, msg)
+		latest[msg.Publisher+msg.Subtopic+msg.Name] = msg
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	// Latest messages ordered by time descending.
+	expected := []senml.Message{messages[9], messages[8], messages[7], messages[6]}
+	for _, msg := range expected {
+		require.Equal(t, latest[msg.Publisher+msg.Subtopic+msg.Name], msg)
+	}
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		pm       readers.PageMetadata
+		total    uint64
+		messages []senml.Message
+	}{
+		{
+			desc:     "read latest messages",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit},
+			total:    4,
+			messages: expected,
+		},
+		{
+			desc:     "read latest messages with limit and offset",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Offset: 1, Limit: 2},
+			total:    4,
+			messages: expected[1:3],
+		},
+		{
+			desc:     "read latest messages of publisher",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Publisher: pubID2},
+			total:    2,
+			messages: []senml.Message{messages[7], messages[6]},
+		},
+		{
+			desc:     "read latest messages with name",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Name: "humidity"},
+			total:    2,
+			messages: []senml.Message{messages[9], messages[7]},
+		},
+		{
+			desc:   "read latest messages of non-existent channel",
+			chanID: testsutil.GenerateUUID(t),
+			pm:     readers.PageMetadata{Limit: limit},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadLatest(tc.chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of latest Messages from ReadLatest()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlGroups(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID1 := testsutil.GenerateUUID(t)
+	chanID2 := testsutil.GenerateUUID(t)
+	if chanID2 < chanID1 {
+		chanID1, chanID2 = chanID2, chanID1
+	}
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs1, msgs2 []senml.Message
+	for i := 0; i < 15; i++ {
+		msg := senml.Message{
+			Channel:   chanID1,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		}
+		msgs1 = append(msgs1, msg)
+		if i < 5 {
+			msg.Channel = chanID2
+			msgs2 = append(msgs2, msg)
+		}
+	}
+	// Only the messages of the first channel have a domain.
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), msgs1)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), msgs2)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+		pm      readers.PageMetadata
+		groups  []readers.MessagesGroup
+	}{
+		{
+			desc:    "read groups of multiple channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:limit])},
+				{Channel: chanID2, Publisher: pubID, Total: 5, Messages: fromSenml(msgs2)},
+			},
+		},
+		{
+			desc:    "read groups of multiple channels with offset",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 10, Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[10:])},
+			},
+		},
+		{
+			desc: "read groups of domain",
+			pm:   readers.PageMetadata{Limit: 3},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:3])},
+			},
+		},
+		{
+			desc:    "read groups of non-existent channel",
+			chanIDs: []string{testsutil.GenerateUUID(t)},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups:  []readers.MessagesGroup{},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadGroups(domainID, tc.chanIDs, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.groups, result.Groups, fmt.Sprintf("%s: got incorrect groups from ReadGroups()", tc.desc))
+	}
+}
+
+func TestReadSenmlOfDomain(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs []senml.Message
+	for i := 0; i < 3; i++ {
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		})
+	}
+	domainMsgs, otherMsgs, legacyMsgs := msgs[0:1], msgs[1:2], msgs[2:3]
+
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), domainMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(writers.WithDomain(context.TODO(), testsutil.GenerateUUID(t)), otherMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), legacyMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := chreader.New(db)
+
+	cases := []struct {
+		desc     string
+		pm       readers.PageMetadata
+		messages []senml.Message
+	}{
+		{
+			desc:     "read messages of channel",
+			pm:       readers.PageMetadata{Limit: limit},
+			messages: msgs,
+		},
+		{
+			desc:     "read messages of channel of domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: domainID},
+			messages: append(append([]senml.Message{}, domainMsgs...), legacyMsgs...),
+		},
+		{
+			desc:     "read messages of channel of other domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: testsutil.GenerateUUID(t)},
+			messages: legacyMsgs,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, uint64(len(tc.messages)), result.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, len(tc.messages), result.Total))
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	writer := chwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	id1 := testsutil.GenerateUUID(t)
+	m := json.Message{
+		Channel:   id1,
+		Publisher: id1,
+		Created:   time.Now().Unix(),
+		Subtopic:  "subtopic/format/some_json",
+		Protocol:  "coap",
+		Payload: map[string]interface{}{
+			"field_1": 123.0,
+			"field_2": "value",
+			"field_3": false,
+			"field_4": 12.344,
+			"field_5": map[string]interface{}{
+				"field_1": "value",
+				"field_2": 42.0,
+			},
+		},
+	}
+	messages1 := json.Messages{
+		Format: format1,
+	}
+	msgs1 := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i++ {
+		msg := m
+		messages1.Data = append(messages1.Data, msg)
+		m := toMap(msg)
+		msgs1 = append(msgs1, m)
+	}
+
+	err := writer.ConsumeBlocking(context.TODO(), messages1)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	id2 := testsutil.GenerateUUID(t)
+	m = json.Message{
+		Channel:   id2,
+		Publisher: id2,
+		Created:   time.Now().Unix(),
+		Subtopic:  "subtopic/other_format/some_other_json",
+		Protocol:  "udp",
+		Payload: map[string]interface{}{
+			"field_1":     "other_value",
+			"false_value": false,
+			"field_pi":    3.14159265,
+		},
+	}
+	messages2 := json.Messages{
+		Format: format2,
+	}
+	msgs2 := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i++ {
+		msg := m
+		if i%2 == 0 {
+			msg.Protocol = httpProt
+		}
+		messages2.Data = append(messages2.Data, msg)
+		m := toMap(msg)
+		msgs2 = append(msgs2, m)
+	}
+
+	err = writer.ConsumeBlocking(context.TODO(), messages2)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	httpMsgs := []map[string]interface{}{}
+	for i := 0; i < msgsNum; i += 2 {
+		httpMsgs = append(httpMsgs, msgs2[i])
+	}
+
+	projected := []map[string]interface{}{}
+	for _, m := range msgs1 {
+		pm := map[string]interface{}{}
+		for k, v := range m {
+			pm[k] = v
+		}
+		pm["payload"] = map[string]interface{}{
+			"field_1": 123.0,
+			"field_5": map[string]interface{}{"field_2": 42.0},
+		}
+		projected = append(projected, pm)
+	}
+
+	reader := chreader.New(db)
+
+	cases := map[string]struct {
+		chanID   string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+	}{
+		"read message page for existing channel": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message page for non-existent channel": {
+			chanID: wrongID,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message last page": {
+			chanID: id2,
+			pageMeta: readers.PageMetadata{
+				Format: messages2.Format,
+				Offset: msgsNum - 20,
+				Limit:  msgsNum,
+			},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Messages: fromJSON(msgs2[msgsNum-20 : msgsNum]),
+			},
+		},
+		"read message with payload equality filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_1"}, Operator: readers.PayloadEqual, Value: "value"},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with payload range filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_2"}, Operator: readers.PayloadGreaterThan, Value: 40.0},
+					{Path: []string{"field_4"}, Operator: readers.PayloadLowerThanEqual, Value: 12.344},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with non-matching payload filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_1"}, Operator: readers.PayloadNotEqual, Value: 123.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload range filter on string value": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_2"}, Operator: readers.PayloadGreaterThan, Value: 1.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload fields": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				Fields: []string{"field_1", "field_5.field_2", "missing"},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(projected[:10]),
+			},
+		},
+		"read message with protocol": {
+			chanID: id2,
+			pageMeta: readers.PageMetadata{
+				Format:   messages2.Format,
+				Offset:   0,
+				Limit:    uint64(msgsNum / 2),
+				Protocol: httpProt,
+			},
+			page: readers.MessagesPage{
+				Total:    uint64(msgsNum / 2),
+				Messages: fromJSON(httpMsgs),
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.ReadAll(tc.chanID, tc.pageMeta)
+		for i := 0; i < len(result.Messages); i++ {
+			m := result.Messages[i]
+			// Remove id as it is not sent by the client.
+			delete(m.(map[string]interface{}), "id")
+			result.Messages[i] = m
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: got incorrect list of json Messages from ReadAll()", desc))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
+	}
+}
+
+func fromSenml(msg []senml.Message) []readers.Message {
+	var ret []readers.Message
+	for _, m := range msg {
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func fromJSON(msg []map[string]interface{}) []readers.Message {
+	var ret []readers.Message
+	for _, m := range msg {
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func toMap(msg json.Message) map[string]interface{} {
+	return map[string]interface{}{
+		"channel":   msg.Channel,
+		"created":   msg.Created,
+		"subtopic":  msg.Subtopic,
+		"publisher": msg.Publisher,
+		"protocol":  msg.Protocol,
+		"payload":   map[string]interface{}(msg.Payload),
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/absmach/magistrala/readers"
+)
+
+// Types of JSON values, as returned by JSONType.
+const (
+	jsonNumber = `('Int64', 'UInt64', 'Double')`
+	jsonString = `('String')`
+	jsonBool   = `('Bool')`
+	jsonNull   = `('Null')`
+)
+
+// payloadCondition adds the payload filters of JSON messages to the
+// condition, and their paths and values to the query parameters. Values
+// are compared only to the values of the same JSON type, so objects and
+// arrays, which have no single type, can't be compared.
+func payloadCondition(cond string, filters []readers.PayloadFilter, params map[string]interface{}) (string, error) {
+	conds := []string{cond}
+	for i, f := range filters {
+		path := payloadPath(i, f.Path, params)
+		value := fmt.Sprintf("payload_value_%d", i)
+
+		var kind, extract string
+		switch f.Value.(type) {
+		case float64:
+			kind, extract = jsonNumber, "JSONExtractFloat"
+		case string:
+			kind, extract = jsonString, "JSONExtractString"
+		case bool:
+			kind, extract = jsonBool, "JSONExtractBool"
+		case nil:
+			kind = jsonNull
+		default:
+			return "", readers.ErrInvalidPayloadFilter
+		}
+
+		switch f.Operator {
+		case readers.PayloadEqual, readers.PayloadNotEqual:
+			// Missing values are of the Null type, so nulls must be present.
+			c := fmt.Sprintf(`(JSONHas(payload, %[1]s) AND JSONType(payload, %[1]s) IN %[2]s`, path, kind)
+			if extract != "" {
+				params[value] = f.Value
+				c = fmt.Sprintf(`%s AND %s(payload, %s) = :%s`, c, extract, path, value)
+			}
+			c += ")"
+			if f.Operator == readers.PayloadNotEqual {
+				c = "NOT " + c
+			}
+			conds = append(conds, c)
+		case readers.PayloadLowerThan, readers.PayloadLowerThanEqual, readers.PayloadGreaterThan, readers.PayloadGreaterThanEqual:
+			if kind != jsonNumber && kind != jsonString {
+				return "", readers.ErrInvalidPayloadFilter
+			}
+			params[value] = f.Value
+			conds = append(conds, fmt.Sprintf(`(JSONType(payload, %[1]s) IN %[2]s AND %[3]s(payload, %[1]s) %[4]s :%[5]s)`, path, kind, extract, f.Operator, value))
+		default:
+			return "", readers.ErrInvalidPayloadFilter
+		}
+	}
+
+	return strings.Join(conds, " AND "), nil
+}
+
+// payloadPath adds the keys of the path to the query parameters and returns
+// their placeholders, which JSON functions take as separate arguments.
+func payloadPath(filter int, path []string, params map[string]interface{}) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		name := fmt.Sprintf("payload_path_%d_%d", filter, i)
+		params[name] = key
+		keys[i] = ":" + name
+	}
+
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clickhouse_test contains tests for ClickHouse repository
+// implementations.
+package clickhouse_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	chwriter "github.com/absmach/magistrala/consumers/writers/clickhouse"
+	chclient "github.com/absmach/magistrala/pkg/clickhouse"
+	"github.com/jmoiron/sqlx"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	container, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "clickhouse/clickhouse-server",
+		Tag:        "24.8-alpine",
+		Env: []string{
+			"CLICKHOUSE_USER=test",
+			"CLICKHOUSE_PASSWORD=test",
+			"CLICKHOUSE_DB=test",
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Fatalf("Could not start container: %s", err)
+	}
+
+	dbConfig := chclient.Config{
+		Host: "localhost",
+		Port: container.GetPort("9000/tcp"),
+		User: "test",
+		Pass: "test",
+		Name: "test",
+	}
+
+	if err := pool.Retry(func() error {
+		db = chclient.Connect(dbConfig)
+		return db.Ping()
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+	db.Close()
+
+	db, err = chclient.Setup(dbConfig, chwriter.Schema())
+	if err != nil {
+		log.Fatalf("Could not setup test DB connection: %s", err)
+	}
+
+	code := m.Run()
+
+	// Defers will not be run when using os.Exit
+	db.Close()
+	if err := pool.Purge(container); err != nil {
+		log.Fatalf("Could not purge container: %s", err)
+	}
+
+	os.Exit(code)
+}
@@ -2,4 +2,9 @@
 // SPDX-License-Identifier: Apache-2.0
 
 // Package readers provides a set of readers for various formats.
+//
+// The page metadata and the message repository extend the ones of the
+// supermq readers with gap filling, cursors, streams, groups and payload
+// filters, which the supermq readers API can't carry. Messages, the value
+// comparators and the read error are the ones of the supermq readers.
 package readers
@@ -0,0 +1,136 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"context"
+	"errors"
+
+	smqreaders "github.com/absmach/supermq/readers"
+)
+
+// Comparison operator keys of the value filter, shared with the supermq
+// readers.
+const (
+	// EqualKey represents the equal comparison operator key.
+	EqualKey = smqreaders.EqualKey
+	// LowerThanKey represents the lower-than comparison operator key.
+	LowerThanKey = smqreaders.LowerThanKey
+	// LowerThanEqualKey represents the lower-than-or-equal comparison operator key.
+	LowerThanEqualKey = smqreaders.LowerThanEqualKey
+	// GreaterThanKey represents the greater-than comparison operator key.
+	GreaterThanKey = smqreaders.GreaterThanKey
+	// GreaterThanEqualKey represents the greater-than-or-equal comparison operator key.
+	GreaterThanEqualKey = smqreaders.GreaterThanEqualKey
+)
+
+// Fill strategies for the buckets of an aggregated read that contain no messages.
+const (
+	// FillNone omits empty buckets.
+	FillNone = "none"
+	// FillNull returns empty buckets without a value.
+	FillNull = "null"
+	// FillPrevious carries the value of the last non-empty bucket forward.
+	FillPrevious = "previous"
+	// FillLinear interpolates the value between the surrounding non-empty buckets.
+	FillLinear = "linear"
+	// FillConstant sets the value of empty buckets to FillValue.
+	FillConstant = "constant"
+)
+
+var (
+	// ErrReadMessages indicates failure occurred while reading messages from database.
+	ErrReadMessages = smqreaders.ErrReadMessages
+
+	// ErrInvalidFill indicates an unsupported fill strategy or a fill without aggregation.
+	ErrInvalidFill = errors.New("invalid fill")
+
+	// ErrInvalidCursor indicates a malformed cursor or a cursor combined with
+	// offset or aggregation.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// MessageRepository specifies message reader API.
+type MessageRepository interface {
+	// ReadAll skips given number of messages for given channel, or the
+	// messages up to the cursor, and returns next limited number of messages.
+	ReadAll(chanID string, pm PageMetadata) (MessagesPage, error)
+
+	// StreamAll reads the messages ReadAll reads with a single query, and
+	// calls send with every batch of limit messages as they are read, so
+	// the messages are never all kept in memory. The offset is skipped once
+	// and the total isn't counted.
+	StreamAll(ctx context.Context, chanID string, pm PageMetadata, send func(MessagesPage) error) error
+
+	// ReadLatest returns the most recent SenML message of every subtopic,
+	// publisher and name of the given channel.
+	ReadLatest(chanID string, pm PageMetadata) (MessagesPage, error)
+
+	// ReadGroups returns messages of the given channels, or of all the
+	// channels of the domain if no channel is given, grouped by channel
+	// and publisher. Offset and limit apply to each group.
+	ReadGroups(domainID string, chanIDs []string, pm PageMetadata) (GroupsPage, error)
+}
+
+// Message represents any message format.
+type Message = smqreaders.Message
+
+// MessagesPage contains page related metadata as well as list of messages that
+// belong to this page.
+type MessagesPage struct {
+	PageMetadata
+	Total    uint64
+	Messages []Message
+	// NextCursor points after the last message of a full page, and is empty
+	// when there are no more messages to read.
+	NextCursor string
+}
+
+// MessagesGroup contains messages of a single channel and publisher.
+type MessagesGroup struct {
+	Channel   string    `json:"channel"`
+	Publisher string    `json:"publisher"`
+	Total     uint64    `json:"total"`
+	Messages  []Message `json:"messages"`
+}
+
+// GroupsPage contains page related metadata as well as list of message
+// groups that belong to this page.
+type GroupsPage struct {
+	PageMetadata
+	Groups []MessagesGroup
+}
+
+// PageMetadata represents the parameters used to create database queries.
+type PageMetadata struct {
+	Offset         uint64          `json:"offset"`
+	Limit          uint64          `json:"limit"`
+	Domain         string          `json:"domain,omitempty"`
+	Subtopic       string          `json:"subtopic,omitempty"`
+	Publisher      string          `json:"publisher,omitempty"`
+	Protocol       string          `json:"protocol,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	Value          float64         `json:"v,omitempty"`
+	Comparator     string          `json:"comparator,omitempty"`
+	BoolValue      bool            `json:"vb,omitempty"`
+	StringValue    string          `json:"vs,omitempty"`
+	DataValue      string          `json:"vd,omitempty"`
+	From           float64         `json:"from,omitempty"`
+	To             float64         `json:"to,omitempty"`
+	Format         string          `json:"format,omitempty"`
+	Aggregation    string          `json:"aggregation,omitempty"`
+	Interval       string          `json:"interval,omitempty"`
+	Fill           string          `json:"fill,omitempty"`
+	FillValue      float64         `json:"fill_value,omitempty"`
+	Cursor         string          `json:"cursor,omitempty"`
+	SkipTotal      bool            `json:"skip_total,omitempty"`
+	PayloadFilters []PayloadFilter `json:"payload_filters,omitempty"`
+	Fields         []string        `json:"fields,omitempty"`
+}
+
+// ParseValueComparator converts the comparison operator key of the query
+// into its mathematical notation, which is equality by default.
+func ParseValueComparator(query map[string]interface{}) string {
+	return smqreaders.ParseValueComparator(query)
+}
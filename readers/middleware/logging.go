@@ -6,10 +6,11 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
 	"time"
 
-	"github.com/absmach/supermq/readers"
+	"github.com/absmach/magistrala/readers"
 )
 
 var _ readers.MessageRepository = (*loggingMiddleware)(nil)
@@ -54,3 +55,87 @@ func (lm *loggingMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (p
 
 	return lm.svc.ReadAll(chanID, rpm)
 }
+
+func (lm *loggingMiddleware) StreamAll(ctx context.Context, chanID string, rpm readers.PageMetadata, send func(readers.MessagesPage) error) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", chanID),
+			slog.Group("page",
+				slog.Uint64("offset", rpm.Offset),
+				slog.Uint64("limit", rpm.Limit),
+			),
+		}
+		if rpm.Subtopic != "" {
+			args = append(args, slog.String("subtopic", rpm.Subtopic))
+		}
+		if rpm.Publisher != "" {
+			args = append(args, slog.String("publisher", rpm.Publisher))
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Stream all failed", args...)
+			return
+		}
+		lm.logger.Info("Stream all completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.StreamAll(ctx, chanID, rpm, send)
+}
+
+func (lm *loggingMiddleware) ReadLatest(chanID string, rpm readers.PageMetadata) (page readers.MessagesPage, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("channel_id", chanID),
+			slog.Group("page",
+				slog.Uint64("offset", rpm.Offset),
+				slog.Uint64("limit", rpm.Limit),
+				slog.Uint64("total", page.Total),
+			),
+		}
+		if rpm.Subtopic != "" {
+			args = append(args, slog.String("subtopic", rpm.Subtopic))
+		}
+		if rpm.Publisher != "" {
+			args = append(args, slog.String("publisher", rpm.Publisher))
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Read latest failed", args...)
+			return
+		}
+		lm.logger.Info("Read latest completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ReadLatest(chanID, rpm)
+}
+
+func (lm *loggingMiddleware) ReadGroups(domainID string, chanIDs []string, rpm readers.PageMetadata) (page readers.GroupsPage, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("domain_id", domainID),
+			slog.Any("channel_ids", chanIDs),
+			slog.Group("page",
+				slog.Uint64("offset", rpm.Offset),
+				slog.Uint64("limit", rpm.Limit),
+				slog.Int("groups", len(page.Groups)),
+			),
+		}
+		if rpm.Name != "" {
+			args = append(args, slog.String("name", rpm.Name))
+		}
+		if rpm.Publisher != "" {
+			args = append(args, slog.String("publisher", rpm.Publisher))
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Read groups failed", args...)
+			return
+		}
+		lm.logger.Info("Read groups completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ReadGroups(domainID, chanIDs, rpm)
+}
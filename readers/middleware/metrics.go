@@ -6,9 +6,10 @@
 package middleware
 
 import (
+	"context"
 	"time"
 
-	"github.com/absmach/supermq/readers"
+	"github.com/absmach/magistrala/readers"
 	"github.com/go-kit/kit/metrics"
 )
 
@@ -37,3 +38,30 @@ func (mm *metricsMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (r
 
 	return mm.svc.ReadAll(chanID, rpm)
 }
+
+func (mm *metricsMiddleware) StreamAll(ctx context.Context, chanID string, rpm readers.PageMetadata, send func(readers.MessagesPage) error) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "stream_all").Add(1)
+		mm.latency.With("method", "stream_all").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.StreamAll(ctx, chanID, rpm, send)
+}
+
+func (mm *metricsMiddleware) ReadLatest(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_latest").Add(1)
+		mm.latency.With("method", "read_latest").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadLatest(chanID, rpm)
+}
+
+func (mm *metricsMiddleware) ReadGroups(domainID string, chanIDs []string, rpm readers.PageMetadata) (readers.GroupsPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_groups").Add(1)
+		mm.latency.With("method", "read_groups").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadGroups(domainID, chanIDs, rpm)
+}
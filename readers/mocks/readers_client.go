@@ -42,6 +42,148 @@ func (_m *ReadersServiceClient) EXPECT() *ReadersServiceClient_Expecter {
 	return &ReadersServiceClient_Expecter{mock: &_m.Mock}
 }
 
+// ReadGroups provides a mock function for the type ReadersServiceClient
+func (_mock *ReadersServiceClient) ReadGroups(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (*v1.ReadGroupsRes, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, in, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, in)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadGroups")
+	}
+
+	var r0 *v1.ReadGroupsRes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, []grpc.CallOption) (*v1.ReadGroupsRes, error)); ok {
+		return returnFunc(ctx, in, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) *v1.ReadGroupsRes); ok {
+		r0 = returnFunc(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ReadGroupsRes)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) error); ok {
+		r1 = returnFunc(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ReadersServiceClient_ReadGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadGroups'
+type ReadersServiceClient_ReadGroups_Call struct {
+	*mock.Call
+}
+
+// ReadGroups is a helper method to define mock.On call
+//   - ctx
+//   - in
+//   - opts
+func (_e *ReadersServiceClient_Expecter) ReadGroups(ctx interface{}, in interface{}, opts ...interface{}) *ReadersServiceClient_ReadGroups_Call {
+	return &ReadersServiceClient_ReadGroups_Call{Call: _e.mock.On("ReadGroups",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *ReadersServiceClient_ReadGroups_Call) Run(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption)) *ReadersServiceClient_ReadGroups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*v1.ReadMessagesReq), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *ReadersServiceClient_ReadGroups_Call) Return(readGroupsRes *v1.ReadGroupsRes, err error) *ReadersServiceClient_ReadGroups_Call {
+	_c.Call.Return(readGroupsRes, err)
+	return _c
+}
+
+func (_c *ReadersServiceClient_ReadGroups_Call) RunAndReturn(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (*v1.ReadGroupsRes, error)) *ReadersServiceClient_ReadGroups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadLatest provides a mock function for the type ReadersServiceClient
+func (_mock *ReadersServiceClient) ReadLatest(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (*v1.ReadMessagesRes, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, in, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, in)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadLatest")
+	}
+
+	var r0 *v1.ReadMessagesRes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, []grpc.CallOption) (*v1.ReadMessagesRes, error)); ok {
+		return returnFunc(ctx, in, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) *v1.ReadMessagesRes); ok {
+		r0 = returnFunc(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ReadMessagesRes)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) error); ok {
+		r1 = returnFunc(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ReadersServiceClient_ReadLatest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadLatest'
+type ReadersServiceClient_ReadLatest_Call struct {
+	*mock.Call
+}
+
+// ReadLatest is a helper method to define mock.On call
+//   - ctx
+//   - in
+//   - opts
+func (_e *ReadersServiceClient_Expecter) ReadLatest(ctx interface{}, in interface{}, opts ...interface{}) *ReadersServiceClient_ReadLatest_Call {
+	return &ReadersServiceClient_ReadLatest_Call{Call: _e.mock.On("ReadLatest",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *ReadersServiceClient_ReadLatest_Call) Run(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption)) *ReadersServiceClient_ReadLatest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*v1.ReadMessagesReq), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *ReadersServiceClient_ReadLatest_Call) Return(readMessagesRes *v1.ReadMessagesRes, err error) *ReadersServiceClient_ReadLatest_Call {
+	_c.Call.Return(readMessagesRes, err)
+	return _c
+}
+
+func (_c *ReadersServiceClient_ReadLatest_Call) RunAndReturn(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (*v1.ReadMessagesRes, error)) *ReadersServiceClient_ReadLatest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReadMessages provides a mock function for the type ReadersServiceClient
 func (_mock *ReadersServiceClient) ReadMessages(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (*v1.ReadMessagesRes, error) {
 	var tmpRet mock.Arguments
@@ -112,3 +254,74 @@ func (_c *ReadersServiceClient_ReadMessages_Call) RunAndReturn(run func(ctx cont
 	_c.Call.Return(run)
 	return _c
 }
+
+// StreamMessages provides a mock function for the type ReadersServiceClient
+func (_mock *ReadersServiceClient) StreamMessages(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.ReadMessagesRes], error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, in, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, in)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamMessages")
+	}
+
+	var r0 grpc.ServerStreamingClient[v1.ReadMessagesRes]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, []grpc.CallOption) (grpc.ServerStreamingClient[v1.ReadMessagesRes], error)); ok {
+		return returnFunc(ctx, in, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) grpc.ServerStreamingClient[v1.ReadMessagesRes]); ok {
+		r0 = returnFunc(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(grpc.ServerStreamingClient[v1.ReadMessagesRes])
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.ReadMessagesReq, ...grpc.CallOption) error); ok {
+		r1 = returnFunc(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ReadersServiceClient_StreamMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamMessages'
+type ReadersServiceClient_StreamMessages_Call struct {
+	*mock.Call
+}
+
+// StreamMessages is a helper method to define mock.On call
+//   - ctx
+//   - in
+//   - opts
+func (_e *ReadersServiceClient_Expecter) StreamMessages(ctx interface{}, in interface{}, opts ...interface{}) *ReadersServiceClient_StreamMessages_Call {
+	return &ReadersServiceClient_StreamMessages_Call{Call: _e.mock.On("StreamMessages",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *ReadersServiceClient_StreamMessages_Call) Run(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption)) *ReadersServiceClient_StreamMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*v1.ReadMessagesReq), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *ReadersServiceClient_StreamMessages_Call) Return(serverStreamingClient grpc.ServerStreamingClient[v1.ReadMessagesRes], err error) *ReadersServiceClient_StreamMessages_Call {
+	_c.Call.Return(serverStreamingClient, err)
+	return _c
+}
+
+func (_c *ReadersServiceClient_StreamMessages_Call) RunAndReturn(run func(ctx context.Context, in *v1.ReadMessagesReq, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.ReadMessagesRes], error)) *ReadersServiceClient_StreamMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
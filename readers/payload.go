@@ -0,0 +1,128 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Payload filter operators.
+const (
+	PayloadEqual            = "="
+	PayloadNotEqual         = "!="
+	PayloadLowerThan        = "<"
+	PayloadLowerThanEqual   = "<="
+	PayloadGreaterThan      = ">"
+	PayloadGreaterThanEqual = ">="
+)
+
+const payloadPrefix = "payload."
+
+// ErrInvalidPayloadFilter indicates a malformed payload filter or field.
+var ErrInvalidPayloadFilter = errors.New("invalid payload filter")
+
+// PayloadFilter compares the value at a path inside JSON message payloads.
+type PayloadFilter struct {
+	Path     []string    `json:"path"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// ParsePayloadFilter parses a filter expression such as
+// payload.engine.rpm>3000. The value is parsed as JSON, and is a string
+// if it isn't valid JSON. Only numbers and strings can be compared with
+// ordering operators.
+func ParsePayloadFilter(expr string) (PayloadFilter, error) {
+	if !strings.HasPrefix(expr, payloadPrefix) {
+		return PayloadFilter{}, ErrInvalidPayloadFilter
+	}
+
+	i := strings.IndexAny(expr, "!=<>")
+	if i < 0 {
+		return PayloadFilter{}, ErrInvalidPayloadFilter
+	}
+	op := expr[i : i+1]
+	if next := i + 1; next < len(expr) && expr[next] == '=' {
+		op = expr[i : next+1]
+	}
+
+	switch op {
+	case PayloadEqual, PayloadNotEqual, PayloadLowerThan, PayloadLowerThanEqual, PayloadGreaterThan, PayloadGreaterThanEqual:
+	default:
+		return PayloadFilter{}, ErrInvalidPayloadFilter
+	}
+
+	path, err := ParsePayloadPath(strings.TrimPrefix(expr[:i], payloadPrefix))
+	if err != nil {
+		return PayloadFilter{}, err
+	}
+
+	raw := expr[i+len(op):]
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+
+	switch value.(type) {
+	case float64, string:
+	default:
+		if op != PayloadEqual && op != PayloadNotEqual {
+			return PayloadFilter{}, ErrInvalidPayloadFilter
+		}
+	}
+
+	return PayloadFilter{Path: path, Operator: op, Value: value}, nil
+}
+
+// ParsePayloadPath splits a dot separated path inside the payload, such as
+// engine.rpm, into its keys.
+func ParsePayloadPath(path string) ([]string, error) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, ErrInvalidPayloadFilter
+		}
+	}
+
+	return keys, nil
+}
+
+// ProjectPayload returns the payload with only the values at the given
+// dot separated paths, keeping their nesting. Missing paths are left out.
+func ProjectPayload(payload map[string]interface{}, fields []string) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, field := range fields {
+		keys := strings.Split(field, ".")
+
+		var val interface{} = payload
+		for _, key := range keys {
+			obj, ok := val.(map[string]interface{})
+			if !ok {
+				val = nil
+				break
+			}
+			if val, ok = obj[key]; !ok {
+				break
+			}
+		}
+		if val == nil {
+			continue
+		}
+
+		dst := ret
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := dst[key].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				dst[key] = next
+			}
+			dst = next
+		}
+		dst[keys[len(keys)-1]] = val
+	}
+
+	return ret
+}
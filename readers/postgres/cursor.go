@@ -0,0 +1,93 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/absmach/magistrala/readers"
+	"github.com/absmach/supermq/pkg/errors"
+)
+
+// cursorColumn is a column of the unique key messages are paged by.
+type cursorColumn struct {
+	name string
+	kind string
+}
+
+// Keys identifying a message of a channel, in the order messages are read.
+var (
+	senmlCursor = []cursorColumn{
+		{"time", "DOUBLE PRECISION"},
+		{"id", "UUID"},
+	}
+	jsonCursor = []cursorColumn{
+		{"created", "BIGINT"},
+		{"id", "UUID"},
+	}
+)
+
+// cursorOrder returns the ORDER BY clause that reads messages newest first.
+func cursorOrder(cols []cursorColumn) string {
+	order := make([]string, len(cols))
+	for i, col := range cols {
+		order[i] = col.name + " DESC"
+	}
+	return strings.Join(order, ", ")
+}
+
+// cursorValue returns the expression that renders the key of a row as a
+// JSON array, so the cursor keeps the exact stored values.
+func cursorValue(cols []cursorColumn) string {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+	}
+	return fmt.Sprintf(`CAST(json_build_array(%s) AS TEXT)`, strings.Join(names, ", "))
+}
+
+// cursorCondition returns the condition that selects messages after the cursor.
+func cursorCondition(cols []cursorColumn) string {
+	names := make([]string, len(cols))
+	params := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+		params[i] = fmt.Sprintf(`CAST(:cursor_%d AS %s)`, i, col.kind)
+	}
+	return fmt.Sprintf(`(%s) < (%s)`, strings.Join(names, ", "), strings.Join(params, ", "))
+}
+
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// decodeCursor adds the key values of the cursor to the query parameters.
+func decodeCursor(cursor string, cols []cursorColumn, params map[string]interface{}) error {
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return errors.Wrap(readers.ErrInvalidCursor, err)
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(key, &values); err != nil {
+		return errors.Wrap(readers.ErrInvalidCursor, err)
+	}
+	if len(values) != len(cols) {
+		return readers.ErrInvalidCursor
+	}
+
+	for i, val := range values {
+		var str string
+		if err := json.Unmarshal(val, &str); err != nil {
+			// Numbers are passed as they were rendered by the database.
+			str = string(val)
+		}
+		params[fmt.Sprintf("cursor_%d", i)] = str
+	}
+
+	return nil
+}
@@ -9,12 +9,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/absmach/magistrala/consumers/writers"
 	pwriter "github.com/absmach/magistrala/consumers/writers/postgres"
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	schemaspg "github.com/absmach/magistrala/consumers/writers/schemas/postgres"
 	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/readers"
 	preader "github.com/absmach/magistrala/readers/postgres"
+	"github.com/absmach/supermq/pkg/errors"
 	"github.com/absmach/supermq/pkg/transformers/json"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
+	migrate "github.com/rubenv/sql-migrate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -41,7 +46,7 @@ var (
 )
 
 func TestReadSenml(t *testing.T) {
-	writer := pwriter.New(db)
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	chanID := testsutil.GenerateUUID(t)
 	pubID := testsutil.GenerateUUID(t)
@@ -518,8 +523,583 @@ func TestReadSenml(t *testing.T) {
 	}
 }
 
+func TestReadSenmlWithAggregation(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// The aggregations are checked against the values all readers share.
+	start := testsutil.AggregationStart
+	messages := testsutil.AggregationMessages(chanID, pubID, mqttProt, msgName)
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	for _, tc := range testsutil.AggregationCases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        start * 1e9,
+			To:          (start + 30) * 1e9,
+			Aggregation: tc.Aggregation,
+			Interval:    testsutil.AggregationInterval,
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.Desc, err))
+		assert.Equal(t, uint64(len(tc.Values)), result.Total, fmt.Sprintf("%s: expected %v got %v", tc.Desc, len(tc.Values), result.Total))
+		require.Len(t, result.Messages, len(tc.Values), fmt.Sprintf("%s: expected %d buckets got %d", tc.Desc, len(tc.Values), len(result.Messages)))
+		for i, value := range tc.Values {
+			msg := result.Messages[i].(senml.Message)
+			bucket := (start + float64((len(tc.Values)-1-i)*10)) * 1e9
+			assert.Equal(t, bucket, msg.Time, fmt.Sprintf("%s: expected bucket %v got %v", tc.Desc, bucket, msg.Time))
+			require.NotNil(t, msg.Value, fmt.Sprintf("%s: expected value of bucket %v", tc.Desc, bucket))
+			assert.InDelta(t, value, *msg.Value, 1e-9, fmt.Sprintf("%s: expected value %v got %v", tc.Desc, value, *msg.Value))
+		}
+	}
+
+	bucket := func(i int, value float64) senml.Message {
+		return senml.Message{
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i*10)) * 1e9,
+			Value:     &value,
+		}
+	}
+
+	cases := []struct {
+		desc        string
+		format      string
+		aggregation string
+		interval    string
+		offset      uint64
+		limit       uint64
+		total       uint64
+		messages    []senml.Message
+		err         error
+	}{
+		{
+			desc:        "read messages with aggregation over a single bucket",
+			aggregation: "COUNT",
+			interval:    "1 minute",
+			limit:       limit,
+			total:       1,
+			messages:    []senml.Message{bucket(0, 30)},
+		},
+		{
+			desc:        "read messages page with aggregation",
+			aggregation: "MAX",
+			interval:    "10s",
+			offset:      1,
+			limit:       1,
+			total:       3,
+			messages:    []senml.Message{bucket(1, 19)},
+		},
+		{
+			desc:        "read messages with invalid aggregation",
+			aggregation: "MEDIAN",
+			interval:    "10s",
+			limit:       limit,
+			err:         readers.ErrReadMessages,
+		},
+		{
+			desc:        "read messages of JSON format with aggregation",
+			format:      "json",
+			aggregation: "MAX",
+			interval:    "10s",
+			limit:       limit,
+			err:         readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Format:      tc.format,
+			Offset:      tc.offset,
+			Limit:       tc.limit,
+			From:        start * 1e9,
+			To:          (start + 30) * 1e9,
+			Aggregation: tc.aggregation,
+			Interval:    tc.interval,
+		})
+		if tc.err != nil {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of aggregated Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlWithGapFill(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// Messages fall into the first and the last of four 10 second
+	// buckets, leaving the two buckets in between empty.
+	start := float64(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC).Unix())
+	messages := []senml.Message{}
+	for _, i := range []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39} {
+		val := float64(i)
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i)) * 1e9,
+			Value:     &val,
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	bucket := func(i int, value float64) senml.Message {
+		return senml.Message{
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i*10)) * 1e9,
+			Value:     &value,
+		}
+	}
+	gap := func(i int, value *float64) senml.Message {
+		return senml.Message{
+			Time:  (start + float64(i*10)) * 1e9,
+			Value: value,
+		}
+	}
+	fillValue, previous := -1.0, 4.5
+	interpolated := []float64{14.5, 24.5}
+
+	cases := []struct {
+		desc      string
+		fill      string
+		fillValue float64
+		total     uint64
+		messages  []senml.Message
+		err       error
+	}{
+		{
+			desc:     "read aggregated messages without fill",
+			fill:     readers.FillNone,
+			total:    2,
+			messages: []senml.Message{bucket(3, 34.5), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with null fill",
+			fill:     readers.FillNull,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, nil), gap(1, nil), bucket(0, 4.5)},
+		},
+		{
+			desc:      "read aggregated messages with constant fill",
+			fill:      readers.FillConstant,
+			fillValue: fillValue,
+			total:     4,
+			messages:  []senml.Message{bucket(3, 34.5), gap(2, &fillValue), gap(1, &fillValue), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with previous fill",
+			fill:     readers.FillPrevious,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, &previous), gap(1, &previous), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with linear fill",
+			fill:     readers.FillLinear,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, &interpolated[1]), gap(1, &interpolated[0]), bucket(0, 4.5)},
+		},
+		{
+			desc: "read aggregated messages with invalid fill",
+			fill: "nearest",
+			err:  readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        start * 1e9,
+			To:          (start + 40) * 1e9,
+			Aggregation: "AVG",
+			Interval:    "10s",
+			Fill:        tc.fill,
+			FillValue:   tc.fillValue,
+		})
+		if tc.err != nil {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of gap filled Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlWithCursor(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Messages of both publishers share timestamps, so pages are split
+	// within the same time as well.
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i/2)*1e9,
+			Value:     &v,
+		}
+		if i%2 == 1 {
+			msg.Publisher = pubID2
+		}
+		messages = append(messages, msg)
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	var read []readers.Message
+	pm := readers.PageMetadata{Limit: limit, SkipTotal: true}
+	for pages := 0; pages < 4; pages++ {
+		page, err := reader.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected no total got %d", page.Total))
+		read = append(read, page.Messages...)
+		if page.NextCursor == "" {
+			break
+		}
+		pm.Cursor = page.NextCursor
+	}
+	assert.ElementsMatch(t, fromSenml(messages), read, "got incorrect list of Messages from ReadAll() with cursor")
+	for i := 1; i < len(read); i++ {
+		assert.GreaterOrEqual(t, read[i-1].(senml.Message).Time, read[i].(senml.Message).Time, "expected messages ordered by time")
+	}
+
+	page, err := reader.ReadAll(chanID, readers.PageMetadata{Limit: limit})
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, uint64(len(messages)), page.Total, fmt.Sprintf("expected %d got %d", len(messages), page.Total))
+	assert.NotEmpty(t, page.NextCursor, "expected cursor of a full page")
+
+	_, err = reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: "invalid"})
+	assert.True(t, errors.Contains(err, readers.ErrInvalidCursor), fmt.Sprintf("expected %s got %s", readers.ErrInvalidCursor, err))
+}
+
+func TestStreamSenml(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := []struct {
+		desc    string
+		pm      readers.PageMetadata
+		batches []int
+		err     error
+	}{
+		{
+			desc:    "stream all messages",
+			pm:      readers.PageMetadata{Limit: limit},
+			batches: []int{10, 10, 5},
+		},
+		{
+			desc:    "stream messages with offset",
+			pm:      readers.PageMetadata{Limit: limit, Offset: 20},
+			batches: []int{5},
+		},
+		{
+			desc: "stream messages with invalid cursor",
+			pm:   readers.PageMetadata{Limit: limit, Cursor: "invalid"},
+			err:  readers.ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var batches []int
+			var read []readers.Message
+			err := reader.StreamAll(context.Background(), chanID, tc.pm, func(page readers.MessagesPage) error {
+				batches = append(batches, len(page.Messages))
+				read = append(read, page.Messages...)
+				return nil
+			})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			assert.Equal(t, tc.batches, batches, fmt.Sprintf("%s: expected batches %v got %v", tc.desc, tc.batches, batches))
+			if tc.err == nil {
+				assert.Equal(t, fromSenml(messages[tc.pm.Offset:]), read, fmt.Sprintf("%s: got incorrect list of Messages from StreamAll()", tc.desc))
+			}
+		})
+	}
+}
+
+func TestReadLatest(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Every publisher sends a reading of two names, older readings first.
+	var messages []senml.Message
+	latest := map[string]senml.Message{}
+	for i := 0; i < 10; i++ {
+		val := float64(i)
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now + float64(i)*1e9,
+			Value:     &val,
+		}
+		switch i % 4 {
+		case 1:
+			msg.Name = "humidity"
+		case 2:
+			msg.Publisher = pubID2
+		case 3:
+			msg.Publisher = pubID2
+			msg.Name = "humidity"
+			msg.Subtopic = subtopic
+		}
+		messages = append(messages, msg)
+		latest[msg.Publisher+msg.Subtopic+msg.Name] = msg
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	// Latest messages ordered by time descending.
+	expected := []senml.Message{messages[9], messages[8], messages[7], messages[6]}
+	for _, msg := range expected {
+		require.Equal(t, latest[msg.Publisher+msg.Subtopic+msg.Name], msg)
+	}
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		pm       readers.PageMetadata
+		total    uint64
+		messages []senml.Message
+	}{
+		{
+			desc:     "read latest messages",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit},
+			total:    4,
+			messages: expected,
+		},
+		{
+			desc:     "read latest messages with limit and offset",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Offset: 1, Limit: 2},
+			total:    4,
+			messages: expected[1:3],
+		},
+		{
+			desc:     "read latest messages of publisher",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Publisher: pubID2},
+			total:    2,
+			messages: []senml.Message{messages[7], messages[6]},
+		},
+		{
+			desc:     "read latest messages with name",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Name: "humidity"},
+			total:    2,
+			messages: []senml.Message{messages[9], messages[7]},
+		},
+		{
+			desc:   "read latest messages of non-existent channel",
+			chanID: testsutil.GenerateUUID(t),
+			pm:     readers.PageMetadata{Limit: limit},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadLatest(tc.chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of latest Messages from ReadLatest()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlGroups(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID1 := testsutil.GenerateUUID(t)
+	chanID2 := testsutil.GenerateUUID(t)
+	if chanID2 < chanID1 {
+		chanID1, chanID2 = chanID2, chanID1
+	}
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs1, msgs2 []senml.Message
+	for i := 0; i < 15; i++ {
+		msg := senml.Message{
+			Channel:   chanID1,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		}
+		msgs1 = append(msgs1, msg)
+		if i < 5 {
+			msg.Channel = chanID2
+			msgs2 = append(msgs2, msg)
+		}
+	}
+	// Only the messages of the first channel have a domain.
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), msgs1)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), msgs2)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+		pm      readers.PageMetadata
+		groups  []readers.MessagesGroup
+	}{
+		{
+			desc:    "read groups of multiple channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:limit])},
+				{Channel: chanID2, Publisher: pubID, Total: 5, Messages: fromSenml(msgs2)},
+			},
+		},
+		{
+			desc:    "read groups of multiple channels with offset",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 10, Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[10:])},
+			},
+		},
+		{
+			desc: "read groups of domain",
+			pm:   readers.PageMetadata{Limit: 3},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:3])},
+			},
+		},
+		{
+			desc:    "read groups of non-existent channel",
+			chanIDs: []string{testsutil.GenerateUUID(t)},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups:  []readers.MessagesGroup{},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadGroups(domainID, tc.chanIDs, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.groups, result.Groups, fmt.Sprintf("%s: got incorrect groups from ReadGroups()", tc.desc))
+	}
+}
+
+func TestReadSenmlOfDomain(t *testing.T) {
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs []senml.Message
+	for i := 0; i < 3; i++ {
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		})
+	}
+	domainMsgs, otherMsgs, legacyMsgs := msgs[0:1], msgs[1:2], msgs[2:3]
+
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), domainMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(writers.WithDomain(context.TODO(), testsutil.GenerateUUID(t)), otherMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), legacyMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := []struct {
+		desc     string
+		pm       readers.PageMetadata
+		messages []senml.Message
+	}{
+		{
+			desc:     "read messages of channel",
+			pm:       readers.PageMetadata{Limit: limit},
+			messages: msgs,
+		},
+		{
+			desc:     "read messages of channel of domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: domainID},
+			messages: append(append([]senml.Message{}, domainMsgs...), legacyMsgs...),
+		},
+		{
+			desc:     "read messages of channel of other domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: testsutil.GenerateUUID(t)},
+			messages: legacyMsgs,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, uint64(len(tc.messages)), result.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, len(tc.messages), result.Total))
+	}
+}
+
 func TestReadJSON(t *testing.T) {
-	writer := pwriter.New(db)
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	id1 := testsutil.GenerateUUID(t)
 	m := json.Message{
@@ -588,6 +1168,19 @@ func TestReadJSON(t *testing.T) {
 		httpMsgs = append(httpMsgs, msgs2[i])
 	}
 
+	projected := []map[string]interface{}{}
+	for _, m := range msgs1 {
+		pm := map[string]interface{}{}
+		for k, v := range m {
+			pm[k] = v
+		}
+		pm["payload"] = map[string]interface{}{
+			"field_1": 123.0,
+			"field_5": map[string]interface{}{"field_2": 42.0},
+		}
+		projected = append(projected, pm)
+	}
+
 	reader := preader.New(db)
 
 	cases := map[string]struct {
@@ -630,6 +1223,78 @@ func TestReadJSON(t *testing.T) {
 				Messages: fromJSON(msgs2[msgsNum-20 : msgsNum]),
 			},
 		},
+		"read message with payload equality filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_1"}, Operator: readers.PayloadEqual, Value: "value"},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with payload range filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_2"}, Operator: readers.PayloadGreaterThan, Value: 40.0},
+					{Path: []string{"field_4"}, Operator: readers.PayloadLowerThanEqual, Value: 12.344},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with non-matching payload filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_1"}, Operator: readers.PayloadNotEqual, Value: 123.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload range filter on string value": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_2"}, Operator: readers.PayloadGreaterThan, Value: 1.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload fields": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				Fields: []string{"field_1", "field_5.field_2", "missing"},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(projected[:10]),
+			},
+		},
 		"read message with protocol": {
 			chanID: id2,
 			pageMeta: readers.PageMetadata{
@@ -659,6 +1324,81 @@ func TestReadJSON(t *testing.T) {
 	}
 }
 
+func TestReadJSONOfDomain(t *testing.T) {
+	_, err := migrate.Exec(db.DB, "postgres", schemaspg.Migration(), migrate.Up)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	writer := pwriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	domainID := testsutil.GenerateUUID(t)
+	chanID := testsutil.GenerateUUID(t)
+
+	// Messages of formats with schemas are stored in tables with typed columns.
+	doc := `{"type":"object","properties":{"temperature":{"type":"number"}},"required":["temperature"]}`
+	_, err = schemas.New(schemaspg.New(db)).Register(context.Background(), domainID, "typed_readings", []byte(doc))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msgs := map[string][]map[string]interface{}{}
+	for _, format := range []string{"typed_readings", "untyped_readings"} {
+		messages := json.Messages{Format: format}
+		for i := 0; i < limit; i++ {
+			m := json.Message{
+				Channel:   chanID,
+				Publisher: chanID,
+				Created:   time.Now().Unix() + int64(i),
+				Subtopic:  format,
+				Protocol:  mqttProt,
+				Payload:   map[string]interface{}{"temperature": float64(i)},
+			}
+			messages.Data = append(messages.Data, m)
+			msgs[format] = append(msgs[format], toMap(m))
+		}
+		err := writer.ConsumeBlocking(writers.WithDomain(context.Background(), domainID), messages)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	}
+
+	reader := preader.New(db)
+
+	cases := []struct {
+		desc     string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+		err      error
+	}{
+		{
+			desc:     "read messages of format with schema",
+			pageMeta: readers.PageMetadata{Format: "typed_readings", Domain: domainID, Limit: limit},
+			page:     readers.MessagesPage{Total: limit, Messages: fromJSON(msgs["typed_readings"])},
+		},
+		{
+			desc:     "read messages of format without schema",
+			pageMeta: readers.PageMetadata{Format: "untyped_readings", Domain: domainID, Limit: limit},
+			page:     readers.MessagesPage{Total: limit, Messages: fromJSON(msgs["untyped_readings"])},
+		},
+		{
+			desc:     "read messages of format of other domain",
+			pageMeta: readers.PageMetadata{Format: "untyped_readings", Domain: testsutil.GenerateUUID(t), Limit: limit},
+			page:     readers.MessagesPage{Messages: []readers.Message{}},
+		},
+		{
+			desc:     "read messages of injected format",
+			pageMeta: readers.PageMetadata{Format: "readings; DROP TABLE messages; --", Domain: domainID, Limit: limit},
+			err:      readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := reader.ReadAll(chanID, tc.pageMeta)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+			for _, m := range result.Messages {
+				delete(m.(map[string]interface{}), "id")
+			}
+			assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: got incorrect list of json Messages from ReadAll()", tc.desc))
+			assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.page.Total, result.Total))
+		})
+	}
+}
+
 func fromSenml(msg []senml.Message) []readers.Message {
 	var ret []readers.Message
 	for _, m := range msg {
@@ -0,0 +1,54 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Rollup is a table of SenML message values the writers aggregate into
+// buckets of a fixed length.
+type Rollup struct {
+	Table  string
+	Bucket time.Duration
+}
+
+// Rollups are the rollups the writers maintain, from the finest. Each rollup
+// is aggregated from the previous one, and the finest from the messages.
+var Rollups = []Rollup{
+	{Table: "messages_1m", Bucket: time.Minute},
+	{Table: "messages_1h", Bucket: time.Hour},
+	{Table: "messages_1d", Bucket: 24 * time.Hour},
+}
+
+// RollupCandidates returns the rollups an aggregated read of SenML messages
+// can be served from, from the coarsest. A rollup can serve the read if the
+// interval is a multiple of its bucket, the read is bounded by times that
+// are multiples of its bucket and the read doesn't filter message values.
+// Whether the rollup is refreshed for the whole read is up to the caller.
+func RollupCandidates(pm PageMetadata) []Rollup {
+	if pm.Aggregation == "" || pm.From == 0 || pm.To == 0 {
+		return nil
+	}
+	// Rollups keep only aggregated numeric values.
+	if pm.Value != 0 || pm.BoolValue || pm.StringValue != "" || pm.DataValue != "" || pm.Protocol != "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(pm.Interval)
+	if err != nil || interval <= 0 {
+		return nil
+	}
+
+	var ret []Rollup
+	for _, r := range slices.Backward(Rollups) {
+		bucket := float64(r.Bucket.Nanoseconds())
+		if interval%r.Bucket == 0 && math.Mod(pm.From, bucket) == 0 && math.Mod(pm.To, bucket) == 0 {
+			ret = append(ret, r)
+		}
+	}
+
+	return ret
+}
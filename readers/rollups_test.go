@@ -0,0 +1,85 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package readers_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala/readers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollupCandidates(t *testing.T) {
+	day := float64((24 * time.Hour).Nanoseconds())
+	hour := float64(time.Hour.Nanoseconds())
+	minute := float64(time.Minute.Nanoseconds())
+	from := 20000 * day
+
+	cases := []struct {
+		desc    string
+		pm      readers.PageMetadata
+		rollups []string
+	}{
+		{
+			desc:    "daily read of whole days",
+			pm:      readers.PageMetadata{Aggregation: "AVG", Interval: "24h", From: from, To: from + 7*day},
+			rollups: []string{"messages_1d", "messages_1h", "messages_1m"},
+		},
+		{
+			desc:    "daily read of whole hours",
+			pm:      readers.PageMetadata{Aggregation: "MAX", Interval: "24h", From: from + hour, To: from + 7*day},
+			rollups: []string{"messages_1h", "messages_1m"},
+		},
+		{
+			desc:    "hourly read of whole days",
+			pm:      readers.PageMetadata{Aggregation: "MIN", Interval: "1h", From: from, To: from + day},
+			rollups: []string{"messages_1h", "messages_1m"},
+		},
+		{
+			desc:    "read of 15 minutes intervals",
+			pm:      readers.PageMetadata{Aggregation: "SUM", Interval: "15m", From: from, To: from + day},
+			rollups: []string{"messages_1m"},
+		},
+		{
+			desc: "read of seconds intervals",
+			pm:   readers.PageMetadata{Aggregation: "SUM", Interval: "30s", From: from, To: from + day},
+		},
+		{
+			desc: "read bounded by partial minutes",
+			pm:   readers.PageMetadata{Aggregation: "COUNT", Interval: "1h", From: from + minute/2, To: from + day},
+		},
+		{
+			desc: "read without upper bound",
+			pm:   readers.PageMetadata{Aggregation: "COUNT", Interval: "1h", From: from},
+		},
+		{
+			desc: "read without aggregation",
+			pm:   readers.PageMetadata{Interval: "1h", From: from, To: from + day},
+		},
+		{
+			desc: "read with invalid interval",
+			pm:   readers.PageMetadata{Aggregation: "AVG", Interval: "1 week", From: from, To: from + day},
+		},
+		{
+			desc: "read with value filter",
+			pm:   readers.PageMetadata{Aggregation: "AVG", Interval: "1h", From: from, To: from + day, Value: 5},
+		},
+		{
+			desc: "read with protocol filter",
+			pm:   readers.PageMetadata{Aggregation: "AVG", Interval: "1h", From: from, To: from + day, Protocol: "mqtt"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var rollups []string
+			for _, r := range readers.RollupCandidates(tc.pm) {
+				rollups = append(rollups, r.Table)
+			}
+			assert.Equal(t, tc.rollups, rollups, fmt.Sprintf("%s: expected rollups %v, got %v", tc.desc, tc.rollups, rollups))
+		})
+	}
+}
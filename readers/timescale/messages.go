@@ -4,14 +4,18 @@
 package timescale
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/absmach/magistrala/consumers/writers/schemas"
+	"github.com/absmach/magistrala/readers"
 	"github.com/absmach/supermq/pkg/errors"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx" // required for DB access
 )
@@ -19,6 +23,21 @@ import (
 // Table for SenML messages.
 const defTable = "messages"
 
+// Message time is stored in nanoseconds.
+const timeDivisor = 1000000000
+
+// Conditions that scope a read to a channel, a list of channels or a domain.
+const (
+	channelScope  = " channel = :channel "
+	channelsScope = " channel = ANY(:channels) "
+	domainScope   = " domain = :domain "
+)
+
+var (
+	errInvalidAggregation = errors.New("invalid aggregation")
+	errInvalidFormat      = errors.New("invalid message format")
+)
+
 var _ readers.MessageRepository = (*timescaleRepository)(nil)
 
 type timescaleRepository struct {
@@ -33,59 +52,268 @@ func New(db *sqlx.DB) readers.MessageRepository {
 }
 
 func (tr timescaleRepository) ReadAll(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
-	order := "time"
+	rq, err := tr.readAllQuery(chanID, rpm, `LIMIT :limit OFFSET :offset`)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	rows, err := tr.db.NamedQuery(rq.query, rq.params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == pgerrcode.UndefinedTable {
+				return readers.MessagesPage{}, nil
+			}
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	var cursor string
+	for rows.Next() {
+		msg, c, err := scanMessage(rows, rq.format, rpm.Fields)
+		if err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, msg)
+		cursor = c
+	}
+	if !rq.aggregated && uint64(len(page.Messages)) == rpm.Limit {
+		page.NextCursor = encodeCursor(cursor)
+	}
+
+	if rpm.SkipTotal {
+		return page, nil
+	}
+
+	rows, err = tr.db.NamedQuery(rq.totalQuery, rq.params)
+	if err != nil {
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return page, err
+		}
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (tr timescaleRepository) StreamAll(ctx context.Context, chanID string, rpm readers.PageMetadata, send func(readers.MessagesPage) error) error {
+	rq, err := tr.readAllQuery(chanID, rpm, `OFFSET :offset`)
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	// Rows are received from the connection as they are iterated, so the
+	// messages are read with a single query and sent in batches.
+	rows, err := tr.db.NamedQueryContext(ctx, rq.query, rq.params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == pgerrcode.UndefinedTable {
+				return nil
+			}
+		}
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	return stream(rows, rq, rpm, send)
+}
+
+// readQuery is the query of the messages ReadAll and StreamAll read.
+type readQuery struct {
+	query      string
+	totalQuery string
+	params     map[string]interface{}
+	format     string
+	aggregated bool
+}
+
+// readAllQuery returns the query of the messages of the channel, which
+// reads the page the page clause selects.
+func (tr timescaleRepository) readAllQuery(chanID string, rpm readers.PageMetadata, page string) (readQuery, error) {
+	key := senmlCursor
 	format := defTable
 
 	if rpm.Format != "" && rpm.Format != defTable {
-		order = "created"
+		key = jsonCursor
 		format = rpm.Format
 	}
+	table, err := messagesTable(rpm)
+	if err != nil {
+		return readQuery{}, err
+	}
+
+	cond := fmtCondition(channelScope, rpm)
+
+	params := queryParams(rpm)
+	params["channel"] = chanID
+
+	if format != defTable {
+		if cond, err = payloadCondition(cond, rpm.PayloadFilters, params); err != nil {
+			return readQuery{}, err
+		}
+	}
+
+	pageCond := cond
+	if rpm.Cursor != "" {
+		if err := decodeCursor(rpm.Cursor, key, params); err != nil {
+			return readQuery{}, err
+		}
+		pageCond = fmt.Sprintf(`%s AND %s`, cond, cursorCondition(key))
+	}
 
-	q := fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY %s DESC LIMIT :limit OFFSET :offset;`, format, fmtCondition(rpm), order)
-	totalQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, format, fmtCondition(rpm))
+	rq := readQuery{
+		query:      fmt.Sprintf(`SELECT *, %s AS cursor FROM %s WHERE %s ORDER BY %s %s;`, cursorValue(key), table, pageCond, cursorOrder(key), page),
+		totalQuery: fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, table, cond),
+		params:     params,
+		format:     format,
+	}
 
 	// If aggregation is provided, add time_bucket and aggregation to the query
-	const timeDivisor = 1000000000
+	rq.aggregated = rpm.Aggregation != ""
+	if !rq.aggregated {
+		return rq, nil
+	}
+	// Aggregation applies to SenML messages only, since JSON messages
+	// have no common value column.
+	if format != defTable {
+		return readQuery{}, errInvalidAggregation
+	}
+	if rpm.Cursor != "" {
+		return readQuery{}, readers.ErrInvalidCursor
+	}
+	agg, err := aggregation(rpm.Aggregation)
+	if err != nil {
+		return readQuery{}, err
+	}
+	// Coarse aggregations are read from the rollups the writers
+	// refresh, if the rollups hold the whole read.
+	if rollup, rollupAgg, ok := tr.rollup(rpm); ok {
+		table, agg = rollup, rollupAgg
+	}
+	rq.query = fmt.Sprintf(`
+		SELECT
+			EXTRACT(epoch FROM time_bucket('%s', to_timestamp(time/%d))) *%d AS time,
+			%s AS value,
+			FIRST(publisher, time) AS publisher,
+			FIRST(protocol, time) AS protocol,
+			FIRST(subtopic, time) AS subtopic,
+			FIRST(name,time) AS name,
+			FIRST(unit, time) AS unit
+		FROM
+			%s
+		WHERE
+			%s
+		GROUP BY 1
+		ORDER BY time DESC
+		%s;
+		`,
+		rpm.Interval, timeDivisor, timeDivisor, agg, table, cond, page)
+
+	rq.totalQuery = fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT EXTRACT(epoch FROM time_bucket('%s', to_timestamp(time/%d))) AS time, %s AS value FROM %s WHERE %s GROUP BY 1) AS subquery;`, rpm.Interval, timeDivisor, agg, table, cond)
+
+	if rpm.Fill != "" && rpm.Fill != readers.FillNone {
+		if rq.query, rq.totalQuery, err = gapFill(rpm, agg, table, cond, page); err != nil {
+			return readQuery{}, err
+		}
+	}
 
-	if rpm.Aggregation != "" {
-		q = fmt.Sprintf(`
-			SELECT
-				EXTRACT(epoch FROM time_bucket('%s', to_timestamp(time/%d))) *%d AS time,
-				%s(value) AS value,
-				FIRST(publisher, time) AS publisher,
-				FIRST(protocol, time) AS protocol,
-				FIRST(subtopic, time) AS subtopic,
-				FIRST(name,time) AS name,
-				FIRST(unit, time) AS unit
-			FROM
-				%s
-			WHERE
-				%s
-			GROUP BY 1
-			ORDER BY time DESC
-			LIMIT :limit OFFSET :offset;
-			`,
-			rpm.Interval, timeDivisor, timeDivisor, rpm.Aggregation, format, fmtCondition(rpm))
+	return rq, nil
+}
+
+// scanMessage returns the message of the row and its cursor.
+func scanMessage(rows *sqlx.Rows, format string, fields []string) (readers.Message, string, error) {
+	switch format {
+	case defTable:
+		msg := senmlMessage{Message: senml.Message{}}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, "", err
+		}
+		return msg.Message, msg.Cursor, nil
+	default:
+		msg := jsonMessage{}
+		if err := rows.StructScan(&msg); err != nil {
+			return nil, "", err
+		}
+		m, err := msg.toMap(fields)
+		if err != nil {
+			return nil, "", err
+		}
+		return m, msg.Cursor, nil
+	}
+}
 
-		totalQuery = fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT EXTRACT(epoch FROM time_bucket('%s', to_timestamp(time/%d))) AS time, %s(value) AS value FROM %s WHERE %s GROUP BY 1) AS subquery;`, rpm.Interval, timeDivisor, rpm.Aggregation, format, fmtCondition(rpm))
+// stream sends the messages of the rows in batches of the page limit. The
+// cursor of a batch of raw messages points after its last message.
+func stream(rows *sqlx.Rows, rq readQuery, rpm readers.PageMetadata, send func(readers.MessagesPage) error) error {
+	page := readers.MessagesPage{PageMetadata: rpm, Messages: []readers.Message{}}
+	var cursor string
+	flush := func() error {
+		if len(page.Messages) == 0 {
+			return nil
+		}
+		if !rq.aggregated {
+			page.NextCursor = encodeCursor(cursor)
+		}
+		if err := send(page); err != nil {
+			return err
+		}
+		page.Messages = []readers.Message{}
+		return nil
 	}
 
-	params := map[string]interface{}{
-		"channel":      chanID,
-		"limit":        rpm.Limit,
-		"offset":       rpm.Offset,
-		"subtopic":     rpm.Subtopic,
-		"publisher":    rpm.Publisher,
-		"name":         rpm.Name,
-		"protocol":     rpm.Protocol,
-		"value":        rpm.Value,
-		"bool_value":   rpm.BoolValue,
-		"string_value": rpm.StringValue,
-		"data_value":   rpm.DataValue,
-		"from":         rpm.From,
-		"to":           rpm.To,
+	for rows.Next() {
+		msg, c, err := scanMessage(rows, rq.format, rpm.Fields)
+		if err != nil {
+			return errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Messages = append(page.Messages, msg)
+		cursor = c
+		if uint64(len(page.Messages)) == rpm.Limit {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
 	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	return flush()
+}
 
+func (tr timescaleRepository) ReadLatest(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	cond := fmtCondition(channelScope, rpm)
+
+	// Latest values are read from SenML messages only, since JSON messages
+	// have no common name to distinguish the readings.
+	q := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT DISTINCT ON (subtopic, publisher, name) *
+			FROM %[1]s
+			WHERE %[2]s
+			ORDER BY subtopic, publisher, name, time DESC
+		) AS latest
+		ORDER BY time DESC
+		LIMIT :limit OFFSET :offset;`, defTable, cond)
+	totalQuery := fmt.Sprintf(`
+		SELECT COUNT(*) FROM (
+			SELECT DISTINCT subtopic, publisher, name
+			FROM %[1]s
+			WHERE %[2]s
+		) AS latest;`, defTable, cond)
+
+	params := queryParams(rpm)
+	params["channel"] = chanID
 	rows, err := tr.db.NamedQuery(q, params)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok {
@@ -101,28 +329,12 @@ func (tr timescaleRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		PageMetadata: rpm,
 		Messages:     []readers.Message{},
 	}
-	switch format {
-	case defTable:
-		for rows.Next() {
-			msg := senmlMessage{Message: senml.Message{}}
-			if err := rows.StructScan(&msg); err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
-
-			page.Messages = append(page.Messages, msg.Message)
-		}
-	default:
-		for rows.Next() {
-			msg := jsonMessage{}
-			if err := rows.StructScan(&msg); err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
-			m, err := msg.toMap()
-			if err != nil {
-				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
-			}
-			page.Messages = append(page.Messages, m)
+	for rows.Next() {
+		msg := senmlMessage{Message: senml.Message{}}
+		if err := rows.StructScan(&msg); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 		}
+		page.Messages = append(page.Messages, msg.Message)
 	}
 
 	rows, err = tr.db.NamedQuery(totalQuery, params)
@@ -131,20 +343,330 @@ func (tr timescaleRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 	}
 	defer rows.Close()
 
-	total := uint64(0)
 	if rows.Next() {
-		if err := rows.Scan(&total); err != nil {
-			return page, err
+		if err := rows.Scan(&page.Total); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 		}
 	}
-	page.Total = total
 
 	return page, nil
 }
 
-func fmtCondition(rpm readers.PageMetadata) string {
+func (tr timescaleRepository) ReadGroups(domainID string, chanIDs []string, rpm readers.PageMetadata) (readers.GroupsPage, error) {
+	order := "time"
+	format := defTable
+
+	if rpm.Format != "" && rpm.Format != defTable {
+		order = "created"
+		format = rpm.Format
+	}
+	table, err := messagesTable(rpm)
+	if err != nil {
+		return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	params := queryParams(rpm)
+	scope := domainScope
+	params["domain"] = domainID
+	if len(chanIDs) > 0 {
+		var channels pgtype.TextArray
+		if err := channels.Set(chanIDs); err != nil {
+			return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		scope = channelsScope
+		params["channels"] = channels
+	}
+
+	cond := fmtCondition(scope, rpm)
+	if format != defTable {
+		if cond, err = payloadCondition(cond, rpm.PayloadFilters, params); err != nil {
+			return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	q := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT
+				*,
+				ROW_NUMBER() OVER (PARTITION BY channel, publisher ORDER BY %[1]s DESC) AS group_row,
+				COUNT(*) OVER (PARTITION BY channel, publisher) AS group_total
+			FROM %[2]s
+			WHERE %[3]s
+		) AS grouped
+		WHERE group_row > :offset AND group_row <= :offset + :limit
+		ORDER BY channel, publisher, %[1]s DESC;`, order, table, cond)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == pgerrcode.UndefinedTable {
+				return readers.GroupsPage{}, nil
+			}
+		}
+		return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.GroupsPage{
+		PageMetadata: rpm,
+		Groups:       []readers.MessagesGroup{},
+	}
+	for rows.Next() {
+		var channel, publisher string
+		var total uint64
+		var msg readers.Message
+		switch format {
+		case defTable:
+			row := struct {
+				senmlMessage
+				groupRow
+			}{}
+			if err := rows.StructScan(&row); err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			channel, publisher, total, msg = row.Channel, row.Publisher, row.Total, row.Message
+		default:
+			row := struct {
+				jsonMessage
+				groupRow
+			}{}
+			if err := rows.StructScan(&row); err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			m, err := row.toMap(rpm.Fields)
+			if err != nil {
+				return readers.GroupsPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			channel, publisher, total, msg = row.Channel, row.Publisher, row.Total, m
+		}
+
+		last := len(page.Groups) - 1
+		if last < 0 || page.Groups[last].Channel != channel || page.Groups[last].Publisher != publisher {
+			page.Groups = append(page.Groups, readers.MessagesGroup{
+				Channel:   channel,
+				Publisher: publisher,
+				Total:     total,
+				Messages:  []readers.Message{},
+			})
+			last++
+		}
+		page.Groups[last].Messages = append(page.Groups[last].Messages, msg)
+	}
+
+	return page, nil
+}
+
+// messagesTable returns the quoted table of the messages of the format.
+// JSON messages of domains are stored in the tables of their domain
+// formats, and the messages without domains in the tables named after
+// their formats.
+func messagesTable(rpm readers.PageMetadata) (string, error) {
+	if rpm.Format == "" || rpm.Format == defTable {
+		return defTable, nil
+	}
+	if rpm.Domain == "" {
+		if err := schemas.ValidateTable(rpm.Format); err != nil {
+			return "", errInvalidFormat
+		}
+		return pgx.Identifier{rpm.Format}.Sanitize(), nil
+	}
+	if err := schemas.ValidateFormat(rpm.Format); err != nil {
+		return "", errInvalidFormat
+	}
+
+	return pgx.Identifier{schemas.Table(rpm.Domain, rpm.Format)}.Sanitize(), nil
+}
+
+// gapFill returns the aggregation query and its total query that return
+// a row for every bucket between from and to, using time_bucket_gapfill.
+// Buckets are aggregated before they're filled, so aggregations over the
+// previous bucket skip the empty buckets.
+func gapFill(rpm readers.PageMetadata, agg, format, cond, page string) (string, string, error) {
+	if rpm.From == 0 || rpm.To == 0 {
+		return "", "", readers.ErrInvalidFill
+	}
+	value, err := fill(rpm.Fill, "MAX(value)")
+	if err != nil {
+		return "", "", err
+	}
+
+	bucket := fmt.Sprintf(`time_bucket_gapfill('%s', to_timestamp(time/%d), to_timestamp(:from/%d), to_timestamp(:to/%d))`,
+		rpm.Interval, timeDivisor, timeDivisor, timeDivisor)
+
+	q := fmt.Sprintf(`
+		SELECT
+			EXTRACT(epoch FROM %s) *%d AS time,
+			%s AS value,
+			COALESCE(FIRST(publisher, time), '') AS publisher,
+			COALESCE(FIRST(protocol, time), '') AS protocol,
+			COALESCE(FIRST(subtopic, time), '') AS subtopic,
+			COALESCE(FIRST(name, time), '') AS name,
+			COALESCE(FIRST(unit, time), '') AS unit
+		FROM (
+			SELECT
+				MIN(time) AS time,
+				%s AS value,
+				FIRST(publisher, time) AS publisher,
+				FIRST(protocol, time) AS protocol,
+				FIRST(subtopic, time) AS subtopic,
+				FIRST(name, time) AS name,
+				FIRST(unit, time) AS unit
+			FROM
+				%s
+			WHERE
+				%s
+			GROUP BY time_bucket('%s', to_timestamp(time/%d))
+		) AS buckets
+		GROUP BY 1
+		ORDER BY time DESC
+		%s;
+		`,
+		bucket, timeDivisor, value, agg, format, cond, rpm.Interval, timeDivisor, page)
+
+	totalQuery := fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT %s AS time FROM %s WHERE %s GROUP BY 1) AS subquery;`, bucket, format, cond)
+
+	return q, totalQuery, nil
+}
+
+// fill returns the SQL expression that sets the value of an empty bucket
+// of the gap filled query for the fill strategy.
+func fill(name, agg string) (string, error) {
+	switch name {
+	case readers.FillNull:
+		return agg, nil
+	case readers.FillConstant:
+		return fmt.Sprintf("COALESCE(%s, CAST(:fill_value AS DOUBLE PRECISION))", agg), nil
+	case readers.FillPrevious:
+		return fmt.Sprintf("locf(%s)", agg), nil
+	case readers.FillLinear:
+		return fmt.Sprintf("interpolate(%s)", agg), nil
+	default:
+		return "", readers.ErrInvalidFill
+	}
+}
+
+const (
+	firstValue = `FIRST(value, time) FILTER (WHERE value IS NOT NULL)`
+	lastValue  = `LAST(value, time) FILTER (WHERE value IS NOT NULL)`
+)
+
+// previous returns the SQL expression of the aggregate of the previous
+// bucket, so changes between buckets are counted in the later bucket. The
+// first bucket of the read has no previous bucket.
+func previous(agg string) string {
+	return fmt.Sprintf("LAG(%s) OVER (ORDER BY MIN(time))", agg)
+}
+
+// aggregation returns the SQL expression that aggregates message values
+// of a single time bucket for the aggregation name.
+func aggregation(name string) (string, error) {
+	switch agg := strings.ToUpper(name); agg {
+	case "MIN", "MAX", "AVG", "SUM", "COUNT":
+		return fmt.Sprintf("%s(value)", agg), nil
+	case "P95":
+		return "percentile_cont(0.95) WITHIN GROUP (ORDER BY value)", nil
+	case "P99":
+		return "percentile_cont(0.99) WITHIN GROUP (ORDER BY value)", nil
+	case "STDDEV":
+		return "stddev_samp(value)", nil
+	case "FIRST":
+		return firstValue, nil
+	case "LAST":
+		return lastValue, nil
+	case "DELTA":
+		return fmt.Sprintf("(%s - COALESCE(%s, %s))", lastValue, previous(lastValue), firstValue), nil
+	case "RATE":
+		// Change per second since the last value of the previous bucket.
+		return fmt.Sprintf("((%s - COALESCE(%s, %s)) / NULLIF((MAX(time) - COALESCE(%s, MIN(time))) / %d, 0))",
+			lastValue, previous(lastValue), firstValue, previous("MAX(time)"), timeDivisor), nil
+	default:
+		return "", errInvalidAggregation
+	}
+}
+
+// rollup returns the rollup the aggregated read is served from and the
+// rollup aggregation expression. The coarsest rollup that is refreshed for
+// the whole read is used, and none if the rollups don't exist.
+func (tr timescaleRepository) rollup(rpm readers.PageMetadata) (string, string, bool) {
+	agg, ok := rollupAggregation(rpm.Aggregation)
+	if !ok || (rpm.Format != "" && rpm.Format != defTable) {
+		return "", "", false
+	}
+	candidates := readers.RollupCandidates(rpm)
+	if len(candidates) == 0 {
+		return "", "", false
+	}
+
+	var refreshes []rollupRefresh
+	if err := tr.db.Select(&refreshes, `SELECT name, since, refreshed FROM rollups`); err != nil {
+		return "", "", false
+	}
+	for _, c := range candidates {
+		for _, r := range refreshes {
+			if r.Name == c.Table && float64(r.Since) <= rpm.From && rpm.To <= float64(r.Refreshed) {
+				return c.Table, agg, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+const (
+	firstRollup = `FIRST(value_first, time)`
+	lastRollup  = `LAST(value_last, time)`
+)
+
+// rollupAggregation returns the SQL expression that aggregates the rollup
+// values of a single time bucket for the aggregation name, if the
+// aggregation can be computed from the rollup values.
+func rollupAggregation(name string) (string, bool) {
+	switch strings.ToUpper(name) {
+	case "MIN":
+		return "MIN(value_min)", true
+	case "MAX":
+		return "MAX(value_max)", true
+	case "SUM":
+		return "SUM(value_sum)", true
+	case "COUNT":
+		return "CAST(SUM(value_count) AS DOUBLE PRECISION)", true
+	case "AVG":
+		return "SUM(value_sum) / NULLIF(CAST(SUM(value_count) AS DOUBLE PRECISION), 0)", true
+	case "FIRST":
+		return firstRollup, true
+	case "LAST":
+		return lastRollup, true
+	case "DELTA":
+		return fmt.Sprintf("(%s - COALESCE(%s, %s))", lastRollup, previous(lastRollup), firstRollup), true
+	default:
+		return "", false
+	}
+}
+
+func queryParams(rpm readers.PageMetadata) map[string]interface{} {
+	return map[string]interface{}{
+		"limit":        rpm.Limit,
+		"offset":       rpm.Offset,
+		"domain":       rpm.Domain,
+		"subtopic":     rpm.Subtopic,
+		"publisher":    rpm.Publisher,
+		"name":         rpm.Name,
+		"protocol":     rpm.Protocol,
+		"value":        rpm.Value,
+		"bool_value":   rpm.BoolValue,
+		"string_value": rpm.StringValue,
+		"data_value":   rpm.DataValue,
+		"from":         rpm.From,
+		"to":           rpm.To,
+		"fill_value":   rpm.FillValue,
+	}
+}
+
+// fmtCondition returns the query condition that narrows scope, the set of
+// channels the messages are read from, with page metadata filters.
+func fmtCondition(scope string, rpm readers.PageMetadata) string {
 	// Indexed columns conditions based on indices order.
-	chCondition := " channel = :channel "
+	chCondition := scope
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -157,6 +679,11 @@ func fmtCondition(rpm readers.PageMetadata) string {
 
 	conditions := []string{chCondition}
 
+	// Messages stored before their domain was stored have no domain.
+	if _, ok := query["domain"]; ok {
+		conditions = append(conditions, " (domain = :domain OR domain IS NULL) ")
+	}
+
 	if _, ok := query["subtopic"]; ok {
 		conditions = append(conditions, " subtopic = :subtopic ")
 	}
@@ -213,20 +740,40 @@ func fmtCondition(rpm readers.PageMetadata) string {
 }
 
 type senmlMessage struct {
-	ID string `db:"id"`
+	ID     string  `db:"id"`
+	Domain *string `db:"domain"`
+	Cursor string  `db:"cursor"`
 	senml.Message
 }
 
+// rollupRefresh holds the times the rollup is refreshed for.
+type rollupRefresh struct {
+	Name      string `db:"name"`
+	Since     int64  `db:"since"`
+	Refreshed int64  `db:"refreshed"`
+}
+
+// groupRow holds the columns a grouped read adds to every message row.
+type groupRow struct {
+	Row   uint64 `db:"group_row"`
+	Total uint64 `db:"group_total"`
+}
+
 type jsonMessage struct {
-	Channel   string `db:"channel"`
-	Created   int64  `db:"created"`
-	Subtopic  string `db:"subtopic"`
-	Publisher string `db:"publisher"`
-	Protocol  string `db:"protocol"`
-	Payload   []byte `db:"payload"`
+	ID        string  `db:"id"`
+	Domain    *string `db:"domain"`
+	Cursor    string  `db:"cursor"`
+	Channel   string  `db:"channel"`
+	Created   int64   `db:"created"`
+	Subtopic  string  `db:"subtopic"`
+	Publisher string  `db:"publisher"`
+	Protocol  string  `db:"protocol"`
+	Payload   []byte  `db:"payload"`
 }
 
-func (msg jsonMessage) toMap() (map[string]interface{}, error) {
+// toMap returns the message with the payload projected to the fields, or
+// with the whole payload if no field is given.
+func (msg jsonMessage) toMap(fields []string) (map[string]interface{}, error) {
 	ret := map[string]interface{}{
 		"channel":   msg.Channel,
 		"created":   msg.Created,
@@ -239,6 +786,9 @@ func (msg jsonMessage) toMap() (map[string]interface{}, error) {
 	if err := json.Unmarshal(msg.Payload, &pld); err != nil {
 		return nil, err
 	}
+	if len(fields) > 0 {
+		pld = readers.ProjectPayload(pld, fields)
+	}
 	ret["payload"] = pld
 	return ret, nil
 }
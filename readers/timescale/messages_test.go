@@ -9,12 +9,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/absmach/magistrala/consumers/writers"
+	"github.com/absmach/magistrala/consumers/writers/retention"
 	twriter "github.com/absmach/magistrala/consumers/writers/timescale"
 	"github.com/absmach/magistrala/internal/testsutil"
+	"github.com/absmach/magistrala/readers"
 	treader "github.com/absmach/magistrala/readers/timescale"
+	"github.com/absmach/supermq/pkg/errors"
 	"github.com/absmach/supermq/pkg/transformers/json"
 	"github.com/absmach/supermq/pkg/transformers/senml"
-	"github.com/absmach/supermq/readers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -41,7 +44,7 @@ var (
 )
 
 func TestReadSenml(t *testing.T) {
-	writer := twriter.New(db)
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	chanID := testsutil.GenerateUUID(t)
 	pubID := testsutil.GenerateUUID(t)
@@ -519,7 +522,7 @@ func TestReadSenml(t *testing.T) {
 }
 
 func TestReadMessagesWithAggregation(t *testing.T) {
-	writer := twriter.New(db)
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	chanID := testsutil.GenerateUUID(t)
 	pubID := testsutil.GenerateUUID(t)
@@ -644,8 +647,738 @@ func TestReadMessagesWithAggregation(t *testing.T) {
 	}
 }
 
+func TestReadSenmlWithAggregation(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// The aggregations are checked against the values all readers share.
+	start := testsutil.AggregationStart
+	messages := testsutil.AggregationMessages(chanID, pubID, mqttProt, msgName)
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	for _, tc := range testsutil.AggregationCases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        start * 1e9,
+			To:          (start + 30) * 1e9,
+			Aggregation: tc.Aggregation,
+			Interval:    testsutil.AggregationInterval,
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.Desc, err))
+		assert.Equal(t, uint64(len(tc.Values)), result.Total, fmt.Sprintf("%s: expected %v got %v", tc.Desc, len(tc.Values), result.Total))
+		require.Len(t, result.Messages, len(tc.Values), fmt.Sprintf("%s: expected %d buckets got %d", tc.Desc, len(tc.Values), len(result.Messages)))
+		for i, value := range tc.Values {
+			msg := result.Messages[i].(senml.Message)
+			bucket := (start + float64((len(tc.Values)-1-i)*10)) * 1e9
+			assert.Equal(t, bucket, msg.Time, fmt.Sprintf("%s: expected bucket %v got %v", tc.Desc, bucket, msg.Time))
+			require.NotNil(t, msg.Value, fmt.Sprintf("%s: expected value of bucket %v", tc.Desc, bucket))
+			assert.InDelta(t, value, *msg.Value, 1e-9, fmt.Sprintf("%s: expected value %v got %v", tc.Desc, value, *msg.Value))
+		}
+	}
+}
+
+func TestReadMessagesWithGapFill(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// Messages fall into the first and the last of four 10 second
+	// buckets, leaving the two buckets in between empty.
+	start := float64(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC).Unix())
+	messages := []senml.Message{}
+	for _, i := range []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39} {
+		val := float64(i)
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i)) * 1e9,
+			Value:     &val,
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	bucket := func(i int, value float64) senml.Message {
+		return senml.Message{
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      (start + float64(i*10)) * 1e9,
+			Value:     &value,
+		}
+	}
+	gap := func(i int, value *float64) senml.Message {
+		return senml.Message{
+			Time:  (start + float64(i*10)) * 1e9,
+			Value: value,
+		}
+	}
+	fillValue, previous := -1.0, 4.5
+	interpolated := []float64{14.5, 24.5}
+
+	cases := []struct {
+		desc      string
+		fill      string
+		fillValue float64
+		total     uint64
+		messages  []senml.Message
+		err       error
+	}{
+		{
+			desc:     "read aggregated messages without fill",
+			fill:     readers.FillNone,
+			total:    2,
+			messages: []senml.Message{bucket(3, 34.5), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with null fill",
+			fill:     readers.FillNull,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, nil), gap(1, nil), bucket(0, 4.5)},
+		},
+		{
+			desc:      "read aggregated messages with constant fill",
+			fill:      readers.FillConstant,
+			fillValue: fillValue,
+			total:     4,
+			messages:  []senml.Message{bucket(3, 34.5), gap(2, &fillValue), gap(1, &fillValue), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with previous fill",
+			fill:     readers.FillPrevious,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, &previous), gap(1, &previous), bucket(0, 4.5)},
+		},
+		{
+			desc:     "read aggregated messages with linear fill",
+			fill:     readers.FillLinear,
+			total:    4,
+			messages: []senml.Message{bucket(3, 34.5), gap(2, &interpolated[1]), gap(1, &interpolated[0]), bucket(0, 4.5)},
+		},
+		{
+			desc: "read aggregated messages with invalid fill",
+			fill: "nearest",
+			err:  readers.ErrReadMessages,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        start * 1e9,
+			To:          (start + 40) * 1e9,
+			Aggregation: "AVG",
+			Interval:    "10s",
+			Fill:        tc.fill,
+			FillValue:   tc.fillValue,
+		})
+		if tc.err != nil {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of gap filled Messages from ReadAll()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlFromRollups(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	rr := twriter.NewRetention(db)
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+
+	// Messages one minute apart over two hours.
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	messages := []senml.Message{}
+	for i := 0; i < 120; i++ {
+		val := float64(i)
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(start.Add(time.Duration(i) * time.Minute).UnixNano()),
+			Value:     &val,
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	err = rr.Rollup(context.TODO(), start, end)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	// Messages are deleted, so only the reads served from the rollups
+	// return the aggregated values.
+	_, err = rr.Delete(context.TODO(), retention.Policy{Scope: retention.ScopeChannel, ID: chanID, Age: time.Hour}, end, nil)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	bucket := func(i int, value float64) senml.Message {
+		return senml.Message{
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(start.Add(time.Duration(i) * time.Hour).UnixNano()),
+			Value:     &value,
+		}
+	}
+
+	cases := []struct {
+		desc        string
+		aggregation string
+		from        time.Time
+		messages    []senml.Message
+	}{
+		{
+			desc:        "read rollups with AVG aggregation",
+			aggregation: "AVG",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 89.5), bucket(0, 29.5)},
+		},
+		{
+			desc:        "read rollups with MIN aggregation",
+			aggregation: "MIN",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 60), bucket(0, 0)},
+		},
+		{
+			desc:        "read rollups with MAX aggregation",
+			aggregation: "MAX",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 119), bucket(0, 59)},
+		},
+		{
+			desc:        "read rollups with SUM aggregation",
+			aggregation: "SUM",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 5370), bucket(0, 1770)},
+		},
+		{
+			desc:        "read rollups with COUNT aggregation",
+			aggregation: "COUNT",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 60), bucket(0, 60)},
+		},
+		{
+			desc:        "read rollups with FIRST aggregation",
+			aggregation: "FIRST",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 60), bucket(0, 0)},
+		},
+		{
+			desc:        "read rollups with LAST aggregation",
+			aggregation: "LAST",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 119), bucket(0, 59)},
+		},
+		{
+			desc:        "read rollups with DELTA aggregation",
+			aggregation: "DELTA",
+			from:        start,
+			messages:    []senml.Message{bucket(1, 60), bucket(0, 59)},
+		},
+		{
+			desc:        "read messages with aggregation not served from rollups",
+			aggregation: "STDDEV",
+			from:        start,
+		},
+		{
+			desc:        "read messages from partial minute",
+			aggregation: "AVG",
+			from:        start.Add(30 * time.Second),
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, readers.PageMetadata{
+			Limit:       limit,
+			From:        float64(tc.from.UnixNano()),
+			To:          float64(end.UnixNano()),
+			Aggregation: tc.aggregation,
+			Interval:    "1h",
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		expected := append([]readers.Message{}, fromSenml(tc.messages)...)
+		assert.Equal(t, expected, result.Messages, fmt.Sprintf("%s: got incorrect list of aggregated Messages from ReadAll()", tc.desc))
+		assert.Equal(t, uint64(len(tc.messages)), result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, len(tc.messages), result.Total))
+	}
+}
+
+func TestReadSenmlFromRollupsWithLateMessages(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+	rr := twriter.NewRetention(db)
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	message := func(at time.Time, val float64) senml.Message {
+		return senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(at.UnixNano()),
+			Value:     &val,
+		}
+	}
+
+	// Messages one minute apart over two hours.
+	start := time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	messages := []senml.Message{}
+	for i := 0; i < 120; i++ {
+		messages = append(messages, message(start.Add(time.Duration(i)*time.Minute), float64(i)))
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	err = rr.Rollup(context.TODO(), start, end)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	// Messages stored after their buckets were aggregated, and before the
+	// window of the next refresh.
+	late := []senml.Message{message(start.Add(30*time.Second), 1000), message(start.Add(90*time.Minute+30*time.Second), -1000)}
+	err = writer.ConsumeBlocking(context.TODO(), late)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	err = rr.Rollup(context.TODO(), end, end.Add(time.Minute))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	for _, agg := range []string{"AVG", "MIN", "MAX", "SUM", "COUNT", "FIRST", "LAST", "DELTA"} {
+		pm := readers.PageMetadata{
+			Limit:       limit,
+			From:        float64(start.UnixNano()),
+			To:          float64(end.UnixNano()),
+			Aggregation: agg,
+			Interval:    "1h",
+		}
+		result, err := reader.ReadAll(chanID, pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", agg, err))
+
+		// Reads of a protocol aren't served from the rollups.
+		pm.Protocol = mqttProt
+		raw, err := reader.ReadAll(chanID, pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", agg, err))
+		assert.Equal(t, raw.Messages, result.Messages, fmt.Sprintf("%s: expected rollup messages %v to match raw messages %v", agg, result.Messages, raw.Messages))
+		assert.Equal(t, raw.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", agg, raw.Total, result.Total))
+	}
+}
+
+func TestReadSenmlWithCursor(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Messages of both publishers share timestamps, so pages are split
+	// within the same time as well.
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i/2)*1e9,
+			Value:     &v,
+		}
+		if i%2 == 1 {
+			msg.Publisher = pubID2
+		}
+		messages = append(messages, msg)
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	var read []readers.Message
+	pm := readers.PageMetadata{Limit: limit, SkipTotal: true}
+	for pages := 0; pages < 4; pages++ {
+		page, err := reader.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected no total got %d", page.Total))
+		read = append(read, page.Messages...)
+		if page.NextCursor == "" {
+			break
+		}
+		pm.Cursor = page.NextCursor
+	}
+	assert.ElementsMatch(t, fromSenml(messages), read, "got incorrect list of Messages from ReadAll() with cursor")
+	for i := 1; i < len(read); i++ {
+		assert.GreaterOrEqual(t, read[i-1].(senml.Message).Time, read[i].(senml.Message).Time, "expected messages ordered by time")
+	}
+
+	page, err := reader.ReadAll(chanID, readers.PageMetadata{Limit: limit})
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, uint64(len(messages)), page.Total, fmt.Sprintf("expected %d got %d", len(messages), page.Total))
+	assert.NotEmpty(t, page.NextCursor, "expected cursor of a full page")
+
+	_, err = reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: "invalid"})
+	assert.True(t, errors.Contains(err, readers.ErrInvalidCursor), fmt.Sprintf("expected %s got %s", readers.ErrInvalidCursor, err))
+}
+
+func TestReadJSONWithCursor(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	created := time.Now().UnixNano()
+
+	// Records of an array payload share their creation time, publisher and
+	// subtopic, so pages are split within the same payload.
+	messages := json.Messages{Format: format1}
+	for i := 0; i < 25; i++ {
+		messages.Data = append(messages.Data, json.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Created:   created,
+			Subtopic:  "subtopic/format/some_json",
+			Protocol:  mqttProt,
+			Payload:   map[string]interface{}{"index": float64(i)},
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	indexes := map[float64]bool{}
+	pm := readers.PageMetadata{Limit: limit, Format: format1, SkipTotal: true}
+	for pages := 0; pages < 4; pages++ {
+		page, err := reader.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		for _, msg := range page.Messages {
+			payload := msg.(map[string]interface{})["payload"].(map[string]interface{})
+			indexes[payload["index"].(float64)] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		pm.Cursor = page.NextCursor
+	}
+	assert.Equal(t, len(messages.Data), len(indexes), fmt.Sprintf("expected %d records read with cursor got %d", len(messages.Data), len(indexes)))
+}
+
+func TestStreamSenml(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		})
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	cases := []struct {
+		desc    string
+		pm      readers.PageMetadata
+		batches []int
+		err     error
+	}{
+		{
+			desc:    "stream all messages",
+			pm:      readers.PageMetadata{Limit: limit},
+			batches: []int{10, 10, 5},
+		},
+		{
+			desc:    "stream messages with offset",
+			pm:      readers.PageMetadata{Limit: limit, Offset: 20},
+			batches: []int{5},
+		},
+		{
+			desc: "stream messages with invalid cursor",
+			pm:   readers.PageMetadata{Limit: limit, Cursor: "invalid"},
+			err:  readers.ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var batches []int
+			var read []readers.Message
+			err := reader.StreamAll(context.Background(), chanID, tc.pm, func(page readers.MessagesPage) error {
+				batches = append(batches, len(page.Messages))
+				read = append(read, page.Messages...)
+				return nil
+			})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			assert.Equal(t, tc.batches, batches, fmt.Sprintf("%s: expected batches %v got %v", tc.desc, tc.batches, batches))
+			if tc.err == nil {
+				assert.Equal(t, fromSenml(messages[tc.pm.Offset:]), read, fmt.Sprintf("%s: got incorrect list of Messages from StreamAll()", tc.desc))
+			}
+		})
+	}
+}
+
+func TestReadLatest(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	pubID2 := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	// Every publisher sends a reading of two names, older readings first.
+	var messages []senml.Message
+	latest := map[string]senml.Message{}
+	for i := 0; i < 10; i++ {
+		val := float64(i)
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now + float64(i)*1e9,
+			Value:     &val,
+		}
+		switch i % 4 {
+		case 1:
+			msg.Name = "humidity"
+		case 2:
+			msg.Publisher = pubID2
+		case 3:
+			msg.Publisher = pubID2
+			msg.Name = "humidity"
+			msg.Subtopic = subtopic
+		}
+		messages = append(messages, msg)
+		latest[msg.Publisher+msg.Subtopic+msg.Name] = msg
+	}
+	err := writer.ConsumeBlocking(context.TODO(), messages)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	// Latest messages ordered by time descending.
+	expected := []senml.Message{messages[9], messages[8], messages[7], messages[6]}
+	for _, msg := range expected {
+		require.Equal(t, latest[msg.Publisher+msg.Subtopic+msg.Name], msg)
+	}
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		pm       readers.PageMetadata
+		total    uint64
+		messages []senml.Message
+	}{
+		{
+			desc:     "read latest messages",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit},
+			total:    4,
+			messages: expected,
+		},
+		{
+			desc:     "read latest messages with limit and offset",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Offset: 1, Limit: 2},
+			total:    4,
+			messages: expected[1:3],
+		},
+		{
+			desc:     "read latest messages of publisher",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Publisher: pubID2},
+			total:    2,
+			messages: []senml.Message{messages[7], messages[6]},
+		},
+		{
+			desc:     "read latest messages with name",
+			chanID:   chanID,
+			pm:       readers.PageMetadata{Limit: limit, Name: "humidity"},
+			total:    2,
+			messages: []senml.Message{messages[9], messages[7]},
+		},
+		{
+			desc:   "read latest messages of non-existent channel",
+			chanID: testsutil.GenerateUUID(t),
+			pm:     readers.PageMetadata{Limit: limit},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadLatest(tc.chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of latest Messages from ReadLatest()", tc.desc))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.total, result.Total))
+	}
+}
+
+func TestReadSenmlGroups(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID1 := testsutil.GenerateUUID(t)
+	chanID2 := testsutil.GenerateUUID(t)
+	if chanID2 < chanID1 {
+		chanID1, chanID2 = chanID2, chanID1
+	}
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs1, msgs2 []senml.Message
+	for i := 0; i < 15; i++ {
+		msg := senml.Message{
+			Channel:   chanID1,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		}
+		msgs1 = append(msgs1, msg)
+		if i < 5 {
+			msg.Channel = chanID2
+			msgs2 = append(msgs2, msg)
+		}
+	}
+	// Only the messages of the first channel have a domain.
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), msgs1)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), msgs2)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+		pm      readers.PageMetadata
+		groups  []readers.MessagesGroup
+	}{
+		{
+			desc:    "read groups of multiple channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:limit])},
+				{Channel: chanID2, Publisher: pubID, Total: 5, Messages: fromSenml(msgs2)},
+			},
+		},
+		{
+			desc:    "read groups of multiple channels with offset",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 10, Limit: limit},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[10:])},
+			},
+		},
+		{
+			desc: "read groups of domain",
+			pm:   readers.PageMetadata{Limit: 3},
+			groups: []readers.MessagesGroup{
+				{Channel: chanID1, Publisher: pubID, Total: 15, Messages: fromSenml(msgs1[0:3])},
+			},
+		},
+		{
+			desc:    "read groups of non-existent channel",
+			chanIDs: []string{testsutil.GenerateUUID(t)},
+			pm:      readers.PageMetadata{Limit: limit},
+			groups:  []readers.MessagesGroup{},
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadGroups(domainID, tc.chanIDs, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.groups, result.Groups, fmt.Sprintf("%s: got incorrect groups from ReadGroups()", tc.desc))
+	}
+}
+
+func TestReadSenmlOfDomain(t *testing.T) {
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
+
+	domainID := testsutil.GenerateUUID(t)
+	chanID := testsutil.GenerateUUID(t)
+	pubID := testsutil.GenerateUUID(t)
+	now := float64(time.Now().UnixNano())
+
+	var msgs []senml.Message
+	for i := 0; i < 3; i++ {
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i)*1e9,
+			Value:     &v,
+		})
+	}
+	domainMsgs, otherMsgs, legacyMsgs := msgs[0:1], msgs[1:2], msgs[2:3]
+
+	err := writer.ConsumeBlocking(writers.WithDomain(context.TODO(), domainID), domainMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(writers.WithDomain(context.TODO(), testsutil.GenerateUUID(t)), otherMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	err = writer.ConsumeBlocking(context.TODO(), legacyMsgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := treader.New(db)
+
+	cases := []struct {
+		desc     string
+		pm       readers.PageMetadata
+		messages []senml.Message
+	}{
+		{
+			desc:     "read messages of channel",
+			pm:       readers.PageMetadata{Limit: limit},
+			messages: msgs,
+		},
+		{
+			desc:     "read messages of channel of domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: domainID},
+			messages: append(append([]senml.Message{}, domainMsgs...), legacyMsgs...),
+		},
+		{
+			desc:     "read messages of channel of other domain",
+			pm:       readers.PageMetadata{Limit: limit, Domain: testsutil.GenerateUUID(t)},
+			messages: legacyMsgs,
+		},
+	}
+
+	for _, tc := range cases {
+		result, err := reader.ReadAll(chanID, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.ElementsMatch(t, fromSenml(tc.messages), result.Messages, fmt.Sprintf("%s: got incorrect list of senml Messages from ReadAll()", tc.desc))
+		assert.Equal(t, uint64(len(tc.messages)), result.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, len(tc.messages), result.Total))
+	}
+}
+
 func TestReadJSON(t *testing.T) {
-	writer := twriter.New(db)
+	writer := twriter.New(db, writers.Config{OnConflict: writers.OnConflictIgnore})
 
 	id1 := testsutil.GenerateUUID(t)
 	messages1 := json.Messages{
@@ -717,6 +1450,19 @@ func TestReadJSON(t *testing.T) {
 		httpMsgs = append(httpMsgs, msgs2[i])
 	}
 
+	projected := []map[string]interface{}{}
+	for _, m := range msgs1 {
+		pm := map[string]interface{}{}
+		for k, v := range m {
+			pm[k] = v
+		}
+		pm["payload"] = map[string]interface{}{
+			"field_1": 123.0,
+			"field_5": map[string]interface{}{"field_2": 42.0},
+		}
+		projected = append(projected, pm)
+	}
+
 	reader := treader.New(db)
 
 	cases := map[string]struct {
@@ -759,6 +1505,78 @@ func TestReadJSON(t *testing.T) {
 				Messages: fromJSON(msgs2[msgsNum-20 : msgsNum]),
 			},
 		},
+		"read message with payload equality filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_1"}, Operator: readers.PayloadEqual, Value: "value"},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with payload range filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_5", "field_2"}, Operator: readers.PayloadGreaterThan, Value: 40.0},
+					{Path: []string{"field_4"}, Operator: readers.PayloadLowerThanEqual, Value: 12.344},
+				},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(msgs1[:10]),
+			},
+		},
+		"read message with non-matching payload filter": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_1"}, Operator: readers.PayloadNotEqual, Value: 123.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload range filter on string value": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				PayloadFilters: []readers.PayloadFilter{
+					{Path: []string{"field_2"}, Operator: readers.PayloadGreaterThan, Value: 1.0},
+				},
+			},
+			page: readers.MessagesPage{
+				Messages: []readers.Message{},
+			},
+		},
+		"read message with payload fields": {
+			chanID: id1,
+			pageMeta: readers.PageMetadata{
+				Format: messages1.Format,
+				Offset: 0,
+				Limit:  10,
+				Fields: []string{"field_1", "field_5.field_2", "missing"},
+			},
+			page: readers.MessagesPage{
+				Total:    100,
+				Messages: fromJSON(projected[:10]),
+			},
+		},
 		"read message with protocol": {
 			chanID: id2,
 			pageMeta: readers.PageMetadata{
@@ -782,6 +1600,18 @@ func TestReadJSON(t *testing.T) {
 	}
 }
 
+func TestReadInvalidFormat(t *testing.T) {
+	reader := treader.New(db)
+
+	formats := []string{"sensors; DROP TABLE messages", "Sensors", "messages_1h", "rollups_pending"}
+	for _, format := range formats {
+		_, err := reader.ReadAll(testsutil.GenerateUUID(t), readers.PageMetadata{Limit: limit, Format: format})
+		assert.True(t, errors.Contains(err, readers.ErrReadMessages), fmt.Sprintf("%s: expected %s got %s", format, readers.ErrReadMessages, err))
+		_, err = reader.ReadGroups(testsutil.GenerateUUID(t), nil, readers.PageMetadata{Limit: limit, Format: format})
+		assert.True(t, errors.Contains(err, readers.ErrReadMessages), fmt.Sprintf("%s: expected %s got %s", format, readers.ErrReadMessages, err))
+	}
+}
+
 func fromSenml(msg []senml.Message) []readers.Message {
 	var ret []readers.Message
 	for _, m := range msg {
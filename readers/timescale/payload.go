@@ -0,0 +1,73 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/absmach/magistrala/readers"
+	"github.com/jackc/pgtype"
+)
+
+// payloadCondition adds the payload filters of JSON messages to the
+// condition, and their paths and values to the query parameters. Equality
+// is checked by containment, which uses the GIN index of the payload.
+func payloadCondition(cond string, filters []readers.PayloadFilter, params map[string]interface{}) (string, error) {
+	conds := []string{cond}
+	for i, f := range filters {
+		path := fmt.Sprintf("payload_path_%d", i)
+		value := fmt.Sprintf("payload_value_%d", i)
+
+		switch f.Operator {
+		case readers.PayloadEqual, readers.PayloadNotEqual:
+			doc, err := containment(f.Path, f.Value)
+			if err != nil {
+				return "", err
+			}
+			params[value] = doc
+			c := fmt.Sprintf(`payload @> CAST(:%s AS JSONB)`, value)
+			if f.Operator == readers.PayloadNotEqual {
+				c = "NOT " + c
+			}
+			conds = append(conds, c)
+		case readers.PayloadLowerThan, readers.PayloadLowerThanEqual, readers.PayloadGreaterThan, readers.PayloadGreaterThanEqual:
+			var keys pgtype.TextArray
+			if err := keys.Set(f.Path); err != nil {
+				return "", err
+			}
+			params[path] = keys
+			params[value] = f.Value
+
+			switch f.Value.(type) {
+			case float64:
+				// Non-numeric values are compared as NULL instead of failing the cast.
+				conds = append(conds, fmt.Sprintf(`CAST(CASE WHEN jsonb_typeof(payload #> CAST(:%[1]s AS TEXT[])) = 'number' THEN payload #>> CAST(:%[1]s AS TEXT[]) END AS DOUBLE PRECISION) %[2]s :%[3]s`, path, f.Operator, value))
+			case string:
+				conds = append(conds, fmt.Sprintf(`payload #>> CAST(:%s AS TEXT[]) %s :%s`, path, f.Operator, value))
+			default:
+				return "", readers.ErrInvalidPayloadFilter
+			}
+		default:
+			return "", readers.ErrInvalidPayloadFilter
+		}
+	}
+
+	return strings.Join(conds, " AND "), nil
+}
+
+// containment returns the JSON document that contains the value at the path.
+func containment(path []string, value interface{}) (string, error) {
+	doc := value
+	for i := len(path) - 1; i >= 0; i-- {
+		doc = map[string]interface{}{path[i]: doc}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}